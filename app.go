@@ -3,10 +3,14 @@ package main
 import (
 	"context"
 	"embed"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -14,8 +18,20 @@ import (
 
 	wailsrt "github.com/wailsapp/wails/v2/pkg/runtime"
 
-	"github.com/rj45lab/bbs-client-go/internal/ansi"
-	"github.com/rj45lab/bbs-client-go/internal/telnet"
+	"github.com/rj45lab/bbs-client-go/internal/addressbook"
+	"github.com/rj45lab/bbs-client-go/internal/asciicast"
+	"github.com/rj45lab/bbs-client-go/internal/bluewave"
+	"github.com/rj45lab/bbs-client-go/internal/charset"
+	"github.com/rj45lab/bbs-client-go/internal/config"
+	"github.com/rj45lab/bbs-client-go/internal/fingerprint"
+	"github.com/rj45lab/bbs-client-go/internal/i18n"
+	"github.com/rj45lab/bbs-client-go/internal/plugin"
+	"github.com/rj45lab/bbs-client-go/internal/sauce"
+	"github.com/rj45lab/bbs-client-go/internal/script"
+	"github.com/rj45lab/bbs-client-go/internal/sshconn"
+	"github.com/rj45lab/bbs-client-go/internal/watch"
+	"github.com/rj45lab/bbs-client-go/pkg/ansi"
+	"github.com/rj45lab/bbs-client-go/pkg/telnet"
 )
 
 //go:embed short_*.txt
@@ -37,82 +53,195 @@ type ScreenCell struct {
 	Underline bool   `json:"ul"`
 	Blink     bool   `json:"blink"`
 	Reverse   bool   `json:"rev"`
+	Faint     bool   `json:"faint"`
+	Italic    bool   `json:"italic"`
+	Strike    bool   `json:"strike"`
+	Conceal   bool   `json:"conceal"`
+	UlStyle   string `json:"ulStyle"` // single, double, curly, dotted, dashed
+	UlR       uint8  `json:"ulR"`     // colore underline (= fg se non impostato)
+	UlG       uint8  `json:"ulG"`
+	UlB       uint8  `json:"ulB"`
 }
 
 // ScreenSnapshot — schermo + cursore in una singola risposta (BUG-010)
 type ScreenSnapshot struct {
-	Cells   [][]ScreenCell `json:"cells"`
-	CursorX int            `json:"cursorX"`
-	CursorY int            `json:"cursorY"`
+	Cells         [][]ScreenCell `json:"cells"`
+	CursorX       int            `json:"cursorX"`
+	CursorY       int            `json:"cursorY"`
+	CursorVisible bool           `json:"cursorVisible"`
+	IceColors     bool           `json:"iceColors"`
+	BoldPolicy    string         `json:"boldPolicy"`
+
+	encoded []byte // codifica JSON già pronta (GetScreenSnapshot)
+}
+
+// MarshalJSON codifica lo snapshot, o ritorna la codifica preparata da
+// GetScreenSnapshot.
+func (s ScreenSnapshot) MarshalJSON() ([]byte, error) {
+	if s.encoded != nil {
+		return s.encoded, nil
+	}
+	type plain ScreenSnapshot
+	return json.Marshal(plain(s))
+}
+
+// CellRun — sequenza di celle consecutive con gli stessi attributi.
+// Attr ha Char vuoto: i caratteri sono in Text (Len celle).
+type CellRun struct {
+	Text string     `json:"text"`
+	Len  int        `json:"len"`
+	Attr ScreenCell `json:"attr"`
+}
+
+// RunSnapshot — come ScreenSnapshot ma con le righe codificate a run
+// (RLE), molto più compatto per le schermate tipiche.
+type RunSnapshot struct {
+	Rows          [][]CellRun `json:"rows"`
+	CursorX       int         `json:"cursorX"`
+	CursorY       int         `json:"cursorY"`
+	CursorVisible bool        `json:"cursorVisible"`
+	IceColors     bool        `json:"iceColors"`
+	BoldPolicy    string      `json:"boldPolicy"`
 }
 
 // ─────────────────────────────────────────────
 // BBS Entry per il dropdown
 // ─────────────────────────────────────────────
 
-type BBSEntry struct {
-	Name string `json:"name"`
-	Host string `json:"host"`
-	Port int    `json:"port"`
-}
+// BBSEntry è una voce della rubrica (lista pubblica + voci utente).
+type BBSEntry = addressbook.Entry
 
 // ─────────────────────────────────────────────
 // App — struct principale Wails
 // ─────────────────────────────────────────────
 
 type App struct {
-	ctx    context.Context
-	conn   *telnet.Connection
-	screen *ansi.Screen
-	mu     sync.Mutex
-
-	// Stato
-	host      string
-	port      int
-	connected bool
-
-	// BBS list
-	bbsList []BBSEntry
-
-	// Log viewer
-	logPages   []string
-	logPageIdx int
-	viewingLog bool
-
-	// Session logger
-	logFile *os.File
-	logDir  string
+	ctx context.Context
+	// mu protegge lo stato dell'app e delle schede (connessioni,
+	// impostazioni, log); vedi anche screenMu
+	mu sync.Mutex
+
+	// Sessione attiva (scheda visibile): i suoi campi sono promossi, così
+	// a.conn, a.screen, a.host... si riferiscono sempre alla scheda attiva
+	*session
+	sessions    []*session // schede aperte, in ordine di apertura
+	nextSession int
+
+	// Rubrica BBS (lista pubblica + voci utente)
+	book *addressbook.Book
+
+	// Chiavi host delle BBS SSH accettate (ssh.go)
+	knownHosts *sshconn.KnownHosts
+
+	// Impostazioni persistenti e copia dell'ultima versione applicata
+	config   *config.Store
+	settings config.Settings
+
+	// Directory dei log di sessione
+	logDir string
+
+	// Pacchetto di posta offline aperto (per le risposte)
+	mailPacket *bluewave.Packet
+
+	// Link telnet:// passato all'avvio, aperto a frontend pronto
+	launchURI string
+
+	// Ripristino dopo un crash (restore.go)
+	pendingRestore *restoreState
+	restoreStop    chan struct{}
+	restoreDone    chan struct{}
+
+	// Icona nell'area di notifica
+	tray tray
+
+	// Chiamate programmate in corso, per ID (scheduler.go)
+	scheduleRunning map[string]bool
+
+	// Condivisione dello schermo via WebSocket (share.go)
+	share shareState
+
+	// Endpoint di controllo JSON-RPC (control.go)
+	control controlState
+
+	// Dimensioni del terminale scelte dal frontend (terminal.go)
+	termSize TerminalSize
+
+	// Copia dello schermo inviata al frontend (delta.go); pushMu ordina
+	// gli invii e si acquisisce prima di mu
+	pushMu sync.Mutex
+	sent   sentScreen
+	// Attributi delle celle già risolti per gli snapshot
+	attrs attrCache
+	// Celle di GetScreenSnapshot, riusate a ogni chiamata sotto pushMu
+	snapshot snapshotRows
+	// Righe annunciate ai lettori di schermo (speech.go), sotto pushMu
+	speech speechState
+
+	// screenMu protegge il contenuto degli schermi e la scheda attiva
+	// (frame.go); si acquisisce dopo mu. frame è l'ultima copia dello
+	// schermo, sotto pushMu
+	screenMu sync.RWMutex
+	frame    *screenFrame
+
+	// Modalità debug (debug.go): indirizzo di --debug e metriche
+	debugAddr  string
+	startedAt  time.Time
+	debugCount debugCounters
+	debugRates debugRates
+
+	// Chiusura ordinata (shutdown.go): cancel annulla ctx, workers conta
+	// le goroutine da attendere
+	cancel  context.CancelFunc
+	workers sync.WaitGroup
 }
 
 // NewApp crea l'app.
 func NewApp() *App {
 	return &App{
-		host: telnet.DefaultHost,
-		port: telnet.DefaultPort,
+		settings:        config.Defaults(),
+		scheduleRunning: map[string]bool{},
+		knownHosts:      sshconn.OpenKnownHosts(knownHostsPath()),
+		termSize:        TerminalSize{Cols: telnet.DefaultCols, Rows: telnet.DefaultRows},
 	}
 }
 
 // Startup è chiamato da Wails all'avvio.
 func (a *App) Startup(ctx context.Context) {
-	a.ctx = ctx
-	a.screen = ansi.NewScreen(80, 25)
-	a.conn = telnet.New()
-	a.conn.SetDownloadDir(a.downloadDir())
-
-	// DSR callback
-	a.screen.OnResponse = func(data []byte) {
-		a.conn.Send(data)
+	a.ctx, a.cancel = context.WithCancel(ctx)
+	a.startedAt = time.Now()
+	if a.debugAddr != "" {
+		a.startDebug(a.debugAddr)
 	}
 
+	// Prima scheda (le goroutine degli eventi partono con la sessione)
+	a.mu.Lock()
+	a.screenMu.Lock()
+	a.session = a.newSession()
+	a.screenMu.Unlock()
+	a.mu.Unlock()
+
+	// Carica le impostazioni e riapplicale a ogni modifica
+	a.config = a.openSettings()
+	a.config.OnChange(a.applySettings)
+	a.applySettings(a.config.Get())
+
 	// Prepara directory logs (SEC-005: 0700 per proteggere dati sensibili)
 	a.logDir = a.logsDir()
 	os.MkdirAll(a.logDir, 0700)
 
-	// Carica lista BBS
-	a.bbsList = a.loadBBSList()
+	// Carica rubrica e lista BBS pubblica
+	a.book = a.openAddressBook()
+	a.book.SetPublic(a.loadBBSList())
 
-	// Goroutine per gestire eventi dalla connessione telnet
-	go a.eventLoop()
+	// Icona nel tray (riconnessione e avvisi con finestra nascosta)
+	a.startTray()
+
+	// Controllo inattività di tutte le schede
+	a.spawn(a.idleLoop)
+	a.spawn(a.scheduleLoop)
+
+	// Stato per il ripristino dopo un crash
+	a.startRestore()
 }
 
 func (a *App) downloadDir() string {
@@ -125,116 +254,441 @@ func (a *App) logsDir() string {
 	return filepath.Join(filepath.Dir(exe), "logs")
 }
 
-// startSessionLog apre un nuovo file di log per la sessione corrente.
-func (a *App) startSessionLog(bbsName, host string, port int) {
-	a.stopSessionLog() // chiudi eventuale log precedente
-
-	// Sanitizza il nome BBS per il filename
-	safe := strings.Map(func(r rune) rune {
+// safeFileName sostituisce con '_' i caratteri non adatti a un nome file.
+func safeFileName(name string) string {
+	return strings.Map(func(r rune) rune {
 		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '-' || r == '_' {
 			return r
 		}
 		return '_'
-	}, bbsName)
+	}, name)
+}
+
+// startSessionLog apre un nuovo file di log per la sessione s.
+func (a *App) startSessionLog(s *session, bbsName, host string, port int) {
+	s.stopSessionLog() // chiudi eventuale log precedente
+
+	// Sanitizza il nome BBS per il filename
+	safe := safeFileName(bbsName)
 	if safe == "" {
 		safe = host
 	}
 
 	ts := time.Now().Format("2006-01-02_150405")
-	filename := fmt.Sprintf("%s_%s.log", safe, ts)
-	path := filepath.Join(a.logDir, filename)
+	s.logBase = filepath.Join(a.logDir, fmt.Sprintf("%s_%s", safe, ts))
+	s.logName = bbsName
+	a.openSessionLog(s, 1)
+}
 
-	f, err := os.Create(path)
-	if err != nil {
-		return
-	}
-	a.logFile = f
-	logBytesWritten = 0 // PT-004: reset contatore
+// openSessionLog apre i file della parte part del log della sessione s
+// (vedi logsplit.go).
+func (a *App) openSessionLog(s *session, part int) {
+	path := logPartPath(s.logBase, part)
+	bbsName := s.logName
+
+	a.mu.Lock()
+	opts := a.settings.Logging
+	limits := a.settings.Limits
+	host, port := s.host, s.port
+	cols, rows := s.screen.Cols, s.screen.Rows
+	a.mu.Unlock()
 
-	// Intestazione
+	now := time.Now()
 	header := fmt.Sprintf("=== Sessione %s (%s:%d) — %s ===\n",
-		bbsName, host, port, time.Now().Format("2006-01-02 15:04:05"))
-	f.WriteString(header)
-}
+		bbsName, host, port, now.Format("2006-01-02 15:04:05"))
+	if part > 1 {
+		header = fmt.Sprintf("=== Sessione %s (%s:%d) — %s, parte %d ===\n",
+			bbsName, host, port, now.Format("2006-01-02 15:04:05"), part)
+	}
+	s.logBytes = 0 // PT-004: reset contatore
+	s.transcriptBytes = 0
+	s.rawBytes = 0
+	s.logLimit = logLimit(limits)
+	s.logPart = part
+	s.logSplit = nextLogSplit(opts, now)
+
+	if opts.ANSI {
+		if f, err := os.Create(path); err == nil {
+			s.logFile = newLogWriter(f)
+			s.logFile.WriteString(header)
+
+			// Tempi di ricezione per il replay temporizzato; le parti
+			// successive continuano a contare dall'ultima scrittura, così
+			// il replay le ricuce senza salti
+			if tf, err := os.Create(timingPath(path)); err == nil {
+				s.timingFile = newLogWriter(tf)
+				if part == 1 {
+					s.lastLogWrite = now
+				}
+			}
+		}
+	}
+
+	// Trascrizione in testo semplice (ANSI rimosso)
+	if opts.Transcript {
+		if f, err := os.Create(strings.TrimSuffix(path, ".log") + ".txt"); err == nil {
+			s.transcriptFile = newLogWriter(f)
+			s.transcript = &ansi.Stripper{}
+			s.transcriptFile.WriteString(header)
+		}
+	}
 
-// maxLogSize è il limite massimo per file di log (PT-004: anti-flooding)
-const maxLogSize = 50 * 1024 * 1024 // 50 MB
+	// Byte ricevuti prima della decodifica, per controllare la codifica
+	if opts.Raw {
+		if f, err := os.Create(strings.TrimSuffix(path, ".log") + ".raw"); err == nil {
+			s.rawFile = newLogWriter(f)
+		}
+	}
 
-// logBytesWritten conta i byte scritti nel log corrente
-var logBytesWritten int64
+	// Eventi della sessione, per l'analisi con script
+	if opts.Events {
+		el, err := newEventLog(strings.TrimSuffix(path, ".log")+".jsonl", s.conn, s.logLimit)
+		if err == nil {
+			s.eventLog = el
+			el.write(evSession, map[string]any{"bbs": bbsName, "host": host, "port": port})
+		} else {
+			log.Printf("[LOG] Log degli eventi non disponibile: %v", err)
+		}
+	}
 
-// writeSessionLog scrive dati decodificati (con sequenze ANSI) nel log.
-func (a *App) writeSessionLog(text string) {
-	if a.logFile != nil {
-		// PT-004: limita dimensione log per prevenire DoS locale
-		if logBytesWritten > maxLogSize {
-			return // silenziosamente ignora dopo il limite
+	// Registrazione asciicast accanto al log, se richiesta
+	if opts.Asciicast {
+		cf, err := os.Create(strings.TrimSuffix(path, ".log") + ".cast")
+		if err != nil {
+			return
 		}
-		n, _ := a.logFile.WriteString(text)
-		logBytesWritten += int64(n)
+		title := fmt.Sprintf("%s (%s:%d)", bbsName, host, port)
+		lw := newLogWriter(cf)
+		if w, err := asciicast.NewWriter(lw, cols, rows, title, s.logLimit); err == nil {
+			s.castFile = w
+		} else {
+			lw.Close()
+		}
+	}
+}
+
+// writeSessionLog accoda dati decodificati (con sequenze ANSI) per il log
+// e, senza sequenze, per la trascrizione; i byte ricevuti così come sono
+// vanno al log grezzo. Ritorna true quando uno dei file raggiunge il
+// limite di dimensione.
+func (s *session) writeSessionLog(raw []byte, text string) (capped bool) {
+	// PT-004: limita dimensione log per prevenire DoS locale
+	// (dopo il limite i dati non vengono più scritti)
+	if s.logFile != nil && s.logBytes <= s.logLimit {
+		n, _ := s.logFile.WriteString(text)
+		s.logBytes += int64(n)
+		s.writeTiming(n)
+		capped = s.logBytes > s.logLimit
+	}
+	if s.transcriptFile != nil && s.transcriptBytes <= s.logLimit {
+		n, _ := s.transcriptFile.WriteString(s.transcript.Write(text))
+		s.transcriptBytes += int64(n)
+		capped = capped || s.transcriptBytes > s.logLimit
 	}
+	if s.rawFile != nil && s.rawBytes <= s.logLimit {
+		n, _ := s.rawFile.Write(raw)
+		s.rawBytes += int64(n)
+		capped = capped || s.rawBytes > s.logLimit
+	}
+	if s.castFile != nil {
+		s.castFile.Output(text)
+	}
+	return capped
 }
 
-// stopSessionLog chiude il file di log corrente.
-func (a *App) stopSessionLog() {
-	if a.logFile != nil {
+// stopSessionLog chiude i file di log correnti. Ritorna dopo che tutto
+// quanto ricevuto è stato scritto: alla disconnessione i file sono
+// completi.
+func (s *session) stopSessionLog() {
+	s.logSplit = time.Time{}
+	if s.logFile != nil {
 		footer := fmt.Sprintf("\n=== Fine sessione — %s ===\n",
 			time.Now().Format("2006-01-02 15:04:05"))
-		a.logFile.WriteString(footer)
-		a.logFile.Close()
-		a.logFile = nil
+		s.logFile.WriteString(footer)
+		s.logFile.Close()
+		s.logFile = nil
+	}
+	if s.castFile != nil {
+		s.castFile.Close()
+		s.castFile = nil
+	}
+	if s.timingFile != nil {
+		s.timingFile.Close()
+		s.timingFile = nil
+	}
+	if s.rawFile != nil {
+		s.rawFile.Close()
+		s.rawFile = nil
+	}
+	if s.eventLog != nil {
+		s.eventLog.close()
+		s.eventLog = nil
 	}
+	if s.transcriptFile != nil {
+		s.transcriptFile.WriteString(s.transcript.Flush())
+		s.transcriptFile.Close()
+		s.transcriptFile = nil
+		s.transcript = nil
+	}
+}
+
+// LogOptions seleziona quali file scrivere per ogni sessione.
+type LogOptions = config.Logging
+
+// SetLogOptions sceglie i file di log da scrivere (log ANSI e
+// trascrizione). Vale dalla prossima connessione.
+func (a *App) SetLogOptions(opts LogOptions) *i18n.Message {
+	return a.updateSettings(func(s *config.Settings) {
+		s.Logging.ANSI = opts.ANSI
+		s.Logging.Transcript = opts.Transcript
+	})
+}
+
+// GetLogOptions ritorna i file di log attivi.
+func (a *App) GetLogOptions() LogOptions {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.settings.Logging
+}
+
+// SetCastRecording attiva la registrazione delle sessioni in formato
+// asciicast v2 (.cast, riproducibile con asciinema) accanto al log.
+// Vale dalla prossima connessione.
+func (a *App) SetCastRecording(enabled bool) *i18n.Message {
+	return a.updateSettings(func(s *config.Settings) {
+		s.Logging.Asciicast = enabled
+	})
+}
+
+// GetCastRecording ritorna se la registrazione asciicast è attiva.
+func (a *App) GetCastRecording() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.settings.Logging.Asciicast
 }
 
 // ─────────────────────────────────────────────
 // Metodi esposti al frontend (Wails bindings)
 // ─────────────────────────────────────────────
 
-// Connect si connette alla BBS. bbsName è il nome visualizzato nel dropdown.
-func (a *App) Connect(host string, port int, bbsName string) string {
+// ConnectResult è l'esito immediato di Connect.
+type ConnectResult struct {
+	Session string        `json:"session"` // scheda che si sta connettendo
+	Error   *i18n.Message `json:"error"`   // errore prima di iniziare (nil = partita)
+}
+
+// ConnectProgress è il payload di "connect-progress".
+type ConnectProgress struct {
+	Session string `json:"session"`
+	Phase   string `json:"phase"` // telnet.Phase*, "connected" o "cancelled"
+}
+
+// Fasi di "connect-progress" oltre a quelle di telnet
+const (
+	phaseConnected = "connected"
+	phaseCancelled = "cancelled"
+)
+
+// Connect avvia la connessione della scheda attiva alla BBS e ritorna
+// subito, senza attendere la rete: le fasi arrivano al frontend con
+// "connect-progress", l'esito con "connection-status", e CancelConnect
+// interrompe una connessione lenta. bbsName è il nome visualizzato nel
+// dropdown.
+func (a *App) Connect(host string, port int, bbsName string) ConnectResult {
 	a.mu.Lock()
-	if a.connected {
-		a.mu.Unlock()
-		return "Già connesso"
+	s := a.session
+	s.cancelReconnectLocked()
+	a.mu.Unlock()
+	dial, msg := a.prepareConnect(s, host, port, bbsName)
+	if msg != nil {
+		return ConnectResult{Session: s.id, Error: msg}
 	}
+	go dial()
+	return ConnectResult{Session: s.id}
+}
+
+// connectActive connette la scheda attiva come Connect, ma attende l'esito
+// della connessione.
+func (a *App) connectActive(host string, port int, bbsName string) *i18n.Message {
+	a.mu.Lock()
+	s := a.session
+	s.cancelReconnectLocked()
 	a.mu.Unlock()
+	return a.connectSession(s, host, port, bbsName)
+}
+
+// CancelConnect interrompe la connessione in corso della scheda id ("" =
+// attiva). Non fa nulla se la scheda non si sta connettendo.
+func (a *App) CancelConnect(id string) *i18n.Message {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	s := a.session
+	if id != "" {
+		s = a.findSession(id)
+	}
+	if s == nil {
+		return i18n.New(i18n.ErrUnknownSession, id)
+	}
+	if s.dialCancel != nil {
+		s.hangup = true
+		s.dialCancel()
+	}
+	return nil
+}
+
+// connectSession connette la scheda s, anche se non è quella attiva, e
+// attende l'esito.
+func (a *App) connectSession(s *session, host string, port int, bbsName string) *i18n.Message {
+	dial, msg := a.prepareConnect(s, host, port, bbsName)
+	if msg != nil {
+		return msg
+	}
+	return dial()
+}
+
+// prepareConnect prepara la scheda s alla connessione (log, schermo,
+// impostazioni della BBS) e ritorna la funzione che apre la connessione.
+// Fino alla fine di dial la scheda risulta in connessione: un secondo
+// tentativo ritorna ErrConnecting.
+func (a *App) prepareConnect(s *session, host string, port int, bbsName string) (dial func() *i18n.Message, msg *i18n.Message) {
+	ctx, cancel := context.WithCancel(a.ctx)
+	a.mu.Lock()
+	switch {
+	case s.connected:
+		msg = i18n.New(i18n.ErrAlreadyConnected)
+	case s.dialCancel != nil:
+		msg = i18n.New(i18n.ErrConnecting)
+	default:
+		s.dialCancel = cancel
+	}
+	a.mu.Unlock()
+	if msg != nil {
+		cancel()
+		return nil, msg
+	}
 	if host == "" {
 		host = telnet.DefaultHost
 	}
 	if port <= 0 {
 		port = telnet.DefaultPort
 	}
-	a.host = host
-	a.port = port
+	a.mu.Lock()
+	s.host = host
+	s.port = port
+	s.hangup = false
+	a.mu.Unlock()
 
 	// Avvia session log
 	if bbsName == "" {
 		bbsName = host
 	}
-	a.startSessionLog(bbsName, host, port)
+	a.startSessionLog(s, bbsName, host, port)
+
+	// Software già noto dalla rubrica: vale finché il banner non lo conferma
+	entry, _ := a.book.Lookup(host, port)
+	emsi := a.newIEMSI(host, port)
+	dialer, msg := a.dialerFor(entry.Proxy)
+	if msg != nil {
+		s.stopSessionLog()
+		a.endDial(s)
+		cancel()
+		return nil, msg
+	}
+	raw := entry.Protocol == protocolSSH
+	if raw {
+		dialer = a.sshDialer(s, host, port, dialer)
+	}
 
 	// BUG-007: reset screen prima di nuova connessione
 	a.mu.Lock()
-	a.screen.Reset()
+	s.stats = ConnectionStats{Host: host, Port: port}
+	s.bbsName = bbsName
+	s.detector = fingerprint.New()
+	s.software = entry.Software
+	a.applyEncoding(s)
+	s.iemsi = emsi
+	s.files.Clear()
+	s.stream = script.NewStream()
+	s.rows, s.statusLine = entry.Rows, entry.StatusLine
+	a.resizeSession(s)
+	a.screenMu.Lock()
+	s.screen.Reset()
+	s.screen.IceColors = a.iceColorsFor(s)
+	a.screenMu.Unlock()
+	a.applyTheme(s)
+	theme := themeFor(a.settings, bbsKey(host, port))
+	s.screen.Emulation = emulationFor(a.settings, bbsKey(host, port))
+	s.conn.TermType = []byte(s.screen.Emulation.TermType())
+	s.conn.Downloads = downloadPolicy(a.settings.Downloads)
+	s.conn.FilePolicy = filePolicy(a.settings.FileTypes)
+	s.conn.FlowControl = a.settings.FlowControl
+	s.conn.Dialer = dialer
+	s.conn.Raw = raw
+	a.markShared(s)
+	s.watcher = watch.New(watchPhrases(a.settings, bbsKey(host, port)))
+	active := s == a.session
 	a.mu.Unlock()
-	wailsrt.EventsEmit(a.ctx, "screen-update", true)
-
-	err := a.conn.Connect(host, port)
-	if err != nil {
-		a.stopSessionLog()
-		return fmt.Sprintf("Errore: %v", err)
+	a.emitFor(s, "screen-update")
+	a.emitFor(s, "theme-changed", theme)
+	if active {
+		a.emitGeometry()
 	}
-	return ""
+
+	return func() *i18n.Message {
+		defer cancel()
+		err := s.conn.ConnectContext(ctx, host, port)
+		a.endDial(s)
+		if errors.Is(err, context.Canceled) {
+			s.eventLog.write(evDisconnected, map[string]any{"reason": phaseCancelled})
+			s.stopSessionLog()
+			a.emitFor(s, "connect-progress", ConnectProgress{Session: s.id, Phase: phaseCancelled})
+			a.emitFor(s, "connection-status", "disconnected")
+			return i18n.Err(err)
+		}
+		if err != nil {
+			s.eventLog.write(evError, map[string]any{"reason": err.Error()})
+			s.stopSessionLog()
+			// Gli errori di verifica della chiave host arrivano avvolti da ssh
+			var m *i18n.Message
+			if errors.As(err, &m) {
+				return m
+			}
+			return i18n.Err(err)
+		}
+		return nil
+	}, nil
+}
+
+// endDial segna la fine del tentativo di connessione di s.
+func (a *App) endDial(s *session) {
+	a.mu.Lock()
+	s.dialCancel = nil
+	a.mu.Unlock()
 }
 
-// Disconnect chiude la connessione.
+// Disconnect chiude la connessione, dopo la sequenza di uscita della BBS
+// se la rubrica ne ha una (vedi logoff).
 func (a *App) Disconnect() {
-	a.conn.Disconnect()
 	a.mu.Lock()
-	a.connected = false
+	s := a.session
+	s.hangup = true // la chiusura dalla BBS non è una linea caduta
 	a.mu.Unlock()
-	a.stopSessionLog()
-	wailsrt.EventsEmit(a.ctx, "connection-status", "disconnected")
+	a.logoff(s)
+	a.disconnectSession(s)
+}
+
+// disconnectSession chiude la connessione di una scheda qualsiasi.
+func (a *App) disconnectSession(s *session) {
+	a.mu.Lock()
+	s.hangup = true
+	s.cancelReconnectLocked()
+	a.mu.Unlock()
+	s.conn.Disconnect()
+	a.endSession(s)
+	a.logClosed(s, evDisconnected, "hangup")
+	s.stopSessionLog()
+	a.emitFor(s, "connection-status", "disconnected")
+	a.emitSessions()
 }
 
 // SendKey invia un tasto al server (chiamato dal frontend su keydown).
@@ -243,59 +697,48 @@ func (a *App) SendKey(data []byte) {
 	ok := a.connected
 	a.mu.Unlock()
 	if ok {
+		a.touchInput()
 		a.conn.Send(data)
 	}
 }
 
-// SendText invia una stringa come bytes CP437 al server.
+// SendText invia una stringa al server nella codifica della sessione
+// (CP437 tradotto secondo il layout della tastiera). Il testo di più
+// caratteri (input method, macro) segue il ritmo di invio della BBS.
 func (a *App) SendText(text string) {
 	a.mu.Lock()
-	ok := a.connected
+	ok, pace := a.connected, pasteFor(a.settings, bbsKey(a.host, a.port))
+	data := a.translateKeys(text)
 	a.mu.Unlock()
 	if !ok {
 		return
 	}
-	// Converti da UTF-8 a bytes da inviare
-	a.conn.Send([]byte(text))
+	if len(data) > 1 && paced(pace) && a.startPaced(data, pace) == nil {
+		return
+	}
+	a.touchInput()
+	a.conn.Send(data)
 }
 
-// SendSpecialKey invia un tasto speciale (arrow, F-key, ecc.)
+// SendSpecialKey invia un tasto speciale (arrow, F-key, tastierino...).
+// Le sequenze dipendono dall'emulazione della BBS (vedi ansi.Emulation),
+// dalla variante dei tasti scelta per lei e dai modi cursore e tastierino
+// che ha chiesto.
 func (a *App) SendSpecialKey(key string) {
 	a.mu.Lock()
 	ok := a.connected
+	data := a.screen.Emulation.Key(key, ansi.KeyModes{
+		AppCursor: a.screen.AppCursorKeys(),
+		AppKeypad: a.screen.AppKeypad(),
+		Profile:   keyProfileFor(a.settings, bbsKey(a.host, a.port)),
+	})
+	a.deadKey = 0
 	a.mu.Unlock()
 	if !ok {
 		return
 	}
-	keyMap := map[string][]byte{
-		"Enter":     {0x0D},
-		"Backspace": {0x08},
-		"Tab":       {0x09},
-		"Escape":    {0x1B},
-		"ArrowUp":   {0x1B, '[', 'A'},
-		"ArrowDown": {0x1B, '[', 'B'},
-		"ArrowRight":{0x1B, '[', 'C'},
-		"ArrowLeft": {0x1B, '[', 'D'},
-		"Home":      {0x1B, '[', 'H'},
-		"End":       {0x1B, '[', 'F'},
-		"PageUp":    {0x1B, '[', '5', '~'},
-		"PageDown":  {0x1B, '[', '6', '~'},
-		"Insert":    {0x1B, '[', '2', '~'},
-		"Delete":    {0x1B, '[', '3', '~'},
-		"F1":        {0x1B, 'O', 'P'},
-		"F2":        {0x1B, 'O', 'Q'},
-		"F3":        {0x1B, 'O', 'R'},
-		"F4":        {0x1B, 'O', 'S'},
-		"F5":        {0x1B, '[', '1', '5', '~'},
-		"F6":        {0x1B, '[', '1', '7', '~'},
-		"F7":        {0x1B, '[', '1', '8', '~'},
-		"F8":        {0x1B, '[', '1', '9', '~'},
-		"F9":        {0x1B, '[', '2', '0', '~'},
-		"F10":       {0x1B, '[', '2', '1', '~'},
-		"F11":       {0x1B, '[', '2', '3', '~'},
-		"F12":       {0x1B, '[', '2', '4', '~'},
-	}
-	if data, ok := keyMap[key]; ok {
+	a.touchInput()
+	if data != nil {
 		a.conn.Send(data)
 	}
 }
@@ -308,6 +751,7 @@ func (a *App) SendCtrlKey(letter string) {
 	if !ok || len(letter) == 0 {
 		return
 	}
+	a.touchInput()
 	ch := letter[0]
 	if ch >= 'a' && ch <= 'z' {
 		ch -= 'a' - 'A'
@@ -319,90 +763,493 @@ func (a *App) SendCtrlKey(letter string) {
 
 // GetScreen ritorna lo stato attuale dello schermo come array 2D di celle.
 func (a *App) GetScreen() [][]ScreenCell {
-	a.mu.Lock()
-	defer a.mu.Unlock()
+	a.pushMu.Lock()
+	defer a.pushMu.Unlock()
+	f := a.takeFrame()
+	if f == nil {
+		return nil
+	}
+	return a.exportRows(f, &snapshotRows{})
+}
 
-	rows := make([][]ScreenCell, a.screen.Rows)
-	for y := 0; y < a.screen.Rows; y++ {
-		row := make([]ScreenCell, a.screen.Cols)
-		for x := 0; x < a.screen.Cols; x++ {
-			cell := a.screen.Buffer[y][x]
-			fgR, fgG, fgB := cell.Attr.FG.ToRGB(true, cell.Attr.Bold)
-			bgR, bgG, bgB := cell.Attr.BG.ToRGB(false, false)
-			if cell.Attr.Reverse {
-				fgR, fgG, fgB, bgR, bgG, bgB = bgR, bgG, bgB, fgR, fgG, fgB
-			}
-			ch := string(cell.Char)
-			if cell.Char < 0x20 {
-				ch = " "
-			}
-			row[x] = ScreenCell{
-				Char: ch,
-				FgR: fgR, FgG: fgG, FgB: fgB,
-				BgR: bgR, BgG: bgG, BgB: bgB,
-				Bold: cell.Attr.Bold, Underline: cell.Attr.Underline,
-				Blink: cell.Attr.Blink, Reverse: cell.Attr.Reverse,
-			}
+// GetScreenRegion ritorna solo il rettangolo di w×h celle che parte da
+// (x, y), ritagliato ai bordi dello schermo: per i widget che seguono una
+// parte dello schermo (minimappa, riga di stato della BBS) senza copiarlo
+// tutto.
+func (a *App) GetScreenRegion(x, y, w, h int) [][]ScreenCell {
+	a.pushMu.Lock()
+	defer a.pushMu.Unlock()
+	f := a.takeFrame()
+	if f == nil {
+		return nil
+	}
+	x0, y0 := max(x, 0), max(y, 0)
+	x1, y1 := min(x+w, f.cols), min(y+h, f.rows)
+	if x0 >= x1 || y0 >= y1 {
+		return [][]ScreenCell{}
+	}
+	a.syncAttrs(f)
+	rows := make([][]ScreenCell, 0, y1-y0)
+	for _, src := range f.cells[y0:y1] {
+		row := make([]ScreenCell, 0, x1-x0)
+		for _, cell := range src[x0:x1] {
+			row = append(row, a.exportCell(cell))
 		}
-		rows[y] = row
+		rows = append(rows, row)
 	}
 	return rows
 }
 
+// snapshotRows contiene le celle di uno snapshot: le righe condividono un
+// unico array. GetScreenSnapshot riusa il proprio (App.snapshot), per non
+// allocare a ogni chiamata quando il frontend interroga spesso.
+type snapshotRows struct {
+	cells []ScreenCell
+	rows  [][]ScreenCell
+}
+
+// exportRows esporta tutte le celle della copia f nel buffer buf, che
+// riallarga solo se le dimensioni sono cresciute. Chiamare con pushMu
+// acquisito.
+func (a *App) exportRows(f *screenFrame, buf *snapshotRows) [][]ScreenCell {
+	cols := f.cols
+	if n := cols * f.rows; cap(buf.cells) < n {
+		buf.cells = make([]ScreenCell, n)
+	}
+	if cap(buf.rows) < f.rows {
+		buf.rows = make([][]ScreenCell, f.rows)
+	}
+	rows := buf.rows[:f.rows]
+	a.syncAttrs(f)
+	for y := range rows {
+		rows[y] = buf.cells[y*cols : (y+1)*cols : (y+1)*cols]
+		for x, cell := range f.cells[y] {
+			rows[y][x] = a.exportCell(cell)
+		}
+	}
+	return rows
+}
+
+// exportCell converte una cella dello screen nel formato del frontend,
+// risolvendo colori, reverse e iCE colors. Chiamare con pushMu acquisito,
+// dopo syncAttrs.
+func (a *App) exportCell(cell ansi.Cell) ScreenCell {
+	out := a.exportAttr(cell.Attr)
+	out.Char = a.attrs.char(cell.Char)
+	return out
+}
+
+// maxAttrCache limita gli attributi memorizzati: l'arte truecolor ne ha
+// uno diverso quasi per ogni cella.
+const maxAttrCache = 4096
+
+// attrCache memorizza gli attributi già risolti (colori RGB, bold, blink)
+// per attributi della cella: uno schermo ne usa pochi diversi, e
+// risolverli per ogni cella dominava il costo degli snapshot. Vale finché
+// palette, iCE colors e bold policy restano quelli di key. Conserva
+// anche le stringhe dei caratteri, per non allocarne una per cella.
+type attrCache struct {
+	key   attrCacheKey
+	cells map[ansi.CellAttr]ScreenCell
+	last  ansi.CellAttr // ultimo attributo cercato: le celle vicine di
+	cell  ScreenCell    // solito lo condividono
+	chars map[rune]string
+	// colors risolve i colori secondo key (vedi screenFrame.colors)
+	colors *ansi.Screen
+}
+
+type attrCacheKey struct {
+	palette ansi.Palette
+	ice     bool
+	bold    ansi.BoldPolicy
+}
+
+// syncAttrs svuota la cache degli attributi se palette, iCE colors o bold
+// policy della copia f sono cambiati. Chiamare con pushMu acquisito,
+// prima di esportare le celle.
+func (a *App) syncAttrs(f *screenFrame) {
+	key := attrCacheKey{palette: *f.colors.Palette, ice: f.colors.IceColors, bold: f.colors.BoldPolicy}
+	if a.attrs.cells == nil || a.attrs.key != key || len(a.attrs.cells) >= maxAttrCache {
+		a.attrs = attrCache{key: key, cells: make(map[ansi.CellAttr]ScreenCell), chars: a.attrs.chars, colors: f.colors}
+	}
+}
+
+// exportAttr ritorna la cella esportata (senza carattere) per gli
+// attributi attr. Chiamare con pushMu acquisito, dopo syncAttrs.
+func (a *App) exportAttr(attr ansi.CellAttr) ScreenCell {
+	if attr == a.attrs.last && len(a.attrs.cells) > 0 {
+		return a.attrs.cell
+	}
+	out, ok := a.attrs.cells[attr]
+	if !ok {
+		out = a.resolveAttr(attr)
+		a.attrs.cells[attr] = out
+	}
+	a.attrs.last, a.attrs.cell = attr, out
+	return out
+}
+
+// resolveAttr risolve colori e attributi visibili di attr secondo la
+// configurazione dei colori della cache.
+func (a *App) resolveAttr(attr ansi.CellAttr) ScreenCell {
+	key := a.attrs.key
+	fg, bg, ul := a.attrs.colors.CellColors(ansi.Cell{Attr: attr})
+	return ScreenCell{
+		FgR: fg[0], FgG: fg[1], FgB: fg[2],
+		BgR: bg[0], BgG: bg[1], BgB: bg[2],
+		Bold: attr.Bold && key.bold.UsesFont(), Underline: attr.Underline,
+		Blink: attr.EffectiveBlink(key.ice), Reverse: attr.Reverse,
+		Faint: attr.Faint, Italic: attr.Italic,
+		Strike: attr.Strike, Conceal: attr.Conceal,
+		UlStyle: attr.UnderlineStyle.String(),
+		UlR:     ul[0], UlG: ul[1], UlB: ul[2],
+	}
+}
+
+// char ritorna il carattere c come stringa, uno spazio per i controlli.
+func (c *attrCache) char(r rune) string {
+	if r < 0x20 {
+		return " "
+	}
+	s, ok := c.chars[r]
+	if !ok {
+		if c.chars == nil || len(c.chars) >= maxAttrCache {
+			c.chars = make(map[rune]string)
+		}
+		s = string(r)
+		c.chars[r] = s
+	}
+	return s
+}
+
 // GetCursor ritorna posizione cursore {x, y}.
 func (a *App) GetCursor() map[string]int {
-	a.mu.Lock()
-	defer a.mu.Unlock()
+	a.screenMu.RLock()
+	defer a.screenMu.RUnlock()
 	return map[string]int{"x": a.screen.CursorX, "y": a.screen.CursorY}
 }
 
 // GetScreenSnapshot ritorna schermo + cursore in una singola chiamata IPC (BUG-010).
+// Le celle passano dal buffer riusato App.snapshot e vengono codificate
+// subito, sotto pushMu: lo snapshot ritornato ha solo la codifica JSON
+// (Cells resta nil).
 func (a *App) GetScreenSnapshot() ScreenSnapshot {
-	a.mu.Lock()
-	defer a.mu.Unlock()
+	a.pushMu.Lock()
+	defer a.pushMu.Unlock()
+	f := a.takeFrame()
+	if f == nil {
+		return ScreenSnapshot{}
+	}
+	snap := ScreenSnapshot{
+		Cells:         a.exportRows(f, &a.snapshot),
+		CursorX:       f.cursorX,
+		CursorY:       f.cursorY,
+		CursorVisible: f.cursorVisible,
+		IceColors:     f.colors.IceColors,
+		BoldPolicy:    f.colors.BoldPolicy.String(),
+	}
+	data, err := snap.MarshalJSON()
+	if err != nil {
+		log.Printf("[SNAPSHOT] codifica: %v", err)
+		return ScreenSnapshot{}
+	}
+	snap.Cells, snap.encoded = nil, data
+	return snap
+}
 
-	rows := make([][]ScreenCell, a.screen.Rows)
-	for y := 0; y < a.screen.Rows; y++ {
-		row := make([]ScreenCell, a.screen.Cols)
-		for x := 0; x < a.screen.Cols; x++ {
-			cell := a.screen.Buffer[y][x]
-			fgR, fgG, fgB := cell.Attr.FG.ToRGB(true, cell.Attr.Bold)
-			bgR, bgG, bgB := cell.Attr.BG.ToRGB(false, false)
-			if cell.Attr.Reverse {
-				fgR, fgG, fgB, bgR, bgG, bgB = bgR, bgG, bgB, fgR, fgG, fgB
-			}
-			ch := string(cell.Char)
-			if cell.Char < 0x20 {
-				ch = " "
-			}
-			row[x] = ScreenCell{
-				Char: ch,
-				FgR: fgR, FgG: fgG, FgB: fgB,
-				BgR: bgR, BgG: bgG, BgB: bgB,
-				Bold: cell.Attr.Bold, Underline: cell.Attr.Underline,
-				Blink: cell.Attr.Blink, Reverse: cell.Attr.Reverse,
-			}
+// GetScreenRuns ritorna lo snapshot con le righe raggruppate in run di
+// attributi identici, riducendo payload JSON e parsing nel frontend. Gli
+// aggiornamenti successivi (vedi pushScreen) partono da questo snapshot.
+func (a *App) GetScreenRuns() RunSnapshot {
+	a.pushMu.Lock()
+	defer a.pushMu.Unlock()
+	f := a.takeFrame()
+	if f == nil {
+		return RunSnapshot{}
+	}
+
+	rows := make([][]CellRun, f.rows)
+	for y := range rows {
+		rows[y] = a.rowRuns(f, y)
+	}
+	a.sent = sentScreen{screen: f.screen, cols: f.cols, rows: append([][]CellRun(nil), rows...)}
+	return RunSnapshot{
+		Rows:          rows,
+		CursorX:       f.cursorX,
+		CursorY:       f.cursorY,
+		CursorVisible: f.cursorVisible,
+		IceColors:     f.colors.IceColors,
+		BoldPolicy:    f.colors.BoldPolicy.String(),
+	}
+}
+
+// BBSQuery filtra e ordina la rubrica (testo, tag, software, preferiti).
+type BBSQuery = addressbook.Query
+
+// GetBBSList ritorna la rubrica filtrata e ordinata secondo query; con la
+// query vuota tutte le voci: preferiti, voci utente e lista pubblica.
+func (a *App) GetBBSList(query BBSQuery) []BBSEntry {
+	return a.book.Search(query)
+}
+
+// GetBBSTags ritorna i tag usati in rubrica, per i filtri del frontend.
+func (a *App) GetBBSTags() []string {
+	return a.book.Tags()
+}
+
+// AddBBS aggiunge una BBS alla rubrica. Ritorna "" o un messaggio d'errore.
+func (a *App) AddBBS(entry BBSEntry) *i18n.Message {
+	_, err := a.book.Add(entry)
+	return i18n.Err(err)
+}
+
+// UpdateBBS modifica una voce della rubrica (individuata dall'ID),
+// compreso il flag preferito.
+func (a *App) UpdateBBS(entry BBSEntry) *i18n.Message {
+	return i18n.Err(a.book.Update(entry))
+}
+
+// DeleteBBS rimuove una voce dalla rubrica.
+func (a *App) DeleteBBS(id string) *i18n.Message {
+	return i18n.Err(a.book.Delete(id))
+}
+
+// ReorderBBS porta in testa le voci indicate, nell'ordine dato.
+func (a *App) ReorderBBS(ids []string) *i18n.Message {
+	return i18n.Err(a.book.Reorder(ids))
+}
+
+// ImportResult riassume l'esito di un import della rubrica.
+type ImportResult struct {
+	Added   int           `json:"added"`
+	Skipped int           `json:"skipped"`
+	Error   *i18n.Message `json:"error"`
+}
+
+// ImportPhonebook importa una rubrica SyncTERM (.lst) o NetRunner (testo)
+// scelta dall'utente, aggiungendo solo le BBS non ancora presenti.
+func (a *App) ImportPhonebook() ImportResult {
+	path, err := wailsrt.OpenFileDialog(a.ctx, wailsrt.OpenDialogOptions{
+		Title: i18n.T(i18n.DlgImportBook),
+		Filters: []wailsrt.FileFilter{
+			{DisplayName: "SyncTERM (*.lst)", Pattern: "*.lst"},
+			{DisplayName: "NetRunner (*.txt)", Pattern: "*.txt"},
+			{DisplayName: i18n.T(i18n.DlgAllFiles), Pattern: "*"},
+		},
+	})
+	if err != nil || path == "" {
+		return ImportResult{Error: i18n.Err(err)}
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return ImportResult{Error: i18n.Err(err)}
+	}
+	defer f.Close()
+
+	parse := addressbook.ImportNetRunner
+	if strings.EqualFold(filepath.Ext(path), ".lst") {
+		parse = addressbook.ImportSyncTERM
+	}
+	entries, err := parse(f)
+	if err != nil {
+		return ImportResult{Error: i18n.Err(err)}
+	}
+	added, skipped, err := a.book.Import(entries)
+	return ImportResult{Added: added, Skipped: skipped, Error: i18n.Err(err)}
+}
+
+// phonebookExts associa ai formati di export l'estensione proposta.
+var phonebookExts = map[string]string{
+	addressbook.FormatJSON:     "json",
+	addressbook.FormatCSV:      "csv",
+	addressbook.FormatSyncTERM: "lst",
+}
+
+// ExportPhonebook salva la rubrica (voci, protocollo, font e preferiti)
+// nel formato indicato: json, csv o syncterm. Con path vuoto chiede
+// all'utente dove salvare.
+func (a *App) ExportPhonebook(format, path string) *i18n.Message {
+	format = strings.ToLower(format)
+	ext, ok := phonebookExts[format]
+	if !ok {
+		return i18n.New(i18n.ErrUnknownFormat, format)
+	}
+	if path == "" {
+		var err error
+		path, err = a.exportDialog(i18n.T(i18n.DlgExportBook), "rubrica."+ext, i18n.T(i18n.DlgBookFiles, ext), "*."+ext)
+		if err != nil || path == "" {
+			return i18n.Err(err)
 		}
-		rows[y] = row
 	}
-	return ScreenSnapshot{
-		Cells:   rows,
-		CursorX: a.screen.CursorX,
-		CursorY: a.screen.CursorY,
+	f, err := os.Create(path)
+	if err != nil {
+		return i18n.Err(err)
+	}
+	if err := addressbook.Export(f, format, a.book.List()); err != nil {
+		f.Close()
+		return i18n.Err(err)
 	}
+	return i18n.Err(f.Close())
 }
 
-// GetBBSList ritorna la lista delle BBS disponibili.
-func (a *App) GetBBSList() []BBSEntry {
-	return a.bbsList
+// openAddressBook apre la rubrica nella directory di configurazione. Se il
+// file è illeggibile la rubrica resta solo in memoria, per non
+// sovrascrivere i dati dell'utente.
+func (a *App) openAddressBook() *addressbook.Book {
+	path, err := addressbook.DefaultPath()
+	if err != nil {
+		return addressbook.New("")
+	}
+	book, err := addressbook.Open(path)
+	if err != nil {
+		log.Printf("[RUBRICA] %v — modifiche non salvate", err)
+		return addressbook.New("")
+	}
+	return book
 }
 
 // ClearScreen pulisce lo schermo.
 func (a *App) ClearScreen() {
 	a.mu.Lock()
+	a.screenMu.Lock()
 	a.screen.Reset()
+	a.screenMu.Unlock()
+	a.mu.Unlock()
+	a.pushScreen()
+}
+
+// SetIceColors attiva/disattiva gli iCE colors per la BBS corrente
+// (blink → sfondo bright). L'impostazione viene ricordata per host:port.
+func (a *App) SetIceColors(enabled bool) *i18n.Message {
+	a.mu.Lock()
+	key := bbsKey(a.host, a.port)
+	a.screenMu.Lock()
+	a.screen.IceColors = enabled
+	a.screenMu.Unlock()
 	a.mu.Unlock()
-	wailsrt.EventsEmit(a.ctx, "screen-update", true)
+	return a.updateSettings(func(s *config.Settings) {
+		s.IceColors[key] = enabled
+	})
+}
+
+// GetIceColors ritorna lo stato iCE colors dello schermo corrente.
+func (a *App) GetIceColors() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.screen.IceColors
+}
+
+// SetC1Controls abilita l'interpretazione dei controlli C1 a 8 bit
+// (0x84 IND, 0x85 NEL, 0x8D RI, 0x9B CSI). Disattivato di default perché
+// in CP437 quei byte sono caratteri accentati (ä, à, ì, ¢).
+func (a *App) SetC1Controls(enabled bool) *i18n.Message {
+	return a.updateSettings(func(s *config.Settings) {
+		s.C1Controls = enabled
+	})
+}
+
+// SetBoldPolicy imposta la resa del bold: "bright" (solo colore),
+// "font" (solo grassetto) o "both".
+func (a *App) SetBoldPolicy(policy string) *i18n.Message {
+	p, ok := ansi.ParseBoldPolicy(policy)
+	if !ok {
+		return i18n.New(i18n.ErrUnknownBold, policy)
+	}
+	return a.updateSettings(func(s *config.Settings) {
+		s.BoldPolicy = p.String()
+	})
+}
+
+// GetBoldPolicy ritorna la policy bold corrente.
+func (a *App) GetBoldPolicy() string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.screen.BoldPolicy.String()
+}
+
+// PaletteInfo descrive la palette 16 colori attiva.
+type PaletteInfo struct {
+	Name   string   `json:"name"`
+	Colors []string `json:"colors"` // 16 colori "#rrggbb"
+}
+
+// ListPalettes ritorna i nomi delle palette predefinite.
+func (a *App) ListPalettes() []string {
+	names := make([]string, 0, len(ansi.Palettes))
+	for name := range ansi.Palettes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetPalette seleziona una palette predefinita per nome, oppure con
+// name "custom" una palette personalizzata da 16 colori esadecimali.
+func (a *App) SetPalette(name string, colors []string) *i18n.Message {
+	if _, err := resolvePalette(name, colors); err != nil {
+		return i18n.Err(err)
+	}
+	return a.updateSettings(func(s *config.Settings) {
+		s.Palette = name
+		s.CustomPalette = nil
+		if name == "custom" {
+			s.CustomPalette = colors
+		}
+	})
+}
+
+// GetPalette ritorna la palette attiva.
+func (a *App) GetPalette() PaletteInfo {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return PaletteInfo{Name: a.settings.Palette, Colors: a.screen.ActivePalette().Hex()}
+}
+
+// bbsKey identifica una BBS per le impostazioni per-BBS.
+func bbsKey(host string, port int) string {
+	return fmt.Sprintf("%s:%d", strings.ToLower(host), port)
+}
+
+// ConnectionStats raccoglie i contatori della connessione corrente.
+type ConnectionStats struct {
+	Host          string    `json:"host"`
+	Port          int       `json:"port"`
+	ConnectedAt   time.Time `json:"connectedAt"`
+	BytesReceived int64     `json:"bytesReceived"`
+	Bells         int       `json:"bells"`
+}
+
+// GetConnectionStats ritorna le statistiche della connessione corrente.
+func (a *App) GetConnectionStats() ConnectionStats {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.stats
+}
+
+// bellInterval è l'intervallo minimo tra due eventi "bell" al frontend:
+// una raffica di BEL (tipica delle pagine del sysop) produce un solo suono.
+const bellInterval = 250 * time.Millisecond
+
+// onBell è chiamato dallo screen su BEL (con a.mu acquisito).
+func (a *App) onBell(s *session) {
+	if s.connected {
+		s.stats.Bells++
+	}
+	now := time.Now()
+	if now.Sub(s.lastBell) < bellInterval {
+		return
+	}
+	s.lastBell = now
+	for _, p := range s.plugins {
+		p.Event(plugin.EventBell)
+	}
+	if s.dnd {
+		s.missEvent(MissedEvent{Kind: missedBell})
+		return
+	}
+	if s == a.session {
+		wailsrt.EventsEmit(a.ctx, "bell", s.stats.Bells)
+	}
 }
 
 // IsConnected ritorna lo stato di connessione.
@@ -413,26 +1260,26 @@ func (a *App) IsConnected() bool {
 }
 
 // UploadFile apre un file dialog e avvia upload ZMODEM.
-func (a *App) UploadFile() string {
+func (a *App) UploadFile() *i18n.Message {
 	a.mu.Lock()
 	ok := a.connected
 	a.mu.Unlock()
 	if !ok {
-		return "Non connesso"
+		return i18n.New(i18n.ErrNotConnected)
 	}
 	path, err := wailsrt.OpenFileDialog(a.ctx, wailsrt.OpenDialogOptions{
-		Title: "Seleziona file per upload ZMODEM",
+		Title: i18n.T(i18n.DlgUpload),
 	})
 	if err != nil {
-		return fmt.Sprintf("Errore: %v", err)
+		return i18n.Err(err)
 	}
 	if path == "" {
-		return "" // annullato
+		return nil // annullato
 	}
 	go func() {
 		a.conn.StartZmodemUpload(path)
 	}()
-	return ""
+	return nil
 }
 
 // CancelZmodem annulla il trasferimento ZMODEM in corso.
@@ -441,44 +1288,62 @@ func (a *App) CancelZmodem() {
 }
 
 // LoadLog apre un file di log sessione e lo renderizza nel terminale.
-func (a *App) LoadLog() string {
+func (a *App) LoadLog() *i18n.Message {
 	path, err := wailsrt.OpenFileDialog(a.ctx, wailsrt.OpenDialogOptions{
-		Title:            "Apri log sessione",
+		Title:            i18n.T(i18n.DlgOpenLog),
 		DefaultDirectory: a.logDir,
 		Filters: []wailsrt.FileFilter{
-			{DisplayName: "Log files (*.log)", Pattern: "*.log"},
-			{DisplayName: "Tutti i file (*)", Pattern: "*"},
+			{DisplayName: i18n.T(i18n.DlgLogFiles), Pattern: "*.log"},
+			{DisplayName: i18n.T(i18n.DlgAllFiles), Pattern: "*"},
 		},
 	})
 	if err != nil {
-		return fmt.Sprintf("Errore: %v", err)
+		return i18n.Err(err)
 	}
 	if path == "" {
-		return "" // annullato
+		return nil // annullato
 	}
 
-	content, err := os.ReadFile(path)
-	if err != nil {
-		return fmt.Sprintf("Errore lettura: %v", err)
+	// Le parti di una sessione divisa (logsplit.go) si leggono insieme
+	text, timing, rec, msg := readLogParts(logParts(path))
+	if msg != nil {
+		return msg
 	}
 
-	// Se connesso, disconnetti
+	a.disconnectForViewer()
+	a.haltPlayback()
+
+	cleanPages := splitLogPages(text)
+
+	// Salva le pagine per navigazione
 	a.mu.Lock()
-	wasConn := a.connected
-	if wasConn {
-		a.connected = false
-	}
+	a.logPages = cleanPages
+	a.logPageIdx = 0
+	a.viewerText = text
+	a.viewerTiming = timing
+	a.art = nil
+	a.viewingLog = true
+	a.applySauce(rec)
 	a.mu.Unlock()
-	if wasConn {
-		a.conn.Disconnect()
-	}
 
-	// Rimuovi intestazione/chiusura sessione
-	text := string(content)
-	text = regexp.MustCompile(`(?m)^=== Sessione .+===\n?`).ReplaceAllString(text, "")
-	text = regexp.MustCompile(`\n?=== Fine sessione .+===$`).ReplaceAllString(text, "")
+	a.showLogPage()
+	return nil
+}
+
+// Regexp di intestazione/chiusura dei log di sessione
+var (
+	logHeaderRe = regexp.MustCompile(`(?m)^=== Sessione .+===\n?`)
+	logFooterRe = regexp.MustCompile(`\n?=== Fine sessione .+===$`)
+)
+
+// stripLogFrame rimuove intestazione e chiusura sessione da un log.
+func stripLogFrame(text string) string {
+	text = logHeaderRe.ReplaceAllString(text, "")
+	return logFooterRe.ReplaceAllString(text, "")
+}
 
-	// Splitta in pagine su ESC[2J (clear screen)
+// splitLogPages divide il testo di un log in pagine su ESC[2J (clear screen).
+func splitLogPages(text string) []string {
 	clearSeq := "\x1b[2J"
 	parts := strings.Split(text, clearSeq)
 	var cleanPages []string
@@ -495,22 +1360,13 @@ func (a *App) LoadLog() string {
 	if len(cleanPages) == 0 {
 		cleanPages = []string{text}
 	}
-
-	// Salva le pagine per navigazione
-	a.mu.Lock()
-	a.logPages = cleanPages
-	a.logPageIdx = 0
-	a.viewingLog = true
-	a.mu.Unlock()
-
-	a.showLogPage()
-	return ""
+	return cleanPages
 }
 
 // LogNextPage avanza alla pagina successiva del log.
 func (a *App) LogNextPage() {
 	a.mu.Lock()
-	if a.logPageIdx < len(a.logPages)-1 {
+	if a.logPageIdx < a.logPageCount()-1 {
 		a.logPageIdx++
 	}
 	a.mu.Unlock()
@@ -529,16 +1385,53 @@ func (a *App) LogPrevPage() {
 
 // LogExit esce dalla visualizzazione log.
 func (a *App) LogExit() {
+	a.haltPlayback()
 	a.mu.Lock()
 	a.viewingLog = false
 	a.logPages = nil
+	a.art = nil
+	a.viewerText = ""
+	a.viewerTiming = nil
 	a.logPageIdx = 0
+	a.applySauce(nil)
+	a.screenMu.Lock()
 	a.screen.Reset()
+	a.screenMu.Unlock()
 	a.mu.Unlock()
-	wailsrt.EventsEmit(a.ctx, "log-mode", false)
-	wailsrt.EventsEmit(a.ctx, "screen-update", true)
+	wailsrt.EventsEmit(a.ctx, "log-mode", LogMode{})
+	a.emitGeometry()
+	a.pushScreen()
+}
+
+// GetSauce ritorna i metadati SAUCE del file visualizzato (nil se assenti).
+func (a *App) GetSauce() *sauce.Record {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.sauce
+}
+
+// applySauce configura lo schermo secondo i suggerimenti SAUCE (larghezza,
+// iCE colors). Con rec nil ripristina la geometria e le impostazioni della
+// BBS corrente. Chiamare con a.mu acquisito.
+func (a *App) applySauce(rec *sauce.Record) {
+	a.sauce = rec
+	cols := a.termSize.Cols
+	ice := a.iceColorsFor(a.session)
+	if rec != nil {
+		if rec.Width > 0 && rec.Width <= maxSauceWidth {
+			cols = rec.Width
+		}
+		ice = rec.IceColors
+	}
+	a.screenMu.Lock()
+	a.screen.Resize(cols, a.termSize.Rows)
+	a.screen.IceColors = ice
+	a.screenMu.Unlock()
 }
 
+// maxSauceWidth limita la larghezza richiesta da un record SAUCE.
+const maxSauceWidth = 255
+
 // IsViewingLog ritorna se siamo in modalità log.
 func (a *App) IsViewingLog() bool {
 	a.mu.Lock()
@@ -546,17 +1439,46 @@ func (a *App) IsViewingLog() bool {
 	return a.viewingLog
 }
 
+// disconnectForViewer chiude l'eventuale connessione attiva prima di
+// entrare nel log viewer.
+func (a *App) disconnectForViewer() {
+	a.mu.Lock()
+	wasConn := a.connected
+	if wasConn {
+		a.connected = false
+		a.hangup = true
+	}
+	a.mu.Unlock()
+	if wasConn {
+		a.conn.Disconnect()
+	}
+}
+
+// logPageCount ritorna il numero di pagine del log o dell'artwork
+// visualizzato. Chiamare con a.mu acquisito.
+func (a *App) logPageCount() int {
+	if a.art != nil {
+		return a.art.pages(a.screen.Rows - 1)
+	}
+	return len(a.logPages)
+}
+
 func (a *App) showLogPage() {
 	a.mu.Lock()
-	if len(a.logPages) == 0 {
+	total := a.logPageCount()
+	if total == 0 {
 		a.mu.Unlock()
 		return
 	}
-	page := a.logPages[a.logPageIdx]
+	a.logPageIdx = min(a.logPageIdx, total-1) // le pagine cambiano con le righe
 	current := a.logPageIdx + 1
-	total := len(a.logPages)
+	a.screenMu.Lock()
 	a.screen.Reset()
-	a.screen.Feed(page)
+	if a.art != nil {
+		a.art.showPage(a.screen, a.logPageIdx)
+	} else {
+		a.screen.Feed(a.logPages[a.logPageIdx])
+	}
 
 	// Barra navigazione in ultima riga (reverse video)
 	var hint string
@@ -566,82 +1488,194 @@ func (a *App) showLogPage() {
 		hint = "ULTIMA PAGINA  |  ← indietro  |  ESC ✖ esci"
 	}
 	bar := fmt.Sprintf(" Log [%d/%d]  %s ", current, total, hint)
-	// BUG-006: pad a 80 colonne usando conteggio rune (non byte) per Unicode
-	for utf8.RuneCountInString(bar) < 80 {
+	// BUG-006: pad alla larghezza schermo usando conteggio rune (non byte) per Unicode
+	for utf8.RuneCountInString(bar) < a.screen.Cols {
 		bar += " "
 	}
-	prompt := fmt.Sprintf("\x1b[25;1H\x1b[0;7m%s\x1b[0m", bar)
+	if r := []rune(bar); len(r) > a.screen.Cols {
+		bar = string(r[:a.screen.Cols])
+	}
+	prompt := fmt.Sprintf("\x1b[%d;1H\x1b[0;7m%s\x1b[0m", a.screen.Rows, bar)
 	a.screen.Feed(prompt)
+	a.screenMu.Unlock()
 	a.mu.Unlock()
 
-	wailsrt.EventsEmit(a.ctx, "log-mode", map[string]interface{}{
-		"active": true, "page": current, "total": total,
-	})
-	wailsrt.EventsEmit(a.ctx, "screen-update", true)
+	wailsrt.EventsEmit(a.ctx, "log-mode", LogMode{Active: true, Page: current, Total: total})
+	a.emitGeometry()
+	a.pushScreen()
 }
 
 // ─────────────────────────────────────────────
 // Event loop — bridge tra telnet events e Wails frontend
 // ─────────────────────────────────────────────
 
-func (a *App) eventLoop() {
+func (a *App) eventLoop(s *session) {
+	defer s.events.Close()
 	for {
 		select {
 		case <-a.ctx.Done():
 			// BUG-002: termina la goroutine quando l'app si chiude
 			return
+		case <-s.done:
+			return
 
-		case data := <-a.conn.DataCh:
-			// Decodifica CP437 e alimenta lo screen buffer
-			text := decodeCp437(data)
-			a.mu.Lock()
-			a.screen.Feed(text)
-			a.mu.Unlock()
-			// Scrivi nel log sessione (con sequenze ANSI intatte)
-			a.writeSessionLog(text)
-			// Notifica il frontend di aggiornare lo schermo
-			wailsrt.EventsEmit(a.ctx, "screen-update", true)
-
-		case event := <-a.conn.EventCh:
+		case event := <-s.events.C:
 			switch event.Type {
+			case telnet.EventData:
+				a.receive(s, event.Data)
+			case telnet.EventPhase:
+				a.emitFor(s, "connect-progress", ConnectProgress{Session: s.id, Phase: event.Message})
 			case telnet.EventConnected:
 				a.mu.Lock()
-				a.connected = true
+				s.connected = true
+				s.stats.ConnectedAt = time.Now()
+				s.idle = idleState{lastInput: s.stats.ConnectedAt}
+				host, port, at := s.stats.Host, s.stats.Port, s.stats.ConnectedAt
+				used := addressbook.LastUsed{
+					Emulation: string(s.screen.Emulation), IceColors: s.screen.IceColors,
+					Theme: themeFor(a.settings, bbsKey(host, port)).Name,
+					Cols:  s.screen.Cols, Rows: s.screen.Rows,
+				}
 				a.mu.Unlock()
-				wailsrt.EventsEmit(a.ctx, "connection-status", "connected")
+				a.book.RecordCall(host, port, at, used)
+				s.eventLog.write(evConnected, map[string]any{"address": event.Message})
+				a.startPlugins(s)
+				a.updateTray()
+				a.emitFor(s, "connect-progress", ConnectProgress{Session: s.id, Phase: phaseConnected})
+				a.emitFor(s, "connection-status", "connected")
+				a.emitSessions()
 			case telnet.EventDisconnected:
-				a.mu.Lock()
-				a.connected = false
-				a.mu.Unlock()
-				a.stopSessionLog()
-				wailsrt.EventsEmit(a.ctx, "connection-status", "disconnected")
-				wailsrt.EventsEmit(a.ctx, "status-message", "Disconnesso: "+event.Message)
+				a.endSession(s)
+				a.logClosed(s, evDisconnected, event.Message)
+				s.stopSessionLog()
+				a.emitFor(s, "connection-status", "disconnected")
+				a.emitFor(s, "status-message", i18n.New(i18n.MsgDisconnected, event.Message))
+				a.emitSessions()
+				a.onLineDropped(s)
 			case telnet.EventError:
+				a.endSession(s)
+				a.logClosed(s, evError, event.Message)
+				s.stopSessionLog()
+				a.emitFor(s, "connection-status", "error")
+				a.emitFor(s, "status-message", i18n.New(i18n.MsgConnectionError, event.Message))
+				a.emitSessions()
+				a.onLineDropped(s)
+			case telnet.EventZmodemOffer:
+				a.onDownloadOffer(s, event.Filename, event.Filesize)
+			case telnet.EventZmodemPolicy:
+				a.onFilePolicy(s, event.Filename, event.Message)
+			case telnet.EventZmodemStarted:
 				a.mu.Lock()
-				a.connected = false
+				s.transfer = event.Filename
 				a.mu.Unlock()
-				a.stopSessionLog()
-				wailsrt.EventsEmit(a.ctx, "connection-status", "error")
-				wailsrt.EventsEmit(a.ctx, "status-message", "Errore: "+event.Message)
-			case telnet.EventZmodemStarted:
-				wailsrt.EventsEmit(a.ctx, "zmodem-started", map[string]interface{}{
-					"filename": event.Filename, "filesize": event.Filesize,
-				})
+				s.eventLog.write(evTransfer, map[string]any{"filename": event.Filename, "filesize": event.Filesize})
+				a.emitFor(s, "zmodem-started", ZmodemFile{Session: s.id, Filename: event.Filename, Filesize: event.Filesize})
 			case telnet.EventZmodemProgress:
-				wailsrt.EventsEmit(a.ctx, "zmodem-progress", map[string]interface{}{
-					"bytes": event.Bytes, "total": event.Filesize, "speed": event.Speed,
+				a.emitFor(s, "zmodem-progress", ZmodemProgress{
+					Session: s.id, Bytes: event.Bytes, Total: event.Filesize, Speed: event.Speed,
+					ETA: transferETA(event.Bytes, event.Filesize, event.Speed),
 				})
 			case telnet.EventZmodemFinished:
-				wailsrt.EventsEmit(a.ctx, "zmodem-finished", map[string]interface{}{
-					"filepath": event.Filepath, "success": event.Success,
-				})
+				a.mu.Lock()
+				s.transfer = ""
+				a.mu.Unlock()
+				s.eventLog.write(evTransferEnd, map[string]any{"filepath": event.Filepath, "success": event.Success})
+				a.emitFor(s, "zmodem-finished", ZmodemFinished{Session: s.id, Filepath: event.Filepath, Success: event.Success})
+			case telnet.EventDataDropped:
+				a.emitFlood(s, floodBuffer, s.events.Cap(), event.Bytes)
 			case telnet.EventZmodemError:
-				wailsrt.EventsEmit(a.ctx, "zmodem-error", event.Message)
+				a.mu.Lock()
+				s.transfer = ""
+				a.mu.Unlock()
+				s.eventLog.write(evTransferErr, map[string]any{"reason": event.Message})
+				a.emitFor(s, "zmodem-error", event.Message)
 			}
 		}
 	}
 }
 
+// receive elabora i dati ricevuti dalla scheda s: schermo, log, script,
+// frasi sorvegliate e riconoscimenti.
+func (a *App) receive(s *session, data []byte) {
+	// Il testo decodificato secondo la codifica della scheda va allo
+	// schermo, a log, script e riconoscimenti
+	a.mu.Lock()
+	s.stats.BytesReceived += int64(len(data))
+	a.debugCount.bytes.Add(int64(len(data)))
+	if b := s.bridge; b != nil {
+		// Con un programma collegato i dati sono suoi, non dello schermo
+		a.mu.Unlock()
+		b.Write(data)
+		s.eventLog.received(len(data))
+		return
+	}
+	text := s.decoder.Decode(data)
+	a.screenMu.Lock()
+	if s.encoding.Name() == charset.CP437 {
+		// Stessa decodifica del decoder, senza passare dal testo
+		s.screen.FeedCP437(data)
+	} else {
+		s.screen.Feed(text)
+	}
+	a.screenMu.Unlock()
+	matches := s.watcher.Feed(text)
+	software, identified := s.detector.Feed(text)
+	emsi := s.iemsi
+	newFiles := s.files.Feed(text)
+	stream := s.stream
+	plugins := s.plugins
+	limits := a.settings.Limits
+	a.markShared(s)
+	a.mu.Unlock()
+	stream.Write(text)
+	for _, p := range plugins {
+		p.Output(text)
+	}
+	if emsi != nil {
+		a.feedIEMSI(s, emsi, data)
+	}
+	// Scrivi nel log sessione (con sequenze ANSI intatte)
+	s.eventLog.received(len(data))
+	if s.logSplitDue(time.Now()) {
+		a.rotateSessionLog(s)
+	}
+	if s.writeSessionLog(data, text) {
+		a.emitFlood(s, floodLog, limits.LogSizeMB, 0)
+	}
+	if s.capture.write(text) {
+		a.emitFlood(s, floodCapture, limits.LogSizeMB, 0)
+	}
+	a.onWatchMatches(s, matches)
+	if identified {
+		a.onSoftwareDetected(s, software)
+	}
+	if newFiles > 0 {
+		a.emitFor(s, "filelist-updated", newFiles)
+	}
+	// Notifica il frontend di aggiornare lo schermo
+	a.emitScreenUpdate(s)
+}
+
+// endSession segna la connessione come chiusa e aggiunge durata e byte
+// della sessione alle statistiche della rubrica (una sola volta, anche se
+// Disconnect e l'evento di disconnessione arrivano entrambi).
+func (a *App) endSession(s *session) {
+	a.mu.Lock()
+	wasConnected := s.connected
+	s.connected = false
+	s.stopPacedLocked()
+	s.endBridgeLocked()
+	st := s.stats
+	s.stream.Close() // sveglia gli script in attesa
+	a.mu.Unlock()
+	a.endPlugins(s)
+
+	if wasConnected && !st.ConnectedAt.IsZero() {
+		a.book.RecordSession(st.Host, st.Port, time.Since(st.ConnectedAt), st.BytesReceived)
+	}
+	a.updateTray()
+}
+
 // ─────────────────────────────────────────────
 // Caricamento lista BBS
 // ─────────────────────────────────────────────
@@ -785,56 +1819,3 @@ func splitBySpaces(s string) []string {
 	}
 	return []string{name, addr}
 }
-
-// ─────────────────────────────────────────────
-// CP437 decode (stessa tabella del CLI main.go)
-// ─────────────────────────────────────────────
-
-var cp437ToUnicode = [256]rune{
-	0x0000, 0x263A, 0x263B, 0x2665, 0x2666, 0x2663, 0x2660, 0x2022,
-	0x25D8, 0x25CB, 0x25D9, 0x2642, 0x2640, 0x266A, 0x266B, 0x263C,
-	0x25BA, 0x25C4, 0x2195, 0x203C, 0x00B6, 0x00A7, 0x25AC, 0x21A8,
-	0x2191, 0x2193, 0x2192, 0x2190, 0x221F, 0x2194, 0x25B2, 0x25BC,
-	' ', '!', '"', '#', '$', '%', '&', '\'',
-	'(', ')', '*', '+', ',', '-', '.', '/',
-	'0', '1', '2', '3', '4', '5', '6', '7',
-	'8', '9', ':', ';', '<', '=', '>', '?',
-	'@', 'A', 'B', 'C', 'D', 'E', 'F', 'G',
-	'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O',
-	'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W',
-	'X', 'Y', 'Z', '[', '\\', ']', '^', '_',
-	'`', 'a', 'b', 'c', 'd', 'e', 'f', 'g',
-	'h', 'i', 'j', 'k', 'l', 'm', 'n', 'o',
-	'p', 'q', 'r', 's', 't', 'u', 'v', 'w',
-	'x', 'y', 'z', '{', '|', '}', '~', 0x2302,
-	0x00C7, 0x00FC, 0x00E9, 0x00E2, 0x00E4, 0x00E0, 0x00E5, 0x00E7,
-	0x00EA, 0x00EB, 0x00E8, 0x00EF, 0x00EE, 0x00EC, 0x00C4, 0x00C5,
-	0x00C9, 0x00E6, 0x00C6, 0x00F4, 0x00F6, 0x00F2, 0x00FB, 0x00F9,
-	0x00FF, 0x00D6, 0x00DC, 0x00A2, 0x00A3, 0x00A5, 0x20A7, 0x0192,
-	0x00E1, 0x00ED, 0x00F3, 0x00FA, 0x00F1, 0x00D1, 0x00AA, 0x00BA,
-	0x00BF, 0x2310, 0x00AC, 0x00BD, 0x00BC, 0x00A1, 0x00AB, 0x00BB,
-	0x2591, 0x2592, 0x2593, 0x2502, 0x2524, 0x2561, 0x2562, 0x2556,
-	0x2555, 0x2563, 0x2551, 0x2557, 0x255D, 0x255C, 0x255B, 0x2510,
-	0x2514, 0x2534, 0x252C, 0x251C, 0x2500, 0x253C, 0x255E, 0x255F,
-	0x255A, 0x2554, 0x2569, 0x2566, 0x2560, 0x2550, 0x256C, 0x2567,
-	0x2568, 0x2564, 0x2565, 0x2559, 0x2558, 0x2552, 0x2553, 0x256B,
-	0x256A, 0x2518, 0x250C, 0x2588, 0x2584, 0x258C, 0x2590, 0x2580,
-	0x03B1, 0x00DF, 0x0393, 0x03C0, 0x03A3, 0x03C3, 0x00B5, 0x03C4,
-	0x03A6, 0x0398, 0x03A9, 0x03B4, 0x221E, 0x03C6, 0x03B5, 0x2229,
-	0x2261, 0x00B1, 0x2265, 0x2264, 0x2320, 0x2321, 0x00F7, 0x2248,
-	0x00B0, 0x2219, 0x00B7, 0x221A, 0x207F, 0x00B2, 0x25A0, 0x00A0,
-}
-
-func decodeCp437(data []byte) string {
-	runes := make([]rune, len(data))
-	for i, b := range data {
-		if b < 0x20 {
-			// Preserva i caratteri di controllo (ESC, CR, LF, BS, TAB, BEL)
-			// così il parser ANSI li riconosce correttamente.
-			runes[i] = rune(b)
-		} else {
-			runes[i] = cp437ToUnicode[b]
-		}
-	}
-	return string(runes)
-}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"os"
+
+	wailsrt "github.com/wailsapp/wails/v2/pkg/runtime"
+
+	"github.com/rj45lab/bbs-client-go/internal/cp437"
+	"github.com/rj45lab/bbs-client-go/internal/i18n"
+	"github.com/rj45lab/bbs-client-go/internal/sauce"
+	"github.com/rj45lab/bbs-client-go/pkg/ansi"
+	"github.com/rj45lab/bbs-client-go/pkg/telnet"
+)
+
+// ─────────────────────────────────────────────
+// ANSI art viewer — file .ANS/.ASC locali
+// ─────────────────────────────────────────────
+
+// maxArtRows limita l'altezza del buffer di rendering per un artwork.
+const maxArtRows = 5000
+
+// artView contiene un artwork già renderizzato su uno schermo alto
+// quanto l'intero disegno, da mostrare a pagine nel log viewer.
+type artView struct {
+	screen *ansi.Screen
+	used   int // righe effettivamente usate dal disegno
+}
+
+// renderArt decodifica i byte CP437 di un artwork e li renderizza su uno
+// schermo largo cols colonne. L'altezza è stimata dal numero di righe del
+// file o dal campo SAUCE, così il disegno non scrolla fuori dal buffer.
+func renderArt(data []byte, cols int, rec *sauce.Record) *artView {
+	rows := bytes.Count(data, []byte{'\n'}) + telnet.DefaultRows
+	if rec != nil && rec.Height > rows {
+		rows = rec.Height
+	}
+	rows = min(rows, maxArtRows)
+
+	scr := ansi.NewScreen(cols, rows)
+	if rec != nil {
+		scr.IceColors = rec.IceColors
+	}
+	scr.FeedCP437(data)
+
+	used := 0
+	for y := range scr.Buffer {
+		for _, cell := range scr.Buffer[y] {
+			if cell.Char != ' ' || cell.Attr.BG != ansi.IndexColor(ansi.DefaultBG) {
+				used = y + 1
+				break
+			}
+		}
+	}
+	return &artView{screen: scr, used: max(used, 1)}
+}
+
+// pages ritorna il numero di pagine da perPage righe.
+func (v *artView) pages(perPage int) int {
+	perPage = max(perPage, 1)
+	return (v.used + perPage - 1) / perPage
+}
+
+// showPage copia la pagina idx dell'artwork sullo schermo dst, lasciando
+// libera l'ultima riga per la barra di navigazione.
+func (v *artView) showPage(dst *ansi.Screen, idx int) {
+	perPage := dst.Rows - 1
+	off := idx * perPage
+	for y := 0; y < perPage && off+y < v.screen.Rows; y++ {
+		copy(dst.Buffer[y], v.screen.Buffer[off+y])
+	}
+	dst.CursorX, dst.CursorY = 0, 0
+}
+
+// LoadAnsiFile apre un file di ANSI art locale (.ANS/.ASC) e lo mostra
+// nel viewer, rispettando larghezza e iCE colors indicati dal SAUCE.
+func (a *App) LoadAnsiFile() *i18n.Message {
+	path, err := wailsrt.OpenFileDialog(a.ctx, wailsrt.OpenDialogOptions{
+		Title: i18n.T(i18n.DlgOpenArt),
+		Filters: []wailsrt.FileFilter{
+			{DisplayName: i18n.T(i18n.DlgArtFiles), Pattern: "*.ans;*.ANS;*.asc;*.ASC;*.diz;*.DIZ;*.nfo;*.NFO"},
+			{DisplayName: i18n.T(i18n.DlgAllFiles), Pattern: "*"},
+		},
+	})
+	if err != nil {
+		return i18n.Err(err)
+	}
+	if path == "" {
+		return nil // annullato
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return i18n.New(i18n.ErrRead, err)
+	}
+	rec, content := sauce.Parse(content)
+
+	a.disconnectForViewer()
+	a.haltPlayback()
+
+	a.mu.Lock()
+	a.logPages = nil
+	a.logPageIdx = 0
+	a.viewingLog = true
+	a.applySauce(rec)
+	a.art = renderArt(content, a.screen.Cols, rec)
+	a.viewerText = cp437.Decode(content)
+	a.viewerTiming = nil
+	a.mu.Unlock()
+
+	a.showLogPage()
+	return nil
+}
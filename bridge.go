@@ -0,0 +1,112 @@
+package main
+
+import (
+	"log"
+
+	"github.com/rj45lab/bbs-client-go/internal/bridge"
+	"github.com/rj45lab/bbs-client-go/internal/i18n"
+)
+
+// ─────────────────────────────────────────────
+// Programmi locali collegati alla sessione
+// ─────────────────────────────────────────────
+//
+// StartBridge collega un programma locale alla scheda attiva (vedi
+// internal/bridge): finché resta aperto i byte ricevuti vanno al programma
+// invece che allo schermo, e quello che scrive va alla BBS. StopBridge è
+// l'interruttore per chiuderlo; alla chiusura il frontend riceve
+// "bridge-finished" con i byte passati e il motivo.
+
+// defaultBridgeLimit è il limite di byte per direzione se il frontend non
+// ne indica uno.
+const defaultBridgeLimit = 64 << 20
+
+// BridgeSpec descrive il programma da collegare.
+type BridgeSpec struct {
+	Command string   `json:"command"`
+	Args    []string `json:"args,omitempty"`
+	MaxIn   int64    `json:"maxIn"`  // byte dalla BBS al programma (0 = defaultBridgeLimit)
+	MaxOut  int64    `json:"maxOut"` // byte dal programma alla BBS (0 = defaultBridgeLimit)
+}
+
+// BridgeResult è il payload di "bridge-finished".
+type BridgeResult struct {
+	Session string `json:"session"`
+	Command string `json:"command"`
+	bridge.Result
+}
+
+// StartBridge avvia il programma spec collegato alla scheda attiva.
+func (a *App) StartBridge(spec BridgeSpec) *i18n.Message {
+	if spec.Command == "" || spec.MaxIn < 0 || spec.MaxOut < 0 {
+		return i18n.New(i18n.ErrInvalidBridge, spec.Command)
+	}
+	if spec.MaxIn == 0 {
+		spec.MaxIn = defaultBridgeLimit
+	}
+	if spec.MaxOut == 0 {
+		spec.MaxOut = defaultBridgeLimit
+	}
+	a.mu.Lock()
+	s := a.session
+	ok, busy := a.connected, s != nil && s.bridge != nil
+	a.mu.Unlock()
+	if !ok {
+		return i18n.New(i18n.ErrNotConnected)
+	}
+	if busy {
+		return i18n.New(i18n.ErrBridgeBusy)
+	}
+
+	b, err := bridge.Start(bridge.Spec{
+		Command: spec.Command, Args: spec.Args, MaxIn: spec.MaxIn, MaxOut: spec.MaxOut,
+	}, s.conn.SendData)
+	if err != nil {
+		log.Printf("[BRIDGE] %s: %v", spec.Command, err)
+		return i18n.New(i18n.ErrBridgeStart, spec.Command, err.Error())
+	}
+	a.mu.Lock()
+	if s.bridge != nil || !s.connected {
+		// Un altro ponte è partito nel frattempo, o la linea è caduta
+		a.mu.Unlock()
+		b.Stop()
+		if !s.connected {
+			return i18n.New(i18n.ErrNotConnected)
+		}
+		return i18n.New(i18n.ErrBridgeBusy)
+	}
+	s.bridge = b
+	a.mu.Unlock()
+
+	go func() {
+		<-b.Done()
+		a.mu.Lock()
+		if s.bridge == b {
+			s.bridge = nil
+		}
+		a.mu.Unlock()
+		a.emitFor(s, "bridge-finished", BridgeResult{Session: s.id, Command: spec.Command, Result: b.Result()})
+	}()
+	return nil
+}
+
+// StopBridge chiude il programma collegato alla scheda attiva, se c'è.
+func (a *App) StopBridge() {
+	a.mu.Lock()
+	var b *bridge.Bridge
+	if a.session != nil {
+		b = a.session.bridge
+	}
+	a.mu.Unlock()
+	if b != nil {
+		b.Stop()
+	}
+}
+
+// endBridgeLocked chiude il programma collegato a s. Chiamare con a.mu
+// acquisito.
+func (s *session) endBridgeLocked() {
+	if s.bridge != nil {
+		s.bridge.Stop()
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	wailsrt "github.com/wailsapp/wails/v2/pkg/runtime"
+
+	"github.com/rj45lab/bbs-client-go/internal/i18n"
+)
+
+// ─────────────────────────────────────────────
+// Capture buffer (come nei terminali DOS)
+// ─────────────────────────────────────────────
+
+// capture salva in un file scelto dall'utente l'output ricevuto tra
+// StartCapture e StopCapture, indipendentemente dal log di sessione.
+type capture struct {
+	mu    sync.Mutex
+	f     *os.File
+	path  string
+	bytes int64
+	limit int64 // dimensione massima del file
+}
+
+// CaptureStatus descrive lo stato della cattura.
+type CaptureStatus struct {
+	Active bool   `json:"active"`
+	Path   string `json:"path"`
+	Bytes  int64  `json:"bytes"`
+}
+
+// StartCapture chiede il nome del file e inizia a catturarvi l'output
+// decodificato (con le sequenze ANSI). Il file viene esteso se esiste già.
+func (a *App) StartCapture() *i18n.Message {
+	path, err := a.exportDialog(i18n.T(i18n.DlgCapture),
+		"capture_"+time.Now().Format("2006-01-02_150405")+".ans",
+		i18n.T(i18n.DlgCaptureFiles), "*.ans;*.txt")
+	if err != nil || path == "" {
+		return i18n.Err(err)
+	}
+	if err := a.capture.start(path, logLimit(a.config.Get().Limits)); err != nil {
+		return i18n.Err(err)
+	}
+	a.emitCaptureStatus()
+	return nil
+}
+
+// StopCapture chiude il file di cattura.
+func (a *App) StopCapture() CaptureStatus {
+	st := a.capture.status()
+	a.capture.stop()
+	a.emitCaptureStatus()
+	return st
+}
+
+// GetCaptureStatus ritorna lo stato della cattura.
+func (a *App) GetCaptureStatus() CaptureStatus {
+	return a.capture.status()
+}
+
+func (a *App) emitCaptureStatus() {
+	wailsrt.EventsEmit(a.ctx, "capture-status", a.capture.status())
+}
+
+// start apre path in append, chiudendo un'eventuale cattura precedente.
+// Si smette di scrivere dopo limit byte.
+func (c *capture) start(path string, limit int64) error {
+	// SEC-005: 0600, può contenere dati personali
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.f != nil {
+		c.f.Close()
+	}
+	c.f, c.path, c.bytes, c.limit = f, path, 0, limit
+	return nil
+}
+
+func (c *capture) stop() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.f != nil {
+		c.f.Close()
+		c.f = nil
+	}
+}
+
+// write aggiunge text alla cattura attiva (stesso limite dei log).
+// Ritorna true quando la cattura raggiunge il limite.
+func (c *capture) write(text string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.f == nil || c.bytes > c.limit {
+		return false
+	}
+	n, _ := c.f.WriteString(text)
+	c.bytes += int64(n)
+	return c.bytes > c.limit
+}
+
+func (c *capture) status() CaptureStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return CaptureStatus{Active: c.f != nil, Path: c.path, Bytes: c.bytes}
+}
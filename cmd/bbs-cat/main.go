@@ -0,0 +1,269 @@
+// Comando bbs-cat: mostra file ANSI e log di sessione in un terminale.
+//
+// Legge artwork .ANS/.ASC (CP437, con eventuale record SAUCE) e i log
+// salvati dal client (UTF-8), li interpreta con lo stesso emulatore della
+// GUI e scrive il risultato su stdout in UTF-8 con colori truecolor, così
+// si possono sfogliare log e disegni senza avviare la GUI:
+//
+//	bbs-cat logo.ans
+//	bbs-cat --pages logs/Metro_Olografix_2025-01-12_213000.log | less -R
+//	curl -s https://example.org/art.ans | bbs-cat -
+//
+// Gli artwork sono resi per intero su uno schermo alto quanto il disegno;
+// i log di sessione, dove la BBS ridisegna lo schermo 80×25, vengono divisi
+// in pagine a ogni cancellazione dello schermo (ESC[2J) come nel viewer.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/rj45lab/bbs-client-go/internal/cp437"
+	"github.com/rj45lab/bbs-client-go/internal/sauce"
+	"github.com/rj45lab/bbs-client-go/pkg/ansi"
+	"github.com/rj45lab/bbs-client-go/pkg/telnet"
+)
+
+const (
+	// maxRows limita l'altezza dello schermo di un artwork
+	maxRows = 5000
+	// maxWidth limita la larghezza (anche quella richiesta da SAUCE)
+	maxWidth = 255
+)
+
+// Codici di uscita
+const (
+	exitOK    = 0
+	exitError = 1 // file illeggibile
+	exitUsage = 3
+)
+
+// options sono le scelte della riga di comando.
+type options struct {
+	width    int    // 0 = SAUCE o 80 colonne
+	encoding string // auto, cp437, utf8
+	ice      string // auto, on, off
+	palette  *ansi.Palette
+	plain    bool // solo testo, senza colori
+	pages    bool // forza la divisione in pagine su ESC[2J
+}
+
+// Intestazione e chiusura dei log di sessione del client
+var (
+	logHeaderRe = regexp.MustCompile(`(?m)^=== Sessione .+===\n?`)
+	logFooterRe = regexp.MustCompile(`\n?=== Fine sessione .+===$`)
+)
+
+func main() {
+	var opt options
+	flag.IntVar(&opt.width, "width", 0, "colonne (default: dal record SAUCE, altrimenti 80)")
+	flag.StringVar(&opt.encoding, "encoding", "auto", "codifica del file: auto, cp437, utf8")
+	flag.StringVar(&opt.ice, "ice", "auto", "iCE colors (blink come sfondo bright): auto, on, off")
+	palette := flag.String("palette", "vga", "palette dei 16 colori: vga, xterm, amiga")
+	flag.BoolVar(&opt.plain, "plain", false, "solo testo, senza colori")
+	flag.BoolVar(&opt.pages, "pages", false, "dividi in pagine a ogni ESC[2J anche se non è un log")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "uso: bbs-cat [opzioni] file... (\"-\" = stdin)\n\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	log.SetFlags(0)
+	log.SetPrefix("bbs-cat: ")
+
+	var ok bool
+	if opt.palette, ok = ansi.Palettes[*palette]; !ok {
+		log.Printf("palette sconosciuta: %s", *palette)
+		os.Exit(exitUsage)
+	}
+	if opt.width < 0 || opt.width > maxWidth {
+		log.Printf("larghezza non valida: %d (1-%d)", opt.width, maxWidth)
+		os.Exit(exitUsage)
+	}
+	files := flag.Args()
+	if len(files) == 0 {
+		flag.Usage()
+		os.Exit(exitUsage)
+	}
+
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+	code := exitOK
+	for _, name := range files {
+		if err := catFile(out, name, opt); err != nil {
+			log.Print(err)
+			code = exitError
+		}
+	}
+	out.Flush()
+	os.Exit(code)
+}
+
+// catFile legge e mostra un file ("-" = stdin).
+func catFile(w io.Writer, name string, opt options) error {
+	var data []byte
+	var err error
+	if name == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(name)
+	}
+	if err != nil {
+		return err
+	}
+	rec, content := sauce.Parse(data)
+
+	text, err := decode(content, opt.encoding)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	isLog := logHeaderRe.MatchString(text)
+	text = stripLogFrame(text)
+
+	cols := telnet.DefaultCols
+	if rec != nil && rec.Width > 0 {
+		cols = min(rec.Width, maxWidth)
+	}
+	if opt.width > 0 {
+		cols = opt.width
+	}
+	ice := rec != nil && rec.IceColors
+	switch opt.ice {
+	case "on":
+		ice = true
+	case "off":
+		ice = false
+	}
+
+	if !isLog && !opt.pages {
+		// Artwork: uno schermo alto quanto il disegno, senza scroll
+		rows := min(strings.Count(text, "\n")+telnet.DefaultRows, maxRows)
+		if rec != nil && rec.Height > rows {
+			rows = min(rec.Height, maxRows)
+		}
+		scr := newScreen(cols, rows, ice, opt)
+		scr.Feed(text)
+		return writeScreen(w, scr, opt.plain)
+	}
+	for i, page := range splitPages(text) {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		scr := newScreen(cols, telnet.DefaultRows, ice, opt)
+		scr.Feed(page)
+		if err := writeScreen(w, scr, opt.plain); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decode converte il contenuto in testo. In automatico i file UTF-8 validi
+// con caratteri non ASCII (i log del client) restano tali, gli altri sono
+// CP437 (gli artwork).
+func decode(data []byte, encoding string) (string, error) {
+	switch encoding {
+	case "cp437":
+		return cp437.Decode(data), nil
+	case "utf8":
+		return string(data), nil
+	case "auto":
+		if utf8.Valid(data) && bytes.IndexFunc(data, func(r rune) bool { return r >= 0x80 }) >= 0 {
+			return string(data), nil
+		}
+		return cp437.Decode(data), nil
+	}
+	return "", errors.New("codifica sconosciuta: " + encoding)
+}
+
+// stripLogFrame toglie intestazione e chiusura di un log di sessione.
+func stripLogFrame(text string) string {
+	text = logHeaderRe.ReplaceAllString(text, "")
+	return logFooterRe.ReplaceAllString(text, "")
+}
+
+// splitPages divide il testo a ogni ESC[2J, tenendo la sequenza all'inizio
+// di ogni pagina e saltando le pagine vuote.
+func splitPages(text string) []string {
+	const clearSeq = "\x1b[2J"
+	var pages []string
+	for i, p := range strings.Split(text, clearSeq) {
+		if strings.TrimSpace(p) == "" {
+			continue
+		}
+		if i > 0 {
+			p = clearSeq + p
+		}
+		pages = append(pages, p)
+	}
+	if len(pages) == 0 {
+		pages = []string{text}
+	}
+	return pages
+}
+
+// newScreen crea lo schermo di rendering. Le richieste della BBS (DSR,
+// stampa...) non hanno destinatario e vengono ignorate.
+func newScreen(cols, rows int, ice bool, opt options) *ansi.Screen {
+	scr := ansi.NewScreen(cols, rows)
+	scr.IceColors = ice
+	scr.Palette = opt.palette
+	return scr
+}
+
+// writeScreen scrive le righe usate dello schermo, ciascuna chiusa da un
+// reset degli attributi. Gli spazi finali con lo sfondo di default sono
+// omessi, così l'output non dipende dalla larghezza del terminale.
+func writeScreen(w io.Writer, scr *ansi.Screen, plain bool) error {
+	blank := ansi.IndexColor(ansi.DefaultBG)
+	isBlank := func(c ansi.Cell) bool {
+		return (c.Char == ' ' || c.Char < 0x20) && c.Attr.EffectiveBG(scr.IceColors) == blank && !c.Attr.Reverse
+	}
+
+	used := 0
+	for y, row := range scr.Buffer {
+		for _, cell := range row {
+			if !isBlank(cell) {
+				used = y + 1
+				break
+			}
+		}
+	}
+
+	var b strings.Builder
+	for _, row := range scr.Buffer[:used] {
+		end := len(row)
+		for end > 0 && isBlank(row[end-1]) {
+			end--
+		}
+		last := ""
+		for _, cell := range row[:end] {
+			if !plain {
+				if sgr := scr.TrueColorSGR(cell); sgr != last {
+					b.WriteString(sgr)
+					last = sgr
+				}
+			}
+			ch := cell.Char
+			if ch < 0x20 || cell.Attr.Conceal {
+				ch = ' '
+			}
+			b.WriteRune(ch)
+		}
+		if last != "" {
+			b.WriteString("\x1b[0m")
+		}
+		b.WriteByte('\n')
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,347 @@
+// Comando bbsclient: client BBS senza interfaccia grafica.
+//
+// Usa lo stesso stack della GUI (telnet, parser ANSI, ZMODEM) e scrive il
+// flusso ricevuto su stdout, convertito da CP437 (o dalla codifica scelta
+// con --charset) a UTF-8 con le sequenze ANSI intatte, così il terminale
+// che lo esegue lo visualizza. Serve per
+// l'automazione e per i server senza display:
+//
+//	bbsclient --connect bbs.olografix.org:23 --log
+//	bbsclient --connect telnet://bbs.example.org --script login.lua
+//
+// Senza script le righe lette da stdin vengono inviate alla BBS. Con --tui
+// il client diventa interattivo nel terminale (vedi tui.go).
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rj45lab/bbs-client-go/internal/addressbook"
+	"github.com/rj45lab/bbs-client-go/internal/charset"
+	"github.com/rj45lab/bbs-client-go/internal/proxy"
+	"github.com/rj45lab/bbs-client-go/internal/script"
+	"github.com/rj45lab/bbs-client-go/pkg/ansi"
+	"github.com/rj45lab/bbs-client-go/pkg/telnet"
+)
+
+// Codici di uscita
+const (
+	exitOK      = 0
+	exitConnect = 1 // connessione fallita o interrotta per errore
+	exitScript  = 2 // errore nello script
+	exitUsage   = 3
+)
+
+// maxLogSize è il limite del file di log (come nella GUI)
+const maxLogSize = 50 * 1024 * 1024
+
+func main() {
+	connect := flag.String("connect", telnet.DefaultHost, "BBS a cui connettersi (host[:porta] o telnet://host[:porta])")
+	logSession := flag.Bool("log", false, "salva il flusso ricevuto in logs/<host>_<data>.log")
+	scriptPath := flag.String("script", "", "script Lua eseguito dopo la connessione")
+	downloadDir := flag.String("download-dir", "downloads", "directory dei download ZMODEM")
+	tuiMode := flag.Bool("tui", false, "sessione interattiva nel terminale (raw mode, Ctrl+] per uscire)")
+	render := flag.Bool("render", false, "con --tui, ridisegna lo schermo 80×25 invece di passare il flusso ANSI")
+	debug := flag.Bool("debug", false, "log della negoziazione telnet su stderr")
+	encoding := flag.String("charset", charset.CP437, "codifica della BBS: cp437, utf8")
+	xonxoff := flag.Bool("xonxoff", false, "rispetta XON/XOFF della BBS e li toglie dai dati")
+	via := flag.String("proxy", "", "proxy per la connessione: socks5://host:porta, http://host:porta o tor")
+	flag.Parse()
+
+	log.SetFlags(0)
+	log.SetPrefix("bbsclient: ")
+
+	host, port, err := parseTarget(*connect)
+	if err != nil {
+		log.Print(err)
+		os.Exit(exitUsage)
+	}
+	if err := os.MkdirAll(*downloadDir, 0700); err != nil {
+		log.Print(err)
+		os.Exit(exitUsage)
+	}
+
+	enc, err := charset.Lookup(*encoding)
+	if err != nil {
+		log.Print(err)
+		os.Exit(exitUsage)
+	}
+
+	c := newClient(os.Stdout, enc)
+	c.conn.Debug = *debug
+	c.conn.FlowControl = *xonxoff
+	if *via != "" {
+		d, err := proxy.Parse(*via)
+		if err != nil {
+			log.Print(err)
+			os.Exit(exitUsage)
+		}
+		c.conn.Dialer = d
+	}
+	c.conn.SetDownloadDir(*downloadDir)
+	if *logSession {
+		if err := c.openLog("logs", host, port); err != nil {
+			log.Printf("log non disponibile: %v", err)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := c.conn.Connect(host, port); err != nil {
+		log.Printf("connessione a %s fallita: %v", net.JoinHostPort(host, strconv.Itoa(port)), err)
+		c.closeLog()
+		os.Exit(exitConnect)
+	}
+	log.Printf("connesso a %s", net.JoinHostPort(host, strconv.Itoa(port)))
+
+	// Terminale locale in raw mode: i tasti vanno alla BBS
+	var t *tui
+	if *tuiMode {
+		if t, err = startTUI(*render); err != nil {
+			log.Print(err)
+			c.conn.Disconnect()
+			c.closeLog()
+			os.Exit(exitUsage)
+		}
+		log.SetOutput(crlfWriter{os.Stderr})
+		if *render {
+			c.out = io.Discard
+			go c.renderLoop(ctx, os.Stdout)
+		}
+		go c.forwardKeys(os.Stdin, stop)
+	}
+
+	// Input: lo script oppure le righe di stdin
+	result := make(chan int, 1)
+	if *scriptPath != "" {
+		go func() {
+			code := exitOK
+			if err := script.Run(ctx, *scriptPath, c, *downloadDir); err != nil {
+				log.Printf("script: %v", err)
+				code = exitScript
+			}
+			result <- code
+		}()
+	} else if t == nil {
+		go c.forwardInput(os.Stdin)
+	}
+
+	code := c.run(ctx, result)
+	c.conn.Disconnect()
+	if t != nil {
+		t.stop()
+	}
+	c.closeLog()
+	os.Exit(code)
+}
+
+// parseTarget interpreta l'argomento di --connect.
+func parseTarget(target string) (string, int, error) {
+	if strings.Contains(target, "://") {
+		e, err := addressbook.ParseURI(target)
+		if err != nil {
+			return "", 0, err
+		}
+		if e.Protocol != "telnet" {
+			return "", 0, fmt.Errorf("protocollo non supportato: %s", e.Protocol)
+		}
+		return e.Host, e.Port, nil
+	}
+	host, p, err := net.SplitHostPort(target)
+	if err != nil {
+		// Solo host, porta di default
+		return target, telnet.DefaultPort, nil
+	}
+	port, err := strconv.Atoi(p)
+	if err != nil || port <= 0 || port > 65535 {
+		return "", 0, fmt.Errorf("porta non valida: %s", p)
+	}
+	return host, port, nil
+}
+
+// ─────────────────────────────────────────────
+// client — sessione senza GUI
+// ─────────────────────────────────────────────
+
+type client struct {
+	conn    *telnet.Connection
+	events  *telnet.Subscription
+	out     io.Writer
+	enc     charset.Encoding
+	decoder charset.Decoder
+
+	mu     sync.Mutex
+	screen *ansi.Screen
+	stream *script.Stream // testo ricevuto per gli script
+
+	logFile  *os.File
+	logBytes int64
+}
+
+func newClient(out io.Writer, enc charset.Encoding) *client {
+	c := &client{
+		conn:    telnet.New(),
+		out:     out,
+		enc:     enc,
+		decoder: enc.NewDecoder(false),
+		screen:  ansi.NewScreen(telnet.DefaultCols, telnet.DefaultRows),
+		stream:  script.NewStream(),
+	}
+	c.events = c.conn.Subscribe(0)
+	// Risposte DSR al server
+	c.screen.OnResponse = func(data []byte) { c.conn.Send(data) }
+	return c
+}
+
+// run smista dati ed eventi della connessione fino alla disconnessione,
+// alla fine dello script (result) o all'interruzione (ctx).
+func (c *client) run(ctx context.Context, result <-chan int) int {
+	for {
+		select {
+		case <-ctx.Done():
+			return exitOK
+		case code := <-result:
+			return code
+
+		case ev := <-c.events.C:
+			switch ev.Type {
+			case telnet.EventData:
+				text := c.decoder.Decode(ev.Data)
+				io.WriteString(c.out, text)
+				c.feed(text)
+				c.writeLog(text)
+			case telnet.EventDisconnected:
+				c.close()
+				log.Printf("disconnesso: %s", ev.Message)
+				return exitOK
+			case telnet.EventError:
+				c.close()
+				log.Printf("errore: %s", ev.Message)
+				return exitConnect
+			case telnet.EventZmodemStarted:
+				log.Printf("ZMODEM: ricezione %s (%d byte)", ev.Filename, ev.Filesize)
+			case telnet.EventZmodemFinished:
+				if ev.Success {
+					log.Printf("ZMODEM: completato %s", ev.Filepath)
+				} else {
+					log.Printf("ZMODEM: trasferimento interrotto")
+				}
+			case telnet.EventZmodemError:
+				log.Printf("ZMODEM: %s", ev.Message)
+			}
+		}
+	}
+}
+
+// feed aggiorna lo schermo e il testo in attesa degli script.
+func (c *client) feed(text string) {
+	c.mu.Lock()
+	c.screen.Feed(text)
+	c.mu.Unlock()
+	c.stream.Write(text)
+}
+
+// close segna la connessione come chiusa e sveglia gli script in attesa.
+func (c *client) close() {
+	c.stream.Close()
+}
+
+// Send invia testo UTF-8 alla BBS, convertito nella sua codifica.
+func (c *client) Send(text string) error {
+	return c.conn.Send(c.enc.Encode(text))
+}
+
+// Wait attende pattern nel testo ricevuto (vedi script.Stream.Wait).
+func (c *client) Wait(ctx context.Context, pattern string, timeout time.Duration) bool {
+	return c.stream.Wait(ctx, pattern, timeout)
+}
+
+// ScreenText ritorna il contenuto dello schermo come testo semplice.
+func (c *client) ScreenText() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return script.ScreenText(c.screen)
+}
+
+// Connected ritorna true se la connessione è attiva.
+func (c *client) Connected() bool {
+	return c.conn.Connected()
+}
+
+// Upload invia un file via ZMODEM.
+func (c *client) Upload(path string) {
+	c.conn.StartZmodemUpload(path)
+}
+
+// Disconnect chiude la connessione.
+func (c *client) Disconnect() {
+	c.conn.Disconnect()
+}
+
+// forwardInput invia alla BBS le righe lette da r, terminate da CR.
+func (c *client) forwardInput(r io.Reader) {
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		if err := c.Send(sc.Text() + "\r"); err != nil {
+			return
+		}
+	}
+}
+
+// ─────────────────────────────────────────────
+// Log della sessione
+// ─────────────────────────────────────────────
+
+// openLog apre il log in dir con lo stesso formato della GUI.
+func (c *client) openLog(dir, host string, port int) error {
+	// SEC-005: directory e file leggibili solo dall'utente
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	safe := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '-' || r == '_' {
+			return r
+		}
+		return '_'
+	}, host)
+	name := fmt.Sprintf("%s_%s.log", safe, time.Now().Format("2006-01-02_150405"))
+	f, err := os.OpenFile(filepath.Join(dir, name), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(f, "=== Sessione %s (%s:%d) — %s ===\n",
+		host, host, port, time.Now().Format("2006-01-02 15:04:05"))
+	c.logFile = f
+	return nil
+}
+
+// writeLog aggiunge testo al log, fino a maxLogSize (PT-004).
+func (c *client) writeLog(text string) {
+	if c.logFile == nil || c.logBytes >= maxLogSize {
+		return
+	}
+	n, _ := c.logFile.WriteString(text)
+	c.logBytes += int64(n)
+}
+
+func (c *client) closeLog() {
+	if c.logFile != nil {
+		fmt.Fprintf(c.logFile, "\n=== Fine sessione — %s ===\n", time.Now().Format("2006-01-02 15:04:05"))
+		c.logFile.Close()
+		c.logFile = nil
+	}
+}
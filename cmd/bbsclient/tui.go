@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+
+	"github.com/rj45lab/bbs-client-go/pkg/ansi"
+)
+
+// ─────────────────────────────────────────────
+// Modalità terminale (TUI)
+// ─────────────────────────────────────────────
+//
+// Con --tui il terminale locale passa in raw mode e ogni tasto va
+// direttamente alla BBS, così il client funziona anche in una sessione
+// SSH o su macchine senza WebKit. Lo schermo può essere:
+//
+//   - passthrough (default): il flusso ANSI ricevuto arriva tale e quale
+//     al terminale locale, che lo interpreta;
+//   - render (--render): il flusso alimenta lo Screen interno e il client
+//     ridisegna la griglia 80×25 con colori RGB della palette, come la
+//     GUI (utile con terminali che interpretano male la ANSI art).
+//
+// Ctrl+] chiude la sessione.
+
+// tuiQuitKey è il tasto che chiude la sessione (Ctrl+], come telnet).
+const tuiQuitKey = 0x1D
+
+// tuiFrameInterval limita la frequenza di ridisegno in modalità render.
+const tuiFrameInterval = 33 * time.Millisecond
+
+// tui conserva lo stato del terminale locale da ripristinare all'uscita.
+type tui struct {
+	fd     int
+	state  *term.State
+	render bool
+}
+
+// startTUI porta stdin in raw mode e, in modalità render, passa allo
+// schermo alternativo. Ripristinare sempre con stop.
+func startTUI(render bool) (*tui, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return nil, fmt.Errorf("--tui richiede un terminale")
+	}
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return nil, err
+	}
+	t := &tui{fd: fd, state: state, render: render}
+	if render {
+		io.WriteString(os.Stdout, "\x1b[?1049h\x1b[2J")
+	}
+	return t, nil
+}
+
+// stop ripristina il terminale locale.
+func (t *tui) stop() {
+	if t.render {
+		io.WriteString(os.Stdout, "\x1b[0m\x1b[?25h\x1b[?1049l")
+	}
+	term.Restore(t.fd, t.state)
+}
+
+// crlfWriter converte LF in CR LF: in raw mode il terminale non torna a
+// capo da solo (usato per i messaggi su stderr).
+type crlfWriter struct{ w io.Writer }
+
+func (cw crlfWriter) Write(p []byte) (int, error) {
+	_, err := io.WriteString(cw.w, strings.ReplaceAll(string(p), "\n", "\r\n"))
+	return len(p), err
+}
+
+// forwardKeys invia alla BBS i tasti letti da r in raw mode. Ritorna
+// quando l'utente preme Ctrl+] o stdin si chiude.
+func (c *client) forwardKeys(r io.Reader, quit func()) {
+	buf := make([]byte, 256)
+	for {
+		n, err := r.Read(buf)
+		if err != nil {
+			quit()
+			return
+		}
+		data := buf[:n]
+		for i, b := range data {
+			if b == tuiQuitKey {
+				c.sendKeys(data[:i])
+				quit()
+				return
+			}
+		}
+		c.sendKeys(data)
+	}
+}
+
+// sendKeys converte i tasti del terminale locale nei byte attesi dalle
+// BBS: DEL diventa Backspace (come nella GUI) e i caratteri non ASCII
+// sono ricodificati nella codifica della BBS.
+func (c *client) sendKeys(data []byte) {
+	if len(data) == 0 {
+		return
+	}
+	out := make([]byte, 0, len(data))
+	for len(data) > 0 {
+		r, size := utf8.DecodeRune(data)
+		switch {
+		case data[0] == 0x7F:
+			out = append(out, 0x08)
+		case r >= 0x80 && r != utf8.RuneError:
+			out = append(out, c.enc.Encode(string(r))...)
+		default:
+			out = append(out, data[:size]...)
+		}
+		data = data[size:]
+	}
+	c.conn.Send(out)
+}
+
+// renderLoop ridisegna lo schermo a ogni dato ricevuto, al massimo una
+// volta ogni tuiFrameInterval, fino alla chiusura o all'annullamento di ctx.
+func (c *client) renderLoop(ctx context.Context, w io.Writer) {
+	for {
+		changed, closed := c.stream.Changed()
+		c.mu.Lock()
+		frame := renderScreen(c.screen)
+		c.mu.Unlock()
+		io.WriteString(w, frame)
+		if closed {
+			return
+		}
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return
+		}
+		select {
+		case <-time.After(tuiFrameInterval):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// renderScreen produce le sequenze che ridisegnano l'intero schermo s su
+// un terminale truecolor, cursore compreso. Chiamare con il lock di s.
+func renderScreen(s *ansi.Screen) string {
+	var b strings.Builder
+	b.WriteString("\x1b[?25l\x1b[H")
+	var last string
+	for y, row := range s.Buffer {
+		if y > 0 {
+			b.WriteString("\r\n")
+		}
+		for _, cell := range row {
+			if sgr := s.TrueColorSGR(cell); sgr != last {
+				b.WriteString(sgr)
+				last = sgr
+			}
+			ch := cell.Char
+			if ch < 0x20 || cell.Attr.Conceal {
+				ch = ' '
+			}
+			b.WriteRune(ch)
+		}
+	}
+	b.WriteString("\x1b[0m")
+	fmt.Fprintf(&b, "\x1b[%d;%dH", s.CursorY+1, s.CursorX+1)
+	if s.CursorVisible {
+		b.WriteString("\x1b[?25h")
+	}
+	return b.String()
+}
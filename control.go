@@ -0,0 +1,254 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rj45lab/bbs-client-go/internal/config"
+	"github.com/rj45lab/bbs-client-go/internal/control"
+	"github.com/rj45lab/bbs-client-go/internal/i18n"
+	"github.com/rj45lab/bbs-client-go/internal/script"
+)
+
+// ─────────────────────────────────────────────
+// Endpoint di controllo locale (JSON-RPC)
+// ─────────────────────────────────────────────
+//
+// Con Settings.Control.Enabled il client apre su 127.0.0.1 un endpoint
+// JSON-RPC (vedi internal/control) che offre ai programmi esterni le
+// stesse operazioni dei binding, sulla scheda attiva. Il token è salvato
+// in un file accanto alle impostazioni, leggibile solo dall'utente, così
+// gli script possono recuperarlo senza passare dalla GUI.
+
+// controlTokenFile è il file con il token, nella directory di configurazione.
+const controlTokenFile = "control-token"
+
+// controlState è lo stato dell'endpoint, protetto da App.mu.
+type controlState struct {
+	server *control.Server
+	port   int
+}
+
+// ControlInfo descrive l'endpoint di controllo per il frontend.
+type ControlInfo struct {
+	Active    bool   `json:"active"`
+	URL       string `json:"url,omitempty"`
+	Token     string `json:"token,omitempty"`
+	TokenFile string `json:"tokenFile,omitempty"`
+}
+
+// syncControl avvia, ferma o riavvia l'endpoint secondo le impostazioni.
+func (a *App) syncControl(cfg config.Control) {
+	a.mu.Lock()
+	cur := a.control
+	if cfg.Enabled && cur.server != nil && cur.port == cfg.Port {
+		a.mu.Unlock()
+		return
+	}
+	a.control = controlState{}
+	a.mu.Unlock()
+
+	if cur.server != nil {
+		cur.server.Close()
+		log.Printf("[CONTROL] Endpoint chiuso")
+	}
+	if !cfg.Enabled {
+		return
+	}
+	token, err := loadControlToken()
+	if err == nil {
+		var srv *control.Server
+		if srv, err = control.Start(cfg.Port, token, a.controlMethods()); err == nil {
+			a.mu.Lock()
+			a.control = controlState{server: srv, port: cfg.Port}
+			a.mu.Unlock()
+			return
+		}
+	}
+	log.Printf("[CONTROL] %v", err)
+	a.emitFor(a.session, "status-message", i18n.New(i18n.ErrControlStart, err.Error()))
+}
+
+// stopControl chiude l'endpoint alla chiusura dell'app.
+func (a *App) stopControl() {
+	a.syncControl(config.Control{})
+}
+
+// SetControlEnabled attiva o disattiva l'endpoint di controllo.
+func (a *App) SetControlEnabled(enabled bool) *i18n.Message {
+	return a.updateSettings(func(s *config.Settings) {
+		s.Control.Enabled = enabled
+	})
+}
+
+// GetControl ritorna indirizzo e token dell'endpoint di controllo.
+func (a *App) GetControl() ControlInfo {
+	a.mu.Lock()
+	srv := a.control.server
+	a.mu.Unlock()
+	if srv == nil {
+		return ControlInfo{}
+	}
+	token, _ := loadControlToken()
+	return ControlInfo{Active: true, URL: srv.URL(), Token: token, TokenFile: controlTokenPath()}
+}
+
+// controlTokenPath ritorna il percorso del file con il token ("" se la
+// directory di configurazione non è disponibile).
+func controlTokenPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "bbs-client", controlTokenFile)
+}
+
+// loadControlToken legge il token, creandolo al primo utilizzo.
+func loadControlToken() (string, error) {
+	path := controlTokenPath()
+	if path == "" {
+		return "", errors.New("directory di configurazione non disponibile")
+	}
+	if data, err := os.ReadFile(path); err == nil {
+		if token := strings.TrimSpace(string(data)); token != "" {
+			return token, nil
+		}
+	}
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(b)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return "", err
+	}
+	return token, os.WriteFile(path, []byte(token+"\n"), 0600)
+}
+
+// ─────────────────────────────────────────────
+// Metodi
+// ─────────────────────────────────────────────
+
+// controlStatus è il risultato del metodo "status".
+type controlStatus struct {
+	Session   string `json:"session"`
+	Connected bool   `json:"connected"`
+	BBS       string `json:"bbs"`
+	Host      string `json:"host"`
+	Port      int    `json:"port"`
+	Transfer  string `json:"transfer,omitempty"`
+}
+
+// controlScreen è il risultato del metodo "screen".
+type controlScreen struct {
+	Text    string `json:"text"`
+	Cols    int    `json:"cols"`
+	Rows    int    `json:"rows"`
+	CursorX int    `json:"cursorX"`
+	CursorY int    `json:"cursorY"`
+}
+
+// controlMethods ritorna i metodi dell'endpoint, che operano sulla scheda
+// attiva come i binding corrispondenti.
+func (a *App) controlMethods() map[string]control.Method {
+	return map[string]control.Method{
+		"status": func(json.RawMessage) (any, error) {
+			a.mu.Lock()
+			defer a.mu.Unlock()
+			return controlStatus{
+				Session: a.id, Connected: a.connected, BBS: a.bbsName,
+				Host: a.host, Port: a.port, Transfer: a.transfer,
+			}, nil
+		},
+		"sessions": func(json.RawMessage) (any, error) {
+			return a.ListSessions(), nil
+		},
+		"switchSession": func(params json.RawMessage) (any, error) {
+			var p struct {
+				ID string `json:"id"`
+			}
+			if err := control.Decode(params, &p); err != nil {
+				return nil, err
+			}
+			return nil, msgErr(a.SwitchSession(p.ID))
+		},
+		"connect": func(params json.RawMessage) (any, error) {
+			var p struct {
+				Host string `json:"host"`
+				Port int    `json:"port"`
+				Name string `json:"name"`
+			}
+			if err := control.Decode(params, &p); err != nil {
+				return nil, err
+			}
+			return nil, msgErr(a.connectActive(p.Host, p.Port, p.Name))
+		},
+		"disconnect": func(json.RawMessage) (any, error) {
+			a.Disconnect()
+			return nil, nil
+		},
+		"send": func(params json.RawMessage) (any, error) {
+			var p struct {
+				Text string `json:"text"`
+			}
+			if err := control.Decode(params, &p); err != nil {
+				return nil, err
+			}
+			if !a.IsConnected() {
+				return nil, i18n.New(i18n.ErrNotConnected)
+			}
+			a.SendText(p.Text)
+			return nil, nil
+		},
+		"screen": func(json.RawMessage) (any, error) {
+			a.mu.Lock()
+			defer a.mu.Unlock()
+			return controlScreen{
+				Text: script.ScreenText(a.screen),
+				Cols: a.screen.Cols, Rows: a.screen.Rows,
+				CursorX: a.screen.CursorX, CursorY: a.screen.CursorY,
+			}, nil
+		},
+		"stats": func(json.RawMessage) (any, error) {
+			return a.GetConnectionStats(), nil
+		},
+		"upload": func(params json.RawMessage) (any, error) {
+			var p struct {
+				Path string `json:"path"`
+			}
+			if err := control.Decode(params, &p); err != nil {
+				return nil, err
+			}
+			if st, err := os.Stat(p.Path); err != nil || !st.Mode().IsRegular() {
+				return nil, control.InvalidParams(errors.New("file non trovato: " + p.Path))
+			}
+			a.mu.Lock()
+			conn, ok := a.conn, a.connected
+			a.mu.Unlock()
+			if !ok {
+				return nil, i18n.New(i18n.ErrNotConnected)
+			}
+			go conn.StartZmodemUpload(p.Path)
+			return nil, nil
+		},
+		"cancelTransfer": func(json.RawMessage) (any, error) {
+			a.CancelZmodem()
+			return nil, nil
+		},
+	}
+}
+
+// msgErr converte l'esito di un binding in error, senza trasformare un
+// *i18n.Message nil in un'interfaccia non nil.
+func msgErr(m *i18n.Message) error {
+	if m == nil {
+		return nil
+	}
+	return m
+}
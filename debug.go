@@ -0,0 +1,171 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ─────────────────────────────────────────────
+// Modalità debug: pprof e metriche di runtime
+// ─────────────────────────────────────────────
+//
+// Avviata con --debug (o --debug=porta), l'app serve su localhost i
+// profili di net/http/pprof e un riepilogo JSON delle metriche di
+// runtime, per capire i picchi di CPU durante l'output ANSI intenso:
+//
+//	go tool pprof http://127.0.0.1:6060/debug/pprof/profile?seconds=20
+//	curl http://127.0.0.1:6060/debug/metrics
+
+// debugDefaultPort è la porta usata da --debug senza valore.
+const debugDefaultPort = 6060
+
+// debugSampleInterval è l'intervallo su cui si calcolano le frequenze.
+const debugSampleInterval = time.Second
+
+// debugCounters conta dati ricevuti e aggiornamenti dello schermo. Sono
+// sempre attivi (un'operazione atomica per blocco) e letti solo in debug.
+type debugCounters struct {
+	bytes   atomic.Int64
+	updates atomic.Int64
+}
+
+// debugRates sono le frequenze misurate nell'ultimo intervallo.
+type debugRates struct {
+	mu            sync.Mutex
+	bytesPerSec   float64
+	updatesPerSec float64
+}
+
+// debugSession sono le metriche di una scheda.
+type debugSession struct {
+	ID        string `json:"id"`
+	Connected bool   `json:"connected"`
+	Queue     int    `json:"queue"` // dati ed eventi in attesa del loop eventi
+	QueueCap  int    `json:"queueCap"`
+	BytesIn   int64  `json:"bytesReceived"`
+}
+
+// debugMetrics è la risposta di /debug/metrics.
+type debugMetrics struct {
+	Uptime        float64        `json:"uptimeSeconds"`
+	Goroutines    int            `json:"goroutines"`
+	HeapAlloc     uint64         `json:"heapAllocBytes"`
+	HeapObjects   uint64         `json:"heapObjects"`
+	NumGC         uint32         `json:"numGC"`
+	GCPauseTotal  float64        `json:"gcPauseTotalMs"`
+	BytesTotal    int64          `json:"bytesTotal"`
+	BytesPerSec   float64        `json:"bytesPerSec"`
+	UpdatesTotal  int64          `json:"screenUpdatesTotal"`
+	UpdatesPerSec float64        `json:"screenUpdatesPerSec"`
+	Sessions      []debugSession `json:"sessions"`
+}
+
+// debugArg ritorna l'indirizzo richiesto con --debug[=porta] tra gli
+// argomenti ("" se assente). Il server ascolta solo su localhost.
+func debugArg(args []string) string {
+	for _, arg := range args {
+		if arg == "--debug" || arg == "-debug" {
+			return net.JoinHostPort("127.0.0.1", strconv.Itoa(debugDefaultPort))
+		}
+		for _, prefix := range []string{"--debug=", "-debug="} {
+			if v, ok := strings.CutPrefix(arg, prefix); ok {
+				if port, err := strconv.Atoi(v); err == nil && port > 0 && port <= 65535 {
+					return net.JoinHostPort("127.0.0.1", v)
+				}
+				log.Printf("[DEBUG] Porta non valida: %q", v)
+			}
+		}
+	}
+	return ""
+}
+
+// startDebug avvia il server di debug su addr.
+func (a *App) startDebug(addr string) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.HandleFunc("/debug/metrics", a.serveDebugMetrics)
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		log.Printf("[DEBUG] Server non avviato: %v", err)
+		return
+	}
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-a.ctx.Done()
+		srv.Close()
+	}()
+	go srv.Serve(ln)
+	go a.debugSampler()
+	log.Printf("[DEBUG] pprof e metriche su http://%s/debug/", ln.Addr())
+}
+
+// debugSampler aggiorna ogni secondo le frequenze di byte e
+// aggiornamenti dello schermo.
+func (a *App) debugSampler() {
+	ticker := time.NewTicker(debugSampleInterval)
+	defer ticker.Stop()
+	lastBytes, lastUpdates := a.debugCount.bytes.Load(), a.debugCount.updates.Load()
+	last := time.Now()
+	for {
+		select {
+		case <-a.ctx.Done():
+			return
+		case now := <-ticker.C:
+			bytes, updates := a.debugCount.bytes.Load(), a.debugCount.updates.Load()
+			secs := now.Sub(last).Seconds()
+			a.debugRates.mu.Lock()
+			a.debugRates.bytesPerSec = float64(bytes-lastBytes) / secs
+			a.debugRates.updatesPerSec = float64(updates-lastUpdates) / secs
+			a.debugRates.mu.Unlock()
+			lastBytes, lastUpdates, last = bytes, updates, now
+		}
+	}
+}
+
+// serveDebugMetrics risponde con le metriche correnti in JSON.
+func (a *App) serveDebugMetrics(w http.ResponseWriter, r *http.Request) {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	m := debugMetrics{
+		Uptime:       time.Since(a.startedAt).Seconds(),
+		Goroutines:   runtime.NumGoroutine(),
+		HeapAlloc:    mem.HeapAlloc,
+		HeapObjects:  mem.HeapObjects,
+		NumGC:        mem.NumGC,
+		GCPauseTotal: float64(mem.PauseTotalNs) / 1e6,
+		BytesTotal:   a.debugCount.bytes.Load(),
+		UpdatesTotal: a.debugCount.updates.Load(),
+	}
+	a.debugRates.mu.Lock()
+	m.BytesPerSec, m.UpdatesPerSec = a.debugRates.bytesPerSec, a.debugRates.updatesPerSec
+	a.debugRates.mu.Unlock()
+
+	a.mu.Lock()
+	for _, s := range a.sessions {
+		m.Sessions = append(m.Sessions, debugSession{
+			ID: s.id, Connected: s.connected,
+			Queue: s.events.Len(), QueueCap: s.events.Cap(),
+			BytesIn: s.stats.BytesReceived,
+		})
+	}
+	a.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(m)
+}
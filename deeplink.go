@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"github.com/wailsapp/wails/v2/pkg/options"
+	wailsrt "github.com/wailsapp/wails/v2/pkg/runtime"
+
+	"github.com/rj45lab/bbs-client-go/internal/addressbook"
+	"github.com/rj45lab/bbs-client-go/internal/i18n"
+)
+
+// ─────────────────────────────────────────────
+// Link telnet:// (deep link)
+// ─────────────────────────────────────────────
+
+// singleInstanceID identifica l'app per l'inoltro dei link alla finestra
+// già aperta.
+const singleInstanceID = "org.olografix.bbs-client"
+
+// uriArg ritorna il primo argomento che sembra un link (telnet://...),
+// passato dal sistema quando l'utente apre un link nel browser.
+func uriArg(args []string) string {
+	for _, arg := range args {
+		if strings.Contains(arg, "://") {
+			return arg
+		}
+	}
+	return ""
+}
+
+// OpenURI si connette alla BBS indicata da un link telnet://host[:porta].
+// Se la scheda attiva è già connessa ne apre una nuova. Il nome mostrato
+// viene preso dalla rubrica, se la BBS c'è.
+func (a *App) OpenURI(uri string) *i18n.Message {
+	e, err := addressbook.ParseURI(uri)
+	if err != nil {
+		return i18n.Err(err)
+	}
+	if e.Protocol != "telnet" {
+		return i18n.New(i18n.ErrUnsupportedScheme, e.Protocol)
+	}
+	name := e.Host
+	for _, b := range a.book.List() {
+		if b.Key() == e.Key() {
+			name = b.Name
+			break
+		}
+	}
+
+	a.mu.Lock()
+	busy := a.connected || a.viewingLog
+	a.mu.Unlock()
+	if busy {
+		a.CreateSession()
+	}
+	return a.Connect(e.Host, e.Port, name).Error
+}
+
+// domReady è l'hook OnDomReady di Wails: a frontend pronto propone il
+// ripristino di una sessione interrotta e apre il link passato all'avvio.
+func (a *App) domReady(ctx context.Context) {
+	// Le finestre di dialogo non devono bloccare il callback di Wails
+	go func() {
+		a.offerRestore()
+		a.openLaunchURI()
+	}()
+}
+
+// openLaunchURI apre il link passato all'avvio.
+func (a *App) openLaunchURI() {
+	a.mu.Lock()
+	uri := a.launchURI
+	a.launchURI = ""
+	a.mu.Unlock()
+	if uri != "" {
+		a.openURL(uri)
+	}
+}
+
+// openURL apre un link ricevuto dal sistema, segnalando gli errori nella
+// barra di stato.
+func (a *App) openURL(uri string) {
+	log.Printf("[LINK] %s", uri)
+	if msg := a.OpenURI(uri); msg != nil {
+		wailsrt.EventsEmit(a.ctx, "status-message", msg)
+	}
+}
+
+// onSecondInstance riceve gli argomenti di un secondo avvio dell'app (ad
+// esempio un altro link cliccato nel browser) e li gestisce qui.
+func (a *App) onSecondInstance(data options.SecondInstanceData) {
+	wailsrt.WindowUnminimise(a.ctx)
+	wailsrt.WindowShow(a.ctx)
+	if uri := uriArg(data.Args); uri != "" {
+		a.openURL(uri)
+	}
+}
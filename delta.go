@@ -0,0 +1,117 @@
+package main
+
+import (
+	"slices"
+	"strings"
+
+	wailsrt "github.com/wailsapp/wails/v2/pkg/runtime"
+
+	"github.com/rj45lab/bbs-client-go/pkg/ansi"
+)
+
+// ─────────────────────────────────────────────
+// Aggiornamenti dello schermo come differenze
+// ─────────────────────────────────────────────
+//
+// "screen-update" porta le righe cambiate dall'ultimo invio, già
+// codificate a run: il frontend le applica alla sua copia dello schermo
+// senza richiamare GetScreenRuns, risparmiando un giro di IPC a ogni
+// aggiornamento. App.sent è la copia che ha il frontend; ogni invio la
+// aggiorna sotto pushMu, così le differenze arrivano nell'ordine in cui
+// sono calcolate.
+
+// RowRuns è una riga cambiata dello schermo.
+type RowRuns struct {
+	Y    int       `json:"y"`
+	Runs []CellRun `json:"runs"`
+}
+
+// ScreenDelta è il payload di "screen-update".
+type ScreenDelta struct {
+	Full          bool      `json:"full"` // Changed contiene tutte le righe
+	Cols          int       `json:"cols"`
+	Rows          int       `json:"rows"`
+	Changed       []RowRuns `json:"changed"`
+	CursorX       int       `json:"cursorX"`
+	CursorY       int       `json:"cursorY"`
+	CursorVisible bool      `json:"cursorVisible"`
+	IceColors     bool      `json:"iceColors"`
+	BoldPolicy    string    `json:"boldPolicy"`
+}
+
+// sentScreen è lo schermo come l'ha ricevuto il frontend.
+type sentScreen struct {
+	screen *ansi.Screen // scheda mostrata (nil = niente inviato)
+	cols   int
+	rows   [][]CellRun
+}
+
+// pushScreen invia al frontend le righe cambiate dello schermo attivo.
+// Chiamare senza a.mu.
+func (a *App) pushScreen() {
+	a.pushMu.Lock()
+	defer a.pushMu.Unlock()
+	f := a.takeFrame()
+	if f == nil {
+		return
+	}
+	wailsrt.EventsEmit(a.ctx, "screen-update", a.screenDelta(f))
+	a.scheduleSpeech()
+}
+
+// screenDelta confronta la copia f con a.sent e ritorna le righe
+// cambiate, tutte se la scheda o le dimensioni sono cambiate. Chiamare con
+// pushMu acquisito.
+func (a *App) screenDelta(f *screenFrame) ScreenDelta {
+	full := a.sent.screen != f.screen || a.sent.cols != f.cols || len(a.sent.rows) != f.rows
+	if full {
+		a.sent = sentScreen{screen: f.screen, cols: f.cols, rows: make([][]CellRun, f.rows)}
+	}
+	d := ScreenDelta{
+		Full:          full,
+		Cols:          f.cols,
+		Rows:          f.rows,
+		CursorX:       f.cursorX,
+		CursorY:       f.cursorY,
+		CursorVisible: f.cursorVisible,
+		IceColors:     f.colors.IceColors,
+		BoldPolicy:    f.colors.BoldPolicy.String(),
+	}
+	for y := range a.sent.rows {
+		runs := a.rowRuns(f, y)
+		if !full && slices.Equal(runs, a.sent.rows[y]) {
+			continue
+		}
+		a.sent.rows[y] = runs
+		d.Changed = append(d.Changed, RowRuns{Y: y, Runs: runs})
+	}
+	return d
+}
+
+// rowRuns codifica la riga y della copia f in run di celle con gli
+// stessi attributi. Chiamare con pushMu acquisito.
+func (a *App) rowRuns(f *screenFrame, y int) []CellRun {
+	a.syncAttrs(f)
+	var runs []CellRun
+	var text strings.Builder
+	for _, c := range f.cells[y] {
+		cell := a.exportCell(c)
+		ch := cell.Char
+		cell.Char = ""
+		if n := len(runs); n > 0 && runs[n-1].Attr == cell {
+			text.WriteString(ch)
+			runs[n-1].Len++
+			continue
+		}
+		if n := len(runs); n > 0 {
+			runs[n-1].Text = text.String()
+			text.Reset()
+		}
+		text.WriteString(ch)
+		runs = append(runs, CellRun{Len: 1, Attr: cell})
+	}
+	if n := len(runs); n > 0 {
+		runs[n-1].Text = text.String()
+	}
+	return runs
+}
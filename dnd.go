@@ -0,0 +1,92 @@
+package main
+
+import (
+	"log"
+	"slices"
+	"time"
+
+	"github.com/rj45lab/bbs-client-go/internal/watch"
+)
+
+// ─────────────────────────────────────────────
+// Non disturbare
+// ─────────────────────────────────────────────
+//
+// Con la modalità "non disturbare" attiva su una scheda, campanello,
+// notifiche di sistema e avvisi delle frasi sorvegliate non vengono
+// mostrati: finiscono nell'elenco degli eventi persi della sessione, da
+// consultare con GetMissedEvents quando si torna disponibili.
+
+// maxMissedEvents limita gli eventi persi conservati per sessione: oltre
+// il limite si scartano i più vecchi.
+const maxMissedEvents = 500
+
+// Tipi di evento perso
+const (
+	missedBell  = "bell"
+	missedWatch = "watch"
+)
+
+// MissedEvent è un avviso soppresso dalla modalità non disturbare.
+type MissedEvent struct {
+	Time   time.Time `json:"time"`
+	Kind   string    `json:"kind"`             // bell, watch
+	Phrase string    `json:"phrase,omitempty"` // frase sorvegliata trovata
+	Line   string    `json:"line,omitempty"`   // riga che la contiene
+}
+
+// SetDoNotDisturb attiva o disattiva la modalità non disturbare per la
+// scheda attiva. Gli eventi persi restano disponibili fino a
+// ClearMissedEvents.
+func (a *App) SetDoNotDisturb(enabled bool) {
+	a.mu.Lock()
+	s := a.session
+	changed := s.dnd != enabled
+	s.dnd = enabled
+	missed := len(s.missed)
+	a.mu.Unlock()
+	if !changed {
+		return
+	}
+	log.Printf("[DND] %s: non disturbare %v (%d eventi persi)", s.id, enabled, missed)
+	a.emitSessions()
+}
+
+// GetMissedEvents ritorna gli avvisi soppressi nella scheda attiva, dal
+// più vecchio.
+func (a *App) GetMissedEvents() []MissedEvent {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return slices.Clone(a.missed)
+}
+
+// ClearMissedEvents svuota l'elenco degli eventi persi della scheda attiva.
+func (a *App) ClearMissedEvents() {
+	a.mu.Lock()
+	a.missed = nil
+	a.mu.Unlock()
+	a.emitSessions()
+}
+
+// missEvent registra un avviso soppresso. Chiamare con a.mu acquisito.
+func (s *session) missEvent(ev MissedEvent) {
+	ev.Time = time.Now()
+	if len(s.missed) >= maxMissedEvents {
+		s.missed = slices.Delete(s.missed, 0, len(s.missed)-maxMissedEvents+1)
+	}
+	s.missed = append(s.missed, ev)
+}
+
+// missWatchMatches registra le frasi trovate se la sessione è in modalità
+// non disturbare e ritorna true; altrimenti non fa nulla.
+func (a *App) missWatchMatches(s *session, matches []watch.Match) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if !s.dnd {
+		return false
+	}
+	for _, m := range matches {
+		s.missEvent(MissedEvent{Kind: missedWatch, Phrase: m.Phrase, Line: m.Line})
+	}
+	return true
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/rj45lab/bbs-client-go/internal/config"
+	"github.com/rj45lab/bbs-client-go/internal/i18n"
+	"github.com/rj45lab/bbs-client-go/pkg/telnet"
+	"github.com/rj45lab/bbs-client-go/pkg/zmodem"
+)
+
+// ─────────────────────────────────────────────
+// Conferma dei download ZMODEM
+// ─────────────────────────────────────────────
+//
+// Quando la BBS offre un file con ZMODEM il Receiver attende una risposta
+// prima di mandare ZRPOS. Secondo Settings.Downloads il file viene
+// accettato subito, dopo una conferma dell'utente, o rifiutato con ZSKIP.
+
+// downloadModes elenca le scelte valide ("" = ask).
+var downloadModes = []string{"", string(telnet.DownloadAlways), string(telnet.DownloadAsk), string(telnet.DownloadNever)}
+
+// downloadPolicy converte Settings.Downloads per la connessione.
+func downloadPolicy(mode string) telnet.DownloadPolicy {
+	if mode == "" {
+		return telnet.DownloadAsk
+	}
+	return telnet.DownloadPolicy(mode)
+}
+
+// onDownloadOffer chiede all'utente se accettare il file offerto su s.
+// Chiamato dall'event loop: la finestra resta aperta in una goroutine e
+// il trasferimento attende la risposta.
+func (a *App) onDownloadOffer(s *session, filename string, filesize int64) {
+	a.mu.Lock()
+	bbsName, conn := s.bbsName, s.conn
+	a.mu.Unlock()
+	events := s.eventLog // la risposta arriva dopo, da un'altra goroutine
+	events.write(evOffer, map[string]any{"filename": filename, "filesize": filesize})
+	a.emitFor(s, "zmodem-offer", ZmodemFile{Session: s.id, Filename: filename, Filesize: filesize})
+	// Come per le stampe non usa spawn: Shutdown non attende la finestra
+	go func() {
+		msg := i18n.T(i18n.DlgDownloadOffer, bbsName, filename, filesize)
+		ok := a.ask(i18n.T(i18n.DlgDownloadTitle), msg, i18n.T(i18n.DlgDownload))
+		conn.AnswerOffer(ok)
+		if !ok {
+			log.Printf("[ZMODEM] Download di %s rifiutato", filename)
+			events.write(evRefused, map[string]any{"filename": filename})
+			a.emitFor(s, "status-message", i18n.New(i18n.MsgDownloadRefused, filename))
+		}
+	}()
+}
+
+// SetDownloads sceglie come trattare i file offerti dalla BBS: always,
+// ask o never. Vale dalla prossima connessione.
+func (a *App) SetDownloads(mode string) *i18n.Message {
+	if !slices.Contains(downloadModes, mode) {
+		return i18n.New(i18n.ErrUnknownDownloads, mode)
+	}
+	return a.updateSettings(func(s *config.Settings) {
+		s.Downloads = mode
+	})
+}
+
+// ─────────────────────────────────────────────
+// Tipi di file
+// ─────────────────────────────────────────────
+
+// filePolicy converte Settings.FileTypes per la connessione.
+func filePolicy(t config.FileTypes) *zmodem.FilePolicy {
+	return &zmodem.FilePolicy{
+		Blocked: slices.Clone(t.Blocked),
+		Warned:  slices.Clone(t.Warned),
+	}
+}
+
+// validFileType dice se ext è un'estensione: "exe" o ".exe", senza
+// separatori di percorso né spazi.
+func validFileType(ext string) bool {
+	name := strings.TrimPrefix(ext, ".")
+	return name != "" && !strings.ContainsAny(name, `./\: `)
+}
+
+// onFilePolicy segnala un file che la politica dei tipi ha bloccato o
+// ritenuto da controllare (verdict, vedi zmodem.Verdict).
+func (a *App) onFilePolicy(s *session, filename, verdict string) {
+	s.eventLog.write(evFilePolicy, map[string]any{"filename": filename, "verdict": verdict})
+	a.emitFor(s, "zmodem-policy", ZmodemPolicy{Session: s.id, Filename: filename, Verdict: verdict})
+	code := i18n.MsgFileWarned
+	if verdict == zmodem.FileBlocked.String() {
+		code = i18n.MsgFileBlocked
+	}
+	a.emitFor(s, "status-message", i18n.New(code, filename))
+}
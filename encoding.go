@@ -0,0 +1,71 @@
+package main
+
+import (
+	"github.com/rj45lab/bbs-client-go/internal/charset"
+	"github.com/rj45lab/bbs-client-go/internal/config"
+	"github.com/rj45lab/bbs-client-go/internal/fingerprint"
+	"github.com/rj45lab/bbs-client-go/internal/i18n"
+)
+
+// ─────────────────────────────────────────────
+// Codifica per scheda
+// ─────────────────────────────────────────────
+//
+// Ogni scheda decodifica i dati ricevuti con la propria codifica prima di
+// passarli a schermo, log e script, e la usa per il testo inviato: quella
+// scelta per la BBS (Settings.Charset) o, in mancanza, quella del suo
+// software (CP437 per le BBS classiche).
+
+// charsetFor ritorna il nome della codifica della scheda s. Chiamare con
+// a.mu acquisito.
+func (a *App) charsetFor(s *session) string {
+	if name, ok := a.settings.Charset[bbsKey(s.host, s.port)]; ok {
+		return name
+	}
+	return fingerprint.PresetFor(s.software).Charset
+}
+
+// applyEncoding imposta codifica e decoder della scheda s. Il decoder
+// resta quello in uso se nulla è cambiato, per non perdere un carattere
+// a cavallo di due blocchi. Chiamare con a.mu acquisito.
+func (a *App) applyEncoding(s *session) {
+	enc, err := charset.Lookup(a.charsetFor(s))
+	if err != nil {
+		enc, _ = charset.Lookup(charset.CP437)
+	}
+	c1 := s.screen.C1Controls
+	if s.decoder != nil && s.encoding.Name() == enc.Name() && s.decodeC1 == c1 {
+		return
+	}
+	s.encoding, s.decoder, s.decodeC1 = enc, enc.NewDecoder(c1), c1
+}
+
+// SetCharset sceglie la codifica per la BBS corrente (cp437, utf8; "" =
+// quella del software riconosciuto).
+func (a *App) SetCharset(name string) *i18n.Message {
+	if _, err := charset.Lookup(name); name != "" && err != nil {
+		return i18n.New(i18n.ErrUnknownCharset, name)
+	}
+	a.mu.Lock()
+	key := bbsKey(a.host, a.port)
+	a.mu.Unlock()
+	return a.updateSettings(func(s *config.Settings) {
+		if name == "" {
+			delete(s.Charset, key)
+		} else {
+			s.Charset[key] = name
+		}
+	})
+}
+
+// GetCharset ritorna la codifica della scheda corrente.
+func (a *App) GetCharset() string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.session.encoding.Name()
+}
+
+// GetCharsets ritorna le codifiche disponibili.
+func (a *App) GetCharsets() []string {
+	return charset.Names
+}
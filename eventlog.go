@@ -0,0 +1,144 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/rj45lab/bbs-client-go/internal/config"
+	"github.com/rj45lab/bbs-client-go/internal/i18n"
+	"github.com/rj45lab/bbs-client-go/pkg/telnet"
+)
+
+// ─────────────────────────────────────────────
+// Log degli eventi (JSON Lines)
+// ─────────────────────────────────────────────
+//
+// Con Logging.Events accanto al log della sessione viene scritto un file
+// .jsonl con un evento per riga, per chi analizza le proprie sessioni con
+// jq o con uno script: connessione e chiusura, negoziazione telnet,
+// software riconosciuto, trasferimenti, frasi sorvegliate e trigger dei
+// plugin. Ogni riga ha "time" (RFC 3339), "elapsed" (secondi dall'inizio
+// della sessione), "event", i byte ricevuti e inviati fino a quel momento
+// ("bytesIn", "bytesOut") e i campi propri dell'evento.
+
+// Eventi del log
+const (
+	evSession      = "session"          // bbs, host, port
+	evConnected    = "connected"        // address
+	evDisconnected = "disconnected"     // reason, negotiation, duration
+	evError        = "error"            // reason
+	evSoftware     = "software"         // software, version, negotiation
+	evOffer        = "transfer-offer"   // filename, filesize
+	evRefused      = "transfer-refused" // filename
+	evTransfer     = "transfer-start"   // filename, filesize
+	evTransferEnd  = "transfer-end"     // filepath, success
+	evTransferErr  = "transfer-error"   // reason
+	evFilePolicy   = "file-policy"      // filename, verdict
+	evWatch        = "watch"            // phrase, line
+	evTrigger      = "trigger"          // plugin, id, line
+)
+
+// eventLog scrive gli eventi di una sessione. Può essere usato da più
+// goroutine; i metodi non fanno nulla su un eventLog nil.
+type eventLog struct {
+	w     *logWriter
+	conn  *telnet.Connection
+	start time.Time
+	limit int64
+
+	mu      sync.Mutex
+	bytesIn int64 // byte ricevuti dalla BBS
+	written int64 // byte scritti nel file
+}
+
+// newEventLog crea il log degli eventi in path.
+func newEventLog(path string, conn *telnet.Connection, limit int64) (*eventLog, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	return &eventLog{w: newLogWriter(f), conn: conn, start: time.Now(), limit: limit}, nil
+}
+
+// received conta n byte ricevuti.
+func (l *eventLog) received(n int) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	l.bytesIn += int64(n)
+	l.mu.Unlock()
+}
+
+// write aggiunge un evento con i campi fields (può essere nil). Oltre il
+// limite di dimensione dei log gli eventi vengono scartati.
+func (l *eventLog) write(event string, fields map[string]any) {
+	if l == nil {
+		return
+	}
+	now := time.Now()
+	rec := make(map[string]any, len(fields)+5)
+	for k, v := range fields {
+		rec[k] = v
+	}
+	rec["time"] = now.Format(time.RFC3339Nano)
+	rec["elapsed"] = now.Sub(l.start).Seconds()
+	rec["event"] = event
+	rec["bytesOut"] = l.conn.BytesSent()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	rec["bytesIn"] = l.bytesIn
+	line, err := json.Marshal(rec)
+	if err != nil {
+		log.Printf("[LOG] Evento %s non scritto: %v", event, err)
+		return
+	}
+	if l.written > l.limit {
+		return
+	}
+	n, _ := l.w.Write(append(line, '\n'))
+	l.written += int64(n)
+}
+
+// close chiude il file dopo aver scritto gli eventi in coda.
+func (l *eventLog) close() {
+	if l == nil {
+		return
+	}
+	l.w.Close()
+}
+
+// logClosed registra la fine della connessione di s (event) con il
+// motivo reason, le richieste di negoziazione del server e la durata.
+func (a *App) logClosed(s *session, event, reason string) {
+	if s.eventLog == nil {
+		return
+	}
+	a.mu.Lock()
+	at := s.stats.ConnectedAt
+	a.mu.Unlock()
+	fields := map[string]any{"reason": reason, "negotiation": s.conn.Negotiation()}
+	if !at.IsZero() {
+		fields["duration"] = time.Since(at).Seconds()
+	}
+	s.eventLog.write(event, fields)
+}
+
+// SetEventLog attiva il log degli eventi in JSON Lines (.jsonl) accanto al
+// log della sessione. Vale dalla prossima connessione.
+func (a *App) SetEventLog(enabled bool) *i18n.Message {
+	return a.updateSettings(func(s *config.Settings) {
+		s.Logging.Events = enabled
+	})
+}
+
+// GetEventLog ritorna se il log degli eventi è attivo.
+func (a *App) GetEventLog() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.settings.Logging.Events
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"github.com/rj45lab/bbs-client-go/internal/config"
+	"github.com/rj45lab/bbs-client-go/internal/fingerprint"
+	"github.com/rj45lab/bbs-client-go/internal/i18n"
+	"github.com/rj45lab/bbs-client-go/internal/iemsi"
+)
+
+// ─────────────────────────────────────────────
+// Payload degli eventi verso il frontend
+// ─────────────────────────────────────────────
+//
+// Ogni evento con più di un valore ha un payload tipizzato, così Wails ne
+// genera i modelli TypeScript e un campo nuovo non rompe il frontend:
+// quello vecchio lo ignora. EventsVersion cresce solo quando un campo
+// cambia significato o sparisce.
+
+// EventsVersion è la versione dei payload degli eventi.
+const EventsVersion = 1
+
+// ZmodemFile è il payload di "zmodem-offer" e "zmodem-started".
+type ZmodemFile struct {
+	Session  string `json:"session"`
+	Filename string `json:"filename"`
+	Filesize int64  `json:"filesize"`
+}
+
+// ZmodemProgress è il payload di "zmodem-progress".
+type ZmodemProgress struct {
+	Session string  `json:"session"`
+	Bytes   int64   `json:"bytes"`
+	Total   int64   `json:"total"`
+	Speed   float64 `json:"speed"` // byte al secondo
+	ETA     float64 `json:"eta"`   // secondi alla fine stimati (0 = ignoto)
+}
+
+// ZmodemFinished è il payload di "zmodem-finished".
+type ZmodemFinished struct {
+	Session  string `json:"session"`
+	Filepath string `json:"filepath"`
+	Success  bool   `json:"success"`
+}
+
+// ZmodemPolicy è il payload di "zmodem-policy".
+type ZmodemPolicy struct {
+	Session  string `json:"session"`
+	Filename string `json:"filename"`
+	Verdict  string `json:"verdict"` // zmodem.Verdict
+}
+
+// PasteProgress è il payload di "paste-progress".
+type PasteProgress struct {
+	Session string `json:"session"`
+	Sent    int    `json:"sent"`
+	Total   int    `json:"total"`
+}
+
+// PasteResult è il payload di "paste-finished".
+type PasteResult struct {
+	Session  string        `json:"session"`
+	Sent     int           `json:"sent"`
+	Total    int           `json:"total"`
+	Canceled bool          `json:"canceled"`
+	Error    *i18n.Message `json:"error"`
+}
+
+// PlaybackState è il payload di "playback-state".
+type PlaybackState struct {
+	Active     bool    `json:"active"`
+	Paused     bool    `json:"paused"`
+	Baud       int     `json:"baud"`
+	Pos        int     `json:"pos"`
+	Total      int     `json:"total"`
+	Timed      bool    `json:"timed"`
+	Scale      float64 `json:"scale"`
+	ElapsedMs  int64   `json:"elapsedMs"`
+	DurationMs int64   `json:"durationMs"`
+}
+
+// LogMode è il payload di "log-mode" (Active false = viewer chiuso).
+type LogMode struct {
+	Active bool `json:"active"`
+	Page   int  `json:"page"`
+	Total  int  `json:"total"`
+}
+
+// SessionActivity è il payload di "session-activity".
+type SessionActivity struct {
+	ID    string `json:"id"`
+	Event string `json:"event"`
+}
+
+// WatchMatch è il payload di "watch-match".
+type WatchMatch struct {
+	Session string `json:"session"`
+	BBS     string `json:"bbs"`
+	Phrase  string `json:"phrase"`
+	Line    string `json:"line"`
+}
+
+// IdleWarning è il payload di "idle-warning".
+type IdleWarning struct {
+	Session           string `json:"session"`
+	IdleSeconds       int64  `json:"idleSeconds"`
+	DisconnectSeconds int64  `json:"disconnectSeconds"` // -1 = mai
+}
+
+// transferETA stima i secondi che mancano alla fine di un trasferimento
+// (0 se la velocità non è ancora nota).
+func transferETA(bytes, total int64, speed float64) float64 {
+	if speed <= 0 || total <= bytes {
+		return 0
+	}
+	return float64(total-bytes) / speed
+}
+
+// EventPayloads elenca gli eventi e i loro payload. Non viene mai inviato:
+// serve a EventTypes perché Wails generi i modelli TypeScript.
+type EventPayloads struct {
+	Version         int                `json:"version"`
+	ScreenUpdate    ScreenDelta        `json:"screen-update"`
+	ScreenLines     []LineChange       `json:"screen-lines"`
+	ConnectProgress ConnectProgress    `json:"connect-progress"`
+	Sessions        []SessionInfo      `json:"sessions-changed"`
+	SessionActivity SessionActivity    `json:"session-activity"`
+	Settings        config.Settings    `json:"settings-changed"`
+	Theme           config.Theme       `json:"theme-changed"`
+	TerminalSize    TerminalSize       `json:"terminal-resized"`
+	LogMode         LogMode            `json:"log-mode"`
+	PlaybackState   PlaybackState      `json:"playback-state"`
+	ZmodemOffer     ZmodemFile         `json:"zmodem-offer"`
+	ZmodemStarted   ZmodemFile         `json:"zmodem-started"`
+	ZmodemProgress  ZmodemProgress     `json:"zmodem-progress"`
+	ZmodemFinished  ZmodemFinished     `json:"zmodem-finished"`
+	ZmodemPolicy    ZmodemPolicy       `json:"zmodem-policy"`
+	PasteProgress   PasteProgress      `json:"paste-progress"`
+	PasteFinished   PasteResult        `json:"paste-finished"`
+	WatchMatch      WatchMatch         `json:"watch-match"`
+	IdleWarning     IdleWarning        `json:"idle-warning"`
+	FloodLimit      FloodEvent         `json:"flood-limit"`
+	Capture         CaptureStatus      `json:"capture-status"`
+	Sharing         ShareInfo          `json:"sharing-changed"`
+	Reconnect       ReconnectEvent     `json:"reconnect-prompt"`
+	Schedule        ScheduleResult     `json:"schedule-finished"`
+	Bridge          BridgeResult       `json:"bridge-finished"`
+	HostKey         HostKeyInfo        `json:"host-key"`
+	Software        fingerprint.Result `json:"bbs-software"`
+	IEMSI           iemsi.ServerInfo   `json:"iemsi"`
+	StatusMessage   *i18n.Message      `json:"status-message"`
+}
+
+// EventTypes ritorna la versione dei payload degli eventi; il tipo di
+// ritorno documenta ogni evento per il frontend.
+func (a *App) EventTypes() EventPayloads {
+	return EventPayloads{Version: EventsVersion}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/rj45lab/bbs-client-go/internal/i18n"
+	"github.com/rj45lab/bbs-client-go/internal/script"
+)
+
+// ─────────────────────────────────────────────
+// Attese e invii per le automazioni semplici
+// ─────────────────────────────────────────────
+//
+// WaitForString e SendLine portano al frontend le wait() e sendln() degli
+// script Lua, per i flussi "aspetta il prompt, rispondi" che non valgono
+// uno script. Le attese leggono lo stesso testo ricevuto degli script
+// (script.Stream): uno script in corso e il frontend che aspettano
+// insieme si contendono il testo.
+
+// WaitResult è l'esito di WaitForString.
+type WaitResult struct {
+	Found bool          `json:"found"` // false a timeout o disconnessione
+	Text  string        `json:"text"`  // testo corrispondente
+	Error *i18n.Message `json:"error"`
+}
+
+// WaitForString attende nel testo ricevuto dalla scheda attiva (senza
+// sequenze ANSI) l'espressione regolare pattern, per al massimo timeoutMs
+// millisecondi (script.DefaultWait se non positivo). Come wait() consuma
+// il testo fino alla fine della corrispondenza.
+func (a *App) WaitForString(pattern string, timeoutMs int) WaitResult {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return WaitResult{Error: i18n.New(i18n.ErrInvalidPattern, pattern)}
+	}
+	timeout := script.DefaultWait
+	if timeoutMs > 0 {
+		timeout = time.Duration(timeoutMs) * time.Millisecond
+	}
+	a.mu.Lock()
+	if !a.connected {
+		a.mu.Unlock()
+		return WaitResult{Error: i18n.New(i18n.ErrNotConnected)}
+	}
+	stream := a.session.stream
+	a.mu.Unlock()
+	text, found := stream.WaitRegexp(a.ctx, re, timeout)
+	return WaitResult{Found: found, Text: text}
+}
+
+// SendLine invia text seguito da CR alla scheda attiva, come SendText.
+func (a *App) SendLine(text string) *i18n.Message {
+	if !a.IsConnected() {
+		return i18n.New(i18n.ErrNotConnected)
+	}
+	a.SendText(text + "\r")
+	return nil
+}
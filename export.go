@@ -0,0 +1,197 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	wailsrt "github.com/wailsapp/wails/v2/pkg/runtime"
+
+	"github.com/rj45lab/bbs-client-go/internal/htmlexport"
+	"github.com/rj45lab/bbs-client-go/internal/i18n"
+	"github.com/rj45lab/bbs-client-go/internal/raster"
+	"github.com/rj45lab/bbs-client-go/internal/sauce"
+	"github.com/rj45lab/bbs-client-go/pkg/ansi"
+)
+
+// ─────────────────────────────────────────────
+// Export — PNG, GIF animate, HTML e testo
+// ─────────────────────────────────────────────
+
+// ExportPNG salva lo schermo attuale come immagine PNG.
+func (a *App) ExportPNG() *i18n.Message {
+	path, err := a.exportDialog(i18n.T(i18n.DlgExportPNG), "schermata.png", i18n.T(i18n.DlgPNGFiles), "*.png")
+	if err != nil || path == "" {
+		return i18n.Err(err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return i18n.Err(err)
+	}
+	defer f.Close()
+
+	font := a.exportFont()
+	a.mu.Lock()
+	err = raster.WritePNG(f, a.screen, font)
+	a.mu.Unlock()
+	return i18n.Err(err)
+}
+
+// ExportGIF riproduce il log o l'artwork aperto nel viewer e lo salva come
+// GIF animata alla velocità indicata in baud (0 = solo schermata finale),
+// con un fotogramma ogni frameMs millisecondi (0 = default).
+func (a *App) ExportGIF(baud int, frameMs int) *i18n.Message {
+	a.mu.Lock()
+	if !a.viewingLog || a.viewerText == "" {
+		a.mu.Unlock()
+		return i18n.New(i18n.ErrNothingOpen)
+	}
+	text := a.viewerText
+	scr := ansi.NewScreen(a.screen.Cols, a.screen.Rows)
+	scr.Palette = a.screen.Palette
+	scr.IceColors = a.screen.IceColors
+	scr.BoldPolicy = a.screen.BoldPolicy
+	a.mu.Unlock()
+
+	path, err := a.exportDialog(i18n.T(i18n.DlgExportGIF), "sessione.gif", i18n.T(i18n.DlgGIFFiles), "*.gif")
+	if err != nil || path == "" {
+		return i18n.Err(err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return i18n.Err(err)
+	}
+	defer f.Close()
+
+	// Il rendering avviene su uno schermo separato: può durare a lungo
+	// e non deve bloccare l'interfaccia.
+	return i18n.Err(raster.WriteGIF(f, scr, text, raster.GIFOptions{
+		Baud:       max(baud, 0),
+		FrameDelay: time.Duration(max(frameMs, 0)) * time.Millisecond,
+		Font:       a.exportFont(),
+	}))
+}
+
+// cp437FontPath è il font CP437 del frontend, incorporato negli export HTML.
+const cp437FontPath = "frontend/fonts/Px437_IBM_VGA8.ttf"
+
+// ExportLogHTML converte un intero log di sessione salvato in una pagina
+// HTML con i colori originali e il font CP437 incorporato, una sezione
+// per ogni schermata (divise su clear screen, come nel log viewer).
+func (a *App) ExportLogHTML() *i18n.Message {
+	src, err := a.pickLogToExport()
+	if err != nil || src == "" {
+		return i18n.Err(err)
+	}
+	content, err := os.ReadFile(src)
+	if err != nil {
+		return i18n.New(i18n.ErrRead, err)
+	}
+	pages := a.renderLogPages(content)
+
+	name := strings.TrimSuffix(filepath.Base(src), filepath.Ext(src))
+	path, err := a.exportDialog(i18n.T(i18n.DlgExportHTML), name+".html", i18n.T(i18n.DlgHTMLFiles), "*.html")
+	if err != nil || path == "" {
+		return i18n.Err(err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return i18n.Err(err)
+	}
+	font, _ := assets.ReadFile(cp437FontPath)
+	if err := htmlexport.Write(f, pages, htmlexport.Options{Title: name, Font: font}); err != nil {
+		f.Close()
+		return i18n.Err(err)
+	}
+	return i18n.Err(f.Close())
+}
+
+// ExportLogAsText converte un log di sessione salvato in una trascrizione
+// in testo semplice, da citare in una email o archiviare: ogni schermata
+// viene resa con lo Screen (così i movimenti del cursore producono il
+// testo visto a video) e ne vengono scritte le righe finali, con
+// intestazione e chiusura della sessione. Con path vuoto chiede quale log
+// convertire.
+func (a *App) ExportLogAsText(path string) *i18n.Message {
+	if path == "" {
+		var err error
+		path, err = a.pickLogToExport()
+		if err != nil || path == "" {
+			return i18n.Err(err)
+		}
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return i18n.New(i18n.ErrRead, err)
+	}
+
+	var b strings.Builder
+	if header := logHeaderRe.Find(content); header != nil {
+		b.WriteString(strings.TrimSuffix(string(header), "\n") + "\n\n")
+	}
+	for i, scr := range a.renderLogPages(content) {
+		if i > 0 {
+			b.WriteString("\n\n")
+		}
+		b.WriteString(scr.Text())
+	}
+	b.WriteByte('\n')
+	if footer := logFooterRe.Find(content); footer != nil {
+		b.WriteString("\n" + strings.TrimPrefix(string(footer), "\n") + "\n")
+	}
+
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	dst, err := a.exportDialog(i18n.T(i18n.DlgExportText), name+".txt", i18n.T(i18n.DlgTranscripts), "*.txt")
+	if err != nil || dst == "" {
+		return i18n.Err(err)
+	}
+	return i18n.Err(os.WriteFile(dst, []byte(b.String()), 0600))
+}
+
+// pickLogToExport chiede il log di sessione da esportare.
+func (a *App) pickLogToExport() (string, error) {
+	return wailsrt.OpenFileDialog(a.ctx, wailsrt.OpenDialogOptions{
+		Title:            i18n.T(i18n.DlgExportLogPick),
+		DefaultDirectory: a.logDir,
+		Filters: []wailsrt.FileFilter{
+			{DisplayName: i18n.T(i18n.DlgLogFiles), Pattern: "*.log"},
+			{DisplayName: i18n.T(i18n.DlgAllFiles), Pattern: "*"},
+		},
+	})
+}
+
+// renderLogPages rende ogni schermata di un log (divise su clear screen,
+// come nel log viewer) su uno Screen separato, con le dimensioni e la
+// palette del terminale o la larghezza del record SAUCE.
+func (a *App) renderLogPages(content []byte) []*ansi.Screen {
+	rec, content := sauce.Parse(content)
+
+	a.mu.Lock()
+	cols, rows := a.screen.Cols, a.screen.Rows
+	pal, bold := a.screen.Palette, a.screen.BoldPolicy
+	a.mu.Unlock()
+	if rec != nil && rec.Width > 0 {
+		cols = min(rec.Width, maxSauceWidth)
+	}
+
+	var pages []*ansi.Screen
+	for _, text := range splitLogPages(stripLogFrame(string(content))) {
+		scr := ansi.NewScreen(cols, rows)
+		scr.Palette, scr.BoldPolicy = pal, bold
+		scr.IceColors = rec != nil && rec.IceColors
+		scr.Feed(text)
+		pages = append(pages, scr)
+	}
+	return pages
+}
+
+// exportDialog chiede il percorso di destinazione di un export.
+func (a *App) exportDialog(title, name, filterName, pattern string) (string, error) {
+	return wailsrt.SaveFileDialog(a.ctx, wailsrt.SaveDialogOptions{
+		Title:            title,
+		DefaultDirectory: a.logDir,
+		DefaultFilename:  name,
+		Filters:          []wailsrt.FileFilter{{DisplayName: filterName, Pattern: pattern}},
+	})
+}
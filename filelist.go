@@ -0,0 +1,53 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/rj45lab/bbs-client-go/internal/filelist"
+	"github.com/rj45lab/bbs-client-go/internal/i18n"
+)
+
+// ─────────────────────────────────────────────
+// Liste di file raccolte dallo schermo
+// ─────────────────────────────────────────────
+
+// GetScrapedFileList ritorna i file riconosciuti nelle liste mostrate dalla
+// BBS nella scheda corrente, nell'ordine di arrivo.
+func (a *App) GetScrapedFileList() []filelist.Entry {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.files.Entries()
+}
+
+// MarkScrapedFile seleziona o deseleziona un file per il download.
+func (a *App) MarkScrapedFile(name string, marked bool) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.files.Mark(name, marked)
+}
+
+// ClearScrapedFileList svuota la lista dei file raccolti.
+func (a *App) ClearScrapedFileList() {
+	a.mu.Lock()
+	a.files.Clear()
+	a.mu.Unlock()
+	a.emitFor(a.session, "filelist-updated", 0)
+}
+
+// SendMarkedFiles digita i nomi dei file selezionati, separati da spazi,
+// al prompt della BBS (ad esempio quello del download in batch). L'invio
+// finale resta all'utente.
+func (a *App) SendMarkedFiles() *i18n.Message {
+	a.mu.Lock()
+	ok := a.connected
+	names := a.files.Marked()
+	a.mu.Unlock()
+	if !ok {
+		return i18n.New(i18n.ErrNotConnected)
+	}
+	if len(names) == 0 {
+		return i18n.New(i18n.ErrNoMarkedFiles)
+	}
+	a.touchInput()
+	return i18n.Err(a.conn.Send([]byte(strings.Join(names, " "))))
+}
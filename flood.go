@@ -0,0 +1,126 @@
+package main
+
+import (
+	"log"
+	"time"
+
+	wailsrt "github.com/wailsapp/wails/v2/pkg/runtime"
+
+	"github.com/rj45lab/bbs-client-go/internal/config"
+	"github.com/rj45lab/bbs-client-go/internal/i18n"
+)
+
+// ─────────────────────────────────────────────
+// Limiti anti-flooding (PT-004)
+// ─────────────────────────────────────────────
+//
+// Una BBS che invia dati senza sosta non deve riempire il disco né
+// bloccare l'interfaccia. I limiti sono in Settings.Limits; quando uno
+// scatta il frontend riceve flood-limit, invece di perdere dati in
+// silenzio.
+
+// Intervalli ammessi per i limiti
+const (
+	maxLogSizeMB     = 4096
+	minDataBuffer    = 16
+	maxDataBuffer    = 65536
+	maxUpdatesPerSec = 1000
+	maxScrollback    = 100000
+)
+
+// floodReportAfter è quanto devono durare gli aggiornamenti accorpati
+// prima di segnalarli: le normali raffiche di una schermata non contano.
+const floodReportAfter = time.Second
+
+// Limiti che possono scattare (FloodEvent.Limit)
+const (
+	floodLog     = "log"     // log o trascrizione oltre LogSizeMB
+	floodCapture = "capture" // cattura oltre LogSizeMB
+	floodBuffer  = "buffer"  // coda dei dati ricevuti piena, dati scartati
+	floodUpdates = "updates" // aggiornamenti dello schermo accorpati
+)
+
+// FloodEvent segnala al frontend un limite scattato.
+type FloodEvent struct {
+	Session string `json:"session"`
+	Limit   string `json:"limit"`
+	Value   int    `json:"value"`             // valore del limite (MB, blocchi, aggiornamenti/s)
+	Dropped int64  `json:"dropped,omitempty"` // byte scartati (buffer)
+}
+
+// screenThrottle accorpa gli aggiornamenti dello schermo oltre
+// Limits.UpdatesPerSec. Protetto da App.mu.
+type screenThrottle struct {
+	last     time.Time
+	pending  bool      // aggiornamento differito in attesa
+	since    time.Time // inizio degli aggiornamenti accorpati (zero = nessuno)
+	reported bool      // flood-limit già inviato per questa raffica
+}
+
+// validateLimits controlla i limiti anti-flooding.
+func validateLimits(l config.Limits) *i18n.Message {
+	switch {
+	case l.LogSizeMB < 1 || l.LogSizeMB > maxLogSizeMB:
+		return i18n.New(i18n.ErrInvalidLimit, "logSizeMB")
+	case l.DataBuffer < minDataBuffer || l.DataBuffer > maxDataBuffer:
+		return i18n.New(i18n.ErrInvalidLimit, "dataBuffer")
+	case l.UpdatesPerSec < 1 || l.UpdatesPerSec > maxUpdatesPerSec:
+		return i18n.New(i18n.ErrInvalidLimit, "updatesPerSec")
+	case l.ScrollbackLines < 0 || l.ScrollbackLines > maxScrollback:
+		return i18n.New(i18n.ErrInvalidLimit, "scrollbackLines")
+	}
+	return nil
+}
+
+// logLimit ritorna la dimensione massima dei file di log in byte.
+func logLimit(l config.Limits) int64 {
+	return int64(l.LogSizeMB) << 20
+}
+
+// emitFlood segnala un limite scattato sulla sessione s.
+func (a *App) emitFlood(s *session, limit string, value int, dropped int64) {
+	log.Printf("[FLOOD] %s: limite %s (%d) raggiunto", s.id, limit, value)
+	wailsrt.EventsEmit(a.ctx, "flood-limit", FloodEvent{Session: s.id, Limit: limit, Value: value, Dropped: dropped})
+}
+
+// emitScreenUpdate notifica al frontend i dati ricevuti, al massimo
+// Limits.UpdatesPerSec volte al secondo: gli aggiornamenti più fitti
+// vengono accorpati in uno differito.
+func (a *App) emitScreenUpdate(s *session) {
+	a.mu.Lock()
+	ups := a.settings.Limits.UpdatesPerSec
+	interval := time.Second / time.Duration(max(ups, 1))
+	t := &s.throttle
+	now := time.Now()
+	wait := interval - now.Sub(t.last)
+	if wait <= 0 {
+		t.last = now
+		t.since, t.reported = time.Time{}, false
+		a.mu.Unlock()
+		a.emitFor(s, "screen-update")
+		return
+	}
+	if t.since.IsZero() {
+		t.since = now
+	}
+	report := !t.reported && now.Sub(t.since) >= floodReportAfter
+	t.reported = t.reported || report
+	if !t.pending {
+		t.pending = true
+		time.AfterFunc(wait, func() {
+			a.mu.Lock()
+			t.pending = false
+			t.last = time.Now()
+			a.mu.Unlock()
+			select {
+			case <-s.done:
+			default:
+				a.emitFor(s, "screen-update")
+			}
+		})
+	}
+	a.mu.Unlock()
+	if report {
+		a.emitFlood(s, floodUpdates, ups, 0)
+	}
+}
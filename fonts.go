@@ -0,0 +1,131 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/rj45lab/bbs-client-go/internal/bitmapfont"
+	"github.com/rj45lab/bbs-client-go/internal/i18n"
+)
+
+// ─────────────────────────────────────────────
+// Font bitmap
+// ─────────────────────────────────────────────
+//
+// I font bitmap (.F08/.F14/.F16, PSF) si copiano nella cartella fonts
+// accanto alle impostazioni. Servono al frontend, che ne riceve i glifi,
+// e agli export PNG e GIF: un artwork con un font nel record SAUCE usa
+// quel font se il file c'è (vedi bitmapfont.SAUCENames per i nomi), gli
+// altri usano Settings.ExportFont.
+
+// BitmapFontInfo descrive un font della cartella fonts.
+type BitmapFontInfo struct {
+	File    string `json:"file"`
+	Name    string `json:"name"`
+	Width   int    `json:"width"`
+	Height  int    `json:"height"`
+	Glyphs  int    `json:"glyphs"`
+	Unicode bool   `json:"unicode"`
+}
+
+// BitmapFontResult è un font con i suoi glifi, o l'errore di caricamento.
+type BitmapFontResult struct {
+	Font  *bitmapfont.Font `json:"font"` // nil se non disponibile
+	Error *i18n.Message    `json:"error"`
+}
+
+// fontsDir ritorna la cartella dei font bitmap ("" se la directory di
+// configurazione non è disponibile).
+func fontsDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "bbs-client", "fonts")
+}
+
+// loadBitmapFont carica un font della cartella fonts per nome di file.
+func loadBitmapFont(file string) (*bitmapfont.Font, error) {
+	dir := fontsDir()
+	if dir == "" || file == "" || file != filepath.Base(file) || strings.HasPrefix(file, ".") {
+		return nil, errors.New("nome di file non valido: " + file)
+	}
+	return bitmapfont.Load(filepath.Join(dir, file))
+}
+
+// ListBitmapFonts ritorna i font validi della cartella fonts.
+func (a *App) ListBitmapFonts() []BitmapFontInfo {
+	entries, _ := os.ReadDir(fontsDir())
+	var out []BitmapFontInfo
+	for _, e := range entries {
+		if e.IsDir() || !slices.Contains(bitmapfont.Extensions, strings.ToLower(filepath.Ext(e.Name()))) {
+			continue
+		}
+		f, err := loadBitmapFont(e.Name())
+		if err != nil {
+			log.Printf("[FONT] %v", err)
+			continue
+		}
+		out = append(out, BitmapFontInfo{
+			File: e.Name(), Name: f.Name, Width: f.Width, Height: f.Height,
+			Glyphs: f.Glyphs, Unicode: f.Unicode,
+		})
+	}
+	return out
+}
+
+// GetBitmapFont ritorna i glifi del font file della cartella fonts.
+func (a *App) GetBitmapFont(file string) BitmapFontResult {
+	f, err := loadBitmapFont(file)
+	if err != nil {
+		return BitmapFontResult{Error: i18n.New(i18n.ErrBitmapFont, err.Error())}
+	}
+	return BitmapFontResult{Font: f}
+}
+
+// GetSauceFont ritorna i glifi del font indicato dal record SAUCE del file
+// aperto nel viewer. Font è nil se il record non indica un font o se il
+// file del font non è nella cartella fonts.
+func (a *App) GetSauceFont() BitmapFontResult {
+	a.mu.Lock()
+	name := ""
+	if a.sauce != nil {
+		name = a.sauce.Font
+	}
+	a.mu.Unlock()
+	if name == "" {
+		return BitmapFontResult{}
+	}
+	path := bitmapfont.FindSAUCE(fontsDir(), name)
+	if path == "" {
+		return BitmapFontResult{}
+	}
+	f, err := bitmapfont.Load(path)
+	if err != nil {
+		return BitmapFontResult{Error: i18n.New(i18n.ErrBitmapFont, err.Error())}
+	}
+	return BitmapFontResult{Font: f}
+}
+
+// exportFont sceglie il font degli export: quello del record SAUCE del
+// file aperto, se disponibile, altrimenti Settings.ExportFont. Ritorna nil
+// per il font interno. Chiamare senza a.mu.
+func (a *App) exportFont() *bitmapfont.Font {
+	if res := a.GetSauceFont(); res.Font != nil {
+		return res.Font
+	}
+	file := a.config.Get().ExportFont
+	if file == "" {
+		return nil
+	}
+	f, err := loadBitmapFont(file)
+	if err != nil {
+		log.Printf("[FONT] Export con il font interno: %v", err)
+		return nil
+	}
+	return f
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"slices"
+
+	"github.com/rj45lab/bbs-client-go/pkg/ansi"
+)
+
+// ─────────────────────────────────────────────
+// Copie dello schermo per gli snapshot
+// ─────────────────────────────────────────────
+//
+// Gli snapshot (GetScreenRuns, screen-update, /screen.bin) non tengono
+// a.mu: copiano lo schermo attivo con screenMu in lettura e lo esportano
+// dalla copia, così un frontend lento non ferma l'event loop né le altre
+// chiamate. Chi modifica gli schermi o cambia la scheda attiva tiene sia
+// a.mu sia screenMu; chi li legge soltanto può tenere l'uno o l'altro.
+// Le righe delle copie non cambiano più: una copia nuova condivide con la
+// precedente quelle rimaste uguali e duplica solo le righe modificate.
+
+// screenFrame è una copia immutabile dello schermo attivo.
+type screenFrame struct {
+	screen        *ansi.Screen // schermo copiato (solo per identità)
+	cols, rows    int
+	cursorX       int
+	cursorY       int
+	cursorVisible bool
+	// colors ha la sola configurazione dei colori dello schermo (palette,
+	// iCE colors, bold policy), per CellColors
+	colors *ansi.Screen
+	cells  [][]ansi.Cell
+}
+
+// takeFrame copia lo schermo attivo (nil prima dell'avvio). Chiamare con
+// pushMu acquisito e senza a.mu: screenMu resta in lettura solo per la
+// copia.
+func (a *App) takeFrame() *screenFrame {
+	a.screenMu.RLock()
+	defer a.screenMu.RUnlock()
+	if a.session == nil {
+		return nil
+	}
+
+	scr := a.screen
+	prev := a.frame
+	if prev != nil && (prev.screen != scr || prev.cols != scr.Cols || prev.rows != scr.Rows) {
+		prev = nil
+	}
+	pal := *scr.ActivePalette()
+	f := &screenFrame{
+		screen:        scr,
+		cols:          scr.Cols,
+		rows:          scr.Rows,
+		cursorX:       scr.CursorX,
+		cursorY:       scr.CursorY,
+		cursorVisible: scr.CursorVisible,
+		colors:        &ansi.Screen{Palette: &pal, IceColors: scr.IceColors, BoldPolicy: scr.BoldPolicy},
+		cells:         make([][]ansi.Cell, scr.Rows),
+	}
+	for y := range f.cells {
+		row := scr.Buffer[y][:scr.Cols]
+		if prev != nil && slices.Equal(prev.cells[y], row) {
+			f.cells[y] = prev.cells[y]
+			continue
+		}
+		f.cells[y] = slices.Clone(row)
+	}
+	a.frame = f
+	return f
+}
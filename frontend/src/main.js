@@ -7,23 +7,31 @@
 // Terminal Renderer (Canvas 80×25)
 // ═══════════════════════════════════════════
 
-const COLS = 80;
-const ROWS = 25;
+// Dimensioni del terminale: dal backend (evento terminal-resized), che le
+// riceve da fitTerminal adattate alla finestra
+const MIN_COLS = 80;
+const MIN_ROWS = 25;
+let cols = MIN_COLS;
+let rows = MIN_ROWS;
 const FONT_VT323 = "'VT323', 'Consolas', 'Courier New', monospace";
 const FONT_IBM_VGA = "'IBM VGA', 'Consolas', 'Courier New', monospace";
 let currentFont = FONT_IBM_VGA; // Default: IBM VGA
 let currentFontLabel = 'IBM VGA';
-const FONT_SIZE = 16; // fisso, come nel Python (IBM VGA 16pt)
+let fontSize = 16; // dal tema (default IBM VGA 16pt, come nel Python)
+let cursorShape = 'block'; // dal tema: block, underline, bar
 
 let canvas, ctx;
 let cellW = 0, cellH = 0;
 let dpr = 1; // devicePixelRatio per Retina
 let cursorOn = true;
 let cursorX = 0, cursorY = 0;
+let cursorVisible = true;
 let screenData = null;
 let connected = false;
 let viewingLog = false;
 let crtEnabled = false;
+let selection = null; // celle selezionate col mouse {x0, y0, x1, y1}
+let selecting = false;
 
 
 function syncCrtOverlays() {
@@ -53,15 +61,15 @@ function resizeCanvas() {
 
     // Misura le dimensioni reali dei caratteri a 1x
     ctx.setTransform(1, 0, 0, 1, 0, 0); // reset transform
-    ctx.font = `${FONT_SIZE}px ${currentFont}`;
-    const testStr = 'M'.repeat(COLS);
+    ctx.font = `${fontSize}px ${currentFont}`;
+    const testStr = 'M'.repeat(cols);
     const measuredW = ctx.measureText(testStr).width;
-    cellW = Math.round(measuredW / COLS);
-    cellH = FONT_SIZE;
+    cellW = Math.round(measuredW / cols);
+    cellH = fontSize;
 
     // Dimensioni CSS (logiche)
-    const logicalW = cellW * COLS;
-    const logicalH = cellH * ROWS;
+    const logicalW = cellW * cols;
+    const logicalH = cellH * rows;
     canvas.style.width = logicalW + 'px';
     canvas.style.height = logicalH + 'px';
 
@@ -99,14 +107,14 @@ function renderScreen(data) {
 
     // ── PASSO 1: Background ──
     // Disegna background riga per riga, raggruppando celle con stesso colore BG
-    for (let y = 0; y < ROWS && y < data.length; y++) {
+    for (let y = 0; y < rows && y < data.length; y++) {
         const row = data[y];
         let x = 0;
-        while (x < COLS && x < row.length) {
+        while (x < cols && x < row.length) {
             const cell = row[x];
             const bgKey = colorKey(cell.bgR, cell.bgG, cell.bgB);
             let runLen = 1;
-            while (x + runLen < COLS && x + runLen < row.length) {
+            while (x + runLen < cols && x + runLen < row.length) {
                 const next = row[x + runLen];
                 if (colorKey(next.bgR, next.bgG, next.bgB) !== bgKey) break;
                 runLen++;
@@ -124,40 +132,119 @@ function renderScreen(data) {
     ctx.textBaseline = 'top';
     let lastFont = '';
     let lastFill = '';
-    for (let y = 0; y < ROWS && y < data.length; y++) {
+    for (let y = 0; y < rows && y < data.length; y++) {
         const row = data[y];
-        for (let x = 0; x < COLS && x < row.length; x++) {
+        for (let x = 0; x < cols && x < row.length; x++) {
             const cell = row[x];
             const ch = cell.ch;
-            if (!ch || ch === ' ' || ch === '\u0000') continue;
+            if (!ch || ch === ' ' || ch === '\u0000' || cell.conceal) continue;
 
             const px = x * cellW;
             const py = y * cellH;
 
             // Cambia font solo se necessario
-            const font = `${cell.bold ? 'bold ' : ''}${FONT_SIZE}px ${currentFont}`;
+            const font = `${cell.italic ? 'italic ' : ''}${cell.bold ? 'bold ' : ''}${fontSize}px ${currentFont}`;
             if (font !== lastFont) { ctx.font = font; lastFont = font; }
 
             // Cambia colore solo se necessario
             const fill = `rgb(${cell.fgR},${cell.fgG},${cell.fgB})`;
             if (fill !== lastFill) { ctx.fillStyle = fill; lastFill = fill; }
 
+            ctx.globalAlpha = cell.faint ? 0.6 : 1;
             ctx.fillText(ch, px, py);
+            ctx.globalAlpha = 1;
 
+            if (cell.strike) {
+                ctx.fillRect(px, py + Math.floor(cellH / 2), cellW, 1);
+            }
             if (cell.ul) {
-                ctx.strokeStyle = fill;
-                ctx.lineWidth = 1;
-                ctx.beginPath();
-                ctx.moveTo(px, py + cellH - 1);
-                ctx.lineTo(px + cellW, py + cellH - 1);
-                ctx.stroke();
+                drawUnderline(cell, px, py);
             }
         }
     }
 
+    renderSelection();
     renderCursor();
 }
 
+// Evidenzia la selezione invertendo i colori, riga per riga come nei
+// terminali: la prima riga parte da x0, l'ultima finisce a x1.
+function renderSelection() {
+    if (!selection) return;
+    let { x0, y0, x1, y1 } = selection;
+    if (y1 < y0 || (y1 === y0 && x1 < x0)) [x0, y0, x1, y1] = [x1, y1, x0, y0];
+    ctx.globalCompositeOperation = 'difference';
+    ctx.fillStyle = '#fff';
+    for (let y = y0; y <= y1; y++) {
+        const from = y === y0 ? x0 : 0;
+        const to = y === y1 ? x1 : cols - 1;
+        ctx.fillRect(from * cellW, y * cellH, (to - from + 1) * cellW, cellH);
+    }
+    ctx.globalCompositeOperation = 'source-over';
+}
+
+// Cella del terminale sotto il puntatore
+function cellAt(e) {
+    const r = canvas.getBoundingClientRect();
+    return {
+        x: Math.min(cols - 1, Math.max(0, Math.floor((e.clientX - r.left) / cellW))),
+        y: Math.min(rows - 1, Math.max(0, Math.floor((e.clientY - r.top) / cellH))),
+    };
+}
+
+// Copia la selezione negli appunti: 'html' con i colori (più il testo
+// semplice per chi non accetta HTML), 'ansi' come testo con le sequenze
+async function copySelection(format) {
+    const s = selection;
+    const res = await window.go.main.App.CopySelection(s.x0, s.y0, s.x1, s.y1, format);
+    if (res.error) {
+        setStatus(msgText(res.error));
+        return;
+    }
+    try {
+        if (format === 'html') {
+            await navigator.clipboard.write([new ClipboardItem({
+                'text/html': new Blob([res.data], { type: 'text/html' }),
+                'text/plain': new Blob([res.text], { type: 'text/plain' }),
+            })]);
+        } else {
+            await window.runtime.ClipboardSetText(res.data);
+        }
+        setStatus(format === 'ansi' ? 'Selezione copiata come ANSI' : 'Selezione copiata con i colori');
+    } catch (e) {
+        // WebView senza ClipboardItem: almeno il testo
+        console.warn('Clipboard:', e);
+        await window.runtime.ClipboardSetText(res.text);
+        setStatus('Selezione copiata come testo');
+    }
+    selection = null;
+    renderScreen(screenData);
+}
+
+// Sottolineatura con stile esteso (SGR 4:x) e colore (SGR 58)
+function drawUnderline(cell, px, py) {
+    const y = py + cellH - 1;
+    ctx.strokeStyle = `rgb(${cell.ulR},${cell.ulG},${cell.ulB})`;
+    ctx.lineWidth = 1;
+    ctx.setLineDash(cell.ulStyle === 'dotted' ? [1, 2] : cell.ulStyle === 'dashed' ? [3, 2] : []);
+    ctx.beginPath();
+    if (cell.ulStyle === 'curly') {
+        for (let i = 0; i <= cellW; i += 2) {
+            const wy = y - ((i / 2) % 2 === 0 ? 0 : 2);
+            if (i === 0) ctx.moveTo(px, wy); else ctx.lineTo(px + i, wy);
+        }
+    } else {
+        ctx.moveTo(px, y);
+        ctx.lineTo(px + cellW, y);
+        if (cell.ulStyle === 'double') {
+            ctx.moveTo(px, y - 2);
+            ctx.lineTo(px + cellW, y - 2);
+        }
+    }
+    ctx.stroke();
+    ctx.setLineDash([]);
+}
+
 function renderCursor() {
     if (!ctx || !screenData) return;
 
@@ -171,44 +258,183 @@ function renderCursor() {
         ctx.fillRect(px, py, cellW, cellH);
         if (cell.ch && cell.ch !== ' ' && cell.ch !== '\u0000') {
             ctx.fillStyle = `rgb(${cell.fgR},${cell.fgG},${cell.fgB})`;
-            ctx.font = `${cell.bold ? 'bold ' : ''}${FONT_SIZE}px ${currentFont}`;
+            ctx.font = `${cell.bold ? 'bold ' : ''}${fontSize}px ${currentFont}`;
             ctx.textBaseline = 'top';
             ctx.fillText(cell.ch, px, py);
         }
 
         // Disegna cursore
-        if (cursorOn && document.activeElement === canvas) {
+        if (cursorOn && cursorVisible && document.activeElement === canvas) {
             ctx.globalCompositeOperation = 'difference';
             ctx.fillStyle = 'rgba(0, 255, 65, 0.7)';
-            ctx.fillRect(px, py, cellW, cellH);
+            if (cursorShape === 'underline') {
+                ctx.fillRect(px, py + cellH - 2, cellW, 2);
+            } else if (cursorShape === 'bar') {
+                ctx.fillRect(px, py, 2, cellH);
+            } else {
+                ctx.fillRect(px, py, cellW, cellH);
+            }
             ctx.globalCompositeOperation = 'source-over';
         }
     }
 }
 
+// Applica il tema ricevuto dal backend: font, dimensione, cursore, sfondo.
+// Palette e bold policy sono già applicate dal backend allo screen.
+function applyTheme(t) {
+    if (!t) return;
+    if (t.font === 'vt323') {
+        currentFont = FONT_VT323;
+        currentFontLabel = 'VT323';
+    } else {
+        currentFont = FONT_IBM_VGA;
+        currentFontLabel = 'IBM VGA';
+    }
+    fontSize = t.fontSize || 16;
+    cursorShape = t.cursor || 'block';
+    document.getElementById('terminal-container').style.background = t.background || '';
+    canvas.style.background = t.background || '';
+    const btnFont = document.getElementById('btn-font');
+    if (btnFont) btnFont.textContent = currentFontLabel;
+    resizeCanvas();
+    fitTerminal();
+}
+
+// fitTerminal chiede al backend quante celle entrano nella finestra, senza
+// scendere sotto 80×25: ingrandendo la finestra la BBS riceve più spazio.
+let fitTimer = null;
+function fitTerminal() {
+    if (!window.go || !cellW || !cellH) return;
+    const container = document.getElementById('terminal-container');
+    const w = container.clientWidth - 8; // padding
+    const h = container.clientHeight - 8;
+    const c = Math.max(MIN_COLS, Math.floor(w / cellW));
+    const r = Math.max(MIN_ROWS, Math.floor(h / cellH));
+    if (c !== cols || r !== rows) window.go.main.App.SetTerminalSize(c, r);
+}
+
+function onTerminalResized(size) {
+    if (!size || (size.cols === cols && size.rows === rows)) return;
+    cols = size.cols;
+    rows = size.rows;
+    resizeCanvas();
+}
+
 // ═══════════════════════════════════════════
 // Screen Update
 // ═══════════════════════════════════════════
 
+// expandRuns converte le righe RLE ({text, len, attr}) nella griglia di
+// celle usata dal renderer. Le celle di un run condividono gli attributi.
+function expandRuns(rows) {
+    return rows.map(runs => {
+        const row = [];
+        for (const run of runs || []) {
+            for (const ch of run.text) row.push({ ...run.attr, ch });
+        }
+        return row;
+    });
+}
+
+// Stili della sottolineatura nell'ordine del formato binario (snapshot.go)
+const UL_STYLES = ['', 'single', 'double', 'curly', 'dotted', 'dashed'];
+
+// Decodifica lo snapshot binario di /screen.bin (formato in snapshot.go)
+function decodePacked(buf) {
+    const v = new DataView(buf);
+    if (buf.byteLength < 16 || v.getUint32(0) !== 0x42425353 || v.getUint8(4) !== 1) {
+        throw new Error('snapshot binario non valido');
+    }
+    const flags = v.getUint8(5);
+    const w = v.getUint16(6, true), h = v.getUint16(8, true);
+    const cells = [];
+    let off = 16;
+    for (let y = 0; y < h; y++) {
+        const row = [];
+        for (let x = 0; x < w; x++, off += 16) {
+            const a = v.getUint8(off + 13);
+            const cp = v.getUint32(off, true);
+            row.push({
+                ch: String.fromCodePoint(cp),
+                fgR: v.getUint8(off + 4), fgG: v.getUint8(off + 5), fgB: v.getUint8(off + 6),
+                bgR: v.getUint8(off + 7), bgG: v.getUint8(off + 8), bgB: v.getUint8(off + 9),
+                ulR: v.getUint8(off + 10), ulG: v.getUint8(off + 11), ulB: v.getUint8(off + 12),
+                bold: !!(a & 1), ul: !!(a & 2), blink: !!(a & 4), rev: !!(a & 8),
+                faint: !!(a & 16), italic: !!(a & 32), strike: !!(a & 64), conceal: !!(a & 128),
+                ulStyle: UL_STYLES[v.getUint8(off + 14)] || '',
+            });
+        }
+        cells.push(row);
+    }
+    return {
+        cells,
+        cursorVisible: !!(flags & 1),
+        iceColors: !!(flags & 2),
+        cursorX: v.getUint16(10, true),
+        cursorY: v.getUint16(12, true),
+    };
+}
+
+// Snapshot completo: solo per riallinearsi al backend, gli aggiornamenti
+// arrivano come differenze con screen-update. Il formato binario evita il
+// JSON di migliaia di celle; se l'asset server non risponde si usa lo
+// snapshot RLE
 async function updateScreen() {
+    try {
+        const res = await fetch('/screen.bin', { cache: 'no-store' });
+        if (res.ok) {
+            const snap = decodePacked(await res.arrayBuffer());
+            cursorX = snap.cursorX;
+            cursorY = snap.cursorY;
+            cursorVisible = snap.cursorVisible;
+            document.getElementById('btn-ice').classList.toggle('active', snap.iceColors);
+            renderScreen(snap.cells);
+            return;
+        }
+    } catch (e) {
+        console.warn('screen.bin:', e);
+    }
     try {
         // BUG-010: singola chiamata IPC invece di GetScreen + GetCursor
-        const snap = await window.go.main.App.GetScreenSnapshot();
+        // Snapshot RLE: payload molto più piccolo, espanso qui in celle
+        const snap = await window.go.main.App.GetScreenRuns();
         cursorX = snap.cursorX;
         cursorY = snap.cursorY;
-        renderScreen(snap.cells);
+        cursorVisible = snap.cursorVisible;
+        document.getElementById('btn-ice').classList.toggle('active', snap.iceColors);
+        renderScreen(expandRuns(snap.rows));
     } catch (e) {
         console.error('updateScreen error:', e);
     }
 }
 
-let updatePending = false;
-function requestScreenUpdate() {
-    if (updatePending) return;
-    updatePending = true;
-    requestAnimationFrame(() => {
-        updatePending = false;
+// Applica le righe cambiate ricevute con screen-update; il ridisegno
+// avviene al frame successivo, una volta per più aggiornamenti
+function applyScreenDelta(d) {
+    if (d.full) {
+        screenData = Array.from({ length: d.rows }, () => []);
+    } else if (!screenData || screenData.length !== d.rows) {
+        // Differenza rispetto a uno schermo che non abbiamo: snapshot
         updateScreen();
+        return;
+    }
+    for (const r of d.changed || []) {
+        screenData[r.y] = expandRuns([r.runs])[0];
+    }
+    cursorX = d.cursorX;
+    cursorY = d.cursorY;
+    cursorVisible = d.cursorVisible;
+    document.getElementById('btn-ice').classList.toggle('active', d.iceColors);
+    requestScreenRender();
+}
+
+let renderPending = false;
+function requestScreenRender() {
+    if (renderPending) return;
+    renderPending = true;
+    requestAnimationFrame(() => {
+        renderPending = false;
+        renderScreen(screenData);
     });
 }
 
@@ -226,6 +452,13 @@ function setupKeyboard() {
         e.preventDefault();
         e.stopPropagation();
 
+        // Cmd+C (Mac) o Ctrl+Shift+C → copia la selezione con i colori,
+        // con Alt come testo ANSI
+        if (selection && (e.metaKey || (e.ctrlKey && e.shiftKey)) && e.code === 'KeyC') {
+            await copySelection(e.altKey ? 'ansi' : 'html');
+            return;
+        }
+
         // Log viewer: navigazione con SPAZIO, frecce, ESC
         if (viewingLog) {
             if (e.key === ' ' || e.key === 'ArrowRight' || e.key === 'ArrowDown' || e.key === 'PageDown') {
@@ -246,18 +479,44 @@ function setupKeyboard() {
 
         if (!connected) return;
 
+        // Tasto morto: l'accento si compone nel backend con la lettera dopo
+        if (e.key === 'Dead') {
+            await window.go.main.App.SendDeadKey(e.code, e.shiftKey);
+            return;
+        }
+
+        // AltGr (Ctrl+Alt su Windows) e Option su macOS producono caratteri
+        // della tastiera nazionale (@, #, [, ]), non combinazioni di tasti
+        const altGr = e.getModifierState('AltGraph') || (e.altKey && navigator.platform.startsWith('Mac'));
+
         // Cmd+D (Mac) o Ctrl+D → disconnetti
         if ((e.metaKey || e.ctrlKey) && e.code === 'KeyD') {
             await window.go.main.App.Disconnect();
             return;
         }
 
+        // Cmd+V (Mac) o Ctrl+Shift+V → incolla (Ctrl+V resta un tasto per la BBS)
+        if ((e.metaKey || (e.ctrlKey && e.shiftKey)) && e.code === 'KeyV') {
+            const text = await window.runtime.ClipboardGetText();
+            const err = await window.go.main.App.PasteText(text || '');
+            if (err) setStatus(msgText(err));
+            return;
+        }
+
         // Ctrl+lettera
-        if (e.ctrlKey && e.key.length === 1) {
+        if (e.ctrlKey && !altGr && e.key.length === 1) {
             await window.go.main.App.SendCtrlKey(e.key);
             return;
         }
 
+        // Tastierino numerico (con Num Lock): la BBS può chiederne la
+        // modalità applicazione, il backend sceglie la sequenza
+        if (e.location === KeyboardEvent.DOM_KEY_LOCATION_NUMPAD && !e.ctrlKey && !e.altKey &&
+            (e.key.length === 1 || e.key === 'Enter')) {
+            await window.go.main.App.SendSpecialKey(e.code);
+            return;
+        }
+
         // Tasti speciali
         const specialKeys = [
             'Enter', 'Backspace', 'Tab', 'Escape',
@@ -272,13 +531,38 @@ function setupKeyboard() {
         }
 
         // Caratteri stampabili
-        if (e.key.length === 1 && !e.ctrlKey && !e.metaKey && !e.altKey) {
+        if (e.key.length === 1 && !e.metaKey && (altGr || (!e.ctrlKey && !e.altKey))) {
             await window.go.main.App.SendText(e.key);
         }
     });
 
     // Mantieni focus sul canvas
     canvas.addEventListener('click', () => canvas.focus());
+
+    // Selezione col mouse; un clic senza trascinare la toglie
+    canvas.addEventListener('mousedown', (e) => {
+        if (e.button !== 0 || !screenData) return;
+        const c = cellAt(e);
+        selection = { x0: c.x, y0: c.y, x1: c.x, y1: c.y };
+        selecting = true;
+        renderScreen(screenData);
+    });
+    canvas.addEventListener('mousemove', (e) => {
+        if (!selecting) return;
+        const c = cellAt(e);
+        if (c.x === selection.x1 && c.y === selection.y1) return;
+        selection.x1 = c.x;
+        selection.y1 = c.y;
+        renderScreen(screenData);
+    });
+    window.addEventListener('mouseup', () => {
+        if (!selecting) return;
+        selecting = false;
+        if (selection.x0 === selection.x1 && selection.y0 === selection.y1) {
+            selection = null;
+            renderScreen(screenData);
+        }
+    });
 }
 
 // ═══════════════════════════════════════════
@@ -308,9 +592,10 @@ function setupControls() {
         portInput.disabled = true;
         bbsSelect.disabled = true;
 
-        const err = await window.go.main.App.Connect(host, port, bbsName);
-        if (err) {
-            setStatus('Errore: ' + err);
+        // Connect ritorna subito: fasi ed esito arrivano come eventi
+        const res = await window.go.main.App.Connect(host, port, bbsName);
+        if (res.error) {
+            setStatus(msgText(res.error));
             btnConnect.disabled = false;
             hostInput.disabled = false;
             portInput.disabled = false;
@@ -333,23 +618,36 @@ function setupControls() {
     btnLog.addEventListener('click', async () => {
         const err = await window.go.main.App.LoadLog();
         if (err) {
-            setStatus('Errore log: ' + err);
+            setStatus(msgText(err));
+        }
+        canvas.focus();
+    });
+
+    // ANSI — viewer artwork locale
+    document.getElementById('btn-art').addEventListener('click', async () => {
+        const err = await window.go.main.App.LoadAnsiFile();
+        if (err) {
+            setStatus(msgText(err));
         }
         canvas.focus();
     });
 
-    // FONT — toggle IBM VGA / VT323
-    btnFont.addEventListener('click', () => {
-        if (currentFont === FONT_IBM_VGA) {
-            currentFont = FONT_VT323;
-            currentFontLabel = 'VT323';
+    // FONT — toggle IBM VGA / VT323, salvato nel tema. I temi predefiniti
+    // non si modificano: il cambio finisce in un tema "personalizzato".
+    btnFont.addEventListener('click', async () => {
+        const t = await window.go.main.App.GetTheme();
+        t.font = t.font === 'vt323' ? 'ibm-vga' : 'vt323';
+        const themes = await window.go.main.App.ListThemes();
+        const builtin = !t.name || themes.some((x) => x.builtin && x.name === t.name);
+        if (builtin) t.name = 'personalizzato';
+        let err = await window.go.main.App.SaveTheme(t);
+        if (!err && builtin) err = await window.go.main.App.SetTheme(t.name);
+        if (err) {
+            setStatus(msgText(err));
         } else {
-            currentFont = FONT_IBM_VGA;
-            currentFontLabel = 'IBM VGA';
+            applyTheme(t);
+            setStatus(`Font: ${currentFontLabel}`);
         }
-        btnFont.textContent = currentFontLabel;
-        resizeCanvas();
-        setStatus(`Font: ${currentFontLabel}`);
         canvas.focus();
     });
     btnFont.textContent = currentFontLabel;
@@ -366,6 +664,27 @@ function setupControls() {
         canvas.focus();
     });
 
+    // ICE toggle (per BBS, ricordato dal backend)
+    const btnIce = document.getElementById('btn-ice');
+    btnIce.addEventListener('click', async () => {
+        const enabled = !btnIce.classList.contains('active');
+        await window.go.main.App.SetIceColors(enabled);
+        btnIce.classList.toggle('active', enabled);
+        setStatus(enabled ? 'iCE colors: ON' : 'iCE colors: OFF');
+        canvas.focus();
+    });
+
+    // Emulazione (per BBS, ricordata dal backend): ANSI-BBS → VT100 → ASCII
+    document.getElementById('btn-emu').addEventListener('click', async () => {
+        const list = await window.go.main.App.GetEmulations();
+        const cur = await window.go.main.App.GetEmulation();
+        const next = list[(list.indexOf(cur) + 1) % list.length];
+        const err = await window.go.main.App.SetEmulation(next);
+        if (err) setStatus(msgText(err));
+        await refreshEmulation();
+        canvas.focus();
+    });
+
     // PULISCI
     btnClear.addEventListener('click', async () => {
         await window.go.main.App.ClearScreen();
@@ -376,11 +695,88 @@ function setupControls() {
     btnUpload.addEventListener('click', async () => {
         const err = await window.go.main.App.UploadFile();
         if (err) {
-            setStatus('Upload: ' + err);
+            setStatus('Upload: ' + msgText(err));
         }
         canvas.focus();
     });
 
+    // CAPTURE — avvia/ferma la cattura dell'output su file
+    const btnCapture = document.getElementById('btn-capture');
+    btnCapture.addEventListener('click', async () => {
+        const st = await window.go.main.App.GetCaptureStatus();
+        if (st.active) {
+            const done = await window.go.main.App.StopCapture();
+            setStatus(`Cattura salvata: ${done.path} (${done.bytes} byte)`);
+        } else {
+            const err = await window.go.main.App.StartCapture();
+            if (err) setStatus('Cattura: ' + msgText(err));
+        }
+        canvas.focus();
+    });
+    window.runtime.EventsOn('capture-status', (st) => {
+        btnCapture.classList.toggle('active', st.active);
+    });
+
+    // SHARE — schermo in sola lettura via WebSocket
+    const btnShare = document.getElementById('btn-share');
+    btnShare.addEventListener('click', async () => {
+        const st = await window.go.main.App.GetSharing();
+        if (st.active) {
+            await window.go.main.App.StopSharing();
+        } else {
+            const err = await window.go.main.App.StartSharing();
+            if (err) setStatus(msgText(err));
+        }
+        canvas.focus();
+    });
+    // Non disturbare: uscendo si mostrano gli eventi persi
+    document.getElementById('btn-dnd').addEventListener('click', async (e) => {
+        const on = !e.currentTarget.dataset.on;
+        await window.go.main.App.SetDoNotDisturb(on);
+        if (on) {
+            setStatus('Non disturbare attivo');
+            return;
+        }
+        const missed = await window.go.main.App.GetMissedEvents();
+        if (!missed.length) {
+            setStatus('Non disturbare disattivato');
+            return;
+        }
+        const bells = missed.filter((m) => m.kind === 'bell').length;
+        const watches = missed.filter((m) => m.kind === 'watch');
+        let msg = `Eventi persi: ${bells} campanelli, ${watches.length} frasi sorvegliate`;
+        if (watches.length) msg += ` — ultima: ★ ${watches[watches.length - 1].phrase}`;
+        setStatus(msg);
+        await window.go.main.App.ClearMissedEvents();
+    });
+
+    window.runtime.EventsOn('sharing-changed', (st) => {
+        btnShare.classList.toggle('active', st.active);
+        btnShare.title = st.active
+            ? `Condivisione attiva (${st.viewers} spettatori): ${st.urls[0]}`
+            : 'Condividi lo schermo in sola lettura sulla LAN';
+        if (st.active) setStatus(`Condivisione: ${st.urls[0]} · ${st.viewers} spettatori`);
+        else setStatus('Condivisione terminata');
+    });
+
+    // TESTO — invia un file di testo come tasti
+    document.getElementById('btn-sendtext').addEventListener('click', async (e) => {
+        const err = await window.go.main.App.SendTextFile(e.shiftKey);
+        if (err) {
+            setStatus('Invio testo: ' + msgText(err));
+        }
+        canvas.focus();
+    });
+
+    // LOGIN — nome utente e password salvati per la BBS
+    document.getElementById('btn-login').addEventListener('click', async () => {
+        canvas.focus();
+        const err = await window.go.main.App.SendLogin('');
+        if (err) {
+            setStatus('Login: ' + msgText(err));
+        }
+    });
+
     // About
     btnAbout.addEventListener('click', () => {
         document.getElementById('about-overlay').classList.remove('hidden');
@@ -424,6 +820,8 @@ function setUIConnected(state) {
         btnConnect.disabled = true;
         btnHangup.disabled = false;
         btnUpload.disabled = false;
+        document.getElementById('btn-sendtext').disabled = false;
+        document.getElementById('btn-login').disabled = false;
         hostInput.disabled = true;
         portInput.disabled = true;
         bbsSelect.disabled = true;
@@ -434,6 +832,8 @@ function setUIConnected(state) {
         btnConnect.disabled = false;
         btnHangup.disabled = true;
         btnUpload.disabled = true;
+        document.getElementById('btn-sendtext').disabled = true;
+        document.getElementById('btn-login').disabled = true;
         hostInput.disabled = false;
         portInput.disabled = false;
         bbsSelect.disabled = false;
@@ -441,10 +841,67 @@ function setUIConnected(state) {
     }
 }
 
+// ═══════════════════════════════════════════
+// Schede delle sessioni
+// ═══════════════════════════════════════════
+
+async function refreshTabs() {
+    renderTabs(await window.go.main.App.ListSessions());
+}
+
+function renderTabs(list) {
+    const bar = document.getElementById('session-tabs');
+    bar.innerHTML = '';
+    for (const s of list) {
+        const tab = document.createElement('span');
+        tab.className = 'session-tab' + (s.active ? ' active' : '') + (s.unread ? ' unread' : '');
+        tab.textContent = (s.connected ? '● ' : '○ ') + s.name + (s.dnd ? ' ☾' : '');
+        tab.title = `${s.host}:${s.port}` + (s.software ? ` — ${s.software}` : '');
+        tab.addEventListener('click', () => window.go.main.App.SwitchSession(s.id));
+        const close = document.createElement('span');
+        close.className = 'close';
+        close.textContent = '×';
+        close.title = 'Chiudi sessione';
+        close.addEventListener('click', (e) => {
+            e.stopPropagation();
+            window.go.main.App.CloseSession(s.id);
+        });
+        tab.appendChild(close);
+        bar.appendChild(tab);
+        if (s.active) {
+            document.getElementById('host-input').value = s.host;
+            document.getElementById('port-input').value = s.port;
+            const dnd = document.getElementById('btn-dnd');
+            dnd.classList.toggle('active', s.dnd);
+            dnd.dataset.on = s.dnd ? '1' : '';
+            dnd.textContent = s.missed ? `DND (${s.missed})` : 'DND';
+        }
+    }
+    const add = document.createElement('span');
+    add.className = 'session-tab';
+    add.textContent = '+';
+    add.title = 'Nuova sessione';
+    add.addEventListener('click', () => window.go.main.App.CreateSession());
+    bar.appendChild(add);
+}
+
+// refreshEmulation mostra l'emulazione della scheda corrente.
+async function refreshEmulation() {
+    const emu = await window.go.main.App.GetEmulation();
+    document.getElementById('btn-emu').textContent = (emu || 'ansi-bbs').toUpperCase();
+}
+
 function setStatus(text) {
     document.getElementById('status-text').textContent = text;
 }
 
+// msgText ritorna il testo di un messaggio del backend ({code, args, text},
+// già tradotto nella lingua scelta) o di una stringa semplice.
+function msgText(msg) {
+    if (!msg) return '';
+    return typeof msg === 'string' ? msg : (msg.text || msg.code);
+}
+
 // ═══════════════════════════════════════════
 // Help Overlay (Alt-Z)
 // ═══════════════════════════════════════════
@@ -535,34 +992,117 @@ let bbsList = [];
 
 function setupEvents() {
     // Screen update dal backend
-    window.runtime.EventsOn('screen-update', () => {
-        requestScreenUpdate();
+    window.runtime.EventsOn('theme-changed', applyTheme);
+    window.runtime.EventsOn('terminal-resized', onTerminalResized);
+    window.addEventListener('resize', () => {
+        clearTimeout(fitTimer);
+        fitTimer = setTimeout(fitTerminal, 150);
     });
+    window.runtime.EventsOn('screen-update', applyScreenDelta);
 
     // Connection status
     window.runtime.EventsOn('connection-status', (status) => {
         setUIConnected(status);
+        refreshEmulation();
         if (status === 'connected') {
             canvas.focus();
         }
     });
 
+    // Fasi della connessione in corso
+    const connectPhases = {
+        resolving: 'Risoluzione del nome…',
+        connecting: 'Connessione in corso…',
+        negotiating: 'Negoziazione telnet…',
+    };
+    window.runtime.EventsOn('connect-progress', (p) => {
+        if (connectPhases[p.phase]) setStatus(connectPhases[p.phase]);
+    });
+
     // Status message
     window.runtime.EventsOn('status-message', (msg) => {
-        setStatus(msg);
+        setStatus(msgText(msg));
     });
 
     // Log mode
     window.runtime.EventsOn('log-mode', (data) => {
-        if (data === false) {
+        if (!data.active) {
             viewingLog = false;
             setStatus('ANSI │ Telnet │ Offline');
-        } else if (data && data.active) {
+        } else {
             viewingLog = true;
             setStatus(`Log [${data.page}/${data.total}] — SPAZIO avanti, ← indietro, ESC esci`);
         }
     });
 
+    // Campanello (BEL): breve beep, come il PC speaker
+    window.runtime.EventsOn('sessions-changed', renderTabs);
+    window.runtime.EventsOn('session-activity', refreshTabs);
+    refreshTabs();
+
+    window.runtime.EventsOn('idle-warning', (w) => {
+        const min = Math.floor(w.idleSeconds / 60);
+        let msg = `Inattivo da ${min} min`;
+        if (w.disconnectSeconds >= 0) {
+            const m = Math.floor(w.disconnectSeconds / 60);
+            const s = String(w.disconnectSeconds % 60).padStart(2, '0');
+            msg += ` │ disconnessione tra ${m}:${s}`;
+        }
+        setStatus(msg);
+    });
+    window.runtime.EventsOn('idle-reset', () => setStatus('ANSI │ Telnet │ Online'));
+
+    // Riconnessione quando la linea cade (politica della voce in rubrica)
+    window.runtime.EventsOn('reconnect-prompt', async (ev) => {
+        if (!confirm(`La connessione con ${ev.name} è caduta. Riconnettere?`)) return;
+        const err = await window.go.main.App.Reconnect(ev.session);
+        if (err) setStatus(msgText(err));
+    });
+    window.runtime.EventsOn('reconnect-countdown', (ev) => {
+        setStatus(ev.seconds > 0
+            ? `Riconnessione a ${ev.name} tra ${ev.seconds}s (tentativo ${ev.attempt}/${ev.retries})`
+            : `Riconnessione a ${ev.name}… (tentativo ${ev.attempt}/${ev.retries})`);
+    });
+    window.runtime.EventsOn('reconnect-cancelled', (ev) => setStatus(`Riconnessione a ${ev.name} annullata`));
+
+    window.runtime.EventsOn('flood-limit', (ev) => {
+        switch (ev.limit) {
+        case 'log': setStatus(`Log oltre ${ev.value} MB: scrittura sospesa`); break;
+        case 'capture': setStatus(`Cattura oltre ${ev.value} MB: scrittura sospesa`); break;
+        case 'buffer': setStatus(`Troppi dati in arrivo: ${ev.dropped} byte scartati`); break;
+        case 'updates': setStatus(`Schermo aggiornato al massimo ${ev.value} volte al secondo`); break;
+        }
+    });
+
+    window.runtime.EventsOn('watch-match', (m) => {
+        setStatus(`★ ${m.phrase} — ${m.line}`);
+    });
+
+    window.runtime.EventsOn('bbs-software', (sw) => {
+        setStatus(`BBS: ${sw.software}${sw.version ? ' ' + sw.version : ''}`);
+    });
+
+    window.runtime.EventsOn('schedule-finished', (res) => {
+        setStatus(res.error ? msgText(res.error) : `Chiamata programmata completata: ${res.name}`);
+    });
+
+    window.runtime.EventsOn('filelist-updated', async (added) => {
+        if (!added) return;
+        const files = await window.go.main.App.GetScrapedFileList();
+        setStatus(`Lista file: ${files.length} file riconosciuti`);
+    });
+
+    window.runtime.EventsOn('paste-progress', (p) => {
+        setStatus(`Invio testo: ${p.sent}/${p.total}`);
+    });
+    window.runtime.EventsOn('paste-finished', (p) => {
+        setStatus(msgText(p.error) || (p.canceled ? 'Invio testo interrotto' : `Inviati ${p.sent} caratteri`));
+    });
+
+    window.runtime.EventsOn('bell', () => {
+        playBell();
+    });
+
     // ZMODEM events
     window.runtime.EventsOn('zmodem-started', (data) => {
         showZmodemProgress(data.filename, data.filesize);
@@ -578,19 +1118,36 @@ function setupEvents() {
     });
 }
 
+let audioCtx = null;
+function playBell() {
+    try {
+        audioCtx = audioCtx || new (window.AudioContext || window.webkitAudioContext)();
+        const osc = audioCtx.createOscillator();
+        const gain = audioCtx.createGain();
+        osc.type = 'square';
+        osc.frequency.value = 800;
+        gain.gain.value = 0.05;
+        osc.connect(gain).connect(audioCtx.destination);
+        osc.start();
+        osc.stop(audioCtx.currentTime + 0.15);
+    } catch (e) {
+        console.warn('bell:', e);
+    }
+}
+
 // ═══════════════════════════════════════════
 // BBS List
 // ═══════════════════════════════════════════
 
 async function loadBBSList() {
     try {
-        bbsList = await window.go.main.App.GetBBSList();
+        bbsList = await window.go.main.App.GetBBSList({});
         const select = document.getElementById('bbs-select');
         select.innerHTML = '';
         let defaultIdx = 0;
         bbsList.forEach((entry, i) => {
             const opt = document.createElement('option');
-            opt.textContent = entry.name;
+            opt.textContent = (entry.favorite ? '★ ' : '') + entry.name;
             opt.value = i;
             select.appendChild(opt);
             // Cerca Metro Olografix come default
@@ -616,7 +1173,7 @@ async function loadBBSList() {
 document.addEventListener('DOMContentLoaded', async () => {
     // Aspetta che il font IBM VGA sia caricato prima di misurare le celle
     try {
-        await document.fonts.load(`${FONT_SIZE}px "IBM VGA"`);
+        await document.fonts.load(`${fontSize}px "IBM VGA"`);
         await document.fonts.ready;
     } catch (e) {
         console.warn('Font preload warning:', e);
@@ -642,12 +1199,14 @@ document.addEventListener('DOMContentLoaded', async () => {
     });
 
     setupEvents();
+    onTerminalResized(await window.go.main.App.GetTerminalSize());
+    applyTheme(await window.go.main.App.GetTheme());
     await loadBBSList();
 
     // Messaggio iniziale
     ctx.fillStyle = '#000';
     ctx.fillRect(0, 0, canvas.width / dpr, canvas.height / dpr);
-    ctx.font = `${FONT_SIZE}px ${currentFont}`;
+    ctx.font = `${fontSize}px ${currentFont}`;
     ctx.fillStyle = '#FFFF55';
     ctx.fillText('BBS Client for Gen-Z v1.1.0 — Pronto', 10, 20);
     ctx.fillStyle = '#55FFFF';
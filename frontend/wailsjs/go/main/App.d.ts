@@ -1,28 +1,164 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
+import {addressbook} from '../models';
+import {i18n} from '../models';
 import {main} from '../models';
+import {config} from '../models';
+import {sauce} from '../models';
+import {filelist} from '../models';
+import {bluewave} from '../models';
+
+export function AddBBS(arg1:addressbook.Entry):Promise<i18n.Message>;
+
+export function CancelConnect(arg1:string):Promise<i18n.Message>;
+
+export function CancelPaste():Promise<void>;
+
+export function CancelReconnect(arg1:string):Promise<void>;
 
 export function CancelZmodem():Promise<void>;
 
+export function ClearMissedEvents():Promise<void>;
+
+export function ClearPacing():Promise<i18n.Message>;
+
+export function ClearScrapedFileList():Promise<void>;
+
 export function ClearScreen():Promise<void>;
 
-export function Connect(arg1:string,arg2:number,arg3:string):Promise<string>;
+export function CloseSession(arg1:string):Promise<i18n.Message>;
+
+export function Connect(arg1:string,arg2:number,arg3:string):Promise<main.ConnectResult>;
+
+export function CopyScrollback():Promise<main.SelectionResult>;
+
+export function CopySelection(arg1:number,arg2:number,arg3:number,arg4:number,arg5:string):Promise<main.SelectionResult>;
+
+export function CreateSession():Promise<main.SessionInfo>;
+
+export function DeleteBBS(arg1:string):Promise<i18n.Message>;
+
+export function DeleteProxy(arg1:string):Promise<i18n.Message>;
+
+export function DeleteSchedule(arg1:string):Promise<i18n.Message>;
+
+export function DeleteTheme(arg1:string):Promise<i18n.Message>;
 
 export function Disconnect():Promise<void>;
 
-export function GetBBSList():Promise<Array<main.BBSEntry>>;
+export function EventTypes():Promise<main.EventPayloads>;
+
+export function ExportGIF(arg1:number,arg2:number):Promise<i18n.Message>;
+
+export function ExportLogAsText(arg1:string):Promise<i18n.Message>;
+
+export function ExportLogHTML():Promise<i18n.Message>;
+
+export function ExportPNG():Promise<i18n.Message>;
+
+export function ExportPhonebook(arg1:string,arg2:string):Promise<i18n.Message>;
+
+export function ForgetHostKey(arg1:string,arg2:number):Promise<i18n.Message>;
+
+export function GetBBSList(arg1:addressbook.Query):Promise<Array<addressbook.Entry>>;
+
+export function GetBBSTags():Promise<Array<string>>;
+
+export function GetBitmapFont(arg1:string):Promise<main.BitmapFontResult>;
+
+export function GetBoldPolicy():Promise<string>;
+
+export function GetCaptureStatus():Promise<main.CaptureStatus>;
+
+export function GetCastRecording():Promise<boolean>;
+
+export function GetCharset():Promise<string>;
+
+export function GetCharsets():Promise<Array<string>>;
+
+export function GetConnectionStats():Promise<main.ConnectionStats>;
+
+export function GetControl():Promise<main.ControlInfo>;
 
 export function GetCursor():Promise<Record<string, number>>;
 
+export function GetEmulation():Promise<string>;
+
+export function GetEmulations():Promise<Array<string>>;
+
+export function GetEventLog():Promise<boolean>;
+
+export function GetIceColors():Promise<boolean>;
+
+export function GetKeyProfile():Promise<string>;
+
+export function GetKeyProfiles():Promise<Array<string>>;
+
+export function GetKeyboardLayouts():Promise<Array<string>>;
+
+export function GetLanguages():Promise<Array<string>>;
+
+export function GetLogOptions():Promise<config.Logging>;
+
+export function GetLogin():Promise<string>;
+
+export function GetMissedEvents():Promise<Array<main.MissedEvent>>;
+
+export function GetPacing():Promise<config.Pacing>;
+
+export function GetPalette():Promise<main.PaletteInfo>;
+
+export function GetPlugins():Promise<Array<config.Plugin>>;
+
+export function GetProxies():Promise<Array<config.Proxy>>;
+
+export function GetProxyTypes():Promise<Array<string>>;
+
+export function GetSauce():Promise<sauce.Record>;
+
+export function GetSauceFont():Promise<main.BitmapFontResult>;
+
+export function GetSchedules():Promise<Array<config.Schedule>>;
+
+export function GetScrapedFileList():Promise<Array<filelist.Entry>>;
+
 export function GetScreen():Promise<Array<any>>;
 
+export function GetScreenPacked():Promise<string>;
+
+export function GetScreenRegion(arg1:number,arg2:number,arg3:number,arg4:number):Promise<Array<any>>;
+
+export function GetScreenRuns():Promise<main.RunSnapshot>;
+
 export function GetScreenSnapshot():Promise<main.ScreenSnapshot>;
 
+export function GetSettings():Promise<config.Settings>;
+
+export function GetSharing():Promise<main.ShareInfo>;
+
+export function GetTerminalSize():Promise<main.TerminalSize>;
+
+export function GetTheme():Promise<config.Theme>;
+
+export function GetWatchPhrases(arg1:string):Promise<Array<string>>;
+
+export function ImportPhonebook():Promise<main.ImportResult>;
+
 export function IsConnected():Promise<boolean>;
 
 export function IsViewingLog():Promise<boolean>;
 
-export function LoadLog():Promise<string>;
+export function ListBitmapFonts():Promise<Array<main.BitmapFontInfo>>;
+
+export function ListPalettes():Promise<Array<string>>;
+
+export function ListSessions():Promise<Array<main.SessionInfo>>;
+
+export function ListThemes():Promise<Array<main.ThemeInfo>>;
+
+export function LoadAnsiFile():Promise<i18n.Message>;
+
+export function LoadLog():Promise<i18n.Message>;
 
 export function LogExit():Promise<void>;
 
@@ -30,12 +166,144 @@ export function LogNextPage():Promise<void>;
 
 export function LogPrevPage():Promise<void>;
 
+export function MarkScrapedFile(arg1:string,arg2:boolean):Promise<boolean>;
+
+export function OpenMailPacket():Promise<main.MailPacketResult>;
+
+export function OpenURI(arg1:string):Promise<i18n.Message>;
+
+export function PasteText(arg1:string):Promise<i18n.Message>;
+
+export function PausePlayback():Promise<void>;
+
+export function Reconnect(arg1:string):Promise<i18n.Message>;
+
+export function ReorderBBS(arg1:Array<string>):Promise<i18n.Message>;
+
+export function ResetSettings():Promise<i18n.Message>;
+
+export function ResumePlayback():Promise<void>;
+
+export function RunScheduleNow(arg1:string):Promise<i18n.Message>;
+
+export function SaveMailReplies(arg1:Array<bluewave.Reply>):Promise<i18n.Message>;
+
+export function SaveSchedule(arg1:config.Schedule):Promise<i18n.Message>;
+
+export function SaveScrollback(arg1:string):Promise<i18n.Message>;
+
+export function SaveTheme(arg1:config.Theme):Promise<i18n.Message>;
+
+export function SeekPlayback(arg1:number):Promise<void>;
+
 export function SendCtrlKey(arg1:string):Promise<void>;
 
+export function SendDeadKey(arg1:string,arg2:boolean):Promise<void>;
+
 export function SendKey(arg1:Array<number>):Promise<void>;
 
+export function SendLine(arg1:string):Promise<i18n.Message>;
+
+export function SendLogin(arg1:string):Promise<i18n.Message>;
+
+export function SendMarkedFiles():Promise<i18n.Message>;
+
 export function SendSpecialKey(arg1:string):Promise<void>;
 
 export function SendText(arg1:string):Promise<void>;
 
-export function UploadFile():Promise<string>;
+export function SendTextFile(arg1:boolean):Promise<i18n.Message>;
+
+export function SetAccessibility(arg1:boolean):Promise<i18n.Message>;
+
+export function SetBBSTheme(arg1:string,arg2:string):Promise<i18n.Message>;
+
+export function SetBoldPolicy(arg1:string):Promise<i18n.Message>;
+
+export function SetC1Controls(arg1:boolean):Promise<i18n.Message>;
+
+export function SetCastRecording(arg1:boolean):Promise<i18n.Message>;
+
+export function SetCharset(arg1:string):Promise<i18n.Message>;
+
+export function SetCloseToTray(arg1:boolean):Promise<i18n.Message>;
+
+export function SetControlEnabled(arg1:boolean):Promise<i18n.Message>;
+
+export function SetDoNotDisturb(arg1:boolean):Promise<void>;
+
+export function SetDownloads(arg1:string):Promise<i18n.Message>;
+
+export function SetEmulation(arg1:string):Promise<i18n.Message>;
+
+export function SetEventLog(arg1:boolean):Promise<i18n.Message>;
+
+export function SetFlowControl(arg1:boolean):Promise<i18n.Message>;
+
+export function SetIceColors(arg1:boolean):Promise<i18n.Message>;
+
+export function SetKeyProfile(arg1:string):Promise<i18n.Message>;
+
+export function SetKeyboardLayout(arg1:string):Promise<i18n.Message>;
+
+export function SetLanguage(arg1:string):Promise<i18n.Message>;
+
+export function SetLogOptions(arg1:config.Logging):Promise<i18n.Message>;
+
+export function SetLogSplit(arg1:boolean,arg2:number):Promise<i18n.Message>;
+
+export function SetLogin(arg1:string,arg2:string):Promise<i18n.Message>;
+
+export function SetPacing(arg1:number,arg2:number):Promise<i18n.Message>;
+
+export function SetPalette(arg1:string,arg2:Array<string>):Promise<i18n.Message>;
+
+export function SetPlaybackScale(arg1:number):Promise<void>;
+
+export function SetPlaybackSpeed(arg1:number):Promise<void>;
+
+export function SetPlugins(arg1:Array<config.Plugin>):Promise<i18n.Message>;
+
+export function SetPrinter(arg1:string):Promise<i18n.Message>;
+
+export function SetProxy(arg1:config.Proxy,arg2:string):Promise<i18n.Message>;
+
+export function SetSettings(arg1:config.Settings):Promise<i18n.Message>;
+
+export function SetTerminalSize(arg1:number,arg2:number):Promise<main.TerminalSize>;
+
+export function SetTheme(arg1:string):Promise<i18n.Message>;
+
+export function SetTorAddress(arg1:string):Promise<i18n.Message>;
+
+export function SetWatchNotify(arg1:boolean):Promise<i18n.Message>;
+
+export function SetWatchPhrases(arg1:string,arg2:Array<string>):Promise<i18n.Message>;
+
+export function StartBridge(arg1:main.BridgeSpec):Promise<i18n.Message>;
+
+export function StartCapture():Promise<i18n.Message>;
+
+export function StartPlayback(arg1:number):Promise<i18n.Message>;
+
+export function StartSharing():Promise<i18n.Message>;
+
+export function StartTimedPlayback(arg1:number):Promise<i18n.Message>;
+
+export function StepPlayback():Promise<void>;
+
+export function StopBridge():Promise<void>;
+
+export function StopCapture():Promise<main.CaptureStatus>;
+
+export function StopPlayback():Promise<void>;
+
+export function StopSharing():Promise<void>;
+
+export function SwitchSession(arg1:string):Promise<i18n.Message>;
+
+export function UpdateBBS(arg1:addressbook.Entry):Promise<i18n.Message>;
+
+export function UploadFile():Promise<i18n.Message>;
+
+export function WaitForString(arg1:string,arg2:number):Promise<main.WaitResult>;
@@ -2,38 +2,278 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function AddBBS(arg1) {
+  return window['go']['main']['App']['AddBBS'](arg1);
+}
+
+export function CancelConnect(arg1) {
+  return window['go']['main']['App']['CancelConnect'](arg1);
+}
+
+export function CancelPaste() {
+  return window['go']['main']['App']['CancelPaste']();
+}
+
+export function CancelReconnect(arg1) {
+  return window['go']['main']['App']['CancelReconnect'](arg1);
+}
+
 export function CancelZmodem() {
   return window['go']['main']['App']['CancelZmodem']();
 }
 
+export function ClearMissedEvents() {
+  return window['go']['main']['App']['ClearMissedEvents']();
+}
+
+export function ClearPacing() {
+  return window['go']['main']['App']['ClearPacing']();
+}
+
+export function ClearScrapedFileList() {
+  return window['go']['main']['App']['ClearScrapedFileList']();
+}
+
 export function ClearScreen() {
   return window['go']['main']['App']['ClearScreen']();
 }
 
+export function CloseSession(arg1) {
+  return window['go']['main']['App']['CloseSession'](arg1);
+}
+
 export function Connect(arg1, arg2, arg3) {
   return window['go']['main']['App']['Connect'](arg1, arg2, arg3);
 }
 
+export function CopyScrollback() {
+  return window['go']['main']['App']['CopyScrollback']();
+}
+
+export function CopySelection(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['main']['App']['CopySelection'](arg1, arg2, arg3, arg4, arg5);
+}
+
+export function CreateSession() {
+  return window['go']['main']['App']['CreateSession']();
+}
+
+export function DeleteBBS(arg1) {
+  return window['go']['main']['App']['DeleteBBS'](arg1);
+}
+
+export function DeleteProxy(arg1) {
+  return window['go']['main']['App']['DeleteProxy'](arg1);
+}
+
+export function DeleteSchedule(arg1) {
+  return window['go']['main']['App']['DeleteSchedule'](arg1);
+}
+
+export function DeleteTheme(arg1) {
+  return window['go']['main']['App']['DeleteTheme'](arg1);
+}
+
 export function Disconnect() {
   return window['go']['main']['App']['Disconnect']();
 }
 
-export function GetBBSList() {
-  return window['go']['main']['App']['GetBBSList']();
+export function EventTypes() {
+  return window['go']['main']['App']['EventTypes']();
+}
+
+export function ExportGIF(arg1, arg2) {
+  return window['go']['main']['App']['ExportGIF'](arg1, arg2);
+}
+
+export function ExportLogAsText(arg1) {
+  return window['go']['main']['App']['ExportLogAsText'](arg1);
+}
+
+export function ExportLogHTML() {
+  return window['go']['main']['App']['ExportLogHTML']();
+}
+
+export function ExportPNG() {
+  return window['go']['main']['App']['ExportPNG']();
+}
+
+export function ExportPhonebook(arg1, arg2) {
+  return window['go']['main']['App']['ExportPhonebook'](arg1, arg2);
+}
+
+export function ForgetHostKey(arg1, arg2) {
+  return window['go']['main']['App']['ForgetHostKey'](arg1, arg2);
+}
+
+export function GetBBSList(arg1) {
+  return window['go']['main']['App']['GetBBSList'](arg1);
+}
+
+export function GetBBSTags() {
+  return window['go']['main']['App']['GetBBSTags']();
+}
+
+export function GetBitmapFont(arg1) {
+  return window['go']['main']['App']['GetBitmapFont'](arg1);
+}
+
+export function GetBoldPolicy() {
+  return window['go']['main']['App']['GetBoldPolicy']();
+}
+
+export function GetCaptureStatus() {
+  return window['go']['main']['App']['GetCaptureStatus']();
+}
+
+export function GetCastRecording() {
+  return window['go']['main']['App']['GetCastRecording']();
+}
+
+export function GetCharset() {
+  return window['go']['main']['App']['GetCharset']();
+}
+
+export function GetCharsets() {
+  return window['go']['main']['App']['GetCharsets']();
+}
+
+export function GetConnectionStats() {
+  return window['go']['main']['App']['GetConnectionStats']();
+}
+
+export function GetControl() {
+  return window['go']['main']['App']['GetControl']();
 }
 
 export function GetCursor() {
   return window['go']['main']['App']['GetCursor']();
 }
 
+export function GetEmulation() {
+  return window['go']['main']['App']['GetEmulation']();
+}
+
+export function GetEmulations() {
+  return window['go']['main']['App']['GetEmulations']();
+}
+
+export function GetEventLog() {
+  return window['go']['main']['App']['GetEventLog']();
+}
+
+export function GetIceColors() {
+  return window['go']['main']['App']['GetIceColors']();
+}
+
+export function GetKeyProfile() {
+  return window['go']['main']['App']['GetKeyProfile']();
+}
+
+export function GetKeyProfiles() {
+  return window['go']['main']['App']['GetKeyProfiles']();
+}
+
+export function GetKeyboardLayouts() {
+  return window['go']['main']['App']['GetKeyboardLayouts']();
+}
+
+export function GetLanguages() {
+  return window['go']['main']['App']['GetLanguages']();
+}
+
+export function GetLogOptions() {
+  return window['go']['main']['App']['GetLogOptions']();
+}
+
+export function GetLogin() {
+  return window['go']['main']['App']['GetLogin']();
+}
+
+export function GetMissedEvents() {
+  return window['go']['main']['App']['GetMissedEvents']();
+}
+
+export function GetPacing() {
+  return window['go']['main']['App']['GetPacing']();
+}
+
+export function GetPalette() {
+  return window['go']['main']['App']['GetPalette']();
+}
+
+export function GetPlugins() {
+  return window['go']['main']['App']['GetPlugins']();
+}
+
+export function GetProxies() {
+  return window['go']['main']['App']['GetProxies']();
+}
+
+export function GetProxyTypes() {
+  return window['go']['main']['App']['GetProxyTypes']();
+}
+
+export function GetSauce() {
+  return window['go']['main']['App']['GetSauce']();
+}
+
+export function GetSauceFont() {
+  return window['go']['main']['App']['GetSauceFont']();
+}
+
+export function GetSchedules() {
+  return window['go']['main']['App']['GetSchedules']();
+}
+
+export function GetScrapedFileList() {
+  return window['go']['main']['App']['GetScrapedFileList']();
+}
+
 export function GetScreen() {
   return window['go']['main']['App']['GetScreen']();
 }
 
+export function GetScreenPacked() {
+  return window['go']['main']['App']['GetScreenPacked']();
+}
+
+export function GetScreenRegion(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['GetScreenRegion'](arg1, arg2, arg3, arg4);
+}
+
+export function GetScreenRuns() {
+  return window['go']['main']['App']['GetScreenRuns']();
+}
+
 export function GetScreenSnapshot() {
   return window['go']['main']['App']['GetScreenSnapshot']();
 }
 
+export function GetSettings() {
+  return window['go']['main']['App']['GetSettings']();
+}
+
+export function GetSharing() {
+  return window['go']['main']['App']['GetSharing']();
+}
+
+export function GetTerminalSize() {
+  return window['go']['main']['App']['GetTerminalSize']();
+}
+
+export function GetTheme() {
+  return window['go']['main']['App']['GetTheme']();
+}
+
+export function GetWatchPhrases(arg1) {
+  return window['go']['main']['App']['GetWatchPhrases'](arg1);
+}
+
+export function ImportPhonebook() {
+  return window['go']['main']['App']['ImportPhonebook']();
+}
+
 export function IsConnected() {
   return window['go']['main']['App']['IsConnected']();
 }
@@ -42,6 +282,26 @@ export function IsViewingLog() {
   return window['go']['main']['App']['IsViewingLog']();
 }
 
+export function ListBitmapFonts() {
+  return window['go']['main']['App']['ListBitmapFonts']();
+}
+
+export function ListPalettes() {
+  return window['go']['main']['App']['ListPalettes']();
+}
+
+export function ListSessions() {
+  return window['go']['main']['App']['ListSessions']();
+}
+
+export function ListThemes() {
+  return window['go']['main']['App']['ListThemes']();
+}
+
+export function LoadAnsiFile() {
+  return window['go']['main']['App']['LoadAnsiFile']();
+}
+
 export function LoadLog() {
   return window['go']['main']['App']['LoadLog']();
 }
@@ -58,14 +318,90 @@ export function LogPrevPage() {
   return window['go']['main']['App']['LogPrevPage']();
 }
 
+export function MarkScrapedFile(arg1, arg2) {
+  return window['go']['main']['App']['MarkScrapedFile'](arg1, arg2);
+}
+
+export function OpenMailPacket() {
+  return window['go']['main']['App']['OpenMailPacket']();
+}
+
+export function OpenURI(arg1) {
+  return window['go']['main']['App']['OpenURI'](arg1);
+}
+
+export function PasteText(arg1) {
+  return window['go']['main']['App']['PasteText'](arg1);
+}
+
+export function PausePlayback() {
+  return window['go']['main']['App']['PausePlayback']();
+}
+
+export function Reconnect(arg1) {
+  return window['go']['main']['App']['Reconnect'](arg1);
+}
+
+export function ReorderBBS(arg1) {
+  return window['go']['main']['App']['ReorderBBS'](arg1);
+}
+
+export function ResetSettings() {
+  return window['go']['main']['App']['ResetSettings']();
+}
+
+export function ResumePlayback() {
+  return window['go']['main']['App']['ResumePlayback']();
+}
+
+export function RunScheduleNow(arg1) {
+  return window['go']['main']['App']['RunScheduleNow'](arg1);
+}
+
+export function SaveMailReplies(arg1) {
+  return window['go']['main']['App']['SaveMailReplies'](arg1);
+}
+
+export function SaveSchedule(arg1) {
+  return window['go']['main']['App']['SaveSchedule'](arg1);
+}
+
+export function SaveScrollback(arg1) {
+  return window['go']['main']['App']['SaveScrollback'](arg1);
+}
+
+export function SaveTheme(arg1) {
+  return window['go']['main']['App']['SaveTheme'](arg1);
+}
+
+export function SeekPlayback(arg1) {
+  return window['go']['main']['App']['SeekPlayback'](arg1);
+}
+
 export function SendCtrlKey(arg1) {
   return window['go']['main']['App']['SendCtrlKey'](arg1);
 }
 
+export function SendDeadKey(arg1, arg2) {
+  return window['go']['main']['App']['SendDeadKey'](arg1, arg2);
+}
+
 export function SendKey(arg1) {
   return window['go']['main']['App']['SendKey'](arg1);
 }
 
+export function SendLine(arg1) {
+  return window['go']['main']['App']['SendLine'](arg1);
+}
+
+export function SendLogin(arg1) {
+  return window['go']['main']['App']['SendLogin'](arg1);
+}
+
+export function SendMarkedFiles() {
+  return window['go']['main']['App']['SendMarkedFiles']();
+}
+
 export function SendSpecialKey(arg1) {
   return window['go']['main']['App']['SendSpecialKey'](arg1);
 }
@@ -74,6 +410,194 @@ export function SendText(arg1) {
   return window['go']['main']['App']['SendText'](arg1);
 }
 
+export function SendTextFile(arg1) {
+  return window['go']['main']['App']['SendTextFile'](arg1);
+}
+
+export function SetAccessibility(arg1) {
+  return window['go']['main']['App']['SetAccessibility'](arg1);
+}
+
+export function SetBBSTheme(arg1, arg2) {
+  return window['go']['main']['App']['SetBBSTheme'](arg1, arg2);
+}
+
+export function SetBoldPolicy(arg1) {
+  return window['go']['main']['App']['SetBoldPolicy'](arg1);
+}
+
+export function SetC1Controls(arg1) {
+  return window['go']['main']['App']['SetC1Controls'](arg1);
+}
+
+export function SetCastRecording(arg1) {
+  return window['go']['main']['App']['SetCastRecording'](arg1);
+}
+
+export function SetCharset(arg1) {
+  return window['go']['main']['App']['SetCharset'](arg1);
+}
+
+export function SetCloseToTray(arg1) {
+  return window['go']['main']['App']['SetCloseToTray'](arg1);
+}
+
+export function SetControlEnabled(arg1) {
+  return window['go']['main']['App']['SetControlEnabled'](arg1);
+}
+
+export function SetDoNotDisturb(arg1) {
+  return window['go']['main']['App']['SetDoNotDisturb'](arg1);
+}
+
+export function SetDownloads(arg1) {
+  return window['go']['main']['App']['SetDownloads'](arg1);
+}
+
+export function SetEmulation(arg1) {
+  return window['go']['main']['App']['SetEmulation'](arg1);
+}
+
+export function SetEventLog(arg1) {
+  return window['go']['main']['App']['SetEventLog'](arg1);
+}
+
+export function SetFlowControl(arg1) {
+  return window['go']['main']['App']['SetFlowControl'](arg1);
+}
+
+export function SetIceColors(arg1) {
+  return window['go']['main']['App']['SetIceColors'](arg1);
+}
+
+export function SetKeyProfile(arg1) {
+  return window['go']['main']['App']['SetKeyProfile'](arg1);
+}
+
+export function SetKeyboardLayout(arg1) {
+  return window['go']['main']['App']['SetKeyboardLayout'](arg1);
+}
+
+export function SetLanguage(arg1) {
+  return window['go']['main']['App']['SetLanguage'](arg1);
+}
+
+export function SetLogOptions(arg1) {
+  return window['go']['main']['App']['SetLogOptions'](arg1);
+}
+
+export function SetLogSplit(arg1, arg2) {
+  return window['go']['main']['App']['SetLogSplit'](arg1, arg2);
+}
+
+export function SetLogin(arg1, arg2) {
+  return window['go']['main']['App']['SetLogin'](arg1, arg2);
+}
+
+export function SetPacing(arg1, arg2) {
+  return window['go']['main']['App']['SetPacing'](arg1, arg2);
+}
+
+export function SetPalette(arg1, arg2) {
+  return window['go']['main']['App']['SetPalette'](arg1, arg2);
+}
+
+export function SetPlaybackScale(arg1) {
+  return window['go']['main']['App']['SetPlaybackScale'](arg1);
+}
+
+export function SetPlaybackSpeed(arg1) {
+  return window['go']['main']['App']['SetPlaybackSpeed'](arg1);
+}
+
+export function SetPlugins(arg1) {
+  return window['go']['main']['App']['SetPlugins'](arg1);
+}
+
+export function SetPrinter(arg1) {
+  return window['go']['main']['App']['SetPrinter'](arg1);
+}
+
+export function SetProxy(arg1, arg2) {
+  return window['go']['main']['App']['SetProxy'](arg1, arg2);
+}
+
+export function SetSettings(arg1) {
+  return window['go']['main']['App']['SetSettings'](arg1);
+}
+
+export function SetTerminalSize(arg1, arg2) {
+  return window['go']['main']['App']['SetTerminalSize'](arg1, arg2);
+}
+
+export function SetTheme(arg1) {
+  return window['go']['main']['App']['SetTheme'](arg1);
+}
+
+export function SetTorAddress(arg1) {
+  return window['go']['main']['App']['SetTorAddress'](arg1);
+}
+
+export function SetWatchNotify(arg1) {
+  return window['go']['main']['App']['SetWatchNotify'](arg1);
+}
+
+export function SetWatchPhrases(arg1, arg2) {
+  return window['go']['main']['App']['SetWatchPhrases'](arg1, arg2);
+}
+
+export function StartBridge(arg1) {
+  return window['go']['main']['App']['StartBridge'](arg1);
+}
+
+export function StartCapture() {
+  return window['go']['main']['App']['StartCapture']();
+}
+
+export function StartPlayback(arg1) {
+  return window['go']['main']['App']['StartPlayback'](arg1);
+}
+
+export function StartSharing() {
+  return window['go']['main']['App']['StartSharing']();
+}
+
+export function StartTimedPlayback(arg1) {
+  return window['go']['main']['App']['StartTimedPlayback'](arg1);
+}
+
+export function StepPlayback() {
+  return window['go']['main']['App']['StepPlayback']();
+}
+
+export function StopBridge() {
+  return window['go']['main']['App']['StopBridge']();
+}
+
+export function StopCapture() {
+  return window['go']['main']['App']['StopCapture']();
+}
+
+export function StopPlayback() {
+  return window['go']['main']['App']['StopPlayback']();
+}
+
+export function StopSharing() {
+  return window['go']['main']['App']['StopSharing']();
+}
+
+export function SwitchSession(arg1) {
+  return window['go']['main']['App']['SwitchSession'](arg1);
+}
+
+export function UpdateBBS(arg1) {
+  return window['go']['main']['App']['UpdateBBS'](arg1);
+}
+
 export function UploadFile() {
   return window['go']['main']['App']['UploadFile']();
 }
+
+export function WaitForString(arg1, arg2) {
+  return window['go']['main']['App']['WaitForString'](arg1, arg2);
+}
@@ -1,57 +1,1803 @@
+export namespace addressbook {
+	
+	export class LastUsed {
+	    emulation: string;
+	    iceColors: boolean;
+	    theme?: string;
+	    cols: number;
+	    rows: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new LastUsed(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.emulation = source["emulation"];
+	        this.iceColors = source["iceColors"];
+	        this.theme = source["theme"];
+	        this.cols = source["cols"];
+	        this.rows = source["rows"];
+	    }
+	}
+	export class Entry {
+	    id: string;
+	    name: string;
+	    host: string;
+	    port: number;
+	    protocol?: string;
+	    proxy?: string;
+	    font?: string;
+	    favorite: boolean;
+	    public: boolean;
+	    hidden?: boolean;
+	    software?: string;
+	    tags?: string[];
+	    notes?: string;
+	    reconnect?: string;
+	    reconnectRetries?: number;
+	    rows?: number;
+	    statusLine?: boolean;
+	    logoff?: string;
+	    // Go type: time
+	    lastConnected: any;
+	    totalCalls: number;
+	    totalOnline: number;
+	    totalBytes: number;
+	    lastUsed?: LastUsed;
+	
+	    static createFrom(source: any = {}) {
+	        return new Entry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.host = source["host"];
+	        this.port = source["port"];
+	        this.protocol = source["protocol"];
+	        this.proxy = source["proxy"];
+	        this.font = source["font"];
+	        this.favorite = source["favorite"];
+	        this.public = source["public"];
+	        this.hidden = source["hidden"];
+	        this.software = source["software"];
+	        this.tags = source["tags"];
+	        this.notes = source["notes"];
+	        this.reconnect = source["reconnect"];
+	        this.reconnectRetries = source["reconnectRetries"];
+	        this.rows = source["rows"];
+	        this.statusLine = source["statusLine"];
+	        this.logoff = source["logoff"];
+	        this.lastConnected = this.convertValues(source["lastConnected"], null);
+	        this.totalCalls = source["totalCalls"];
+	        this.totalOnline = source["totalOnline"];
+	        this.totalBytes = source["totalBytes"];
+	        this.lastUsed = this.convertValues(source["lastUsed"], LastUsed);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class Query {
+	    text: string;
+	    tags: string[];
+	    software: string;
+	    favorites: boolean;
+	    sort: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Query(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.text = source["text"];
+	        this.tags = source["tags"];
+	        this.software = source["software"];
+	        this.favorites = source["favorites"];
+	        this.sort = source["sort"];
+	    }
+	}
+
+}
+
+export namespace bitmapfont {
+	
+	export class Font {
+	    name: string;
+	    width: number;
+	    height: number;
+	    glyphs: number;
+	    stride: number;
+	    bitmap: number[];
+	    unicode: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new Font(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.width = source["width"];
+	        this.height = source["height"];
+	        this.glyphs = source["glyphs"];
+	        this.stride = source["stride"];
+	        this.bitmap = source["bitmap"];
+	        this.unicode = source["unicode"];
+	    }
+	}
+
+}
+
+export namespace bluewave {
+	
+	export class Message {
+	    number: number;
+	    from: string;
+	    to: string;
+	    subject: string;
+	    date: string;
+	    replyTo: number;
+	    flags: number;
+	    text: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Message(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.number = source["number"];
+	        this.from = source["from"];
+	        this.to = source["to"];
+	        this.subject = source["subject"];
+	        this.date = source["date"];
+	        this.replyTo = source["replyTo"];
+	        this.flags = source["flags"];
+	        this.text = source["text"];
+	    }
+	}
+	export class Area {
+	    number: string;
+	    tag: string;
+	    title: string;
+	    flags: number;
+	    networkType: number;
+	    messages: Message[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Area(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.number = source["number"];
+	        this.tag = source["tag"];
+	        this.title = source["title"];
+	        this.flags = source["flags"];
+	        this.networkType = source["networkType"];
+	        this.messages = this.convertValues(source["messages"], Message);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class Packet {
+	    id: string;
+	    version: number;
+	    bbs: string;
+	    sysop: string;
+	    loginName: string;
+	    aliasName: string;
+	    areas: Area[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Packet(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.version = source["version"];
+	        this.bbs = source["bbs"];
+	        this.sysop = source["sysop"];
+	        this.loginName = source["loginName"];
+	        this.aliasName = source["aliasName"];
+	        this.areas = this.convertValues(source["areas"], Area);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Reply {
+	    area: string;
+	    to: string;
+	    subject: string;
+	    text: string;
+	    replyTo: number;
+	    private: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new Reply(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.area = source["area"];
+	        this.to = source["to"];
+	        this.subject = source["subject"];
+	        this.text = source["text"];
+	        this.replyTo = source["replyTo"];
+	        this.private = source["private"];
+	    }
+	}
+
+}
+
+export namespace config {
+	
+	export class Control {
+	    enabled: boolean;
+	    port: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Control(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.port = source["port"];
+	    }
+	}
+	export class FileTypes {
+	    blocked: string[];
+	    warned: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new FileTypes(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.blocked = source["blocked"];
+	        this.warned = source["warned"];
+	    }
+	}
+	export class IEMSI {
+	    enabled: boolean;
+	    alias?: string;
+	    location?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new IEMSI(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.alias = source["alias"];
+	        this.location = source["location"];
+	    }
+	}
+	export class Idle {
+	    warnMinutes: number;
+	    keepaliveMinutes: number;
+	    keepaliveText: string;
+	    disconnectMinutes: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Idle(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.warnMinutes = source["warnMinutes"];
+	        this.keepaliveMinutes = source["keepaliveMinutes"];
+	        this.keepaliveText = source["keepaliveText"];
+	        this.disconnectMinutes = source["disconnectMinutes"];
+	    }
+	}
+	export class Limits {
+	    logSizeMB: number;
+	    dataBuffer: number;
+	    updatesPerSec: number;
+	    scrollbackLines: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Limits(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.logSizeMB = source["logSizeMB"];
+	        this.dataBuffer = source["dataBuffer"];
+	        this.updatesPerSec = source["updatesPerSec"];
+	        this.scrollbackLines = source["scrollbackLines"];
+	    }
+	}
+	export class Logging {
+	    ansi: boolean;
+	    transcript: boolean;
+	    asciicast: boolean;
+	    raw: boolean;
+	    events: boolean;
+	    splitDaily: boolean;
+	    splitHours: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Logging(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.ansi = source["ansi"];
+	        this.transcript = source["transcript"];
+	        this.asciicast = source["asciicast"];
+	        this.raw = source["raw"];
+	        this.events = source["events"];
+	        this.splitDaily = source["splitDaily"];
+	        this.splitHours = source["splitHours"];
+	    }
+	}
+	export class Pacing {
+	    charDelayMs: number;
+	    lineDelayMs: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Pacing(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.charDelayMs = source["charDelayMs"];
+	        this.lineDelayMs = source["lineDelayMs"];
+	    }
+	}
+	export class Paste {
+	    charDelayMs: number;
+	    lineDelayMs: number;
+	    confirmLines: number;
+	    confirmChars: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Paste(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.charDelayMs = source["charDelayMs"];
+	        this.lineDelayMs = source["lineDelayMs"];
+	        this.confirmLines = source["confirmLines"];
+	        this.confirmChars = source["confirmChars"];
+	    }
+	}
+	export class Plugin {
+	    name: string;
+	    enabled: boolean;
+	    command: string;
+	    args?: string[];
+	    bbs?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Plugin(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.enabled = source["enabled"];
+	        this.command = source["command"];
+	        this.args = source["args"];
+	        this.bbs = source["bbs"];
+	    }
+	}
+	export class Proxy {
+	    name: string;
+	    type: string;
+	    host: string;
+	    port: number;
+	    username?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Proxy(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.type = source["type"];
+	        this.host = source["host"];
+	        this.port = source["port"];
+	        this.username = source["username"];
+	    }
+	}
+	export class Schedule {
+	    id: string;
+	    enabled: boolean;
+	    name: string;
+	    host: string;
+	    port: number;
+	    time: string;
+	    days?: number[];
+	    script?: string;
+	    disconnect: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new Schedule(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.enabled = source["enabled"];
+	        this.name = source["name"];
+	        this.host = source["host"];
+	        this.port = source["port"];
+	        this.time = source["time"];
+	        this.days = source["days"];
+	        this.script = source["script"];
+	        this.disconnect = source["disconnect"];
+	    }
+	}
+	export class Share {
+	    port: number;
+	    localOnly: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new Share(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.port = source["port"];
+	        this.localOnly = source["localOnly"];
+	    }
+	}
+	export class Theme {
+	    name: string;
+	    palette: string;
+	    customPalette?: string[];
+	    boldPolicy: string;
+	    font: string;
+	    fontSize: number;
+	    cursor: string;
+	    background: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Theme(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.palette = source["palette"];
+	        this.customPalette = source["customPalette"];
+	        this.boldPolicy = source["boldPolicy"];
+	        this.font = source["font"];
+	        this.fontSize = source["fontSize"];
+	        this.cursor = source["cursor"];
+	        this.background = source["background"];
+	    }
+	}
+	export class Settings {
+	    version: number;
+	    language: string;
+	    keyboard?: string;
+	    palette: string;
+	    customPalette?: string[];
+	    boldPolicy: string;
+	    c1Controls: boolean;
+	    iceColors: Record<string, boolean>;
+	    theme: string;
+	    themes: Theme[];
+	    bbsThemes: Record<string, string>;
+	    exportFont: string;
+	    emulation: Record<string, string>;
+	    keys: Record<string, string>;
+	    charset: Record<string, string>;
+	    logging: Logging;
+	    limits: Limits;
+	    paste: Paste;
+	    pacing: Record<string, Pacing>;
+	    watch: Record<string, Array<string>>;
+	    watchNotify: boolean;
+	    closeToTray: boolean;
+	    idle: Idle;
+	    logins: Record<string, string>;
+	    passwordPrompt?: string;
+	    iemsi: IEMSI;
+	    schedules: Schedule[];
+	    share: Share;
+	    printer: string;
+	    downloads: string;
+	    fileTypes: FileTypes;
+	    flowControl: boolean;
+	    accessibility: boolean;
+	    proxies: Proxy[];
+	    tor?: string;
+	    plugins: Plugin[];
+	    control: Control;
+	
+	    static createFrom(source: any = {}) {
+	        return new Settings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.version = source["version"];
+	        this.language = source["language"];
+	        this.keyboard = source["keyboard"];
+	        this.palette = source["palette"];
+	        this.customPalette = source["customPalette"];
+	        this.boldPolicy = source["boldPolicy"];
+	        this.c1Controls = source["c1Controls"];
+	        this.iceColors = source["iceColors"];
+	        this.theme = source["theme"];
+	        this.themes = this.convertValues(source["themes"], Theme);
+	        this.bbsThemes = source["bbsThemes"];
+	        this.exportFont = source["exportFont"];
+	        this.emulation = source["emulation"];
+	        this.keys = source["keys"];
+	        this.charset = source["charset"];
+	        this.logging = this.convertValues(source["logging"], Logging);
+	        this.limits = this.convertValues(source["limits"], Limits);
+	        this.paste = this.convertValues(source["paste"], Paste);
+	        this.pacing = this.convertValues(source["pacing"], Pacing, true);
+	        this.watch = source["watch"];
+	        this.watchNotify = source["watchNotify"];
+	        this.closeToTray = source["closeToTray"];
+	        this.idle = this.convertValues(source["idle"], Idle);
+	        this.logins = source["logins"];
+	        this.passwordPrompt = source["passwordPrompt"];
+	        this.iemsi = this.convertValues(source["iemsi"], IEMSI);
+	        this.schedules = this.convertValues(source["schedules"], Schedule);
+	        this.share = this.convertValues(source["share"], Share);
+	        this.printer = source["printer"];
+	        this.downloads = source["downloads"];
+	        this.fileTypes = this.convertValues(source["fileTypes"], FileTypes);
+	        this.flowControl = source["flowControl"];
+	        this.accessibility = source["accessibility"];
+	        this.proxies = this.convertValues(source["proxies"], Proxy);
+	        this.tor = source["tor"];
+	        this.plugins = this.convertValues(source["plugins"], Plugin);
+	        this.control = this.convertValues(source["control"], Control);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+
+}
+
+export namespace filelist {
+	
+	export class Entry {
+	    name: string;
+	    size: number;
+	    sizeText: string;
+	    date?: string;
+	    description: string;
+	    marked: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new Entry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.size = source["size"];
+	        this.sizeText = source["sizeText"];
+	        this.date = source["date"];
+	        this.description = source["description"];
+	        this.marked = source["marked"];
+	    }
+	}
+
+}
+
+export namespace fingerprint {
+	
+	export class Preset {
+	    iceColors: boolean;
+	    charset: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Preset(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.iceColors = source["iceColors"];
+	        this.charset = source["charset"];
+	    }
+	}
+	export class Result {
+	    software: string;
+	    version?: string;
+	    evidence: string;
+	    negotiation?: string[];
+	    preset: Preset;
+	
+	    static createFrom(source: any = {}) {
+	        return new Result(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.software = source["software"];
+	        this.version = source["version"];
+	        this.evidence = source["evidence"];
+	        this.negotiation = source["negotiation"];
+	        this.preset = this.convertValues(source["preset"], Preset);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace i18n {
+	
+	export class Message {
+	    code: string;
+	    args?: string[];
+	    text: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Message(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.code = source["code"];
+	        this.args = source["args"];
+	        this.text = source["text"];
+	    }
+	}
+
+}
+
+export namespace iemsi {
+	
+	export class ServerInfo {
+	    name: string;
+	    location: string;
+	    operator: string;
+	    localTime: string;
+	    notice: string;
+	    wait: string;
+	    capabilities: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ServerInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.location = source["location"];
+	        this.operator = source["operator"];
+	        this.localTime = source["localTime"];
+	        this.notice = source["notice"];
+	        this.wait = source["wait"];
+	        this.capabilities = source["capabilities"];
+	    }
+	}
+
+}
+
 export namespace main {
 	
-	export class BBSEntry {
+	export class BitmapFontInfo {
+	    file: string;
+	    name: string;
+	    width: number;
+	    height: number;
+	    glyphs: number;
+	    unicode: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new BitmapFontInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.file = source["file"];
+	        this.name = source["name"];
+	        this.width = source["width"];
+	        this.height = source["height"];
+	        this.glyphs = source["glyphs"];
+	        this.unicode = source["unicode"];
+	    }
+	}
+	export class BitmapFontResult {
+	    font?: bitmapfont.Font;
+	    error?: i18n.Message;
+	
+	    static createFrom(source: any = {}) {
+	        return new BitmapFontResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.font = this.convertValues(source["font"], bitmapfont.Font);
+	        this.error = this.convertValues(source["error"], i18n.Message);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class BridgeResult {
+	    session: string;
+	    command: string;
+	    bytesIn: number;
+	    bytesOut: number;
+	    reason: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new BridgeResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.session = source["session"];
+	        this.command = source["command"];
+	        this.bytesIn = source["bytesIn"];
+	        this.bytesOut = source["bytesOut"];
+	        this.reason = source["reason"];
+	    }
+	}
+	export class BridgeSpec {
+	    command: string;
+	    args?: string[];
+	    maxIn: number;
+	    maxOut: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new BridgeSpec(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.command = source["command"];
+	        this.args = source["args"];
+	        this.maxIn = source["maxIn"];
+	        this.maxOut = source["maxOut"];
+	    }
+	}
+	export class CaptureStatus {
+	    active: boolean;
+	    path: string;
+	    bytes: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new CaptureStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.active = source["active"];
+	        this.path = source["path"];
+	        this.bytes = source["bytes"];
+	    }
+	}
+	export class ScreenCell {
+	    ch: string;
+	    fgR: number;
+	    fgG: number;
+	    fgB: number;
+	    bgR: number;
+	    bgG: number;
+	    bgB: number;
+	    bold: boolean;
+	    ul: boolean;
+	    blink: boolean;
+	    rev: boolean;
+	    faint: boolean;
+	    italic: boolean;
+	    strike: boolean;
+	    conceal: boolean;
+	    ulStyle: string;
+	    ulR: number;
+	    ulG: number;
+	    ulB: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ScreenCell(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.ch = source["ch"];
+	        this.fgR = source["fgR"];
+	        this.fgG = source["fgG"];
+	        this.fgB = source["fgB"];
+	        this.bgR = source["bgR"];
+	        this.bgG = source["bgG"];
+	        this.bgB = source["bgB"];
+	        this.bold = source["bold"];
+	        this.ul = source["ul"];
+	        this.blink = source["blink"];
+	        this.rev = source["rev"];
+	        this.faint = source["faint"];
+	        this.italic = source["italic"];
+	        this.strike = source["strike"];
+	        this.conceal = source["conceal"];
+	        this.ulStyle = source["ulStyle"];
+	        this.ulR = source["ulR"];
+	        this.ulG = source["ulG"];
+	        this.ulB = source["ulB"];
+	    }
+	}
+	export class CellRun {
+	    text: string;
+	    len: number;
+	    attr: ScreenCell;
+	
+	    static createFrom(source: any = {}) {
+	        return new CellRun(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.text = source["text"];
+	        this.len = source["len"];
+	        this.attr = this.convertValues(source["attr"], ScreenCell);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ConnectProgress {
+	    session: string;
+	    phase: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ConnectProgress(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.session = source["session"];
+	        this.phase = source["phase"];
+	    }
+	}
+	export class ConnectResult {
+	    session: string;
+	    error?: i18n.Message;
+	
+	    static createFrom(source: any = {}) {
+	        return new ConnectResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.session = source["session"];
+	        this.error = this.convertValues(source["error"], i18n.Message);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ConnectionStats {
+	    host: string;
+	    port: number;
+	    // Go type: time
+	    connectedAt: any;
+	    bytesReceived: number;
+	    bells: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ConnectionStats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.host = source["host"];
+	        this.port = source["port"];
+	        this.connectedAt = this.convertValues(source["connectedAt"], null);
+	        this.bytesReceived = source["bytesReceived"];
+	        this.bells = source["bells"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ControlInfo {
+	    active: boolean;
+	    url?: string;
+	    token?: string;
+	    tokenFile?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ControlInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.active = source["active"];
+	        this.url = source["url"];
+	        this.token = source["token"];
+	        this.tokenFile = source["tokenFile"];
+	    }
+	}
+	export class HostKeyInfo {
+	    session: string;
+	    host: string;
+	    type: string;
+	    fingerprint: string;
+	    known: string;
+	    status: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new HostKeyInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.session = source["session"];
+	        this.host = source["host"];
+	        this.type = source["type"];
+	        this.fingerprint = source["fingerprint"];
+	        this.known = source["known"];
+	        this.status = source["status"];
+	    }
+	}
+	export class ScheduleResult {
+	    id: string;
+	    name: string;
+	    error?: i18n.Message;
+	
+	    static createFrom(source: any = {}) {
+	        return new ScheduleResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.error = this.convertValues(source["error"], i18n.Message);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ReconnectEvent {
+	    session: string;
+	    name: string;
+	    host: string;
+	    port: number;
+	    attempt?: number;
+	    retries?: number;
+	    seconds: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ReconnectEvent(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.session = source["session"];
+	        this.name = source["name"];
+	        this.host = source["host"];
+	        this.port = source["port"];
+	        this.attempt = source["attempt"];
+	        this.retries = source["retries"];
+	        this.seconds = source["seconds"];
+	    }
+	}
+	export class ShareInfo {
+	    active: boolean;
+	    session?: string;
+	    urls?: string[];
+	    viewers: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ShareInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.active = source["active"];
+	        this.session = source["session"];
+	        this.urls = source["urls"];
+	        this.viewers = source["viewers"];
+	    }
+	}
+	export class FloodEvent {
+	    session: string;
+	    limit: string;
+	    value: number;
+	    dropped?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new FloodEvent(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.session = source["session"];
+	        this.limit = source["limit"];
+	        this.value = source["value"];
+	        this.dropped = source["dropped"];
+	    }
+	}
+	export class IdleWarning {
+	    session: string;
+	    idleSeconds: number;
+	    disconnectSeconds: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new IdleWarning(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.session = source["session"];
+	        this.idleSeconds = source["idleSeconds"];
+	        this.disconnectSeconds = source["disconnectSeconds"];
+	    }
+	}
+	export class WatchMatch {
+	    session: string;
+	    bbs: string;
+	    phrase: string;
+	    line: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new WatchMatch(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.session = source["session"];
+	        this.bbs = source["bbs"];
+	        this.phrase = source["phrase"];
+	        this.line = source["line"];
+	    }
+	}
+	export class PasteResult {
+	    session: string;
+	    sent: number;
+	    total: number;
+	    canceled: boolean;
+	    error?: i18n.Message;
+	
+	    static createFrom(source: any = {}) {
+	        return new PasteResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.session = source["session"];
+	        this.sent = source["sent"];
+	        this.total = source["total"];
+	        this.canceled = source["canceled"];
+	        this.error = this.convertValues(source["error"], i18n.Message);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class PasteProgress {
+	    session: string;
+	    sent: number;
+	    total: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new PasteProgress(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.session = source["session"];
+	        this.sent = source["sent"];
+	        this.total = source["total"];
+	    }
+	}
+	export class ZmodemPolicy {
+	    session: string;
+	    filename: string;
+	    verdict: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ZmodemPolicy(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.session = source["session"];
+	        this.filename = source["filename"];
+	        this.verdict = source["verdict"];
+	    }
+	}
+	export class ZmodemFinished {
+	    session: string;
+	    filepath: string;
+	    success: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ZmodemFinished(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.session = source["session"];
+	        this.filepath = source["filepath"];
+	        this.success = source["success"];
+	    }
+	}
+	export class ZmodemProgress {
+	    session: string;
+	    bytes: number;
+	    total: number;
+	    speed: number;
+	    eta: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ZmodemProgress(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.session = source["session"];
+	        this.bytes = source["bytes"];
+	        this.total = source["total"];
+	        this.speed = source["speed"];
+	        this.eta = source["eta"];
+	    }
+	}
+	export class ZmodemFile {
+	    session: string;
+	    filename: string;
+	    filesize: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ZmodemFile(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.session = source["session"];
+	        this.filename = source["filename"];
+	        this.filesize = source["filesize"];
+	    }
+	}
+	export class PlaybackState {
+	    active: boolean;
+	    paused: boolean;
+	    baud: number;
+	    pos: number;
+	    total: number;
+	    timed: boolean;
+	    scale: number;
+	    elapsedMs: number;
+	    durationMs: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new PlaybackState(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.active = source["active"];
+	        this.paused = source["paused"];
+	        this.baud = source["baud"];
+	        this.pos = source["pos"];
+	        this.total = source["total"];
+	        this.timed = source["timed"];
+	        this.scale = source["scale"];
+	        this.elapsedMs = source["elapsedMs"];
+	        this.durationMs = source["durationMs"];
+	    }
+	}
+	export class LogMode {
+	    active: boolean;
+	    page: number;
+	    total: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new LogMode(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.active = source["active"];
+	        this.page = source["page"];
+	        this.total = source["total"];
+	    }
+	}
+	export class TerminalSize {
+	    cols: number;
+	    rows: number;
+	    statusLine?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new TerminalSize(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.cols = source["cols"];
+	        this.rows = source["rows"];
+	        this.statusLine = source["statusLine"];
+	    }
+	}
+	export class SessionActivity {
+	    id: string;
+	    event: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SessionActivity(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.event = source["event"];
+	    }
+	}
+	export class SessionInfo {
+	    id: string;
+	    name: string;
+	    host: string;
+	    port: number;
+	    connected: boolean;
+	    active: boolean;
+	    unread: number;
+	    software?: string;
+	    dnd: boolean;
+	    missed: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SessionInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.host = source["host"];
+	        this.port = source["port"];
+	        this.connected = source["connected"];
+	        this.active = source["active"];
+	        this.unread = source["unread"];
+	        this.software = source["software"];
+	        this.dnd = source["dnd"];
+	        this.missed = source["missed"];
+	    }
+	}
+	export class LineChange {
+	    line: number;
+	    text: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new LineChange(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.line = source["line"];
+	        this.text = source["text"];
+	    }
+	}
+	export class RowRuns {
+	    y: number;
+	    runs: CellRun[];
+	
+	    static createFrom(source: any = {}) {
+	        return new RowRuns(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.y = source["y"];
+	        this.runs = this.convertValues(source["runs"], CellRun);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ScreenDelta {
+	    full: boolean;
+	    cols: number;
+	    rows: number;
+	    changed: RowRuns[];
+	    cursorX: number;
+	    cursorY: number;
+	    cursorVisible: boolean;
+	    iceColors: boolean;
+	    boldPolicy: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ScreenDelta(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.full = source["full"];
+	        this.cols = source["cols"];
+	        this.rows = source["rows"];
+	        this.changed = this.convertValues(source["changed"], RowRuns);
+	        this.cursorX = source["cursorX"];
+	        this.cursorY = source["cursorY"];
+	        this.cursorVisible = source["cursorVisible"];
+	        this.iceColors = source["iceColors"];
+	        this.boldPolicy = source["boldPolicy"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class EventPayloads {
+	    version: number;
+	    "screen-update": ScreenDelta;
+	    "screen-lines": LineChange[];
+	    "connect-progress": ConnectProgress;
+	    "sessions-changed": SessionInfo[];
+	    "session-activity": SessionActivity;
+	    "settings-changed": config.Settings;
+	    "theme-changed": config.Theme;
+	    "terminal-resized": TerminalSize;
+	    "log-mode": LogMode;
+	    "playback-state": PlaybackState;
+	    "zmodem-offer": ZmodemFile;
+	    "zmodem-started": ZmodemFile;
+	    "zmodem-progress": ZmodemProgress;
+	    "zmodem-finished": ZmodemFinished;
+	    "zmodem-policy": ZmodemPolicy;
+	    "paste-progress": PasteProgress;
+	    "paste-finished": PasteResult;
+	    "watch-match": WatchMatch;
+	    "idle-warning": IdleWarning;
+	    "flood-limit": FloodEvent;
+	    "capture-status": CaptureStatus;
+	    "sharing-changed": ShareInfo;
+	    "reconnect-prompt": ReconnectEvent;
+	    "schedule-finished": ScheduleResult;
+	    "bridge-finished": BridgeResult;
+	    "host-key": HostKeyInfo;
+	    "bbs-software": fingerprint.Result;
+	    iemsi: iemsi.ServerInfo;
+	    "status-message"?: i18n.Message;
+	
+	    static createFrom(source: any = {}) {
+	        return new EventPayloads(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.version = source["version"];
+	        this["screen-update"] = this.convertValues(source["screen-update"], ScreenDelta);
+	        this["screen-lines"] = this.convertValues(source["screen-lines"], LineChange);
+	        this["connect-progress"] = this.convertValues(source["connect-progress"], ConnectProgress);
+	        this["sessions-changed"] = this.convertValues(source["sessions-changed"], SessionInfo);
+	        this["session-activity"] = this.convertValues(source["session-activity"], SessionActivity);
+	        this["settings-changed"] = this.convertValues(source["settings-changed"], config.Settings);
+	        this["theme-changed"] = this.convertValues(source["theme-changed"], config.Theme);
+	        this["terminal-resized"] = this.convertValues(source["terminal-resized"], TerminalSize);
+	        this["log-mode"] = this.convertValues(source["log-mode"], LogMode);
+	        this["playback-state"] = this.convertValues(source["playback-state"], PlaybackState);
+	        this["zmodem-offer"] = this.convertValues(source["zmodem-offer"], ZmodemFile);
+	        this["zmodem-started"] = this.convertValues(source["zmodem-started"], ZmodemFile);
+	        this["zmodem-progress"] = this.convertValues(source["zmodem-progress"], ZmodemProgress);
+	        this["zmodem-finished"] = this.convertValues(source["zmodem-finished"], ZmodemFinished);
+	        this["zmodem-policy"] = this.convertValues(source["zmodem-policy"], ZmodemPolicy);
+	        this["paste-progress"] = this.convertValues(source["paste-progress"], PasteProgress);
+	        this["paste-finished"] = this.convertValues(source["paste-finished"], PasteResult);
+	        this["watch-match"] = this.convertValues(source["watch-match"], WatchMatch);
+	        this["idle-warning"] = this.convertValues(source["idle-warning"], IdleWarning);
+	        this["flood-limit"] = this.convertValues(source["flood-limit"], FloodEvent);
+	        this["capture-status"] = this.convertValues(source["capture-status"], CaptureStatus);
+	        this["sharing-changed"] = this.convertValues(source["sharing-changed"], ShareInfo);
+	        this["reconnect-prompt"] = this.convertValues(source["reconnect-prompt"], ReconnectEvent);
+	        this["schedule-finished"] = this.convertValues(source["schedule-finished"], ScheduleResult);
+	        this["bridge-finished"] = this.convertValues(source["bridge-finished"], BridgeResult);
+	        this["host-key"] = this.convertValues(source["host-key"], HostKeyInfo);
+	        this["bbs-software"] = this.convertValues(source["bbs-software"], fingerprint.Result);
+	        this.iemsi = this.convertValues(source["iemsi"], iemsi.ServerInfo);
+	        this["status-message"] = this.convertValues(source["status-message"], i18n.Message);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	
+	export class ImportResult {
+	    added: number;
+	    skipped: number;
+	    error?: i18n.Message;
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.added = source["added"];
+	        this.skipped = source["skipped"];
+	        this.error = this.convertValues(source["error"], i18n.Message);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	export class MailPacketResult {
+	    packet?: bluewave.Packet;
+	    error?: i18n.Message;
+	
+	    static createFrom(source: any = {}) {
+	        return new MailPacketResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.packet = this.convertValues(source["packet"], bluewave.Packet);
+	        this.error = this.convertValues(source["error"], i18n.Message);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class MissedEvent {
+	    // Go type: time
+	    time: any;
+	    kind: string;
+	    phrase?: string;
+	    line?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new MissedEvent(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.time = this.convertValues(source["time"], null);
+	        this.kind = source["kind"];
+	        this.phrase = source["phrase"];
+	        this.line = source["line"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class PaletteInfo {
 	    name: string;
-	    host: string;
-	    port: number;
+	    colors: string[];
 	
 	    static createFrom(source: any = {}) {
-	        return new BBSEntry(source);
+	        return new PaletteInfo(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.name = source["name"];
-	        this.host = source["host"];
-	        this.port = source["port"];
+	        this.colors = source["colors"];
 	    }
 	}
-	export class ScreenCell {
-	    ch: string;
-	    fgR: number;
-	    fgG: number;
-	    fgB: number;
-	    bgR: number;
-	    bgG: number;
-	    bgB: number;
-	    bold: boolean;
-	    ul: boolean;
-	    blink: boolean;
-	    rev: boolean;
+	
+	
+	
+	
+	
+	export class RunSnapshot {
+	    rows: CellRun[][];
+	    cursorX: number;
+	    cursorY: number;
+	    cursorVisible: boolean;
+	    iceColors: boolean;
+	    boldPolicy: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new ScreenCell(source);
+	        return new RunSnapshot(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.ch = source["ch"];
-	        this.fgR = source["fgR"];
-	        this.fgG = source["fgG"];
-	        this.fgB = source["fgB"];
-	        this.bgR = source["bgR"];
-	        this.bgG = source["bgG"];
-	        this.bgB = source["bgB"];
-	        this.bold = source["bold"];
-	        this.ul = source["ul"];
-	        this.blink = source["blink"];
-	        this.rev = source["rev"];
+	        this.rows = this.convertValues(source["rows"], CellRun);
+	        this.cursorX = source["cursorX"];
+	        this.cursorY = source["cursorY"];
+	        this.cursorVisible = source["cursorVisible"];
+	        this.iceColors = source["iceColors"];
+	        this.boldPolicy = source["boldPolicy"];
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
+	
+	
+	
 	export class ScreenSnapshot {
 	    cells: ScreenCell[][];
 	    cursorX: number;
 	    cursorY: number;
+	    cursorVisible: boolean;
+	    iceColors: boolean;
+	    boldPolicy: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new ScreenSnapshot(source);
@@ -62,6 +1808,109 @@ export namespace main {
 	        this.cells = this.convertValues(source["cells"], ScreenCell);
 	        this.cursorX = source["cursorX"];
 	        this.cursorY = source["cursorY"];
+	        this.cursorVisible = source["cursorVisible"];
+	        this.iceColors = source["iceColors"];
+	        this.boldPolicy = source["boldPolicy"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SelectionResult {
+	    text: string;
+	    data: string;
+	    error?: i18n.Message;
+	
+	    static createFrom(source: any = {}) {
+	        return new SelectionResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.text = source["text"];
+	        this.data = source["data"];
+	        this.error = this.convertValues(source["error"], i18n.Message);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	
+	
+	export class ThemeInfo {
+	    name: string;
+	    palette: string;
+	    customPalette?: string[];
+	    boldPolicy: string;
+	    font: string;
+	    fontSize: number;
+	    cursor: string;
+	    background: string;
+	    builtin: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ThemeInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.palette = source["palette"];
+	        this.customPalette = source["customPalette"];
+	        this.boldPolicy = source["boldPolicy"];
+	        this.font = source["font"];
+	        this.fontSize = source["fontSize"];
+	        this.cursor = source["cursor"];
+	        this.background = source["background"];
+	        this.builtin = source["builtin"];
+	    }
+	}
+	export class WaitResult {
+	    found: boolean;
+	    text: string;
+	    error?: i18n.Message;
+	
+	    static createFrom(source: any = {}) {
+	        return new WaitResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.found = source["found"];
+	        this.text = source["text"];
+	        this.error = this.convertValues(source["error"], i18n.Message);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -82,6 +1931,59 @@ export namespace main {
 		    return a;
 		}
 	}
+	
+	
+	
+	
+
+}
+
+export namespace sauce {
+	
+	export class Record {
+	    title: string;
+	    author: string;
+	    group: string;
+	    date: string;
+	    fileSize: number;
+	    dataType: number;
+	    fileType: number;
+	    tinfo1: number;
+	    tinfo2: number;
+	    tinfo3: number;
+	    tinfo4: number;
+	    flags: number;
+	    font: string;
+	    comments: string[];
+	    width: number;
+	    height: number;
+	    iceColors: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new Record(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.title = source["title"];
+	        this.author = source["author"];
+	        this.group = source["group"];
+	        this.date = source["date"];
+	        this.fileSize = source["fileSize"];
+	        this.dataType = source["dataType"];
+	        this.fileType = source["fileType"];
+	        this.tinfo1 = source["tinfo1"];
+	        this.tinfo2 = source["tinfo2"];
+	        this.tinfo3 = source["tinfo3"];
+	        this.tinfo4 = source["tinfo4"];
+	        this.flags = source["flags"];
+	        this.font = source["font"];
+	        this.comments = source["comments"];
+	        this.width = source["width"];
+	        this.height = source["height"];
+	        this.iceColors = source["iceColors"];
+	    }
+	}
 
 }
 
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"log"
+	"time"
+
+	wailsrt "github.com/wailsapp/wails/v2/pkg/runtime"
+
+	"github.com/rj45lab/bbs-client-go/internal/cp437"
+	"github.com/rj45lab/bbs-client-go/internal/i18n"
+)
+
+// ─────────────────────────────────────────────
+// Timer di inattività
+// ─────────────────────────────────────────────
+
+// idleTick è la frequenza di controllo (e del conto alla rovescia).
+const idleTick = time.Second
+
+// idleState tiene traccia dell'ultimo input dell'utente in una sessione.
+type idleState struct {
+	lastInput     time.Time
+	lastKeepalive time.Time
+	warned        bool
+}
+
+// touchInput segna un input dell'utente sulla scheda attiva; se era
+// visibile un avviso di inattività, il frontend lo toglie su "idle-reset".
+func (a *App) touchInput() {
+	a.mu.Lock()
+	warned := a.idle.warned
+	a.idle = idleState{lastInput: time.Now()}
+	a.mu.Unlock()
+	if warned {
+		wailsrt.EventsEmit(a.ctx, "idle-reset", true)
+	}
+}
+
+// idleLoop controlla ogni secondo l'inattività delle sessioni connesse:
+// avvisa (evento "idle-warning" con i secondi alla disconnessione), invia
+// i keepalive e disconnette secondo Settings.Idle.
+func (a *App) idleLoop() {
+	ticker := time.NewTicker(idleTick)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-a.ctx.Done():
+			return
+		case now := <-ticker.C:
+			a.checkIdle(now)
+		}
+	}
+}
+
+// idleAction è ciò che checkIdle deve fare per una sessione, deciso sotto
+// lock ed eseguito dopo.
+type idleAction struct {
+	s          *session
+	warn       bool
+	idleFor    time.Duration
+	remaining  time.Duration // alla disconnessione (-1 = mai)
+	keepalive  bool
+	disconnect bool
+}
+
+func (a *App) checkIdle(now time.Time) {
+	a.mu.Lock()
+	cfg := a.settings.Idle
+	var actions []idleAction
+	for _, s := range a.sessions {
+		if !s.connected || s.idle.lastInput.IsZero() {
+			continue
+		}
+		idleFor := now.Sub(s.idle.lastInput)
+		act := idleAction{s: s, idleFor: idleFor, remaining: -1}
+		if cfg.DisconnectMinutes > 0 {
+			act.remaining = max(minutes(cfg.DisconnectMinutes)-idleFor, 0)
+			act.disconnect = act.remaining == 0
+		}
+		if cfg.WarnMinutes > 0 && idleFor >= minutes(cfg.WarnMinutes) {
+			act.warn = true
+			if !s.idle.warned {
+				s.idle.warned = true
+				log.Printf("[IDLE] %s inattivo da %v", s.host, idleFor.Round(time.Second))
+			}
+		}
+		if k := minutes(cfg.KeepaliveMinutes); k > 0 && idleFor >= k {
+			last := s.idle.lastKeepalive
+			if last.Before(s.idle.lastInput) {
+				last = s.idle.lastInput
+			}
+			if now.Sub(last) >= k {
+				s.idle.lastKeepalive = now
+				act.keepalive = true
+			}
+		}
+		actions = append(actions, act)
+	}
+	a.mu.Unlock()
+
+	for _, act := range actions {
+		s := act.s
+		switch {
+		case act.disconnect:
+			log.Printf("[IDLE] disconnessione automatica da %s", s.host)
+			a.emitFor(s, "status-message", i18n.New(i18n.MsgIdleDisconnected))
+			a.disconnectSession(s)
+		case act.keepalive:
+			var err error
+			if cfg.KeepaliveText != "" {
+				err = s.conn.Send(cp437.Encode(cfg.KeepaliveText))
+			} else {
+				err = s.conn.SendNOP()
+			}
+			if err != nil {
+				log.Printf("[IDLE] keepalive: %v", err)
+			}
+		}
+		if act.warn && !act.disconnect {
+			a.emitFor(s, "idle-warning", IdleWarning{
+				Session:           s.id,
+				IdleSeconds:       int64(act.idleFor / time.Second),
+				DisconnectSeconds: int64(act.remaining / time.Second),
+			})
+		}
+	}
+}
+
+// minutes converte minuti in durata.
+func minutes(n int) time.Duration {
+	return time.Duration(n) * time.Minute
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"cmp"
+	"log"
+	"time"
+
+	"github.com/rj45lab/bbs-client-go/internal/config"
+	"github.com/rj45lab/bbs-client-go/internal/i18n"
+	"github.com/rj45lab/bbs-client-go/internal/iemsi"
+	"github.com/rj45lab/bbs-client-go/internal/keychain"
+	"github.com/rj45lab/bbs-client-go/pkg/ansi"
+)
+
+// ─────────────────────────────────────────────
+// Credenziali e login automatico IEMSI
+// ─────────────────────────────────────────────
+
+// SetLogin salva nome utente e password della BBS corrente: il nome nelle
+// impostazioni, la password nel portachiavi di sistema. Valori vuoti
+// cancellano quelli salvati.
+func (a *App) SetLogin(user, password string) *i18n.Message {
+	a.mu.Lock()
+	key := bbsKey(a.host, a.port)
+	a.mu.Unlock()
+	if err := keychain.SetPassword(key, password); err != nil {
+		return i18n.New(i18n.ErrKeychain, err)
+	}
+	return a.updateSettings(func(s *config.Settings) {
+		if user == "" {
+			delete(s.Logins, key)
+		} else {
+			s.Logins[key] = user
+		}
+	})
+}
+
+// GetLogin ritorna il nome utente salvato per la BBS corrente.
+func (a *App) GetLogin() string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.settings.Logins[bbsKey(a.host, a.port)]
+}
+
+// ─────────────────────────────────────────────
+// Invio delle credenziali
+// ─────────────────────────────────────────────
+//
+// Per le BBS senza IEMSI ma con prompt standard, SendLogin digita il nome
+// utente, attende la richiesta della password (Settings.PasswordPrompt) e
+// invia la password dal portachiavi: un'alternativa leggera agli script
+// di login.
+
+const (
+	// loginTimeout limita l'attesa della richiesta della password
+	loginTimeout = 30 * time.Second
+	// defaultPasswordPrompt è il prompt atteso se le impostazioni non ne
+	// indicano un altro
+	defaultPasswordPrompt = "assword"
+)
+
+// SendLogin invia le credenziali salvate alla BBS della scheda id ("" =
+// attiva). Ritorna quando la password è stata inviata o l'attesa della
+// richiesta è scaduta.
+func (a *App) SendLogin(id string) *i18n.Message {
+	a.mu.Lock()
+	s := a.session
+	if id != "" {
+		s = a.findSession(id)
+	}
+	if s == nil {
+		a.mu.Unlock()
+		return i18n.New(i18n.ErrUnknownSession, id)
+	}
+	key := bbsKey(s.host, s.port)
+	name := s.bbsName
+	a.mu.Unlock()
+	if !s.conn.Connected() {
+		return i18n.New(i18n.ErrNotConnected)
+	}
+
+	st := a.config.Get()
+	user := st.Logins[key]
+	password, err := keychain.Password(key)
+	if err != nil {
+		return i18n.New(i18n.ErrKeychain, err)
+	}
+	if user == "" || password == "" {
+		return i18n.New(i18n.ErrNoLogin, name)
+	}
+
+	h := sessionHost{a, s}
+	a.mu.Lock()
+	stream := s.stream
+	a.mu.Unlock()
+	// Solo la richiesta che segue il nome utente conta, non il testo già
+	// ricevuto (es. "Forgot your password?" nella schermata di benvenuto)
+	stream.Discard()
+	if err := h.Send(user + "\r"); err != nil {
+		return i18n.New(i18n.ErrGeneric, err)
+	}
+	if !h.Wait(a.ctx, cmp.Or(st.PasswordPrompt, defaultPasswordPrompt), loginTimeout) {
+		log.Printf("[LOGIN] %s: richiesta della password non ricevuta", key)
+		return i18n.New(i18n.ErrNoPasswordPrompt)
+	}
+	if err := h.Send(password + "\r"); err != nil {
+		return i18n.New(i18n.ErrGeneric, err)
+	}
+	log.Printf("[LOGIN] %s: credenziali inviate", key)
+	a.emitFor(s, "status-message", i18n.New(i18n.MsgLoginSent, name))
+	return nil
+}
+
+// newIEMSI prepara l'handshake IEMSI per la BBS host:port; nil se il login
+// automatico è disattivato o per la BBS non c'è un nome utente.
+func (a *App) newIEMSI(host string, port int) *iemsi.Client {
+	st := a.config.Get()
+	key := bbsKey(host, port)
+	a.mu.Lock()
+	size := a.termSize
+	a.mu.Unlock()
+	user := st.Logins[key]
+	if !st.IEMSI.Enabled || user == "" {
+		return nil
+	}
+	password, err := keychain.Password(key)
+	if err != nil {
+		log.Printf("[IEMSI] Portachiavi non disponibile, login senza password: %v", err)
+	}
+	return iemsi.NewClient(iemsi.Profile{
+		Name:         user,
+		Alias:        st.IEMSI.Alias,
+		Location:     st.IEMSI.Location,
+		Password:     password,
+		TermType:     iemsiTermType(emulationFor(st, key)),
+		Rows:         size.Rows,
+		Cols:         size.Cols,
+		Protocols:    []string{"ZMO"},
+		Capabilities: []string{"ASCII8"},
+		Requests:     []string{"HOT", "MORE", "FSED", "CLR"},
+		Software:     "BBSClientGo",
+	})
+}
+
+// iemsiTermType ritorna il nome IEMSI del terminale per l'emulazione.
+func iemsiTermType(emu ansi.Emulation) string {
+	switch emu {
+	case ansi.EmulationVT100:
+		return "VT100"
+	case ansi.EmulationASCII:
+		return "TTY"
+	}
+	return "ANSI"
+}
+
+// feedIEMSI passa i dati ricevuti all'handshake IEMSI della scheda, invia
+// la risposta e notifica l'esito: "iemsi" con i dati della BBS a login
+// avvenuto. Il client IEMSI è usato solo dal loop eventi della scheda.
+func (a *App) feedIEMSI(s *session, c *iemsi.Client, data []byte) {
+	reply, res := c.Feed(data)
+	server := c.Server()
+	if len(reply) > 0 {
+		s.conn.Send(reply)
+	}
+	switch res {
+	case iemsi.Started:
+		log.Printf("[IEMSI] Richiesta ricevuta, dati utente inviati")
+	case iemsi.Completed:
+		log.Printf("[IEMSI] Login su %s (%s)", server.Name, server.Location)
+		a.emitFor(s, "iemsi", server)
+		a.emitFor(s, "status-message", i18n.New(i18n.MsgIEMSILogin, server.Name))
+	case iemsi.Failed:
+		log.Printf("[IEMSI] Handshake fallito")
+		a.emitFor(s, "status-message", i18n.New(i18n.ErrIEMSIFailed))
+	}
+}
@@ -0,0 +1,504 @@
+// Package addressbook gestisce la rubrica delle BBS: le voci inserite
+// dall'utente, salvate in JSON nella directory di configurazione, unite
+// alla lista pubblica (Telnet BBS Guide) caricata a ogni avvio.
+//
+// Le voci pubbliche non vengono salvate finché l'utente non le modifica,
+// le riordina o le elimina: solo allora ne viene memorizzata una copia
+// (o una voce nascosta), così gli aggiornamenti della lista restano visibili.
+package addressbook
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ─────────────────────────────────────────────
+// Voci
+// ─────────────────────────────────────────────
+
+const (
+	// FileName è il nome del file della rubrica nella directory di config
+	FileName = "addressbook.json"
+	// DefaultPort è la porta telnet usata se la voce non ne specifica una
+	DefaultPort = 23
+
+	publicPrefix = "pub:"
+	fileVersion  = 1
+)
+
+// defaultPorts associa a ogni protocollo la sua porta standard.
+var defaultPorts = map[string]int{
+	"":       DefaultPort,
+	"telnet": DefaultPort,
+	"ssh":    22,
+	"rlogin": 513,
+	"raw":    DefaultPort,
+}
+
+// Politiche di riconnessione quando la linea cade
+const (
+	ReconnectOff  = "off"  // nessuna riconnessione (default)
+	ReconnectAsk  = "ask"  // chiede all'utente
+	ReconnectAuto = "auto" // riprova da sola, fino a ReconnectRetries volte
+
+	// DefaultReconnectRetries sono i tentativi con ReconnectAuto se la voce
+	// non li indica
+	DefaultReconnectRetries = 5
+	maxReconnectRetries     = 100
+)
+
+// Proxy speciali di una voce; gli altri valori sono nomi di profili delle
+// impostazioni
+const (
+	ProxyDirect = "direct" // connessione diretta (come "")
+	ProxyTor    = "tor"    // servizio Tor locale
+)
+
+// Limiti di Entry.Rows, come quelli del terminale
+const (
+	minRows = 5
+	maxRows = 200
+)
+
+var (
+	ErrNotFound  = errors.New("voce non trovata")
+	ErrDuplicate = errors.New("BBS già presente in rubrica")
+)
+
+// Entry è una voce della rubrica.
+type Entry struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Host     string `json:"host"`
+	Port     int    `json:"port"`
+	Protocol string `json:"protocol,omitempty"` // telnet (default), ssh, rlogin, raw
+	Proxy    string `json:"proxy,omitempty"`    // direct (default), tor o nome di un profilo
+	Font     string `json:"font,omitempty"`     // font preferito (es. "Topaz (Amiga)")
+	Favorite bool   `json:"favorite"`
+	Public   bool   `json:"public"`           // proviene dalla lista pubblica
+	Hidden   bool   `json:"hidden,omitempty"` // voce pubblica eliminata dall'utente
+
+	// Catalogazione
+	Software string   `json:"software,omitempty"` // software della BBS (Mystic, Synchronet...)
+	Tags     []string `json:"tags,omitempty"`     // es. "door games", "retro hw", "italiana"
+	Notes    string   `json:"notes,omitempty"`    // note libere dell'utente
+
+	// Riconnessione quando la linea cade
+	Reconnect        string `json:"reconnect,omitempty"`        // off (default), ask, auto
+	ReconnectRetries int    `json:"reconnectRetries,omitempty"` // tentativi con auto (0 = default)
+
+	// Righe del terminale: fisse per le BBS che ne aspettano 24 o 25 (0 =
+	// quelle della finestra); con StatusLine l'ultima resta al client per
+	// una riga di stato e la BBS ne vede una in meno
+	Rows       int  `json:"rows,omitempty"`
+	StatusLine bool `json:"statusLine,omitempty"`
+
+	// Testo inviato da Disconnect prima di chiudere (es. "G\r"), perché la
+	// BBS chiuda la sessione in modo pulito invece di vedere cadere la linea
+	Logoff string `json:"logoff,omitempty"`
+
+	// Statistiche di chiamata
+	LastConnected time.Time `json:"lastConnected"`
+	TotalCalls    int       `json:"totalCalls"`
+	TotalOnline   int64     `json:"totalOnline"` // secondi
+	TotalBytes    int64     `json:"totalBytes"`  // byte ricevuti
+
+	// Impostazioni dell'ultima connessione riuscita
+	LastUsed *LastUsed `json:"lastUsed,omitempty"`
+}
+
+// LastUsed sono le impostazioni del terminale con cui la BBS ha risposto
+// l'ultima volta.
+type LastUsed struct {
+	Emulation string `json:"emulation"`
+	IceColors bool   `json:"iceColors"`
+	Theme     string `json:"theme,omitempty"`
+	Cols      int    `json:"cols"`
+	Rows      int    `json:"rows"`
+}
+
+// Retries ritorna i tentativi di riconnessione automatica della voce.
+func (e Entry) Retries() int {
+	if e.ReconnectRetries <= 0 {
+		return DefaultReconnectRetries
+	}
+	return e.ReconnectRetries
+}
+
+// withStats ritorna una copia di e con le statistiche di src.
+func (e Entry) withStats(src Entry) Entry {
+	e.LastConnected = src.LastConnected
+	e.TotalCalls = src.TotalCalls
+	e.TotalOnline = src.TotalOnline
+	e.TotalBytes = src.TotalBytes
+	e.LastUsed = src.LastUsed
+	return e
+}
+
+// Key ritorna la chiave host:port (case-insensitive) di una BBS.
+func Key(host string, port int) string {
+	return fmt.Sprintf("%s:%d", strings.ToLower(host), port)
+}
+
+// Key ritorna la chiave host:port della voce.
+func (e Entry) Key() string {
+	return Key(e.Host, e.Port)
+}
+
+// normalize completa e valida i campi inseriti dall'utente.
+func (e *Entry) normalize() error {
+	e.Name = strings.TrimSpace(e.Name)
+	e.Host = strings.TrimSpace(e.Host)
+	if e.Host == "" {
+		return errors.New("host mancante")
+	}
+	e.Protocol = strings.ToLower(strings.TrimSpace(e.Protocol))
+	if e.Port == 0 {
+		e.Port = defaultPorts[e.Protocol]
+	}
+	if _, ok := defaultPorts[e.Protocol]; !ok {
+		return fmt.Errorf("protocollo non supportato: %s", e.Protocol)
+	}
+	if e.Port < 1 || e.Port > 65535 {
+		return fmt.Errorf("porta non valida: %d", e.Port)
+	}
+	if e.Name == "" {
+		e.Name = e.Host
+	}
+	e.Software = strings.TrimSpace(e.Software)
+	e.Proxy = strings.TrimSpace(e.Proxy)
+	if strings.EqualFold(e.Proxy, ProxyDirect) || strings.EqualFold(e.Proxy, ProxyTor) {
+		e.Proxy = strings.ToLower(e.Proxy)
+	}
+	e.Tags = normalizeTags(e.Tags)
+	switch e.Reconnect = strings.ToLower(strings.TrimSpace(e.Reconnect)); e.Reconnect {
+	case "", ReconnectOff, ReconnectAsk, ReconnectAuto:
+	default:
+		return fmt.Errorf("riconnessione non valida: %s", e.Reconnect)
+	}
+	if e.ReconnectRetries < 0 || e.ReconnectRetries > maxReconnectRetries {
+		return fmt.Errorf("tentativi di riconnessione non validi: %d", e.ReconnectRetries)
+	}
+	if e.Rows != 0 && (e.Rows < minRows || e.Rows > maxRows) {
+		return fmt.Errorf("righe non valide: %d", e.Rows)
+	}
+	return nil
+}
+
+// normalizeTags porta i tag in minuscolo, senza spazi superflui né
+// duplicati, mantenendo l'ordine.
+func normalizeTags(tags []string) []string {
+	var out []string
+	seen := map[string]bool{}
+	for _, t := range tags {
+		t = strings.Join(strings.Fields(strings.ToLower(t)), " ")
+		if t != "" && !seen[t] {
+			seen[t] = true
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// ─────────────────────────────────────────────
+// Book
+// ─────────────────────────────────────────────
+
+// Book è la rubrica: voci salvate (nell'ordine scelto dall'utente) più la
+// lista pubblica corrente. È sicuro per l'uso concorrente.
+type Book struct {
+	mu      sync.Mutex
+	path    string  // "" = solo in memoria
+	entries []Entry // voci salvate, incluse le copie di voci pubbliche
+	public  []Entry // lista pubblica, non salvata
+}
+
+// fileData è il formato su disco.
+type fileData struct {
+	Version int     `json:"version"`
+	Entries []Entry `json:"entries"`
+}
+
+// DefaultPath ritorna il percorso della rubrica nella directory di
+// configurazione dell'utente.
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "bbs-client", FileName), nil
+}
+
+// New crea una rubrica vuota salvata in path ("" = solo in memoria).
+func New(path string) *Book {
+	return &Book{path: path}
+}
+
+// Open carica la rubrica da path. Un file assente non è un errore.
+func Open(path string) (*Book, error) {
+	b := New(path)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return b, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var f fileData
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("rubrica %s non valida: %w", path, err)
+	}
+	b.entries = f.Entries
+	return b, nil
+}
+
+// SetPublic imposta la lista pubblica da unire alle voci salvate.
+func (b *Book) SetPublic(list []Entry) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.public = make([]Entry, 0, len(list))
+	for _, e := range list {
+		e.ID = publicPrefix + e.Key()
+		e.Public = true
+		b.public = append(b.public, e)
+	}
+}
+
+// List ritorna la rubrica completa: preferiti in cima, poi le voci salvate
+// nel loro ordine, poi le voci pubbliche non ancora personalizzate.
+func (b *Book) List() []Entry {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	stored := make(map[string]bool, len(b.entries))
+	list := make([]Entry, 0, len(b.entries)+len(b.public))
+	for _, e := range b.entries {
+		if e.Public {
+			stored[e.ID] = true
+		}
+		if !e.Hidden {
+			list = append(list, e)
+		}
+	}
+	for _, e := range b.public {
+		if !stored[e.ID] {
+			list = append(list, e)
+		}
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].Favorite && !list[j].Favorite
+	})
+	return list
+}
+
+// Add aggiunge una nuova voce utente e la ritorna con l'ID assegnato.
+func (b *Book) Add(e Entry) (Entry, error) {
+	if err := e.normalize(); err != nil {
+		return Entry{}, err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, old := range b.entries {
+		if !old.Hidden && old.Key() == e.Key() {
+			return Entry{}, ErrDuplicate
+		}
+	}
+	e.ID = newID()
+	e.Public = false
+	e.Hidden = false
+	b.entries = append(b.entries, e)
+	return e, b.save()
+}
+
+// Update sostituisce i campi della voce con lo stesso ID. Una voce
+// pubblica viene copiata tra quelle salvate.
+func (b *Book) Update(e Entry) error {
+	if err := e.normalize(); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	i, err := b.materialize(e.ID)
+	if err != nil {
+		return err
+	}
+	e.Public = b.entries[i].Public
+	e.Hidden = false
+	b.entries[i] = e.withStats(b.entries[i])
+	return b.save()
+}
+
+// Delete rimuove una voce. Le voci pubbliche restano come voci nascoste,
+// altrimenti ricomparirebbero al prossimo caricamento della lista.
+func (b *Book) Delete(id string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	i, err := b.materialize(id)
+	if err != nil {
+		return err
+	}
+	if b.entries[i].Public {
+		b.entries[i].Hidden = true
+	} else {
+		b.entries = append(b.entries[:i], b.entries[i+1:]...)
+	}
+	return b.save()
+}
+
+// Reorder mette le voci indicate in testa, nell'ordine dato; le altre
+// voci salvate seguono nel loro ordine attuale.
+func (b *Book) Reorder(ids []string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	front := make([]Entry, 0, len(ids))
+	moved := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if moved[id] {
+			continue
+		}
+		i, err := b.materialize(id)
+		if err != nil {
+			return err
+		}
+		front = append(front, b.entries[i])
+		moved[id] = true
+	}
+	for _, e := range b.entries {
+		if !moved[e.ID] {
+			front = append(front, e)
+		}
+	}
+	b.entries = front
+	return b.save()
+}
+
+// RecordCall registra una chiamata riuscita alla BBS host:port con le
+// impostazioni usate. Le BBS che non sono in rubrica vengono ignorate.
+func (b *Book) RecordCall(host string, port int, at time.Time, used LastUsed) error {
+	return b.updateStats(host, port, func(e *Entry) {
+		e.TotalCalls++
+		e.LastConnected = at
+		e.LastUsed = &used
+	})
+}
+
+// RecordSession aggiunge durata e byte ricevuti di una sessione conclusa.
+func (b *Book) RecordSession(host string, port int, online time.Duration, bytes int64) error {
+	return b.updateStats(host, port, func(e *Entry) {
+		e.TotalOnline += int64(online / time.Second)
+		e.TotalBytes += bytes
+	})
+}
+
+// SetSoftware annota il software riconosciuto della BBS host:port, se la
+// voce non ne indica già uno (quello scelto dall'utente non viene
+// sovrascritto).
+func (b *Book) SetSoftware(host string, port int, software string) error {
+	if e, ok := b.Lookup(host, port); !ok || e.Software != "" {
+		return nil
+	}
+	return b.updateStats(host, port, func(e *Entry) {
+		if e.Software == "" {
+			e.Software = software
+		}
+	})
+}
+
+// Lookup ritorna la voce visibile della BBS host:port.
+func (b *Book) Lookup(host string, port int) (Entry, bool) {
+	key := Key(host, port)
+	for _, e := range b.List() {
+		if e.Key() == key {
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
+
+// updateStats applica fn alla voce host:port e salva la rubrica.
+func (b *Book) updateStats(host string, port int, fn func(*Entry)) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	key := Key(host, port)
+	for i := range b.entries {
+		if !b.entries[i].Hidden && b.entries[i].Key() == key {
+			fn(&b.entries[i])
+			return b.save()
+		}
+	}
+	i, err := b.materialize(publicPrefix + key)
+	if err != nil {
+		return nil // BBS non in rubrica
+	}
+	fn(&b.entries[i])
+	return b.save()
+}
+
+// Find ritorna la voce con l'ID dato.
+func (b *Book) Find(id string) (Entry, bool) {
+	for _, e := range b.List() {
+		if e.ID == id {
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
+
+// materialize ritorna l'indice in b.entries della voce id, copiandovi la
+// voce pubblica corrispondente se non è ancora salvata. Chiamare con b.mu.
+func (b *Book) materialize(id string) (int, error) {
+	for i, e := range b.entries {
+		if e.ID == id {
+			return i, nil
+		}
+	}
+	for _, e := range b.public {
+		if e.ID == id {
+			b.entries = append(b.entries, e)
+			return len(b.entries) - 1, nil
+		}
+	}
+	return 0, ErrNotFound
+}
+
+// save scrive la rubrica su disco (scrittura atomica via file temporaneo).
+// Chiamare con b.mu acquisito.
+func (b *Book) save() error {
+	if b.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(fileData{Version: fileVersion, Entries: b.entries}, "", "  ")
+	if err != nil {
+		return err
+	}
+	// SEC-005: directory e file leggibili solo dall'utente
+	if err := os.MkdirAll(filepath.Dir(b.path), 0700); err != nil {
+		return err
+	}
+	tmp := b.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, b.path)
+}
+
+// newID genera un identificativo casuale per una voce utente.
+func newID() string {
+	var buf [8]byte
+	rand.Read(buf[:])
+	return hex.EncodeToString(buf[:])
+}
@@ -0,0 +1,110 @@
+package addressbook
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ─────────────────────────────────────────────
+// Export verso formati interoperabili
+// ─────────────────────────────────────────────
+
+// Formati di export supportati.
+const (
+	FormatJSON     = "json"
+	FormatCSV      = "csv"
+	FormatSyncTERM = "syncterm"
+)
+
+// Export scrive le voci nel formato indicato (json, csv, syncterm).
+func Export(w io.Writer, format string, entries []Entry) error {
+	switch strings.ToLower(format) {
+	case FormatJSON:
+		return ExportJSON(w, entries)
+	case FormatCSV:
+		return ExportCSV(w, entries)
+	case FormatSyncTERM:
+		return ExportSyncTERM(w, entries)
+	}
+	return fmt.Errorf("formato di export sconosciuto: %s", format)
+}
+
+// ExportJSON scrive le voci nello stesso formato del file della rubrica,
+// così il backup può essere reimportato o copiato in un'altra installazione.
+func ExportJSON(w io.Writer, entries []Entry) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(fileData{Version: fileVersion, Entries: entries})
+}
+
+// csvHeader sono le colonne dell'export CSV.
+var csvHeader = []string{
+	"name", "host", "port", "protocol", "font", "favorite",
+	"software", "tags", "notes",
+	"lastConnected", "totalCalls", "totalOnline", "totalBytes",
+}
+
+// ExportCSV scrive le voci come CSV con intestazione.
+func ExportCSV(w io.Writer, entries []Entry) error {
+	cw := csv.NewWriter(w)
+	cw.Write(csvHeader)
+	for _, e := range entries {
+		cw.Write([]string{
+			e.Name, e.Host, strconv.Itoa(e.Port),
+			protocolOrDefault(e.Protocol), e.Font, strconv.FormatBool(e.Favorite),
+			e.Software, strings.Join(e.Tags, ";"), e.Notes,
+			formatTime(e.LastConnected), strconv.Itoa(e.TotalCalls),
+			strconv.FormatInt(e.TotalOnline, 10), strconv.FormatInt(e.TotalBytes, 10),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// formatTime formatta un istante in RFC 3339 ("" se mai avvenuto).
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// syncTERMTypes è l'inverso di syncTERMProtocol.
+var syncTERMTypes = map[string]string{
+	"telnet": "Telnet",
+	"ssh":    "SSH",
+	"rlogin": "RLogin",
+	"raw":    "Raw",
+}
+
+// ExportSyncTERM scrive le voci come rubrica SyncTERM (syncterm.lst).
+func ExportSyncTERM(w io.Writer, entries []Entry) error {
+	for _, e := range entries {
+		// Le parentesi quadre chiuderebbero la sezione INI
+		name := strings.NewReplacer("[", "(", "]", ")").Replace(e.Name)
+		fmt.Fprintf(w, "[%s]\n", name)
+		fmt.Fprintf(w, "\tConnectionType=%s\n", syncTERMTypes[protocolOrDefault(e.Protocol)])
+		fmt.Fprintf(w, "\tAddress=%s\n", e.Host)
+		fmt.Fprintf(w, "\tPort=%d\n", e.Port)
+		if e.Font != "" {
+			fmt.Fprintf(w, "\tFont=%s\n", e.Font)
+		}
+		if _, err := fmt.Fprintln(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// protocolOrDefault ritorna il protocollo della voce, telnet se vuoto.
+func protocolOrDefault(p string) string {
+	if p == "" {
+		return "telnet"
+	}
+	return p
+}
@@ -0,0 +1,160 @@
+package addressbook
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// ─────────────────────────────────────────────
+// Import da altri client
+// ─────────────────────────────────────────────
+
+// ImportSyncTERM legge una rubrica SyncTERM (syncterm.lst, formato INI):
+// ogni sezione [Nome] è una BBS con chiavi Address, Port, ConnectionType
+// e Font. Le voci senza indirizzo di rete (modem, seriale, shell) sono
+// ignorate.
+func ImportSyncTERM(r io.Reader) ([]Entry, error) {
+	var entries []Entry
+	var cur *Entry
+	flush := func() {
+		if cur != nil && cur.Host != "" && cur.Protocol != "" {
+			entries = append(entries, *cur)
+		}
+		cur = nil
+	}
+
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		switch {
+		case line == "" || line[0] == ';' || line[0] == '#':
+			continue
+		case line[0] == '[' && strings.HasSuffix(line, "]"):
+			flush()
+			cur = &Entry{Name: line[1 : len(line)-1], Protocol: "telnet"}
+			continue
+		}
+		if cur == nil {
+			continue // chiavi globali prima della prima sezione
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "address":
+			cur.Host = value
+		case "port":
+			cur.Port, _ = strconv.Atoi(value)
+		case "connectiontype":
+			cur.Protocol = syncTERMProtocol(value)
+		case "font":
+			cur.Font = value
+		}
+	}
+	flush()
+	return entries, sc.Err()
+}
+
+// syncTERMProtocol converte un ConnectionType SyncTERM nel protocollo
+// della rubrica ("" = tipo senza equivalente, voce da scartare).
+func syncTERMProtocol(ct string) string {
+	ct = strings.ToLower(ct)
+	switch {
+	case strings.HasPrefix(ct, "telnet"):
+		return "telnet"
+	case strings.HasPrefix(ct, "ssh"):
+		return "ssh"
+	case strings.HasPrefix(ct, "rlogin"):
+		return "rlogin"
+	case ct == "raw":
+		return "raw"
+	}
+	return ""
+}
+
+// ImportNetRunner legge una rubrica NetRunner esportata come testo: una
+// BBS per riga nella forma "Nome<TAB>host[:porta]" oppure con un URI
+// telnet:// o ssh://; righe vuote e commenti (# o ;) sono ignorati. Senza
+// nome viene usato l'host.
+func ImportNetRunner(r io.Reader) ([]Entry, error) {
+	var entries []Entry
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || line[0] == ';' || line[0] == '#' {
+			continue
+		}
+
+		// L'indirizzo è l'ultimo campo, il nome tutto ciò che lo precede
+		name, addr := "", line
+		if i := strings.LastIndexAny(line, "\t "); i >= 0 {
+			name, addr = strings.TrimSpace(line[:i]), line[i+1:]
+		}
+		e, ok := parseAddress(addr)
+		if !ok {
+			continue
+		}
+		e.Name = name
+		entries = append(entries, e)
+	}
+	return entries, sc.Err()
+}
+
+// parseAddress interpreta "host", "host:porta" o "proto://host[:porta]".
+func parseAddress(addr string) (Entry, bool) {
+	e := Entry{Protocol: "telnet"}
+	if proto, rest, ok := strings.Cut(addr, "://"); ok {
+		e.Protocol = strings.ToLower(proto)
+		addr = strings.TrimSuffix(rest, "/")
+	}
+	if _, ok := defaultPorts[e.Protocol]; !ok {
+		return e, false
+	}
+	if host, port, err := net.SplitHostPort(addr); err == nil {
+		p, err := strconv.Atoi(port)
+		if err != nil {
+			return e, false
+		}
+		e.Host, e.Port = host, p
+	} else {
+		e.Host = addr
+	}
+	return e, e.Host != "" && !strings.ContainsAny(e.Host, "/ ")
+}
+
+// Import aggiunge le voci importate saltando quelle già presenti in
+// rubrica o non valide. Ritorna quante voci sono state aggiunte e saltate.
+func (b *Book) Import(entries []Entry) (added, skipped int, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	known := make(map[string]bool, len(b.entries)+len(b.public))
+	for _, e := range b.entries {
+		if !e.Hidden {
+			known[e.Key()] = true
+		}
+	}
+	for _, e := range b.public {
+		known[e.Key()] = true
+	}
+	for _, e := range entries {
+		if e.normalize() != nil || known[e.Key()] {
+			skipped++
+			continue
+		}
+		e.ID = newID()
+		e.Public, e.Hidden = false, false
+		b.entries = append(b.entries, e)
+		known[e.Key()] = true
+		added++
+	}
+	if added > 0 {
+		err = b.save()
+	}
+	return added, skipped, err
+}
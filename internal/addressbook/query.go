@@ -0,0 +1,95 @@
+package addressbook
+
+import (
+	"slices"
+	"sort"
+	"strings"
+)
+
+// ─────────────────────────────────────────────
+// Ricerca e filtri
+// ─────────────────────────────────────────────
+
+// Criteri di ordinamento di Query.Sort
+const (
+	SortDefault       = ""              // ordine della rubrica (preferiti in cima)
+	SortName          = "name"          // alfabetico
+	SortLastConnected = "lastConnected" // ultima chiamata, le più recenti prima
+	SortCalls         = "calls"         // numero di chiamate, le più usate prima
+)
+
+// Query filtra e ordina la rubrica. I campi vuoti non filtrano.
+type Query struct {
+	Text      string   `json:"text"`      // cerca in nome, host, software, tag e note
+	Tags      []string `json:"tags"`      // la voce deve avere tutti questi tag
+	Software  string   `json:"software"`  // software della BBS (senza maiuscole)
+	Favorites bool     `json:"favorites"` // solo i preferiti
+	Sort      string   `json:"sort"`
+}
+
+// Match dice se la voce soddisfa i filtri della query.
+func (q Query) Match(e Entry) bool {
+	if q.Favorites && !e.Favorite {
+		return false
+	}
+	if q.Software != "" && !strings.EqualFold(q.Software, e.Software) {
+		return false
+	}
+	for _, t := range normalizeTags(q.Tags) {
+		if !slices.Contains(e.Tags, t) {
+			return false
+		}
+	}
+	text := strings.ToLower(strings.TrimSpace(q.Text))
+	if text == "" {
+		return true
+	}
+	fields := append([]string{e.Name, e.Host, e.Software, e.Notes}, e.Tags...)
+	for _, f := range fields {
+		if strings.Contains(strings.ToLower(f), text) {
+			return true
+		}
+	}
+	return false
+}
+
+// Search ritorna le voci che soddisfano q, nell'ordine richiesto.
+func (b *Book) Search(q Query) []Entry {
+	var out []Entry
+	for _, e := range b.List() {
+		if q.Match(e) {
+			out = append(out, e)
+		}
+	}
+	switch q.Sort {
+	case SortName:
+		sort.SliceStable(out, func(i, j int) bool {
+			return strings.ToLower(out[i].Name) < strings.ToLower(out[j].Name)
+		})
+	case SortLastConnected:
+		sort.SliceStable(out, func(i, j int) bool {
+			return out[i].LastConnected.After(out[j].LastConnected)
+		})
+	case SortCalls:
+		sort.SliceStable(out, func(i, j int) bool {
+			return out[i].TotalCalls > out[j].TotalCalls
+		})
+	}
+	return out
+}
+
+// Tags ritorna tutti i tag usati nella rubrica, in ordine alfabetico.
+func (b *Book) Tags() []string {
+	seen := map[string]bool{}
+	var tags []string
+	for _, e := range b.List() {
+		for _, t := range e.Tags {
+			if !seen[t] {
+				seen[t] = true
+				tags = append(tags, t)
+			}
+		}
+	}
+	sort.Strings(tags)
+	return tags
+}
@@ -0,0 +1,31 @@
+package addressbook
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ParseURI interpreta un indirizzo telnet://host[:porta] (o ssh://,
+// rlogin://) come quelli dei link nelle pagine web delle BBS. L'eventuale
+// utente (telnet://utente@host) viene ignorato.
+func ParseURI(uri string) (Entry, error) {
+	u, err := url.Parse(strings.TrimSpace(uri))
+	if err != nil {
+		return Entry{}, fmt.Errorf("indirizzo non valido: %w", err)
+	}
+	e := Entry{Protocol: strings.ToLower(u.Scheme), Host: u.Hostname()}
+	if _, ok := defaultPorts[e.Protocol]; !ok || e.Protocol == "" {
+		return Entry{}, fmt.Errorf("schema non supportato: %q", u.Scheme)
+	}
+	if p := u.Port(); p != "" {
+		if e.Port, err = strconv.Atoi(p); err != nil {
+			return Entry{}, fmt.Errorf("porta non valida: %s", p)
+		}
+	}
+	if err := e.normalize(); err != nil {
+		return Entry{}, err
+	}
+	return e, nil
+}
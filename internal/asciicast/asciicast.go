@@ -0,0 +1,94 @@
+// Package asciicast scrive registrazioni di sessione nel formato
+// asciicast v2 (.cast) di asciinema: un header JSON seguito da un evento
+// [tempo, "o", dati] per riga, riproducibile con i player standard.
+//
+// Riferimento: https://docs.asciinema.org/manual/asciicast/v2/
+package asciicast
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// Header è la prima riga di un file .cast.
+type Header struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp,omitempty"`
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// Writer registra l'output di una sessione come eventi asciicast. È sicuro
+// per l'uso concorrente.
+type Writer struct {
+	mu      sync.Mutex
+	w       *bufio.Writer
+	c       io.Closer
+	start   time.Time
+	written int64
+	max     int64 // limite in byte dei dati registrati (0 = nessuno)
+}
+
+// NewWriter scrive l'header e ritorna un Writer che registra su wc. Gli
+// eventi successivi a max byte di dati vengono scartati (0 = illimitato).
+func NewWriter(wc io.WriteCloser, cols, rows int, title string, max int64) (*Writer, error) {
+	start := time.Now()
+	w := &Writer{w: bufio.NewWriter(wc), c: wc, start: start, max: max}
+	hdr := Header{
+		Version:   2,
+		Width:     cols,
+		Height:    rows,
+		Timestamp: start.Unix(),
+		Title:     title,
+		Env:       map[string]string{"TERM": "ansi"},
+	}
+	if err := w.writeLine(hdr); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Output registra dati ricevuti dal server con il tempo trascorso
+// dall'inizio della registrazione.
+func (w *Writer) Output(data string) error {
+	return w.event("o", data)
+}
+
+func (w *Writer) event(kind, data string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.max > 0 && w.written > w.max {
+		return nil // limite raggiunto: come il log, ignora in silenzio
+	}
+	w.written += int64(len(data))
+	elapsed := time.Since(w.start).Seconds()
+	return w.writeLine([]interface{}{elapsed, kind, data})
+}
+
+// writeLine serializza v su una riga. Chiamare con w.mu acquisito (o
+// prima che il Writer sia condiviso).
+func (w *Writer) writeLine(v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+	_, err = w.w.Write(b)
+	return err
+}
+
+// Close scarica il buffer e chiude il file.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	err := w.w.Flush()
+	if cerr := w.c.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
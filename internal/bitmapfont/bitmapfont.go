@@ -0,0 +1,288 @@
+// Package bitmapfont legge i font bitmap classici usati dall'ANSI art:
+// i dump grezzi della ROM video (.F08, .F14, .F16: 256 glifi larghi 8
+// pixel) e i font console PSF (versioni 1 e 2, con l'eventuale tabella
+// Unicode).
+//
+// I glifi sono indicizzati come i byte del file d'origine: senza tabella
+// Unicode un carattere dello schermo viene riportato al suo byte CP437,
+// così anche i font Amiga e Atari citati dai record SAUCE mostrano il
+// glifo che l'autore vedeva per quel byte.
+package bitmapfont
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/rj45lab/bbs-client-go/internal/cp437"
+)
+
+// Font è un font bitmap monospazio.
+type Font struct {
+	Name   string `json:"name"`
+	Width  int    `json:"width"`  // pixel
+	Height int    `json:"height"` // pixel
+	Glyphs int    `json:"glyphs"` // numero di glifi
+	Stride int    `json:"stride"` // byte per riga di un glifo
+	// Bitmap contiene Glyphs × Height righe di Stride byte, bit più
+	// significativo a sinistra
+	Bitmap  []byte `json:"bitmap"`
+	Unicode bool   `json:"unicode"` // il font ha una tabella Unicode
+
+	unicode map[rune]int
+}
+
+// Estensioni riconosciute, in ordine di preferenza
+var Extensions = []string{".psf", ".psfu", ".f16", ".f14", ".f08"}
+
+// Limiti dei glifi accettati
+const (
+	maxSize   = 64
+	maxGlyphs = 4096
+)
+
+var (
+	psf1Magic = []byte{0x36, 0x04}
+	psf2Magic = []byte{0x72, 0xb5, 0x4a, 0x86}
+)
+
+// ─────────────────────────────────────────────
+// Caricamento
+// ─────────────────────────────────────────────
+
+// Load legge un font da file. Il nome del font è quello del file senza
+// estensione.
+func Load(path string) (*Font, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	f, err := Parse(name, data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	return f, nil
+}
+
+// Parse riconosce il formato dai primi byte: PSF1, PSF2 o, in mancanza
+// di intestazione, un dump di 256 glifi larghi 8 pixel.
+func Parse(name string, data []byte) (*Font, error) {
+	switch {
+	case bytes.HasPrefix(data, psf2Magic):
+		return parsePSF2(name, data)
+	case bytes.HasPrefix(data, psf1Magic):
+		return parsePSF1(name, data)
+	}
+	return parseRaw(name, data)
+}
+
+// parseRaw legge un dump .Fxx: l'altezza si ricava dalla dimensione.
+func parseRaw(name string, data []byte) (*Font, error) {
+	h := len(data) / 256
+	if len(data)%256 != 0 || h < 1 || h > maxSize {
+		return nil, errors.New("formato non riconosciuto")
+	}
+	return &Font{Name: name, Width: 8, Height: h, Glyphs: 256, Stride: 1, Bitmap: data}, nil
+}
+
+// parsePSF1 legge un font PSF versione 1 (8 pixel di larghezza, 256 o
+// 512 glifi, tabella Unicode in UCS-2).
+func parsePSF1(name string, data []byte) (*Font, error) {
+	if len(data) < 4 {
+		return nil, errors.New("intestazione PSF1 troncata")
+	}
+	mode, h := data[2], int(data[3])
+	glyphs := 256
+	if mode&0x01 != 0 {
+		glyphs = 512
+	}
+	if h < 1 {
+		return nil, errors.New("altezza PSF1 non valida")
+	}
+	end := 4 + glyphs*h
+	if len(data) < end {
+		return nil, errors.New("glifi PSF1 troncati")
+	}
+	f := &Font{Name: name, Width: 8, Height: h, Glyphs: glyphs, Stride: 1, Bitmap: data[4:end]}
+	if mode&0x06 != 0 {
+		f.unicode = make(map[rune]int)
+		table := data[end:]
+		for g := 0; g < glyphs && len(table) >= 2; g++ {
+			seq := false // dopo 0xFFFE seguono sequenze combinate, ignorate
+			for len(table) >= 2 {
+				v := binary.LittleEndian.Uint16(table)
+				table = table[2:]
+				if v == 0xFFFF {
+					break
+				}
+				if v == 0xFFFE {
+					seq = true
+				} else if !seq {
+					f.addRune(rune(v), g)
+				}
+			}
+		}
+	}
+	return f, nil
+}
+
+// parsePSF2 legge un font PSF versione 2 (dimensioni qualsiasi, tabella
+// Unicode in UTF-8).
+func parsePSF2(name string, data []byte) (*Font, error) {
+	if len(data) < 32 {
+		return nil, errors.New("intestazione PSF2 troncata")
+	}
+	u32 := func(off int) int { return int(binary.LittleEndian.Uint32(data[off:])) }
+	header, flags, glyphs, size, h, w := u32(8), u32(12), u32(16), u32(20), u32(24), u32(28)
+	stride := (w + 7) / 8
+	if w < 1 || h < 1 || w > maxSize || h > maxSize || glyphs < 1 || glyphs > maxGlyphs || size != stride*h || header < 32 {
+		return nil, errors.New("intestazione PSF2 non valida")
+	}
+	end := header + glyphs*size
+	if len(data) < end {
+		return nil, errors.New("glifi PSF2 troncati")
+	}
+	f := &Font{Name: name, Width: w, Height: h, Glyphs: glyphs, Stride: stride, Bitmap: data[header:end]}
+	if flags&0x01 != 0 {
+		f.unicode = make(map[rune]int)
+		table := data[end:]
+		for g := 0; g < glyphs && len(table) > 0; g++ {
+			entry, rest, _ := bytes.Cut(table, []byte{0xFF})
+			table = rest
+			single, _, _ := bytes.Cut(entry, []byte{0xFE})
+			for len(single) > 0 {
+				r, n := utf8.DecodeRune(single)
+				single = single[n:]
+				if r != utf8.RuneError {
+					f.addRune(r, g)
+				}
+			}
+		}
+	}
+	return f, nil
+}
+
+// addRune associa r al glifo g, se non è già associato a un altro.
+func (f *Font) addRune(r rune, g int) {
+	f.Unicode = true
+	if _, ok := f.unicode[r]; !ok {
+		f.unicode[r] = g
+	}
+}
+
+// ─────────────────────────────────────────────
+// Glifi
+// ─────────────────────────────────────────────
+
+// Index ritorna il glifo che rappresenta r: dalla tabella Unicode se il
+// font ne ha una, altrimenti quello in posizione del byte CP437 di r.
+func (f *Font) Index(r rune) (int, bool) {
+	if f.unicode != nil {
+		g, ok := f.unicode[r]
+		return g, ok
+	}
+	b, ok := cp437.Byte(r)
+	if !ok || int(b) >= f.Glyphs {
+		return 0, false
+	}
+	return int(b), true
+}
+
+// Pixel dice se il pixel (x, y) del glifo g è acceso.
+func (f *Font) Pixel(g, x, y int) bool {
+	if g < 0 || g >= f.Glyphs || x < 0 || x >= f.Width || y < 0 || y >= f.Height {
+		return false
+	}
+	b := f.Bitmap[(g*f.Height+y)*f.Stride+x/8]
+	return b&(0x80>>(x%8)) != 0
+}
+
+// ─────────────────────────────────────────────
+// Font dei record SAUCE
+// ─────────────────────────────────────────────
+
+// sauceFiles associa i nomi dei font SAUCE (campo TInfoS) al nome del
+// file che li contiene, senza estensione.
+var sauceFiles = map[string]string{
+	"IBM VGA":               "ibm-vga",
+	"IBM VGA50":             "ibm-vga50",
+	"IBM VGA25G":            "ibm-vga25g",
+	"IBM EGA":               "ibm-ega",
+	"IBM EGA43":             "ibm-ega43",
+	"Amiga Topaz 1":         "topaz1",
+	"Amiga Topaz 1+":        "topaz1plus",
+	"Amiga Topaz 2":         "topaz2",
+	"Amiga Topaz 2+":        "topaz2plus",
+	"Amiga P0T-NOoDLE":      "pot-noodle",
+	"Amiga MicroKnight":     "microknight",
+	"Amiga MicroKnight+":    "microknightplus",
+	"Amiga mOsOul":          "mosoul",
+	"C64 PETSCII unshifted": "petscii-unshifted",
+	"C64 PETSCII shifted":   "petscii-shifted",
+	"Atari ATASCII":         "atascii",
+}
+
+// codePageRe separa la codepage dai nomi dei font IBM ("IBM VGA 850").
+var codePageRe = regexp.MustCompile(`^(IBM [A-Z0-9]+) (\d+)$`)
+
+// SAUCENames ritorna i nomi di file (senza estensione) da cercare per il
+// font SAUCE name, dal più specifico al più generico.
+func SAUCENames(name string) []string {
+	var out []string
+	if m := codePageRe.FindStringSubmatch(name); m != nil {
+		if base, ok := sauceFiles[m[1]]; ok {
+			out = append(out, base+"-"+m[2])
+			if m[2] == "437" {
+				out = append(out, base)
+			}
+		}
+	} else if base, ok := sauceFiles[name]; ok {
+		out = append(out, base)
+	}
+	if len(out) == 0 && normalize(name) != "" {
+		// Font fuori dalla specifica: il nome ridotto a minuscole e trattini
+		out = append(out, normalize(name))
+	}
+	return out
+}
+
+// normalize riduce un nome a minuscole, cifre e trattini.
+func normalize(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+	return b.String()
+}
+
+// FindSAUCE cerca in dir il file del font SAUCE name. Ritorna "" se non
+// c'è.
+func FindSAUCE(dir, name string) string {
+	for _, base := range SAUCENames(name) {
+		for _, ext := range Extensions {
+			for _, file := range []string{base + ext, base + strings.ToUpper(ext)} {
+				path := filepath.Join(dir, file)
+				if st, err := os.Stat(path); err == nil && st.Mode().IsRegular() {
+					return path
+				}
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,300 @@
+// Package bluewave legge i pacchetti di posta offline Blue Wave (versione
+// 3) e prepara i pacchetti di risposta da caricare sulla BBS.
+//
+// Un pacchetto di posta (<ID>.SU0, .MO1, ...) è un archivio con:
+//
+//	<ID>.INF  intestazione e elenco delle aree
+//	<ID>.MIX  indice dei messaggi per area
+//	<ID>.FTI  mittente, destinatario e oggetto di ogni messaggio
+//	<ID>.DAT  testo dei messaggi
+//
+// La risposta (<ID>.NEW) contiene <ID>.UPL, con un record per messaggio, e
+// un file di testo per ogni messaggio. Sono supportati solo gli archivi
+// ZIP (o le directory già estratte). I testi restano nella codifica del
+// pacchetto, di solito CP437: la conversione spetta al chiamante.
+package bluewave
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+)
+
+// Dimensioni originali delle strutture (versione 3). I pacchetti più
+// recenti possono dichiarare strutture più lunghe nell'intestazione .INF.
+const (
+	infHeaderLen   = 1230
+	infAreaInfoLen = 80
+	mixRecLen      = 14
+	ftiRecLen      = 186
+)
+
+// Flag delle aree (INF_AREA_INFO.area_flags)
+const (
+	AreaScanned  = 0x0001 // area selezionata dall'utente
+	AreaAlias    = 0x0002 // area con alias
+	AreaAnyName  = 0x0004 // qualunque nome ammesso
+	AreaEcho     = 0x0008 // echomail
+	AreaNetmail  = 0x0010 // netmail
+	AreaPost     = 0x0020 // l'utente può scrivere
+	AreaNoPublic = 0x0040 // solo messaggi privati
+	AreaNoPriv   = 0x0080 // solo messaggi pubblici
+)
+
+// ErrNotPacket indica che i file non formano un pacchetto Blue Wave.
+var ErrNotPacket = errors.New("non è un pacchetto Blue Wave")
+
+// Packet è un pacchetto di posta decodificato.
+type Packet struct {
+	ID        string `json:"id"` // nome dei file, ad esempio "OLOGRAF"
+	Version   int    `json:"version"`
+	BBS       string `json:"bbs"`
+	Sysop     string `json:"sysop"`
+	LoginName string `json:"loginName"`
+	AliasName string `json:"aliasName"`
+	Areas     []Area `json:"areas"`
+}
+
+// Area è un'area messaggi con i messaggi presenti nel pacchetto.
+type Area struct {
+	Number      string    `json:"number"`
+	Tag         string    `json:"tag"`
+	Title       string    `json:"title"`
+	Flags       uint16    `json:"flags"`
+	NetworkType byte      `json:"networkType"`
+	Messages    []Message `json:"messages"`
+}
+
+// Message è un messaggio del pacchetto.
+type Message struct {
+	Number  int    `json:"number"`
+	From    string `json:"from"`
+	To      string `json:"to"`
+	Subject string `json:"subject"`
+	Date    string `json:"date"`
+	ReplyTo int    `json:"replyTo"` // numero del messaggio a cui risponde
+	Flags   uint16 `json:"flags"`
+	Text    string `json:"text"`
+}
+
+// FindArea ritorna l'area con il tag (o il numero) indicato.
+func (p *Packet) FindArea(tag string) *Area {
+	for i := range p.Areas {
+		if strings.EqualFold(p.Areas[i].Tag, tag) || p.Areas[i].Number == tag {
+			return &p.Areas[i]
+		}
+	}
+	return nil
+}
+
+// OpenFile apre un pacchetto da un archivio ZIP o da una directory.
+func OpenFile(name string) (*Packet, error) {
+	info, err := os.Stat(name)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return Open(os.DirFS(name))
+	}
+	zr, err := zip.OpenReader(name)
+	if err != nil {
+		return nil, fmt.Errorf("%s: archivio non supportato (solo ZIP): %w", name, err)
+	}
+	defer zr.Close()
+	return Open(zr)
+}
+
+// Open legge un pacchetto dai file in fsys (nomi senza distinzione tra
+// maiuscole e minuscole).
+func Open(fsys fs.FS) (*Packet, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+	files := map[string]string{} // nome maiuscolo → nome reale
+	id := ""
+	for _, e := range entries {
+		upper := strings.ToUpper(e.Name())
+		files[upper] = e.Name()
+		if ext := path.Ext(upper); ext == ".INF" {
+			id = strings.TrimSuffix(upper, ext)
+		}
+	}
+	if id == "" {
+		return nil, ErrNotPacket
+	}
+	read := func(ext string) ([]byte, error) {
+		name, ok := files[id+ext]
+		if !ok {
+			return nil, fmt.Errorf("%w: manca %s%s", ErrNotPacket, id, ext)
+		}
+		return fs.ReadFile(fsys, name)
+	}
+
+	inf, err := read(".INF")
+	if err != nil {
+		return nil, err
+	}
+	p, sizes, err := parseINF(inf)
+	if err != nil {
+		return nil, err
+	}
+	p.ID = id
+
+	mix, err := read(".MIX")
+	if err != nil {
+		return nil, err
+	}
+	fti, err := read(".FTI")
+	if err != nil {
+		return nil, err
+	}
+	dat, err := read(".DAT")
+	if err != nil {
+		return nil, err
+	}
+	if err := p.parseMessages(mix, fti, dat, sizes); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// structSizes sono le dimensioni delle strutture dichiarate nel .INF.
+type structSizes struct {
+	header, area, mix, fti int
+}
+
+// Offset dei campi di INF_HEADER usati dal parser.
+const (
+	infOffLogin  = 76
+	infOffAlias  = 119
+	infOffSysop  = 192
+	infOffSystem = 235
+)
+
+// infOffStructs sono le posizioni possibili dei campi inf_header_len,
+// inf_areainfo_len, mix_structlen e fti_structlen: i door non concordano
+// sulla larghezza del campo is_QWK che li precede.
+var infOffStructs = []int{973, 972}
+
+// parseINF decodifica l'intestazione e l'elenco delle aree.
+func parseINF(data []byte) (*Packet, structSizes, error) {
+	sizes := structSizes{infHeaderLen, infAreaInfoLen, mixRecLen, ftiRecLen}
+	if len(data) < infHeaderLen || data[0] < 2 {
+		return nil, sizes, fmt.Errorf("%w: intestazione .INF non valida", ErrNotPacket)
+	}
+	p := &Packet{
+		Version:   int(data[0]),
+		LoginName: cstring(data[infOffLogin : infOffLogin+43]),
+		AliasName: cstring(data[infOffAlias : infOffAlias+43]),
+		Sysop:     cstring(data[infOffSysop : infOffSysop+41]),
+		BBS:       cstring(data[infOffSystem : infOffSystem+65]),
+	}
+
+	// I pacchetti v3 dichiarano le dimensioni delle strutture; valori
+	// assurdi (pacchetti più vecchi) lasciano quelle originali
+	if p.Version >= 3 {
+		le := binary.LittleEndian
+		for _, off := range infOffStructs {
+			d := structSizes{
+				header: int(le.Uint16(data[off:])),
+				area:   int(le.Uint16(data[off+2:])),
+				mix:    int(le.Uint16(data[off+4:])),
+				fti:    int(le.Uint16(data[off+6:])),
+			}
+			if d.header >= infHeaderLen && d.header <= len(data) &&
+				d.area >= infAreaInfoLen && d.area < 4096 &&
+				d.mix >= mixRecLen && d.mix < 4096 &&
+				d.fti >= ftiRecLen && d.fti < 4096 {
+				sizes = d
+				break
+			}
+		}
+	}
+
+	for off := sizes.header; off+infAreaInfoLen <= len(data); off += sizes.area {
+		rec := data[off:]
+		p.Areas = append(p.Areas, Area{
+			Number:      cstring(rec[0:6]),
+			Tag:         cstring(rec[6:27]),
+			Title:       cstring(rec[27:77]),
+			Flags:       binary.LittleEndian.Uint16(rec[77:79]),
+			NetworkType: rec[79],
+		})
+	}
+	return p, sizes, nil
+}
+
+// parseMessages associa alle aree i messaggi indicati dall'indice .MIX.
+func (p *Packet) parseMessages(mix, fti, dat []byte, sizes structSizes) error {
+	le := binary.LittleEndian
+	byNumber := map[string]*Area{}
+	for i := range p.Areas {
+		byNumber[p.Areas[i].Number] = &p.Areas[i]
+	}
+	for off := 0; off+mixRecLen <= len(mix); off += sizes.mix {
+		rec := mix[off:]
+		area := byNumber[cstring(rec[0:6])]
+		total := int(le.Uint16(rec[6:8]))
+		ptr := int(le.Uint32(rec[10:14]))
+		if area == nil {
+			continue
+		}
+		for n := 0; n < total; n++ {
+			at := ptr + n*sizes.fti
+			if at < 0 || at+ftiRecLen > len(fti) {
+				return fmt.Errorf("%w: indice .MIX oltre la fine di .FTI", ErrNotPacket)
+			}
+			m, err := parseFTI(fti[at:at+ftiRecLen], dat)
+			if err != nil {
+				return err
+			}
+			area.Messages = append(area.Messages, m)
+		}
+	}
+	return nil
+}
+
+// parseFTI decodifica un record FTI e il testo corrispondente in .DAT.
+func parseFTI(rec, dat []byte) (Message, error) {
+	le := binary.LittleEndian
+	m := Message{
+		From:    cstring(rec[0:36]),
+		To:      cstring(rec[36:72]),
+		Subject: cstring(rec[72:144]),
+		Date:    cstring(rec[144:164]),
+		Number:  int(le.Uint16(rec[164:166])),
+		ReplyTo: int(le.Uint16(rec[166:168])),
+		Flags:   le.Uint16(rec[178:180]),
+	}
+	ptr, n := int64(le.Uint32(rec[170:174])), int64(le.Uint32(rec[174:178]))
+	if ptr+n > int64(len(dat)) {
+		return m, fmt.Errorf("%w: testo del messaggio %d oltre la fine di .DAT", ErrNotPacket, m.Number)
+	}
+	m.Text = messageText(dat[ptr : ptr+n])
+	return m, nil
+}
+
+// messageText normalizza il testo: il primo byte di ogni messaggio è uno
+// spazio di riempimento, le righe finiscono con CR (o CR LF).
+func messageText(b []byte) string {
+	b = bytes.TrimPrefix(b, []byte{' '})
+	s := strings.ReplaceAll(string(b), "\r\n", "\n")
+	s = strings.ReplaceAll(s, "\r", "\n")
+	return strings.TrimRight(s, "\n\x00\x1a")
+}
+
+// cstring ritorna il contenuto di un campo a lunghezza fissa terminato
+// da NUL.
+func cstring(b []byte) string {
+	if i := bytes.IndexByte(b, 0); i >= 0 {
+		b = b[:i]
+	}
+	return strings.TrimSpace(string(b))
+}
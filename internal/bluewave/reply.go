@@ -0,0 +1,118 @@
+package bluewave
+
+import (
+	"archive/zip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ─────────────────────────────────────────────
+// Pacchetto di risposta (.NEW)
+// ─────────────────────────────────────────────
+
+// Dimensioni delle strutture del file .UPL (versione 3).
+const (
+	uplHeaderLen = 256
+	uplRecLen    = 245
+)
+
+// Attributi dei messaggi in uscita (UPL_REC.msg_attr)
+const (
+	UplPrivate = 0x0001
+	UplNetmail = 0x0008
+)
+
+// ReaderName è il nome del lettore scritto nelle risposte.
+var ReaderName = "BBS Client"
+
+// Reply è un messaggio scritto offline da caricare sulla BBS.
+type Reply struct {
+	Area    string `json:"area"` // tag (o numero) dell'area
+	To      string `json:"to"`
+	Subject string `json:"subject"`
+	Text    string `json:"text"`
+	ReplyTo int    `json:"replyTo"` // messaggio a cui si risponde (0 = nuovo)
+	Private bool   `json:"private"`
+}
+
+// WriteReplies scrive in w l'archivio ZIP delle risposte (<ID>.NEW) per il
+// pacchetto p: il file <ID>.UPL e un file di testo per ogni messaggio.
+func WriteReplies(w io.Writer, p *Packet, replies []Reply) error {
+	upl := make([]byte, uplHeaderLen, uplHeaderLen+len(replies)*uplRecLen)
+	putUPLHeader(upl, p)
+
+	zw := zip.NewWriter(w)
+	now := time.Now()
+	for i, r := range replies {
+		area := p.FindArea(r.Area)
+		if area == nil {
+			return fmt.Errorf("risposta %d: area sconosciuta %q", i+1, r.Area)
+		}
+		from := p.LoginName
+		if area.Flags&AreaAlias != 0 && p.AliasName != "" {
+			from = p.AliasName
+		}
+		name := fmt.Sprintf("%s.%03d", p.ID, i+1)
+
+		rec := make([]byte, uplRecLen)
+		putString(rec[0:36], from)
+		putString(rec[36:72], r.To)
+		putString(rec[72:144], r.Subject)
+		le := binary.LittleEndian
+		var attr uint16
+		if r.Private {
+			attr |= UplPrivate
+		}
+		if area.Flags&AreaNetmail != 0 {
+			attr |= UplNetmail
+		}
+		le.PutUint16(rec[152:154], attr)
+		le.PutUint32(rec[156:160], uint32(now.Unix()))
+		le.PutUint32(rec[160:164], uint32(r.ReplyTo))
+		putString(rec[164:177], name)
+		putString(rec[177:198], area.Tag)
+		le.PutUint16(rec[198:200], area.Flags)
+		upl = append(upl, rec...)
+
+		fw, err := zw.Create(name)
+		if err != nil {
+			return err
+		}
+		text := strings.ReplaceAll(r.Text, "\r\n", "\n")
+		if _, err := io.WriteString(fw, strings.ReplaceAll(text, "\n", "\r\n")); err != nil {
+			return err
+		}
+	}
+
+	fw, err := zw.Create(p.ID + ".UPL")
+	if err != nil {
+		return err
+	}
+	if _, err := fw.Write(upl); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// putUPLHeader compila UPL_HEADER: lettore, dimensioni delle strutture e
+// nomi dell'utente.
+func putUPLHeader(h []byte, p *Packet) {
+	putString(h[10:30], "1.00")
+	h[30], h[31] = 1, 0 // versione del lettore
+	putString(h[32:112], ReaderName)
+	binary.LittleEndian.PutUint16(h[112:114], uplHeaderLen)
+	binary.LittleEndian.PutUint16(h[114:116], uplRecLen)
+	putString(h[116:159], p.LoginName)
+	putString(h[159:202], p.AliasName)
+	putString(h[202:218], ReaderName)
+}
+
+// putString copia s in un campo a lunghezza fissa, lasciando spazio per
+// il NUL finale.
+func putString(dst []byte, s string) {
+	n := copy(dst[:len(dst)-1], s)
+	clear(dst[n:])
+}
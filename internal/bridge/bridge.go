@@ -0,0 +1,257 @@
+// Package bridge collega un programma locale a una sessione BBS: i byte
+// ricevuti dalla BBS vanno sullo stdin del programma e quello che il
+// programma scrive su stdout va alla BBS, senza conversioni. Serve per le
+// door eseguite in locale, per i gestori di protocolli non supportati dal
+// client e per gli esperimenti.
+//
+// Il ponte si chiude quando il programma esce, quando viene fermato, quando
+// supera il limite di byte in una delle due direzioni o quando smette di
+// leggere: la sessione non resta mai bloccata ad aspettarlo. Quello che il
+// programma scrive su stderr finisce nel log del client.
+package bridge
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"log"
+	"os/exec"
+	"slices"
+	"sync"
+)
+
+const (
+	// queueSize è il numero di blocchi ricevuti in attesa di essere scritti
+	// sullo stdin del programma: oltre, il ponte si chiude (ReasonStalled)
+	queueSize = 256
+	// chunkSize è la dimensione massima di un blocco letto da stdout
+	chunkSize = 4096
+	// maxLine limita una riga di stderr
+	maxLine = 1 << 20
+)
+
+// Motivi della chiusura (Result.Reason)
+const (
+	ReasonExited  = "exited"  // il programma è uscito
+	ReasonStopped = "stopped" // fermato con Stop
+	ReasonLimit   = "limit"   // superato MaxIn o MaxOut
+	ReasonStalled = "stalled" // il programma non legge lo stdin
+	ReasonError   = "error"   // invio alla BBS fallito
+)
+
+// Spec descrive il programma da collegare.
+type Spec struct {
+	Command string
+	Args    []string
+	MaxIn   int64 // byte dalla BBS al programma (0 = senza limite)
+	MaxOut  int64 // byte dal programma alla BBS (0 = senza limite)
+}
+
+// Result riassume un ponte chiuso.
+type Result struct {
+	BytesIn  int64  `json:"bytesIn"`  // byte passati dalla BBS al programma
+	BytesOut int64  `json:"bytesOut"` // byte passati dal programma alla BBS
+	Reason   string `json:"reason"`
+}
+
+// Bridge è un programma collegato alla sessione. Write va chiamato da una
+// sola goroutine alla volta (il loop eventi della sessione); Stop e Result
+// da qualsiasi goroutine.
+type Bridge struct {
+	name string
+	cmd  *exec.Cmd
+	send func([]byte) error
+
+	queue  chan []byte
+	exited chan struct{} // chiuso all'uscita del processo
+
+	mu      sync.Mutex
+	closed  bool
+	maxIn   int64
+	maxOut  int64
+	in, out int64
+	reason  string
+}
+
+// Start avvia il programma; send riceve quello che scrive su stdout, da
+// inoltrare alla BBS.
+func Start(spec Spec, send func([]byte) error) (*Bridge, error) {
+	if spec.Command == "" {
+		return nil, errors.New("comando del ponte mancante")
+	}
+	cmd := exec.Command(spec.Command, spec.Args...)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	b := &Bridge{
+		name:   spec.Command,
+		cmd:    cmd,
+		send:   send,
+		queue:  make(chan []byte, queueSize),
+		exited: make(chan struct{}),
+		maxIn:  spec.MaxIn,
+		maxOut: spec.MaxOut,
+	}
+	var readers sync.WaitGroup
+	readers.Add(2)
+	go func() {
+		defer readers.Done()
+		b.readLoop(stdout)
+	}()
+	go func() {
+		defer readers.Done()
+		b.logStderr(stderr)
+	}()
+	go b.writeLoop(stdin)
+	go func() {
+		// Wait va chiamato dopo aver letto tutto stdout e stderr
+		readers.Wait()
+		err := cmd.Wait()
+		b.mu.Lock()
+		b.stopLocked(ReasonExited)
+		b.mu.Unlock()
+		close(b.exited)
+		log.Printf("[BRIDGE] %s terminato: %v", b.name, exitStatus(err))
+	}()
+
+	log.Printf("[BRIDGE] %s avviato (pid %d)", b.name, cmd.Process.Pid)
+	return b, nil
+}
+
+// Write passa al programma i byte ricevuti dalla BBS senza mai bloccare.
+func (b *Bridge) Write(data []byte) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return
+	}
+	if b.maxIn > 0 && b.in+int64(len(data)) > b.maxIn {
+		b.stopLocked(ReasonLimit)
+		return
+	}
+	select {
+	case b.queue <- slices.Clone(data):
+		b.in += int64(len(data))
+	default:
+		b.stopLocked(ReasonStalled)
+	}
+}
+
+// Stop chiude il ponte e termina il programma.
+func (b *Bridge) Stop() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.stopLocked(ReasonStopped)
+}
+
+// Done ritorna un canale chiuso all'uscita del programma.
+func (b *Bridge) Done() <-chan struct{} {
+	return b.exited
+}
+
+// Result ritorna i byte passati finora e il motivo della chiusura ("" se
+// il ponte è ancora aperto).
+func (b *Bridge) Result() Result {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return Result{BytesIn: b.in, BytesOut: b.out, Reason: b.reason}
+}
+
+// stopLocked chiude il ponte la prima volta con il motivo reason: chiude
+// la coda (writeLoop chiude lo stdin) e termina il programma se è ancora
+// in esecuzione. Chiamare con b.mu acquisito.
+func (b *Bridge) stopLocked(reason string) {
+	if b.closed {
+		return
+	}
+	b.closed = true
+	b.reason = reason
+	close(b.queue)
+	if reason != ReasonExited {
+		log.Printf("[BRIDGE] %s chiuso: %s", b.name, reason)
+		b.cmd.Process.Kill()
+	}
+}
+
+// writeLoop scrive i blocchi accodati sullo stdin del programma.
+func (b *Bridge) writeLoop(stdin io.WriteCloser) {
+	defer stdin.Close()
+	for data := range b.queue {
+		if _, err := stdin.Write(data); err != nil {
+			// Programma uscito o stdin chiuso: svuota la coda fino alla chiusura
+			for range b.queue {
+			}
+			return
+		}
+	}
+}
+
+// readLoop inoltra alla BBS quello che il programma scrive su stdout.
+func (b *Bridge) readLoop(stdout io.Reader) {
+	buf := make([]byte, chunkSize)
+	for {
+		n, err := stdout.Read(buf)
+		if n > 0 && !b.forward(buf[:n]) {
+			io.Copy(io.Discard, stdout) // il programma non resta bloccato
+			return
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// forward invia data alla BBS se il ponte è aperto e il limite lo
+// consente.
+func (b *Bridge) forward(data []byte) bool {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return false
+	}
+	if b.maxOut > 0 && b.out+int64(len(data)) > b.maxOut {
+		b.stopLocked(ReasonLimit)
+		b.mu.Unlock()
+		return false
+	}
+	b.out += int64(len(data))
+	b.mu.Unlock()
+	if err := b.send(data); err != nil {
+		log.Printf("[BRIDGE] %s: invio alla BBS fallito: %v", b.name, err)
+		b.mu.Lock()
+		b.stopLocked(ReasonError)
+		b.mu.Unlock()
+		return false
+	}
+	return true
+}
+
+// logStderr copia nel log quello che il programma scrive su stderr.
+func (b *Bridge) logStderr(stderr io.Reader) {
+	sc := bufio.NewScanner(stderr)
+	sc.Buffer(make([]byte, 0, 4096), maxLine)
+	for sc.Scan() {
+		log.Printf("[BRIDGE] %s (stderr): %s", b.name, sc.Text())
+	}
+}
+
+// exitStatus descrive l'esito del processo per il log.
+func exitStatus(err error) string {
+	if err == nil {
+		return "uscita regolare"
+	}
+	return err.Error()
+}
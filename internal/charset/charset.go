@@ -0,0 +1,125 @@
+// Package charset è lo strato di codifica tra la connessione e lo
+// schermo: converte i byte ricevuti dalla BBS nel testo che arriva a
+// schermo, log e script, e il testo da inviare nei byte che la BBS si
+// aspetta. Le BBS classiche usano CP437, quelle recenti anche UTF-8.
+package charset
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/rj45lab/bbs-client-go/internal/cp437"
+	"github.com/rj45lab/bbs-client-go/pkg/ansi"
+)
+
+// Nomi delle codifiche disponibili.
+const (
+	CP437 = "cp437"
+	UTF8  = "utf8"
+)
+
+// Names elenca le codifiche disponibili.
+var Names = []string{CP437, UTF8}
+
+// Encoding è una codifica dei dati scambiati con la BBS.
+type Encoding interface {
+	// Name ritorna il nome della codifica (CP437, UTF8)
+	Name() string
+	// NewDecoder crea un Decoder per un flusso ricevuto. Con c1 i
+	// controlli C1 a 8 bit restano tali (vedi ansi.IsC1Control).
+	NewDecoder(c1 bool) Decoder
+	// Encode converte il testo da inviare; i caratteri che la codifica
+	// non ha vengono traslitterati o sostituiti
+	Encode(text string) []byte
+}
+
+// Decoder converte un flusso di byte in testo, un blocco alla volta. I
+// controlli C0 (ESC, CR, LF...) restano tali, così il parser ANSI li
+// riconosce. Non è sicuro per l'uso concorrente.
+type Decoder interface {
+	// Decode converte il blocco data; un carattere spezzato tra due
+	// blocchi viene completato al blocco successivo
+	Decode(data []byte) string
+}
+
+// Lookup ritorna la codifica name ("" = CP437).
+func Lookup(name string) (Encoding, error) {
+	switch name {
+	case "", CP437:
+		return cp437Encoding{}, nil
+	case UTF8:
+		return utf8Encoding{}, nil
+	}
+	return nil, fmt.Errorf("codifica sconosciuta: %s", name)
+}
+
+// ─────────────────────────────────────────────
+// CP437
+// ─────────────────────────────────────────────
+
+type cp437Encoding struct{}
+
+func (cp437Encoding) Name() string { return CP437 }
+
+func (cp437Encoding) NewDecoder(c1 bool) Decoder { return cp437Decoder{c1: c1} }
+
+func (cp437Encoding) Encode(text string) []byte { return cp437.Encode(text) }
+
+type cp437Decoder struct{ c1 bool }
+
+// Decode costruisce il testo con una sola allocazione.
+func (d cp437Decoder) Decode(data []byte) string {
+	var b strings.Builder
+	b.Grow(len(data) * 3) // i caratteri CP437 occupano al più 3 byte in UTF-8
+	for _, c := range data {
+		b.WriteRune(ansi.CP437Rune(c, d.c1))
+	}
+	return b.String()
+}
+
+// ─────────────────────────────────────────────
+// UTF-8
+// ─────────────────────────────────────────────
+
+type utf8Encoding struct{}
+
+func (utf8Encoding) Name() string { return UTF8 }
+
+func (utf8Encoding) NewDecoder(c1 bool) Decoder { return &utf8Decoder{c1: c1} }
+
+func (utf8Encoding) Encode(text string) []byte { return []byte(text) }
+
+// utf8Decoder tiene da parte la sequenza incompleta alla fine di un
+// blocco. I byte che non formano UTF-8 valido sono letti come CP437: le
+// BBS che usano UTF-8 mostrano spesso anche vecchie schermate ANSI.
+type utf8Decoder struct {
+	c1      bool
+	pending []byte
+}
+
+func (d *utf8Decoder) Decode(data []byte) string {
+	if len(d.pending) > 0 {
+		data = append(d.pending, data...)
+		d.pending = nil
+	}
+	var b strings.Builder
+	b.Grow(len(data))
+	for len(data) > 0 {
+		r, size := utf8.DecodeRune(data)
+		switch {
+		case r != utf8.RuneError || size > 1:
+			if !d.c1 && r >= 0x80 && r < 0xA0 {
+				r = utf8.RuneError // C1 non richiesti: non pilotano lo schermo
+			}
+			b.WriteRune(r)
+		case !utf8.FullRune(data):
+			d.pending = append([]byte(nil), data...)
+			return b.String()
+		default:
+			b.WriteRune(ansi.CP437Rune(data[0], false))
+		}
+		data = data[size:]
+	}
+	return b.String()
+}
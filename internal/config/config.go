@@ -0,0 +1,482 @@
+// Package config gestisce le impostazioni persistenti del client, salvate
+// in JSON nella directory di configurazione dell'utente.
+//
+// Il file ha un numero di versione: all'apertura i file di versioni
+// precedenti vengono convertiti dalle migrazioni registrate, mentre i
+// campi assenti prendono i valori di default. Un file scritto da una
+// versione più recente del client non viene mai sovrascritto.
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+)
+
+// ─────────────────────────────────────────────
+// Settings
+// ─────────────────────────────────────────────
+
+const (
+	// FileName è il nome del file delle impostazioni
+	FileName = "settings.json"
+	// CurrentVersion è la versione del formato scritta da questo client
+	CurrentVersion = 1
+)
+
+// Settings contiene tutte le impostazioni configurabili.
+type Settings struct {
+	Version int `json:"version"`
+
+	// Lingua dei messaggi del backend (it, en)
+	Language string `json:"language"`
+
+	// Layout della tastiera per accenti e tasti morti (it, us, us-intl,
+	// de, fr, es; "" = it)
+	Keyboard string `json:"keyboard,omitempty"`
+
+	// Resa dello schermo
+	Palette       string          `json:"palette"`                 // vga, xterm, amiga, custom
+	CustomPalette []string        `json:"customPalette,omitempty"` // 16 colori "#rrggbb"
+	BoldPolicy    string          `json:"boldPolicy"`              // both, bright, font
+	C1Controls    bool            `json:"c1Controls"`
+	IceColors     map[string]bool `json:"iceColors"` // per BBS (host:port)
+
+	// Tema scelto ("" = palette e bold policy qui sopra), temi creati
+	// dall'utente e tema per BBS (host:port → nome)
+	Theme     string            `json:"theme"`
+	Themes    []Theme           `json:"themes"`
+	BBSThemes map[string]string `json:"bbsThemes"`
+
+	// Font bitmap per gli export PNG e GIF: file nella cartella fonts
+	// della configurazione ("" = font interno)
+	ExportFont string `json:"exportFont"`
+
+	// Emulazione del terminale per BBS (host:port → ansi-bbs, vt100, ascii)
+	Emulation map[string]string `json:"emulation"`
+	// Varianti dei tasti speciali per BBS (host:port → vt, xterm, sco;
+	// assente = quelle dell'emulazione)
+	Keys map[string]string `json:"keys"`
+	// Codifica per BBS (host:port → cp437, utf8; assente = quella del
+	// software riconosciuto)
+	Charset map[string]string `json:"charset"`
+
+	// File scritti per ogni sessione
+	Logging Logging `json:"logging"`
+
+	// Limiti anti-flooding
+	Limits Limits `json:"limits"`
+
+	// Invio di testo incollato o da file
+	Paste Paste `json:"paste"`
+	// Ritmo di invio per BBS (host:port), al posto di quello di Paste
+	Pacing map[string]Pacing `json:"pacing"`
+
+	// Frasi da segnalare, per BBS (host:port; "*" = tutte le BBS)
+	Watch       map[string][]string `json:"watch"`
+	WatchNotify bool                `json:"watchNotify"` // anche come notifica di sistema
+
+	// Chiudere la finestra la nasconde nel tray, la sessione resta attiva
+	CloseToTray bool `json:"closeToTray"`
+
+	// Comportamento con l'utente inattivo
+	Idle Idle `json:"idle"`
+
+	// Nome utente per BBS (host:port); le password sono nel portachiavi
+	Logins map[string]string `json:"logins"`
+	// Testo che precede la richiesta della password, atteso da SendLogin
+	// ("" = "assword", che copre Password e password)
+	PasswordPrompt string `json:"passwordPrompt,omitempty"`
+	// Login automatico IEMSI
+	IEMSI IEMSI `json:"iemsi"`
+
+	// Chiamate programmate
+	Schedules []Schedule `json:"schedules"`
+
+	// Condivisione dello schermo in sola lettura
+	Share Share `json:"share"`
+
+	// Destinazione delle stampe richieste dalla BBS (media copy):
+	// file, system (stampante del sistema, con conferma), off
+	Printer string `json:"printer"`
+
+	// File offerti dalla BBS con ZMODEM: always, ask (conferma), never
+	Downloads string `json:"downloads"`
+	// Tipi di file rifiutati o segnalati nei download
+	FileTypes FileTypes `json:"fileTypes"`
+
+	// Controllo di flusso XON/XOFF della BBS (alcuni gateway lo usano
+	// ancora): i due caratteri non arrivano allo schermo e sospendono
+	// l'invio, upload compresi
+	FlowControl bool `json:"flowControl"`
+
+	// Eventi "screen-lines" con le righe cambiate, per i lettori di
+	// schermo
+	Accessibility bool `json:"accessibility"`
+
+	// Profili di proxy, scelti per BBS nella rubrica (Entry.Proxy)
+	Proxies []Proxy `json:"proxies"`
+	// Indirizzo SOCKS del servizio Tor ("" = 127.0.0.1:9050)
+	Tor string `json:"tor,omitempty"`
+
+	// Plugin esterni avviati alla connessione (vedi internal/plugin)
+	Plugins []Plugin `json:"plugins"`
+
+	// Endpoint JSON-RPC locale per comandare il client da script esterni
+	Control Control `json:"control"`
+}
+
+// Logging seleziona i file scritti per ogni sessione.
+type Logging struct {
+	ANSI       bool `json:"ansi"`       // log con sequenze ANSI (+ tempi)
+	Transcript bool `json:"transcript"` // trascrizione in testo semplice
+	Asciicast  bool `json:"asciicast"`  // registrazione .cast (asciinema)
+	Raw        bool `json:"raw"`        // byte ricevuti prima della decodifica (.raw)
+	Events     bool `json:"events"`     // eventi della sessione in JSON Lines (.jsonl)
+	// Divisione dei log delle sessioni lunghe in parti numerate: a
+	// mezzanotte e/o ogni SplitHours ore (0 = mai)
+	SplitDaily bool `json:"splitDaily"`
+	SplitHours int  `json:"splitHours"`
+}
+
+// Proxy è un profilo di proxy. La password è nel portachiavi.
+type Proxy struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"` // socks5, http (CONNECT)
+	Host     string `json:"host"`
+	Port     int    `json:"port"`
+	Username string `json:"username,omitempty"`
+}
+
+// FileTypes sono le estensioni dei file ricevuti con ZMODEM ("exe" o
+// ".exe") da rifiutare o da segnalare all'utente.
+type FileTypes struct {
+	Blocked []string `json:"blocked"` // rifiutati con ZSKIP
+	Warned  []string `json:"warned"`  // ricevuti, con un avviso
+}
+
+// Limits sono le protezioni contro una BBS che inonda il client di dati.
+type Limits struct {
+	LogSizeMB       int `json:"logSizeMB"`       // dimensione massima di log, trascrizioni e catture
+	DataBuffer      int `json:"dataBuffer"`      // blocchi ricevuti in coda (dalle nuove schede)
+	UpdatesPerSec   int `json:"updatesPerSec"`   // aggiornamenti dello schermo al secondo
+	ScrollbackLines int `json:"scrollbackLines"` // righe conservate nello scrollback (0 = nessuna)
+}
+
+// Paste regola l'invio di testo incollato: conferma per i testi lunghi e
+// ritmo di invio, perché gli editor di riga delle BBS perdono caratteri
+// se il testo arriva tutto insieme.
+type Paste struct {
+	CharDelayMs  int `json:"charDelayMs"`  // pausa tra i caratteri (0 = riga intera)
+	LineDelayMs  int `json:"lineDelayMs"`  // pausa dopo ogni fine riga
+	ConfirmLines int `json:"confirmLines"` // chiedi conferma oltre N righe (0 = mai)
+	ConfirmChars int `json:"confirmChars"` // chiedi conferma oltre N caratteri (0 = mai)
+}
+
+// Pacing è il ritmo di invio scelto per una BBS: il suo editor di riga
+// può richiedere pause diverse da quelle di default.
+type Pacing struct {
+	CharDelayMs int `json:"charDelayMs"`
+	LineDelayMs int `json:"lineDelayMs"`
+}
+
+// Idle regola cosa fare quando l'utente non digita nulla per un po'.
+// Tutti i tempi sono in minuti; 0 disattiva il comportamento.
+type Idle struct {
+	WarnMinutes       int    `json:"warnMinutes"`       // avviso con conto alla rovescia
+	KeepaliveMinutes  int    `json:"keepaliveMinutes"`  // intervallo dei keepalive
+	KeepaliveText     string `json:"keepaliveText"`     // testo inviato ("" = IAC NOP)
+	DisconnectMinutes int    `json:"disconnectMinutes"` // disconnessione automatica
+}
+
+// IEMSI regola il login automatico con le BBS che lo supportano. Nome e
+// password sono quelli della BBS (Logins e portachiavi): senza nome
+// l'handshake non parte.
+type IEMSI struct {
+	Enabled  bool   `json:"enabled"`
+	Alias    string `json:"alias,omitempty"`
+	Location string `json:"location,omitempty"` // città, inviata alla BBS
+}
+
+// Share regola il server di condivisione dello schermo (WebSocket).
+type Share struct {
+	Port      int  `json:"port"`
+	LocalOnly bool `json:"localOnly"` // solo da questo computer (127.0.0.1)
+}
+
+// Schedule è una chiamata programmata: connessione a un'ora fissa, script
+// facoltativo e disconnessione.
+type Schedule struct {
+	ID         string `json:"id"`
+	Enabled    bool   `json:"enabled"`
+	Name       string `json:"name"` // nome della BBS
+	Host       string `json:"host"`
+	Port       int    `json:"port"`
+	Time       string `json:"time"`             // "HH:MM", ora locale
+	Days       []int  `json:"days,omitempty"`   // giorni (0 = domenica); vuoto = ogni giorno
+	Script     string `json:"script,omitempty"` // script Lua eseguito dopo la connessione
+	Disconnect bool   `json:"disconnect"`       // riaggancia a fine script
+}
+
+// Theme è un aspetto del terminale: colori, font, cursore e sfondo.
+type Theme struct {
+	Name          string   `json:"name"`
+	Palette       string   `json:"palette"`                 // vga, xterm, amiga, custom
+	CustomPalette []string `json:"customPalette,omitempty"` // 16 colori "#rrggbb"
+	BoldPolicy    string   `json:"boldPolicy"`              // both, bright, font
+	Font          string   `json:"font"`                    // ibm-vga, vt323
+	FontSize      int      `json:"fontSize"`                // pixel
+	Cursor        string   `json:"cursor"`                  // block, underline, bar
+	Background    string   `json:"background"`              // "#rrggbb", attorno al terminale
+}
+
+// Control regola l'endpoint di controllo locale (vedi internal/control).
+type Control struct {
+	Enabled bool `json:"enabled"`
+	Port    int  `json:"port"` // su 127.0.0.1
+}
+
+// Plugin è un plugin esterno: un programma che dialoga con il client su
+// stdin/stdout per tutta la durata di una connessione.
+type Plugin struct {
+	Name    string   `json:"name"`
+	Enabled bool     `json:"enabled"`
+	Command string   `json:"command"`
+	Args    []string `json:"args,omitempty"`
+	BBS     []string `json:"bbs,omitempty"` // BBS key (host:port) a cui si applica; vuoto = tutte
+}
+
+// Defaults ritorna le impostazioni di fabbrica.
+func Defaults() Settings {
+	return Settings{
+		Version:    CurrentVersion,
+		Language:   "it",
+		Palette:    "vga",
+		BoldPolicy: "both",
+		IceColors:  map[string]bool{},
+		Emulation:  map[string]string{},
+		Keys:       map[string]string{},
+		Charset:    map[string]string{},
+		BBSThemes:  map[string]string{},
+		Logins:     map[string]string{},
+		Pacing:     map[string]Pacing{},
+		Watch:      map[string][]string{},
+		Logging:    Logging{ANSI: true, Transcript: true},
+		Limits:     Limits{LogSizeMB: 50, DataBuffer: 256, UpdatesPerSec: 60, ScrollbackLines: 10000},
+		Paste:      Paste{CharDelayMs: 5, LineDelayMs: 150, ConfirmLines: 1, ConfirmChars: 256},
+		Share:      Share{Port: 8023},
+		Printer:    "file",
+		Downloads:  "ask",
+		Control:    Control{Port: 8024},
+		FileTypes: FileTypes{
+			Blocked: []string{".exe", ".scr", ".com", ".bat", ".cmd", ".pif", ".msi", ".vbs", ".js", ".lnk"},
+			Warned:  []string{".zip", ".arj", ".lzh", ".lha", ".rar", ".7z", ".arc", ".zoo"},
+		},
+	}
+}
+
+// Clone ritorna una copia indipendente (mappe e slice comprese).
+func (s Settings) Clone() Settings {
+	s.CustomPalette = slices.Clone(s.CustomPalette)
+	s.IceColors = maps.Clone(s.IceColors)
+	if s.IceColors == nil {
+		s.IceColors = map[string]bool{}
+	}
+	s.Emulation = maps.Clone(s.Emulation)
+	if s.Emulation == nil {
+		s.Emulation = map[string]string{}
+	}
+	s.Keys = maps.Clone(s.Keys)
+	if s.Keys == nil {
+		s.Keys = map[string]string{}
+	}
+	s.Charset = maps.Clone(s.Charset)
+	if s.Charset == nil {
+		s.Charset = map[string]string{}
+	}
+	s.Themes = slices.Clone(s.Themes)
+	for i := range s.Themes {
+		s.Themes[i].CustomPalette = slices.Clone(s.Themes[i].CustomPalette)
+	}
+	s.BBSThemes = maps.Clone(s.BBSThemes)
+	if s.BBSThemes == nil {
+		s.BBSThemes = map[string]string{}
+	}
+	s.Logins = maps.Clone(s.Logins)
+	if s.Logins == nil {
+		s.Logins = map[string]string{}
+	}
+	s.Pacing = maps.Clone(s.Pacing)
+	if s.Pacing == nil {
+		s.Pacing = map[string]Pacing{}
+	}
+	s.Schedules = slices.Clone(s.Schedules)
+	for i := range s.Schedules {
+		s.Schedules[i].Days = slices.Clone(s.Schedules[i].Days)
+	}
+	s.FileTypes.Blocked = slices.Clone(s.FileTypes.Blocked)
+	s.FileTypes.Warned = slices.Clone(s.FileTypes.Warned)
+	s.Proxies = slices.Clone(s.Proxies)
+	s.Plugins = slices.Clone(s.Plugins)
+	for i := range s.Plugins {
+		s.Plugins[i].Args = slices.Clone(s.Plugins[i].Args)
+		s.Plugins[i].BBS = slices.Clone(s.Plugins[i].BBS)
+	}
+	watch := make(map[string][]string, len(s.Watch))
+	for k, v := range s.Watch {
+		watch[k] = slices.Clone(v)
+	}
+	s.Watch = watch
+	return s
+}
+
+// ─────────────────────────────────────────────
+// Migrazioni
+// ─────────────────────────────────────────────
+
+// migrations[v] converte il JSON grezzo dalla versione v alla v+1. Quando
+// il formato cambia: incrementare CurrentVersion e aggiungere qui la
+// conversione dalla versione precedente.
+var migrations = []func(raw map[string]any){
+	// 0 → 1: file senza numero di versione, stesso formato della 1
+	0: func(raw map[string]any) {},
+}
+
+// migrate porta raw alla versione corrente.
+func migrate(raw map[string]any) error {
+	v := 0
+	if f, ok := raw["version"].(float64); ok {
+		v = int(f)
+	}
+	if v > CurrentVersion {
+		return fmt.Errorf("%w (versione %d)", ErrNewerVersion, v)
+	}
+	for ; v < CurrentVersion; v++ {
+		migrations[v](raw)
+	}
+	raw["version"] = CurrentVersion
+	return nil
+}
+
+// ErrNewerVersion indica un file scritto da una versione più recente.
+var ErrNewerVersion = errors.New("impostazioni create da una versione più recente del client")
+
+// ─────────────────────────────────────────────
+// Store
+// ─────────────────────────────────────────────
+
+// Store conserva le impostazioni correnti e le salva a ogni modifica,
+// notificando gli osservatori. È sicuro per l'uso concorrente.
+type Store struct {
+	mu       sync.Mutex
+	path     string // "" = solo in memoria
+	cur      Settings
+	onChange []func(Settings)
+}
+
+// DefaultPath ritorna il percorso del file nella directory di
+// configurazione dell'utente.
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "bbs-client", FileName), nil
+}
+
+// New crea uno store con le impostazioni di default, salvato in path
+// ("" = solo in memoria).
+func New(path string) *Store {
+	return &Store{path: path, cur: Defaults()}
+}
+
+// Open carica le impostazioni da path applicando le migrazioni. Un file
+// assente non è un errore: si parte dai default.
+func Open(path string) (*Store, error) {
+	st := New(path)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return st, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("impostazioni %s non valide: %w", path, err)
+	}
+	if err := migrate(raw); err != nil {
+		return nil, err
+	}
+	data, _ = json.Marshal(raw)
+	if err := json.Unmarshal(data, &st.cur); err != nil {
+		return nil, fmt.Errorf("impostazioni %s non valide: %w", path, err)
+	}
+	st.cur = st.cur.Clone()
+	return st, nil
+}
+
+// Get ritorna una copia delle impostazioni correnti.
+func (st *Store) Get() Settings {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	return st.cur.Clone()
+}
+
+// Set sostituisce le impostazioni, le salva e notifica gli osservatori.
+func (st *Store) Set(s Settings) error {
+	s = s.Clone()
+	s.Version = CurrentVersion
+
+	st.mu.Lock()
+	st.cur = s
+	err := st.save()
+	subs := slices.Clone(st.onChange)
+	st.mu.Unlock()
+
+	for _, fn := range subs {
+		fn(s.Clone())
+	}
+	return err
+}
+
+// Update applica fn a una copia delle impostazioni correnti e la salva.
+func (st *Store) Update(fn func(*Settings)) error {
+	s := st.Get()
+	fn(&s)
+	return st.Set(s)
+}
+
+// OnChange registra un osservatore chiamato dopo ogni modifica (senza
+// lock dello store acquisito).
+func (st *Store) OnChange(fn func(Settings)) {
+	st.mu.Lock()
+	st.onChange = append(st.onChange, fn)
+	st.mu.Unlock()
+}
+
+// save scrive il file (atomico via file temporaneo). Chiamare con st.mu.
+func (st *Store) save() error {
+	if st.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(st.cur, "", "  ")
+	if err != nil {
+		return err
+	}
+	// SEC-005: directory e file leggibili solo dall'utente
+	if err := os.MkdirAll(filepath.Dir(st.path), 0700); err != nil {
+		return err
+	}
+	tmp := st.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, st.path)
+}
@@ -0,0 +1,274 @@
+// Package control offre un endpoint JSON-RPC 2.0 locale con cui script e
+// programmi esterni possono comandare il client: connettersi, inviare
+// testo, leggere lo schermo, avviare trasferimenti.
+//
+// Il server ascolta solo su 127.0.0.1 e risponde su /rpc in due modi:
+//
+//   - POST /rpc con una richiesta JSON-RPC nel corpo (una risposta per
+//     richiesta, comodo da curl);
+//   - GET /rpc con upgrade a WebSocket: ogni messaggio di testo è una
+//     richiesta e riceve la sua risposta sulla stessa connessione.
+//
+// Ogni richiesta HTTP deve portare il token nell'intestazione
+// "Authorization: Bearer <token>" (o, per i client WebSocket che non
+// possono impostare intestazioni, nel parametro "t" dell'URL). Le
+// richieste provenienti da pagine web (intestazione Origin) sono
+// rifiutate, così un sito aperto nel browser non può usare l'endpoint.
+//
+//	curl -s -H "Authorization: Bearer $TOKEN" \
+//	     -d '{"jsonrpc":"2.0","id":1,"method":"screen"}' \
+//	     http://127.0.0.1:8024/rpc
+package control
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// maxRequest limita la dimensione di una richiesta
+	maxRequest = 1 << 20
+	// MaxClients limita le connessioni WebSocket aperte insieme
+	MaxClients = 8
+)
+
+// Codici di errore JSON-RPC 2.0
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeFailed         = -32000 // errore dell'operazione richiesta
+)
+
+// Method esegue un metodo con i parametri della richiesta (eventualmente
+// nil) e ritorna il risultato da serializzare in JSON.
+type Method func(params json.RawMessage) (any, error)
+
+// Error è un errore con un codice JSON-RPC. Gli altri errori ritornati da
+// un Method diventano CodeFailed.
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// InvalidParams ritorna un errore CodeInvalidParams.
+func InvalidParams(err error) *Error {
+	return &Error{Code: CodeInvalidParams, Message: err.Error()}
+}
+
+// Decode legge i parametri di una richiesta in v, segnalando i parametri
+// mancanti o malformati come CodeInvalidParams.
+func Decode(params json.RawMessage, v any) error {
+	if len(params) == 0 {
+		return InvalidParams(errors.New("parametri mancanti"))
+	}
+	if err := json.Unmarshal(params, v); err != nil {
+		return InvalidParams(err)
+	}
+	return nil
+}
+
+// request e response sono i messaggi JSON-RPC 2.0.
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// Server è l'endpoint di controllo in ascolto.
+type Server struct {
+	token   string
+	methods map[string]Method
+	ln      net.Listener
+	srv     *http.Server
+
+	mu      sync.Mutex
+	clients map[*websocket.Conn]struct{}
+}
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  4096,
+	WriteBufferSize: 16 * 1024,
+	// L'origine è già controllata in authorized
+	CheckOrigin: func(*http.Request) bool { return true },
+}
+
+// Start apre il server su 127.0.0.1:port (0 = porta libera qualsiasi).
+func Start(port int, token string, methods map[string]Method) (*Server, error) {
+	if token == "" {
+		return nil, errors.New("token mancante")
+	}
+	ln, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{
+		token:   token,
+		methods: methods,
+		ln:      ln,
+		clients: make(map[*websocket.Conn]struct{}),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rpc", s.handle)
+	s.srv = &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		if err := s.srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("[CONTROL] Server terminato: %v", err)
+		}
+	}()
+	log.Printf("[CONTROL] In ascolto su %s", ln.Addr())
+	return s, nil
+}
+
+// URL ritorna l'indirizzo dell'endpoint.
+func (s *Server) URL() string {
+	return "http://" + s.ln.Addr().String() + "/rpc"
+}
+
+// Close chiude il server e le connessioni WebSocket aperte.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	for c := range s.clients {
+		c.Close()
+	}
+	s.mu.Unlock()
+	return s.srv.Close()
+}
+
+// authorized controlla token e origine della richiesta.
+func (s *Server) authorized(r *http.Request) bool {
+	if r.Header.Get("Origin") != "" {
+		return false
+	}
+	t, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		t = r.URL.Query().Get("t")
+	}
+	return subtle.ConstantTimeCompare([]byte(t), []byte(s.token)) == 1
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	if !s.authorized(r) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+	switch r.Method {
+	case http.MethodPost:
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxRequest+1))
+		if err != nil || len(body) > maxRequest {
+			http.Error(w, "richiesta troppo grande", http.StatusRequestEntityTooLarge)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(s.call(body))
+	case http.MethodGet:
+		s.serveWS(w, r)
+	default:
+		http.Error(w, "metodo non consentito", http.StatusMethodNotAllowed)
+	}
+}
+
+// serveWS esegue le richieste ricevute su una connessione WebSocket, una
+// alla volta e nell'ordine di arrivo.
+func (s *Server) serveWS(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	full := len(s.clients) >= MaxClients
+	s.mu.Unlock()
+	if full {
+		http.Error(w, "troppi client collegati", http.StatusServiceUnavailable)
+		return
+	}
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return // Upgrade ha già risposto al client
+	}
+	s.mu.Lock()
+	s.clients[conn] = struct{}{}
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.clients, conn)
+		s.mu.Unlock()
+		conn.Close()
+	}()
+
+	conn.SetReadLimit(maxRequest)
+	for {
+		kind, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		if kind != websocket.TextMessage {
+			continue
+		}
+		if err := conn.WriteMessage(websocket.TextMessage, s.call(data)); err != nil {
+			return
+		}
+	}
+}
+
+// call esegue una richiesta JSON-RPC e ritorna la risposta serializzata.
+func (s *Server) call(data []byte) []byte {
+	var req request
+	resp := response{JSONRPC: "2.0", ID: json.RawMessage("null")}
+	if err := json.Unmarshal(data, &req); err != nil {
+		resp.Error = &Error{Code: CodeParseError, Message: err.Error()}
+		return marshal(resp)
+	}
+	if len(req.ID) > 0 {
+		resp.ID = req.ID
+	}
+	method, ok := s.methods[req.Method]
+	switch {
+	case req.JSONRPC != "2.0" || req.Method == "":
+		resp.Error = &Error{Code: CodeInvalidRequest, Message: "richiesta JSON-RPC 2.0 non valida"}
+	case !ok:
+		resp.Error = &Error{Code: CodeMethodNotFound, Message: "metodo sconosciuto: " + req.Method}
+	default:
+		result, err := method(req.Params)
+		var rpcErr *Error
+		switch {
+		case errors.As(err, &rpcErr):
+			resp.Error = rpcErr
+		case err != nil:
+			resp.Error = &Error{Code: CodeFailed, Message: err.Error()}
+		case result == nil:
+			resp.Result = true // "result" è obbligatorio nelle risposte riuscite
+		default:
+			resp.Result = result
+		}
+	}
+	return marshal(resp)
+}
+
+func marshal(resp response) []byte {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		data, _ = json.Marshal(response{JSONRPC: "2.0", ID: resp.ID, Error: &Error{Code: CodeFailed, Message: err.Error()}})
+	}
+	return data
+}
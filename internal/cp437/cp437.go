@@ -0,0 +1,120 @@
+// Package cp437 converte tra la codepage 437 (IBM PC), usata dalle BBS, e
+// UTF-8.
+package cp437
+
+import "github.com/rj45lab/bbs-client-go/pkg/ansi"
+
+// ToUnicode mappa ogni byte CP437 sul carattere Unicode corrispondente,
+// compresi i simboli grafici 0x01-0x1F. È la tabella di ansi.CP437, usata
+// anche da Screen.FeedCP437.
+var ToUnicode = ansi.CP437
+
+// fromUnicode è la tabella inversa di ToUnicode.
+var fromUnicode = func() map[rune]byte {
+	m := make(map[rune]byte, 256)
+	for i, r := range ToUnicode {
+		m[r] = byte(i)
+	}
+	// I controlli restano tali, non i simboli CP437 omonimi
+	for i := 0; i < 0x20; i++ {
+		m[rune(i)] = byte(i)
+	}
+	return m
+}()
+
+// Decode converte byte CP437 in UTF-8. I caratteri di controllo (ESC, CR,
+// LF, BS, TAB, BEL) restano tali, così il parser ANSI li riconosce.
+func Decode(data []byte) string {
+	runes := make([]rune, len(data))
+	for i, b := range data {
+		runes[i] = ansi.CP437Rune(b, false)
+	}
+	return string(runes)
+}
+
+// Byte ritorna il byte CP437 di r, se esiste.
+func Byte(r rune) (byte, bool) {
+	b, ok := fromUnicode[r]
+	return b, ok
+}
+
+// Text ritorna il byte con cui inviare r come testo: come Byte, ma i
+// simboli di 0x01-0x1F (☺, ♥, •...) non diventano caratteri di controllo
+// che la BBS eseguirebbe.
+func Text(r rune) (byte, bool) {
+	b, ok := fromUnicode[r]
+	return b, ok && (b >= 0x20 || r < 0x20)
+}
+
+// Encode converte una stringa UTF-8 in byte CP437. I caratteri senza
+// equivalente diventano la loro traslitterazione (vedi Transliterate) o,
+// se non ne hanno una, '?'. Lo spazio non separabile (U+00A0) diventa
+// 0xFF, che su telnet è IAC: Connection.Send lo raddoppia.
+func Encode(text string) []byte {
+	out := make([]byte, 0, len(text))
+	for _, r := range text {
+		if b, ok := Text(r); ok {
+			out = append(out, b)
+		} else if s, ok := translit[r]; ok {
+			out = append(out, s...)
+		} else {
+			out = append(out, '?')
+		}
+	}
+	return out
+}
+
+// ─────────────────────────────────────────────
+// Traslitterazione
+// ─────────────────────────────────────────────
+
+// translit sostituisce i caratteri che CP437 non ha con l'equivalente più
+// vicino, già in CP437: la punteggiatura tipografica di word processor e
+// browser diventa ASCII, le lettere accentate mancanti perdono l'accento.
+var translit = func() map[rune]string {
+	m := map[rune]string{
+		'‘': "'", '’': "'", '‚': "'", '‛': "'", '′': "'", '´': "'",
+		'“': "\"", '”': "\"", '„': "\"", '‟': "\"", '″': "\"", '¨': "\"",
+		'‹': "<", '›': ">",
+		'‐': "-", '‑': "-", '‒': "-", '–': "-", '—': "--", '―': "--", '−': "-",
+		'…': "...", '•': "\xf9", '‣': "\xf9",
+		'\u2002': " ", '\u2003': " ", '\u2009': " ", '\u200a': " ", '\u202f': " ",
+		'\u200b': "", '\u200c': "", '\u200d': "", '\ufeff': "",
+		'€': "EUR", '©': "(c)", '®': "(R)", '™': "TM", '×': "x",
+		'¹': "1", '³': "3", '¾': "3/4", '‰': "o/oo",
+		'Œ': "OE", 'œ': "oe",
+	}
+	for letter, pairs := range unaccented {
+		for _, r := range pairs {
+			m[r] = string(letter)
+		}
+	}
+	return m
+}()
+
+// unaccented elenca, per ogni lettera, le varianti accentate che CP437
+// non ha.
+var unaccented = map[byte]string{
+	'A': "ÀÁÂÃĀĂĄ", 'a': "ãāăą",
+	'C': "ĆĈĊČ", 'c': "ćĉċč",
+	'D': "ĎĐ", 'd': "ďđ",
+	'E': "ÈÊËĒĔĖĘĚ", 'e': "ēĕėęě",
+	'G': "ĜĞĠĢ", 'g': "ĝğġģ",
+	'I': "ÌÍÎÏĨĪĬĮİ", 'i': "ĩīĭįı",
+	'L': "ĹĻĽĿŁ", 'l': "ĺļľŀł",
+	'N': "ŃŅŇ", 'n': "ńņň",
+	'O': "ÒÓÔÕØŌŎŐ", 'o': "õøōŏő",
+	'R': "ŔŖŘ", 'r': "ŕŗř",
+	'S': "ŚŜŞŠ", 's': "śŝşš",
+	'T': "ŢŤ", 't': "ţť",
+	'U': "ÙÚÛŨŪŬŮŰŲ", 'u': "ũūŭůűų",
+	'Y': "ÝŸ", 'y': "ý",
+	'Z': "ŹŻŽ", 'z': "źżž",
+}
+
+// Transliterate ritorna i byte CP437 che sostituiscono r quando CP437 non
+// lo ha ("E" per È, "--" per il trattino lungo).
+func Transliterate(r rune) (string, bool) {
+	s, ok := translit[r]
+	return s, ok
+}
@@ -0,0 +1,174 @@
+// Package filelist riconosce le liste di file delle BBS mentre scorrono
+// sullo schermo (nome, dimensione, data, descrizione) e le raccoglie in
+// record strutturati, così l'utente può selezionare i file da scaricare
+// invece di ricopiarne i nomi.
+//
+// I formati riconosciuti sono quelli comuni a PCBoard, RemoteAccess,
+// Synchronet, Mystic e simili: una riga per file con nome, dimensione
+// (in byte o con suffisso k/M), data facoltativa e descrizione, seguita da
+// eventuali righe rientrate con il seguito della descrizione.
+package filelist
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/rj45lab/bbs-client-go/pkg/ansi"
+)
+
+// MaxEntries limita i file raccolti in una sessione.
+const MaxEntries = 5000
+
+// Entry è un file della lista.
+type Entry struct {
+	Name        string `json:"name"`
+	Size        int64  `json:"size"`     // in byte (stimata per i suffissi k/M)
+	SizeText    string `json:"sizeText"` // come mostrata dalla BBS
+	Date        string `json:"date,omitempty"`
+	Description string `json:"description"`
+	Marked      bool   `json:"marked"`
+}
+
+var (
+	// entryLine: [numero/casella] NOME.EXT dimensione [data] [download] descrizione
+	entryLine = regexp.MustCompile(`^\s*(?:\d{1,4}[.)]\s+|\[[ xX*]\]\s+)?` +
+		`([A-Za-z0-9_\-!#$%&'@^~]{1,32}\.[A-Za-z0-9_\-]{1,4})\s+` +
+		`(\d[\d,.]*\s?(?:[kKmMgG][bB]?|[bB])?)\s+` +
+		`(?:(\d{1,2}[-/.]\d{1,2}[-/.]\d{2,4}|\d{4}-\d{2}-\d{2})\s+)?` +
+		`(?:[\[(]\s*\d+\s*[\])]\s+)?` +
+		`(.*)$`)
+
+	// continuation: seguito della descrizione, rientrato (spesso con | o +)
+	continuation = regexp.MustCompile(`^\s{8,}[|+:]?\s*(\S.*)$`)
+)
+
+// Scraper accumula i file riconosciuti nel testo ricevuto. Non è sicuro
+// per l'uso concorrente.
+type Scraper struct {
+	strip   ansi.Stripper
+	entries []Entry
+	index   map[string]int // nome in maiuscolo → posizione in entries
+	last    int            // ultimo file letto (-1 = la riga precedente non era un file)
+}
+
+// New crea uno Scraper vuoto.
+func New() *Scraper {
+	return &Scraper{index: map[string]int{}, last: -1}
+}
+
+// Feed elabora un blocco di testo decodificato e ritorna il numero di file
+// nuovi riconosciuti.
+func (s *Scraper) Feed(text string) int {
+	added := 0
+	lines := strings.Split(s.strip.Write(text), "\n")
+	for _, line := range lines[:len(lines)-1] {
+		if s.scan(line) {
+			added++
+		}
+	}
+	return added
+}
+
+// scan interpreta una riga completa; ritorna true per un file nuovo.
+func (s *Scraper) scan(line string) bool {
+	if m := entryLine.FindStringSubmatch(line); m != nil {
+		if size, ok := parseSize(m[2]); ok {
+			return s.add(Entry{
+				Name:        m[1],
+				Size:        size,
+				SizeText:    strings.TrimSpace(m[2]),
+				Date:        m[3],
+				Description: strings.TrimSpace(m[4]),
+			})
+		}
+	}
+	if s.last >= 0 {
+		if m := continuation.FindStringSubmatch(line); m != nil {
+			e := &s.entries[s.last]
+			e.Description = strings.TrimSpace(e.Description + " " + m[1])
+			return false
+		}
+	}
+	s.last = -1
+	return false
+}
+
+// add inserisce un file, o aggiorna quello con lo stesso nome (la lista
+// riletta): la selezione resta.
+func (s *Scraper) add(e Entry) bool {
+	key := strings.ToUpper(e.Name)
+	if i, ok := s.index[key]; ok {
+		e.Marked = s.entries[i].Marked
+		s.entries[i] = e
+		s.last = i
+		return false
+	}
+	if len(s.entries) >= MaxEntries {
+		s.last = -1
+		return false
+	}
+	s.index[key] = len(s.entries)
+	s.entries = append(s.entries, e)
+	s.last = len(s.entries) - 1
+	return true
+}
+
+// parseSize interpreta una dimensione ("123456", "1,234", "12k", "1.5M").
+func parseSize(text string) (int64, bool) {
+	t := strings.ToUpper(strings.TrimSpace(text))
+	t = strings.TrimSuffix(t, "B")
+	mult := 1.0
+	switch {
+	case strings.HasSuffix(t, "K"):
+		mult = 1 << 10
+	case strings.HasSuffix(t, "M"):
+		mult = 1 << 20
+	case strings.HasSuffix(t, "G"):
+		mult = 1 << 30
+	}
+	t = strings.TrimSpace(strings.TrimRight(t, "KMG"))
+	if mult == 1 {
+		// Senza suffisso: byte, con separatori delle migliaia
+		t = strings.NewReplacer(",", "", ".", "").Replace(t)
+	} else {
+		t = strings.ReplaceAll(t, ",", ".")
+	}
+	v, err := strconv.ParseFloat(t, 64)
+	if err != nil || v < 0 {
+		return 0, false
+	}
+	return int64(v * mult), true
+}
+
+// Entries ritorna una copia dei file raccolti, nell'ordine di arrivo.
+func (s *Scraper) Entries() []Entry {
+	return append([]Entry(nil), s.entries...)
+}
+
+// Mark seleziona o deseleziona il file name; ritorna false se non c'è.
+func (s *Scraper) Mark(name string, marked bool) bool {
+	i, ok := s.index[strings.ToUpper(name)]
+	if ok {
+		s.entries[i].Marked = marked
+	}
+	return ok
+}
+
+// Marked ritorna i nomi dei file selezionati.
+func (s *Scraper) Marked() []string {
+	var out []string
+	for _, e := range s.entries {
+		if e.Marked {
+			out = append(out, e.Name)
+		}
+	}
+	return out
+}
+
+// Clear svuota la lista.
+func (s *Scraper) Clear() {
+	s.entries = nil
+	s.index = map[string]int{}
+	s.last = -1
+}
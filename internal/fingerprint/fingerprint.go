@@ -0,0 +1,158 @@
+// Package fingerprint riconosce il software di una BBS (Mystic, Synchronet,
+// WWIV...) dal banner mostrato alla connessione, così il client può
+// applicare le impostazioni adatte (iCE colors, set di caratteri) e
+// annotarlo in rubrica.
+package fingerprint
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/rj45lab/bbs-client-go/internal/charset"
+	"github.com/rj45lab/bbs-client-go/pkg/ansi"
+)
+
+// ─────────────────────────────────────────────
+// Firme
+// ─────────────────────────────────────────────
+
+// Nomi del software riconosciuto
+const (
+	Mystic     = "Mystic"
+	Synchronet = "Synchronet"
+	WWIV       = "WWIV"
+	Renegade   = "Renegade"
+	Enigma     = "ENiGMA½"
+	Maximus    = "Maximus"
+	Wildcat    = "Wildcat!"
+	PCBoard    = "PCBoard"
+	Telegard   = "Telegard"
+)
+
+// signature associa al software l'espressione che lo riconosce in una
+// riga del banner.
+type signature struct {
+	software string
+	pattern  *regexp.Regexp
+}
+
+// signatures è in ordine di priorità: a parità di riga vince la prima.
+// Le parole comuni (renegade, maximus) richiedono "BBS" o una versione
+// accanto, per non scambiare il nome della BBS per il software.
+var signatures = []signature{
+	{Mystic, regexp.MustCompile(`(?i)\bmystic\s+bbs\b`)},
+	{Synchronet, regexp.MustCompile(`(?i)\bsynchronet\b`)},
+	{Enigma, regexp.MustCompile(`(?i)\benigma(?:½|1/2|\s*bbs\b|-bbs\b)`)},
+	{WWIV, regexp.MustCompile(`(?i)\bwwiv\b`)},
+	{Renegade, regexp.MustCompile(`(?i)\brenegade(?:\s+bbs\b|\s+v?\d)`)},
+	{Maximus, regexp.MustCompile(`(?i)\bmaximus(?:\s+bbs\b|\s+v?\d)`)},
+	{Wildcat, regexp.MustCompile(`(?i)\bwildcat!`)},
+	{PCBoard, regexp.MustCompile(`(?i)\bpcboard\b`)},
+	{Telegard, regexp.MustCompile(`(?i)\btelegard\b`)},
+}
+
+// versionPattern cerca la versione dopo il nome: "v1.12 A47",
+// "Version 3.19b", "5.4.0".
+var versionPattern = regexp.MustCompile(`(?i)(?:\bv(?:ersion|er\.?)?\s*|\s)(\d+(?:\.\d+)+[a-z]?(?:[ -][a-z]\d+)?)`)
+
+// Preset sono le impostazioni consigliate per il software riconosciuto.
+type Preset struct {
+	IceColors bool   `json:"iceColors"` // i temi usano sfondi bright invece del blink
+	Charset   string `json:"charset"`   // codifica del software (vedi charset.Lookup)
+}
+
+// presets per software; quelli assenti usano defaultPreset.
+var presets = map[string]Preset{
+	Mystic: {IceColors: true, Charset: charset.CP437},
+	Enigma: {IceColors: true, Charset: charset.CP437},
+}
+
+var defaultPreset = Preset{Charset: charset.CP437}
+
+// PresetFor ritorna le impostazioni consigliate per software, senza
+// distinguere maiuscole e minuscole (il nome può venire dalla rubrica).
+func PresetFor(software string) Preset {
+	for name, p := range presets {
+		if strings.EqualFold(name, software) {
+			return p
+		}
+	}
+	return defaultPreset
+}
+
+// ─────────────────────────────────────────────
+// Detector
+// ─────────────────────────────────────────────
+
+// MaxBanner è la quantità di testo (in caratteri) esaminata dopo la
+// connessione: oltre, il banner è finito e la ricerca si ferma.
+const MaxBanner = 16 * 1024
+
+// Result descrive il software riconosciuto.
+type Result struct {
+	Software    string   `json:"software"`
+	Version     string   `json:"version,omitempty"`
+	Evidence    string   `json:"evidence"`              // riga del banner riconosciuta
+	Negotiation []string `json:"negotiation,omitempty"` // opzioni telnet richieste dal server
+	Preset      Preset   `json:"preset"`
+}
+
+// Detector esamina il testo ricevuto all'inizio della sessione, ignorando
+// colori e sequenze ANSI. Non è sicuro per l'uso concorrente.
+type Detector struct {
+	strip ansi.Stripper
+	seen  int
+	done  bool
+}
+
+// New crea un Detector per una nuova connessione.
+func New() *Detector {
+	return &Detector{}
+}
+
+// Done indica se la ricerca è conclusa (software trovato o banner finito).
+func (d *Detector) Done() bool {
+	return d.done
+}
+
+// Feed elabora un blocco di testo decodificato. Ritorna true, una sola
+// volta, quando il software viene riconosciuto.
+func (d *Detector) Feed(text string) (Result, bool) {
+	if d.done {
+		return Result{}, false
+	}
+	d.seen += len(text)
+	lines := strings.Split(d.strip.Write(text), "\n")
+	for _, line := range lines[:len(lines)-1] {
+		if res, ok := Identify(line); ok {
+			d.done = true
+			return res, true
+		}
+	}
+	if d.seen >= MaxBanner {
+		d.done = true
+		// L'ultima riga del banner può non essere terminata
+		return Identify(d.strip.Pending())
+	}
+	return Result{}, false
+}
+
+// Identify cerca una firma nota in una riga di testo semplice.
+func Identify(line string) (Result, bool) {
+	for _, sig := range signatures {
+		loc := sig.pattern.FindStringIndex(line)
+		if loc == nil {
+			continue
+		}
+		res := Result{
+			Software: sig.software,
+			Evidence: strings.TrimSpace(line),
+			Preset:   PresetFor(sig.software),
+		}
+		if m := versionPattern.FindStringSubmatch(line[loc[1]:]); m != nil {
+			res.Version = m[1]
+		}
+		return res, true
+	}
+	return Result{}, false
+}
@@ -0,0 +1,207 @@
+// Package htmlexport converte schermate ANSI in un documento HTML
+// autonomo, con i colori originali e il font CP437 incorporato, per
+// archiviare sessioni e log.
+package htmlexport
+
+import (
+	"encoding/base64"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	"github.com/rj45lab/bbs-client-go/pkg/ansi"
+)
+
+// Options configura il documento generato.
+type Options struct {
+	Title string
+	Font  []byte // TrueType del font CP437 da incorporare (nil = monospace di sistema)
+}
+
+// Write scrive un documento HTML con una sezione per ogni pagina e una
+// barra di navigazione tra le pagine.
+func Write(w io.Writer, pages []*ansi.Screen, opt Options) error {
+	var b strings.Builder
+	title := html.EscapeString(opt.Title)
+	fmt.Fprintf(&b, "<!DOCTYPE html>\n<html lang=\"it\">\n<head>\n<meta charset=\"utf-8\">\n<title>%s</title>\n<style>\n", title)
+	b.WriteString(Stylesheet(opt.Font))
+	fmt.Fprintf(&b, "</style>\n</head>\n<body>\n<h1>%s</h1>\n", title)
+
+	if len(pages) > 1 {
+		b.WriteString("<nav>")
+		for i := range pages {
+			fmt.Fprintf(&b, "<a href=\"#p%d\">%d</a> ", i+1, i+1)
+		}
+		b.WriteString("</nav>\n")
+	}
+	for i, s := range pages {
+		fmt.Fprintf(&b, "<section id=\"p%d\">\n<h2>Pagina %d/%d</h2>\n", i+1, i+1, len(pages))
+		writeScreen(&b, s)
+		b.WriteString("</section>\n")
+	}
+	b.WriteString("</body>\n</html>\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Stylesheet ritorna il CSS dei documenti generati, con il font CP437
+// incorporato se font non è nil.
+func Stylesheet(font []byte) string {
+	if len(font) == 0 {
+		return stylesheet
+	}
+	return fmt.Sprintf("@font-face { font-family: 'IBM VGA'; src: url(data:font/ttf;base64,%s) format('truetype'); }\n",
+		base64.StdEncoding.EncodeToString(font)) + stylesheet
+}
+
+// Screen ritorna lo schermo s come frammento HTML (<pre class="screen">),
+// da inserire in una pagina con lo stile di Stylesheet.
+func Screen(s *ansi.Screen) string {
+	var b strings.Builder
+	writeScreen(&b, s)
+	return b.String()
+}
+
+const stylesheet = `body { background: #111; color: #aaa; font-family: sans-serif; }
+nav { margin: 1em 0; }
+nav a { color: #ffff55; }
+h2 { font-size: 1em; color: #55ffff; }
+pre.screen { font-family: 'IBM VGA', 'Consolas', 'DejaVu Sans Mono', monospace;
+  font-size: 16px; line-height: 16px; background: #000; display: inline-block;
+  margin: 0; padding: 4px; }
+.b { font-weight: bold; } .i { font-style: italic; }
+.u { text-decoration: underline; } .s { text-decoration: line-through; }
+.u.s { text-decoration: underline line-through; }
+.k { animation: blink 1s steps(1) infinite; }
+@keyframes blink { 50% { color: transparent; } }
+`
+
+// span descrive lo stile di una sequenza di celle.
+type span struct {
+	fg, bg  [3]uint8
+	classes string
+}
+
+// writeScreen scrive lo schermo come <pre>, raggruppando in un unico
+// <span> le celle consecutive con lo stesso stile.
+func writeScreen(b *strings.Builder, s *ansi.Screen) {
+	b.WriteString("<pre class=\"screen\">")
+	for y, row := range s.Buffer {
+		if y > 0 {
+			b.WriteByte('\n')
+		}
+		var cur span
+		var text strings.Builder
+		flush := func() {
+			if text.Len() == 0 {
+				return
+			}
+			fmt.Fprintf(b, "<span style=\"color:#%02x%02x%02x;background:#%02x%02x%02x\"",
+				cur.fg[0], cur.fg[1], cur.fg[2], cur.bg[0], cur.bg[1], cur.bg[2])
+			if cur.classes != "" {
+				fmt.Fprintf(b, " class=\"%s\"", cur.classes)
+			}
+			b.WriteByte('>')
+			b.WriteString(html.EscapeString(text.String()))
+			b.WriteString("</span>")
+			text.Reset()
+		}
+		for x, cell := range row {
+			sp := cellSpan(s, cell)
+			if x == 0 || sp != cur {
+				flush()
+				cur = sp
+			}
+			ch := cell.Char
+			if ch < ' ' || cell.Attr.Conceal {
+				ch = ' '
+			}
+			text.WriteRune(ch)
+		}
+		flush()
+	}
+	b.WriteString("</pre>\n")
+}
+
+// cellSpan ritorna lo stile di una cella.
+func cellSpan(s *ansi.Screen, cell ansi.Cell) span {
+	fg, bg, _ := s.CellColors(cell)
+	attr := cell.Attr
+	if attr.Faint {
+		for i := range fg {
+			fg[i] = uint8((int(fg[i]) + int(bg[i])) / 2)
+		}
+	}
+	var cls []string
+	if attr.Bold && s.BoldPolicy.UsesFont() {
+		cls = append(cls, "b")
+	}
+	if attr.Italic {
+		cls = append(cls, "i")
+	}
+	if attr.Underline {
+		cls = append(cls, "u")
+	}
+	if attr.Strike {
+		cls = append(cls, "s")
+	}
+	if attr.EffectiveBlink(s.IceColors) {
+		cls = append(cls, "k")
+	}
+	return span{fg: fg, bg: bg, classes: strings.Join(cls, " ")}
+}
+
+// Cells ritorna le righe di celle di s (vedi ansi.Screen.Region) come
+// frammento HTML con gli stili in linea, da copiare negli appunti: i
+// documenti e le chat in cui si incolla non hanno il foglio di stile.
+func Cells(s *ansi.Screen, rows [][]ansi.Cell) string {
+	var b strings.Builder
+	bg := s.ActivePalette()[ansi.DefaultBG]
+	fmt.Fprintf(&b, "<pre style=\"font-family:'IBM VGA',Consolas,'DejaVu Sans Mono',monospace;background:#%02x%02x%02x;padding:4px\">",
+		bg[0], bg[1], bg[2])
+	for y, row := range rows {
+		if y > 0 {
+			b.WriteByte('\n')
+		}
+		for x := 0; x < len(row); {
+			sp := cellSpan(s, row[x])
+			var text strings.Builder
+			for ; x < len(row) && cellSpan(s, row[x]) == sp; x++ {
+				ch := row[x].Char
+				if ch < ' ' || row[x].Attr.Conceal {
+					ch = ' '
+				}
+				text.WriteRune(ch)
+			}
+			fmt.Fprintf(&b, "<span style=\"color:#%02x%02x%02x;background:#%02x%02x%02x%s\">%s</span>",
+				sp.fg[0], sp.fg[1], sp.fg[2], sp.bg[0], sp.bg[1], sp.bg[2], inlineStyle(sp.classes), html.EscapeString(text.String()))
+		}
+	}
+	b.WriteString("</pre>")
+	return b.String()
+}
+
+// inlineStyle traduce le classi di cellSpan in proprietà CSS. Il
+// lampeggio non ha un equivalente senza foglio di stile.
+func inlineStyle(classes string) string {
+	var b strings.Builder
+	var deco []string
+	for _, c := range strings.Fields(classes) {
+		switch c {
+		case "b":
+			b.WriteString(";font-weight:bold")
+		case "i":
+			b.WriteString(";font-style:italic")
+		case "u":
+			deco = append(deco, "underline")
+		case "s":
+			deco = append(deco, "line-through")
+		}
+	}
+	if len(deco) > 0 {
+		b.WriteString(";text-decoration:" + strings.Join(deco, " "))
+	}
+	return b.String()
+}
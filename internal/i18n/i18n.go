@@ -0,0 +1,114 @@
+// Package i18n traduce i messaggi del backend.
+//
+// Ogni messaggio ha un codice stabile (ad esempio "conn.not_connected") e
+// un testo per lingua con segnaposto %s. I binding ritornano al frontend un
+// Message con codice e argomenti, così l'interfaccia può tradurlo da sé; il
+// campo Text contiene già la traduzione nella lingua scelta nelle
+// impostazioni, per chi si limita a mostrarlo.
+package i18n
+
+import (
+	"fmt"
+	"slices"
+	"sync/atomic"
+)
+
+// Lingue disponibili
+const (
+	Italian = "it"
+	English = "en"
+
+	// Default è la lingua iniziale e quella di riserva per i testi mancanti
+	Default = Italian
+)
+
+// Code identifica un messaggio indipendentemente dalla lingua.
+type Code string
+
+// Message è un messaggio localizzato per il frontend.
+type Message struct {
+	Code Code     `json:"code"`
+	Args []string `json:"args,omitempty"`
+	Text string   `json:"text"` // traduzione nella lingua corrente
+}
+
+// Error rende Message utilizzabile come errore.
+func (m *Message) Error() string {
+	return m.Text
+}
+
+var current atomic.Value // string
+
+// SetLanguage imposta la lingua dei messaggi; ritorna false (e lascia la
+// lingua invariata) se non è tra quelle disponibili.
+func SetLanguage(lang string) bool {
+	if _, ok := catalogs[lang]; !ok {
+		return false
+	}
+	current.Store(lang)
+	return true
+}
+
+// Language ritorna la lingua corrente.
+func Language() string {
+	if lang, ok := current.Load().(string); ok {
+		return lang
+	}
+	return Default
+}
+
+// Languages ritorna le lingue disponibili, in ordine alfabetico.
+func Languages() []string {
+	langs := make([]string, 0, len(catalogs))
+	for lang := range catalogs {
+		langs = append(langs, lang)
+	}
+	slices.Sort(langs)
+	return langs
+}
+
+// T traduce code nella lingua corrente con gli argomenti dati.
+func T(code Code, args ...any) string {
+	return Translate(Language(), code, args...)
+}
+
+// Translate traduce code in lang. Un codice senza traduzione in lang usa
+// la lingua di default; un codice sconosciuto ritorna il codice stesso.
+func Translate(lang string, code Code, args ...any) string {
+	format, ok := catalogs[lang][code]
+	if !ok {
+		if format, ok = catalogs[Default][code]; !ok {
+			format = string(code)
+		}
+	}
+	if len(args) == 0 {
+		return format
+	}
+	// Tutti i segnaposto sono %s: gli argomenti diventano stringhe
+	strs := make([]any, len(args))
+	for i, arg := range args {
+		strs[i] = fmt.Sprint(arg)
+	}
+	return fmt.Sprintf(format, strs...)
+}
+
+// New crea un Message con il testo nella lingua corrente.
+func New(code Code, args ...any) *Message {
+	m := &Message{Code: code, Text: T(code, args...)}
+	for _, arg := range args {
+		m.Args = append(m.Args, fmt.Sprint(arg))
+	}
+	return m
+}
+
+// Err converte un errore in Message: un Message resta tale, gli altri
+// errori diventano ErrGeneric con il testo dell'errore. nil resta nil.
+func Err(err error) *Message {
+	if err == nil {
+		return nil
+	}
+	if m, ok := err.(*Message); ok {
+		return m
+	}
+	return New(ErrGeneric, err.Error())
+}
@@ -0,0 +1,386 @@
+package i18n
+
+// ─────────────────────────────────────────────
+// Codici dei messaggi
+// ─────────────────────────────────────────────
+
+// Errori ed esiti ritornati dai binding o inviati con "status-message".
+const (
+	ErrGeneric           Code = "error"
+	ErrRead              Code = "error.read"
+	ErrAlreadyConnected  Code = "conn.already_connected"
+	ErrConnecting        Code = "conn.in_progress"
+	ErrNotConnected      Code = "conn.not_connected"
+	ErrUnsupportedScheme Code = "conn.unsupported_protocol"
+	ErrUnknownSession    Code = "session.unknown"
+	ErrUnknownBold       Code = "settings.unknown_bold_policy"
+	ErrUnknownLanguage   Code = "settings.unknown_language"
+	ErrUnknownEmulation  Code = "settings.unknown_emulation"
+	ErrUnknownKeyboard   Code = "settings.unknown_keyboard"
+	ErrUnknownKeyProfile Code = "settings.unknown_key_profile"
+	ErrUnknownCharset    Code = "settings.unknown_charset"
+	ErrUnknownFormat     Code = "phonebook.unknown_format"
+	ErrNothingOpen       Code = "viewer.nothing_open"
+	ErrNoTiming          Code = "viewer.no_timing"
+	ErrNoMailPacket      Code = "mail.no_packet"
+	ErrNoReplies         Code = "mail.no_replies"
+	ErrPasteBusy         Code = "paste.busy"
+	ErrFileTooLarge      Code = "paste.file_too_large"
+	ErrKeychain          Code = "login.keychain"
+	ErrIEMSIFailed       Code = "login.iemsi_failed"
+	ErrNoLogin           Code = "login.missing"
+	ErrNoPasswordPrompt  Code = "login.no_prompt"
+	ErrNoMarkedFiles     Code = "files.none_marked"
+	ErrInvalidSchedule   Code = "schedule.invalid"
+	ErrUnknownSchedule   Code = "schedule.unknown"
+	ErrScheduleFailed    Code = "schedule.failed"
+	ErrShareStart        Code = "share.start"
+	ErrPrint             Code = "print.failed"
+	ErrUnknownPrinter    Code = "settings.unknown_printer"
+	ErrInvalidPacing     Code = "settings.invalid_pacing"
+	ErrInvalidPlugin     Code = "plugin.invalid"
+	ErrPluginStart       Code = "plugin.start"
+	ErrControlStart      Code = "control.start"
+	ErrInvalidTheme      Code = "theme.invalid"
+	ErrUnknownTheme      Code = "theme.unknown"
+	ErrBitmapFont        Code = "font.invalid"
+	ErrInvalidLimit      Code = "limits.invalid"
+	ErrUnknownDownloads  Code = "settings.unknown_downloads"
+	ErrInvalidFileType   Code = "settings.invalid_file_type"
+	ErrInvalidProxy      Code = "settings.invalid_proxy"
+	ErrUnknownProxy      Code = "conn.unknown_proxy"
+	ErrHostKeyChanged    Code = "ssh.host_key_changed"
+	ErrHostKeyRejected   Code = "ssh.host_key_rejected"
+	ErrInvalidPattern    Code = "script.invalid_pattern"
+	ErrInvalidBridge     Code = "bridge.invalid"
+	ErrBridgeBusy        Code = "bridge.busy"
+	ErrBridgeStart       Code = "bridge.start"
+
+	MsgDisconnected     Code = "conn.disconnected"
+	MsgConnectionError  Code = "conn.error"
+	MsgIdleDisconnected Code = "conn.idle_disconnected"
+	MsgNewSession       Code = "session.new"
+	MsgIEMSILogin       Code = "login.iemsi"
+	MsgLoginSent        Code = "login.sent"
+	MsgScheduleDone     Code = "schedule.done"
+	MsgPrintSaved       Code = "print.saved"
+	MsgPrinted          Code = "print.sent"
+	MsgPluginStatus     Code = "plugin.status"
+	MsgReconnectFailed  Code = "reconnect.failed"
+	MsgDownloadRefused  Code = "zmodem.download_refused"
+	MsgFileBlocked      Code = "zmodem.file_blocked"
+	MsgFileWarned       Code = "zmodem.file_warned"
+)
+
+// Testi delle finestre di dialogo e del tray.
+const (
+	DlgAllFiles       Code = "dialog.all_files"
+	DlgLogFiles       Code = "dialog.log_files"
+	DlgImportBook     Code = "dialog.import_phonebook"
+	DlgExportBook     Code = "dialog.export_phonebook"
+	DlgBookFiles      Code = "dialog.phonebook_files"
+	DlgUpload         Code = "dialog.upload"
+	DlgOpenLog        Code = "dialog.open_log"
+	DlgOpenArt        Code = "dialog.open_art"
+	DlgArtFiles       Code = "dialog.art_files"
+	DlgExportPNG      Code = "dialog.export_png"
+	DlgPNGFiles       Code = "dialog.png_files"
+	DlgExportGIF      Code = "dialog.export_gif"
+	DlgGIFFiles       Code = "dialog.gif_files"
+	DlgExportLogPick  Code = "dialog.export_log_pick"
+	DlgExportHTML     Code = "dialog.export_html"
+	DlgHTMLFiles      Code = "dialog.html_files"
+	DlgExportText     Code = "dialog.export_text"
+	DlgTranscripts    Code = "dialog.transcript_files"
+	DlgSaveScrollback Code = "dialog.save_scrollback"
+	DlgScrollback     Code = "dialog.scrollback_files"
+	DlgCapture        Code = "dialog.capture"
+	DlgCaptureFiles   Code = "dialog.capture_files"
+	DlgOpenMail       Code = "dialog.open_mail"
+	DlgMailFiles      Code = "dialog.mail_files"
+	DlgSaveReplies    Code = "dialog.save_replies"
+	DlgReplyFiles     Code = "dialog.reply_files"
+	DlgSendText       Code = "dialog.send_text"
+	DlgTextFiles      Code = "dialog.text_files"
+	DlgPasteTitle     Code = "dialog.paste_title"
+	DlgPasteConfirm   Code = "dialog.paste_confirm"
+	DlgSend           Code = "dialog.send"
+	DlgCancel         Code = "dialog.cancel"
+	DlgRestoreTitle   Code = "dialog.restore_title"
+	DlgRestoreMessage Code = "dialog.restore_message"
+	DlgRestoreFiles   Code = "dialog.restore_transfers"
+	DlgReconnect      Code = "dialog.reconnect"
+	DlgPrintTitle     Code = "dialog.print_title"
+	DlgPrintMessage   Code = "dialog.print_message"
+	DlgPrint          Code = "dialog.print"
+	DlgDownloadTitle  Code = "dialog.download_title"
+	DlgDownloadOffer  Code = "dialog.download_offer"
+	DlgDownload       Code = "dialog.download"
+	DlgHostKeyTitle   Code = "dialog.host_key_title"
+	DlgHostKey        Code = "dialog.host_key"
+	DlgTrust          Code = "dialog.trust"
+
+	TrayShow          Code = "tray.show"
+	TrayShowHint      Code = "tray.show_hint"
+	TrayReconnect     Code = "tray.reconnect"
+	TrayReconnectHint Code = "tray.reconnect_hint"
+	TrayDisconnect    Code = "tray.disconnect"
+	TrayDisconnHint   Code = "tray.disconnect_hint"
+	TrayQuit          Code = "tray.quit"
+	TrayQuitHint      Code = "tray.quit_hint"
+	TrayOffline       Code = "tray.offline"
+	TrayUnread        Code = "tray.unread"
+)
+
+// ─────────────────────────────────────────────
+// Traduzioni
+// ─────────────────────────────────────────────
+
+var catalogs = map[string]map[Code]string{
+	Italian: {
+		ErrGeneric:           "Errore: %s",
+		ErrRead:              "Errore lettura: %s",
+		ErrAlreadyConnected:  "Già connesso",
+		ErrConnecting:        "Connessione già in corso",
+		ErrNotConnected:      "Non connesso",
+		ErrUnsupportedScheme: "Protocollo non ancora supportato: %s",
+		ErrUnknownSession:    "Sessione sconosciuta: %s",
+		ErrUnknownBold:       "Policy bold sconosciuta: %s",
+		ErrUnknownLanguage:   "Lingua sconosciuta: %s",
+		ErrUnknownEmulation:  "Emulazione sconosciuta: %s",
+		ErrUnknownKeyboard:   "Layout di tastiera sconosciuto: %s",
+		ErrUnknownKeyProfile: "Variante dei tasti sconosciuta: %s",
+		ErrUnknownCharset:    "Codifica sconosciuta: %s",
+		ErrUnknownFormat:     "Formato sconosciuto: %s",
+		ErrNothingOpen:       "Nessun log o artwork aperto",
+		ErrNoTiming:          "Il log non ha informazioni di temporizzazione",
+		ErrNoMailPacket:      "Nessun pacchetto di posta aperto",
+		ErrNoReplies:         "Nessuna risposta da salvare",
+		ErrPasteBusy:         "Invio testo già in corso",
+		ErrFileTooLarge:      "File troppo grande (%s KB, max %s KB): usa l'upload ZMODEM",
+		ErrKeychain:          "Portachiavi di sistema non disponibile: %s",
+		ErrIEMSIFailed:       "Login IEMSI non riuscito",
+		ErrNoLogin:           "Nessuna credenziale salvata per %s",
+		ErrNoPasswordPrompt:  "Richiesta della password non ricevuta",
+		ErrNoMarkedFiles:     "Nessun file selezionato",
+		ErrInvalidSchedule:   "Chiamata programmata non valida: %s",
+		ErrUnknownSchedule:   "Chiamata programmata sconosciuta: %s",
+		ErrScheduleFailed:    "Chiamata programmata non riuscita: %s",
+		ErrShareStart:        "Condivisione non avviata: %s",
+		ErrPrint:             "Stampa non riuscita: %s",
+		ErrUnknownPrinter:    "Destinazione di stampa sconosciuta: %s",
+		ErrInvalidPacing:     "Pause di invio non valide: %s ms, %s ms",
+		ErrInvalidPlugin:     "Plugin non valido: %s",
+		ErrPluginStart:       "Plugin %s non avviato: %s",
+		ErrControlStart:      "Endpoint di controllo non avviato: %s",
+		ErrInvalidTheme:      "Tema non valido: %s",
+		ErrUnknownTheme:      "Tema sconosciuto: %s",
+		ErrBitmapFont:        "Font non valido: %s",
+		ErrInvalidLimit:      "Limite fuori intervallo: %s",
+		ErrUnknownDownloads:  "Scelta sconosciuta per i download: %s",
+		ErrInvalidFileType:   "Estensione non valida: %q",
+		ErrInvalidProxy:      "Proxy non valido: %s",
+		ErrUnknownProxy:      "Proxy sconosciuto: %s",
+		ErrHostKeyChanged:    "La chiave host di %s è cambiata (ora %s): connessione rifiutata. Se il cambio è atteso, dimentica la chiave registrata e riconnettiti",
+		ErrHostKeyRejected:   "Chiave host di %s non accettata",
+		ErrInvalidPattern:    "Espressione regolare non valida: %s",
+		ErrInvalidBridge:     "Programma da collegare non valido: %s",
+		ErrBridgeBusy:        "Un programma è già collegato alla sessione",
+		ErrBridgeStart:       "Impossibile avviare %s: %s",
+
+		MsgDisconnected:     "Disconnesso: %s",
+		MsgConnectionError:  "Errore: %s",
+		MsgIdleDisconnected: "Disconnesso per inattività",
+		MsgNewSession:       "Nuova sessione",
+		MsgIEMSILogin:       "Login IEMSI su %s",
+		MsgLoginSent:        "Credenziali inviate a %s",
+		MsgScheduleDone:     "Chiamata programmata completata: %s",
+		MsgPrintSaved:       "Stampa della BBS salvata in %s",
+		MsgPrinted:          "Stampa inviata alla stampante",
+		MsgPluginStatus:     "%s: %s",
+		MsgReconnectFailed:  "Riconnessione a %s non riuscita dopo %s tentativi",
+		MsgDownloadRefused:  "Download di %s rifiutato",
+		MsgFileBlocked:      "File %s rifiutato: tipo di file bloccato",
+		MsgFileWarned:       "Attenzione: %s può contenere programmi, controllalo prima di aprirlo",
+
+		DlgAllFiles:       "Tutti i file (*)",
+		DlgLogFiles:       "Log di sessione (*.log)",
+		DlgImportBook:     "Importa rubrica",
+		DlgExportBook:     "Esporta rubrica",
+		DlgBookFiles:      "Rubrica (*.%s)",
+		DlgUpload:         "Seleziona file per upload ZMODEM",
+		DlgOpenLog:        "Apri log sessione",
+		DlgOpenArt:        "Apri ANSI art",
+		DlgArtFiles:       "ANSI art (*.ans, *.asc, *.diz, *.nfo)",
+		DlgExportPNG:      "Esporta schermata PNG",
+		DlgPNGFiles:       "Immagini PNG (*.png)",
+		DlgExportGIF:      "Esporta GIF animata",
+		DlgGIFFiles:       "GIF animate (*.gif)",
+		DlgExportLogPick:  "Scegli il log da esportare",
+		DlgExportHTML:     "Esporta log in HTML",
+		DlgHTMLFiles:      "Pagine HTML (*.html)",
+		DlgExportText:     "Esporta log come testo",
+		DlgTranscripts:    "Trascrizioni (*.txt)",
+		DlgSaveScrollback: "Salva lo scrollback",
+		DlgScrollback:     "Testo (*.txt) o ANSI (*.ans)",
+		DlgCapture:        "Cattura output in…",
+		DlgCaptureFiles:   "Capture ANSI (*.ans, *.txt)",
+		DlgOpenMail:       "Apri pacchetto di posta Blue Wave",
+		DlgMailFiles:      "Pacchetti Blue Wave (*.su?, *.mo?, *.tu?, *.we?, *.th?, *.fr?, *.sa?)",
+		DlgSaveReplies:    "Salva risposte Blue Wave",
+		DlgReplyFiles:     "Risposte Blue Wave (*.new)",
+		DlgSendText:       "Scegli il file di testo da inviare",
+		DlgTextFiles:      "File di testo (*.txt, *.msg)",
+		DlgPasteTitle:     "Incolla testo",
+		DlgPasteConfirm:   "Stai per inviare %s righe (%s caratteri). Continuare?",
+		DlgSend:           "Invia",
+		DlgCancel:         "Annulla",
+		DlgRestoreTitle:   "Sessione interrotta",
+		DlgRestoreMessage: "Il client si è chiuso in modo inatteso (%s).\n\nRiconnettersi a:\n%s",
+		DlgRestoreFiles:   "Download interrotti (da richiedere di nuovo alla BBS):\n%s",
+		DlgReconnect:      "Riconnetti",
+		DlgPrintTitle:     "Stampa dalla BBS",
+		DlgPrintMessage:   "%s vuole stampare %s caratteri sulla stampante di sistema. Stampare?",
+		DlgPrint:          "Stampa",
+		DlgDownloadTitle:  "File in arrivo",
+		DlgDownloadOffer:  "%s vuole inviare il file %s (%s byte). Scaricarlo?",
+		DlgDownload:       "Scarica",
+		DlgHostKeyTitle:   "Chiave host sconosciuta",
+		DlgHostKey:        "È la prima connessione a %s. La sua chiave %s ha impronta:\n\n%s\n\nSe corrisponde a quella pubblicata dalla BBS, fidarsi e continuare?",
+		DlgTrust:          "Fidati",
+
+		TrayShow:          "Mostra finestra",
+		TrayShowHint:      "Riporta in primo piano la finestra",
+		TrayReconnect:     "Riconnetti",
+		TrayReconnectHint: "Riconnette all'ultima BBS",
+		TrayDisconnect:    "Disconnetti",
+		TrayDisconnHint:   "Chiude la connessione",
+		TrayQuit:          "Esci",
+		TrayQuitHint:      "Chiude il client",
+		TrayOffline:       "offline",
+		TrayUnread:        " (%s avvisi)",
+	},
+	English: {
+		ErrGeneric:           "Error: %s",
+		ErrRead:              "Read error: %s",
+		ErrAlreadyConnected:  "Already connected",
+		ErrConnecting:        "Connection already in progress",
+		ErrNotConnected:      "Not connected",
+		ErrUnsupportedScheme: "Protocol not supported yet: %s",
+		ErrUnknownSession:    "Unknown session: %s",
+		ErrUnknownBold:       "Unknown bold policy: %s",
+		ErrUnknownLanguage:   "Unknown language: %s",
+		ErrUnknownEmulation:  "Unknown emulation: %s",
+		ErrUnknownKeyboard:   "Unknown keyboard layout: %s",
+		ErrUnknownKeyProfile: "Unknown key profile: %s",
+		ErrUnknownCharset:    "Unknown character encoding: %s",
+		ErrUnknownFormat:     "Unknown format: %s",
+		ErrNothingOpen:       "No log or artwork open",
+		ErrNoTiming:          "The log has no timing information",
+		ErrNoMailPacket:      "No mail packet open",
+		ErrNoReplies:         "No replies to save",
+		ErrPasteBusy:         "Text sending already in progress",
+		ErrFileTooLarge:      "File too large (%s KB, max %s KB): use a ZMODEM upload",
+		ErrKeychain:          "System keychain not available: %s",
+		ErrIEMSIFailed:       "IEMSI login failed",
+		ErrNoLogin:           "No saved credentials for %s",
+		ErrNoPasswordPrompt:  "Password prompt not received",
+		ErrNoMarkedFiles:     "No files marked",
+		ErrInvalidSchedule:   "Invalid scheduled call: %s",
+		ErrUnknownSchedule:   "Unknown scheduled call: %s",
+		ErrScheduleFailed:    "Scheduled call failed: %s",
+		ErrShareStart:        "Could not start sharing: %s",
+		ErrPrint:             "Printing failed: %s",
+		ErrUnknownPrinter:    "Unknown print destination: %s",
+		ErrInvalidPacing:     "Invalid send delays: %s ms, %s ms",
+		ErrInvalidPlugin:     "Invalid plugin: %s",
+		ErrPluginStart:       "Plugin %s not started: %s",
+		ErrControlStart:      "Control endpoint not started: %s",
+		ErrInvalidTheme:      "Invalid theme: %s",
+		ErrUnknownTheme:      "Unknown theme: %s",
+		ErrBitmapFont:        "Invalid font: %s",
+		ErrInvalidLimit:      "Limit out of range: %s",
+		ErrUnknownDownloads:  "Unknown download choice: %s",
+		ErrInvalidFileType:   "Invalid extension: %q",
+		ErrInvalidProxy:      "Invalid proxy: %s",
+		ErrUnknownProxy:      "Unknown proxy: %s",
+		ErrHostKeyChanged:    "The host key of %s has changed (now %s): connection refused. If the change is expected, forget the stored key and reconnect",
+		ErrHostKeyRejected:   "Host key of %s not accepted",
+		ErrInvalidPattern:    "Invalid regular expression: %s",
+		ErrInvalidBridge:     "Invalid program to attach: %s",
+		ErrBridgeBusy:        "A program is already attached to the session",
+		ErrBridgeStart:       "Could not start %s: %s",
+
+		MsgDisconnected:     "Disconnected: %s",
+		MsgConnectionError:  "Error: %s",
+		MsgIdleDisconnected: "Disconnected after inactivity",
+		MsgNewSession:       "New session",
+		MsgIEMSILogin:       "IEMSI login to %s",
+		MsgLoginSent:        "Credentials sent to %s",
+		MsgScheduleDone:     "Scheduled call completed: %s",
+		MsgPrintSaved:       "BBS printout saved to %s",
+		MsgPrinted:          "Printout sent to the printer",
+		MsgPluginStatus:     "%s: %s",
+		MsgReconnectFailed:  "Could not reconnect to %s after %s attempts",
+		MsgDownloadRefused:  "Download of %s refused",
+		MsgFileBlocked:      "File %s refused: blocked file type",
+		MsgFileWarned:       "Warning: %s may contain programs, check it before opening",
+
+		DlgAllFiles:       "All files (*)",
+		DlgLogFiles:       "Session logs (*.log)",
+		DlgImportBook:     "Import phonebook",
+		DlgExportBook:     "Export phonebook",
+		DlgBookFiles:      "Phonebook (*.%s)",
+		DlgUpload:         "Select file for ZMODEM upload",
+		DlgOpenLog:        "Open session log",
+		DlgOpenArt:        "Open ANSI art",
+		DlgArtFiles:       "ANSI art (*.ans, *.asc, *.diz, *.nfo)",
+		DlgExportPNG:      "Export PNG screenshot",
+		DlgPNGFiles:       "PNG images (*.png)",
+		DlgExportGIF:      "Export animated GIF",
+		DlgGIFFiles:       "Animated GIFs (*.gif)",
+		DlgExportLogPick:  "Choose the log to export",
+		DlgExportHTML:     "Export log as HTML",
+		DlgHTMLFiles:      "HTML pages (*.html)",
+		DlgExportText:     "Export log as text",
+		DlgTranscripts:    "Transcripts (*.txt)",
+		DlgSaveScrollback: "Save scrollback",
+		DlgScrollback:     "Text (*.txt) or ANSI (*.ans)",
+		DlgCapture:        "Capture output to…",
+		DlgCaptureFiles:   "ANSI capture (*.ans, *.txt)",
+		DlgOpenMail:       "Open Blue Wave mail packet",
+		DlgMailFiles:      "Blue Wave packets (*.su?, *.mo?, *.tu?, *.we?, *.th?, *.fr?, *.sa?)",
+		DlgSaveReplies:    "Save Blue Wave replies",
+		DlgReplyFiles:     "Blue Wave replies (*.new)",
+		DlgSendText:       "Choose the text file to send",
+		DlgTextFiles:      "Text files (*.txt, *.msg)",
+		DlgPasteTitle:     "Paste text",
+		DlgPasteConfirm:   "You are about to send %s lines (%s characters). Continue?",
+		DlgSend:           "Send",
+		DlgCancel:         "Cancel",
+		DlgRestoreTitle:   "Session interrupted",
+		DlgRestoreMessage: "The client closed unexpectedly (%s).\n\nReconnect to:\n%s",
+		DlgRestoreFiles:   "Interrupted downloads (request them again from the BBS):\n%s",
+		DlgReconnect:      "Reconnect",
+		DlgPrintTitle:     "Print from BBS",
+		DlgPrintMessage:   "%s wants to print %s characters on the system printer. Print?",
+		DlgPrint:          "Print",
+		DlgDownloadTitle:  "Incoming file",
+		DlgDownloadOffer:  "%s wants to send the file %s (%s bytes). Download it?",
+		DlgDownload:       "Download",
+		DlgHostKeyTitle:   "Unknown host key",
+		DlgHostKey:        "This is the first connection to %s. Its %s key has fingerprint:\n\n%s\n\nIf it matches the one published by the BBS, trust it and continue?",
+		DlgTrust:          "Trust",
+
+		TrayShow:          "Show window",
+		TrayShowHint:      "Bring the window to the front",
+		TrayReconnect:     "Reconnect",
+		TrayReconnectHint: "Reconnect to the last BBS",
+		TrayDisconnect:    "Disconnect",
+		TrayDisconnHint:   "Close the connection",
+		TrayQuit:          "Quit",
+		TrayQuitHint:      "Close the client",
+		TrayOffline:       "offline",
+		TrayUnread:        " (%s alerts)",
+	},
+}
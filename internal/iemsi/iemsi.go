@@ -0,0 +1,311 @@
+// Package iemsi implementa il lato client dell'handshake IEMSI (Interactive
+// EMSI), con cui le BBS dell'epoca RemoteAccess/ProBoard ricevono nome,
+// password e caratteristiche del terminale senza passare dai prompt.
+//
+// Lo scambio è:
+//
+//	BBS    → **EMSI_IRQ8E08       richiesta
+//	client → **EMSI_ICI<...>      dati dell'utente (CRC-32)
+//	BBS    → **EMSI_ISI<...>      dati della BBS   (o EMSI_NAK: si ripete)
+//	client → **EMSI_ACKA490 ×2    conferma
+//
+// I pacchetti brevi terminano con un CRC-16 in esadecimale, quelli con dati
+// con lunghezza (4 cifre esadecimali) e CRC-32: gli stessi CRC di ZMODEM.
+package iemsi
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rj45lab/bbs-client-go/pkg/zmodem"
+)
+
+// ─────────────────────────────────────────────
+// Pacchetti
+// ─────────────────────────────────────────────
+
+const (
+	prefix = "**EMSI_"
+
+	// Pacchetti brevi completi di CRC-16
+	irq = "**EMSI_IRQ8E08"
+	ack = "**EMSI_ACKA490"
+	nak = "**EMSI_NAKEEC3"
+
+	// MaxTries è il numero massimo di invii di EMSI_ICI (NAK compresi)
+	MaxTries = 3
+
+	// maxPacket limita la lunghezza dei dati di un pacchetto (4 cifre hex)
+	maxPacket = 0xFFFF
+	// maxPending limita i dati tenuti in attesa di un pacchetto completo
+	maxPending = 8 * 1024
+)
+
+// Profile sono i dati dell'utente inviati nel pacchetto EMSI_ICI.
+type Profile struct {
+	Name       string
+	Alias      string
+	Location   string
+	DataPhone  string
+	VoicePhone string
+	Password   string
+	Birthdate  time.Time // zero = non indicata
+
+	// Terminale
+	TermType string // ANSI, AVT0, VT100, TTY
+	Rows     int
+	Cols     int
+
+	Protocols    []string // trasferimenti supportati: ZMO, ZAP, KER...
+	Capabilities []string // CHT (chat), TAB, ASCII8
+	Requests     []string // NEWS, MAIL, FILE, HOT, CLR, HUSH, MORE, FSED
+
+	Software string
+	Version  string
+}
+
+// ServerInfo sono i dati della BBS ricevuti nel pacchetto EMSI_ISI.
+type ServerInfo struct {
+	Name         string `json:"name"`
+	Location     string `json:"location"`
+	Operator     string `json:"operator"`
+	LocalTime    string `json:"localTime"`
+	Notice       string `json:"notice"`
+	Wait         string `json:"wait"`
+	Capabilities string `json:"capabilities"`
+}
+
+// ICI ritorna il pacchetto EMSI_ICI completo per il profilo p.
+func (p Profile) ICI() []byte {
+	birth := ""
+	if !p.Birthdate.IsZero() {
+		birth = strings.ToUpper(strconv.FormatInt(p.Birthdate.Unix(), 16))
+	}
+	fields := []string{
+		p.Name, p.Alias, p.Location, p.DataPhone, p.VoicePhone, p.Password, birth,
+		fmt.Sprintf("%s,%d,%d,0", p.TermType, p.Rows, p.Cols),
+		strings.Join(p.Protocols, ","),
+		strings.Join(p.Capabilities, ","),
+		strings.Join(p.Requests, ","),
+		strings.Join([]string{p.Software, p.Version, ""}, ","),
+		"", // tabella di traduzione
+	}
+	var data strings.Builder
+	for _, f := range fields {
+		data.WriteByte('{')
+		data.WriteString(escape(f))
+		data.WriteByte('}')
+	}
+	return packet("ICI", data.String())
+}
+
+// packet costruisce un pacchetto con dati: **EMSI_<tipo><len><dati><crc32>\r.
+func packet(kind, data string) []byte {
+	body := fmt.Sprintf("EMSI_%s%04X%s", kind, len(data), data)
+	crc := zmodem.CRC32([]byte(body), 0xFFFFFFFF)
+	return []byte(fmt.Sprintf("**%s%08X\r", body, crc))
+}
+
+// escape codifica un campo: le parentesi di chiusura vengono raddoppiate,
+// il backslash e i caratteri non stampabili diventano \xx esadecimale.
+func escape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '}' || c == ']':
+			b.WriteByte(c)
+			b.WriteByte(c)
+		case c == '\\' || c < 0x20 || c > 0x7E:
+			fmt.Fprintf(&b, "\\%02X", c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// fields divide i dati di un pacchetto nei campi {...}, togliendo la
+// codifica di escape.
+func fields(data string) []string {
+	var out []string
+	for len(data) > 0 && data[0] == '{' {
+		var f strings.Builder
+		i := 1
+		for ; i < len(data); i++ {
+			c := data[i]
+			if c == '}' {
+				if i+1 < len(data) && data[i+1] == '}' {
+					f.WriteByte('}')
+					i++
+					continue
+				}
+				break
+			}
+			if c == ']' && i+1 < len(data) && data[i+1] == ']' {
+				i++
+			} else if c == '\\' && i+2 < len(data) {
+				if v, err := strconv.ParseUint(data[i+1:i+3], 16, 8); err == nil {
+					c = byte(v)
+					i += 2
+				}
+			}
+			f.WriteByte(c)
+		}
+		out = append(out, f.String())
+		if i >= len(data) {
+			break
+		}
+		data = data[i+1:]
+	}
+	return out
+}
+
+// parseISI interpreta i dati di un pacchetto EMSI_ISI.
+func parseISI(data string) ServerInfo {
+	f := fields(data)
+	for len(f) < 7 {
+		f = append(f, "")
+	}
+	return ServerInfo{
+		Name: f[0], Location: f[1], Operator: f[2], LocalTime: f[3],
+		Notice: f[4], Wait: f[5], Capabilities: f[6],
+	}
+}
+
+// ─────────────────────────────────────────────
+// Client
+// ─────────────────────────────────────────────
+
+// Result è l'esito dell'elaborazione di un blocco ricevuto.
+type Result int
+
+const (
+	Pending   Result = iota // nulla di concluso
+	Started                 // richiesta ricevuta, EMSI_ICI inviato
+	Completed               // EMSI_ISI ricevuto e confermato
+	Failed                  // troppi NAK o pacchetti non validi
+)
+
+// Client riconosce i pacchetti IEMSI nel flusso ricevuto e prepara le
+// risposte. Non è sicuro per l'uso concorrente.
+type Client struct {
+	profile Profile
+	buf     []byte
+	tries   int
+	done    bool
+	server  ServerInfo
+}
+
+// NewClient crea un client che risponde con il profilo p.
+func NewClient(p Profile) *Client {
+	return &Client{profile: p}
+}
+
+// Server ritorna i dati della BBS (validi dopo Completed).
+func (c *Client) Server() ServerInfo {
+	return c.server
+}
+
+// Feed esamina un blocco ricevuto e ritorna i byte da inviare alla BBS
+// (nil se nessuno) e l'esito. Dopo Completed o Failed i dati sono ignorati.
+func (c *Client) Feed(data []byte) ([]byte, Result) {
+	if c.done {
+		return nil, Pending
+	}
+	c.buf = append(c.buf, data...)
+
+	var reply []byte
+	result := Pending
+	for {
+		i := bytes.Index(c.buf, []byte(prefix))
+		if i < 0 {
+			// Si conserva solo una possibile parte iniziale del prefisso
+			c.buf = c.buf[max(0, len(c.buf)-len(prefix)+1):]
+			break
+		}
+		c.buf = c.buf[i:]
+		n, out, res := c.handle()
+		if n == 0 {
+			// Pacchetto incompleto: si attende altro
+			if len(c.buf) > maxPending {
+				c.buf = c.buf[len(prefix):]
+				continue
+			}
+			break
+		}
+		c.buf = c.buf[n:]
+		reply = append(reply, out...)
+		if res != Pending {
+			result = res
+		}
+		if c.done {
+			c.buf = nil
+			break
+		}
+	}
+	return reply, result
+}
+
+// handle interpreta il pacchetto all'inizio di c.buf. Ritorna i byte
+// consumati (0 = incompleto), la risposta e l'esito.
+func (c *Client) handle() (int, []byte, Result) {
+	buf := string(c.buf)
+	if len(buf) < len(irq) {
+		return 0, nil, Pending
+	}
+	switch {
+	case strings.HasPrefix(buf, irq):
+		return c.sendICI(len(irq))
+	case strings.HasPrefix(buf, nak):
+		return c.sendICI(len(nak))
+	case strings.HasPrefix(buf, prefix+"ISI"):
+		return c.handleISI(buf)
+	}
+	// Altri pacchetti (ACK, CHT...) o testo qualsiasi: si prosegue oltre
+	return len(prefix), nil, Pending
+}
+
+// sendICI invia (o ripete) il pacchetto con i dati dell'utente, dopo
+// aver consumato n byte.
+func (c *Client) sendICI(n int) (int, []byte, Result) {
+	c.tries++
+	if c.tries > MaxTries {
+		c.done = true
+		return n, nil, Failed
+	}
+	return n, c.profile.ICI(), Started
+}
+
+// handleISI verifica un pacchetto EMSI_ISI e lo conferma.
+func (c *Client) handleISI(buf string) (int, []byte, Result) {
+	head := len(prefix) + 3
+	if len(buf) < head+4 {
+		return 0, nil, Pending
+	}
+	size, err := strconv.ParseUint(buf[head:head+4], 16, 16)
+	if err != nil || size > maxPacket {
+		return head, nil, Pending
+	}
+	end := head + 4 + int(size) + 8
+	if len(buf) < end {
+		return 0, nil, Pending
+	}
+	body := buf[2 : end-8]
+	want, err := strconv.ParseUint(buf[end-8:end], 16, 32)
+	if err != nil || uint32(want) != zmodem.CRC32([]byte(body), 0xFFFFFFFF) {
+		// CRC errato: la BBS ripete il pacchetto dopo il NAK
+		c.tries++
+		if c.tries > MaxTries {
+			c.done = true
+			return end, nil, Failed
+		}
+		return end, []byte(nak + "\r"), Pending
+	}
+	c.server = parseISI(body[head-2+4:])
+	c.done = true
+	return end, []byte(ack + "\r" + ack + "\r"), Completed
+}
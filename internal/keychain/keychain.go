@@ -0,0 +1,35 @@
+// Package keychain conserva le password delle BBS nel portachiavi del
+// sistema operativo (Keychain su macOS, Credential Manager su Windows,
+// Secret Service su Linux), così non finiscono nei file di configurazione.
+package keychain
+
+import (
+	"errors"
+
+	"github.com/zalando/go-keyring"
+)
+
+// service è il nome con cui le voci compaiono nel portachiavi.
+const service = "bbs-client"
+
+// SetPassword salva la password della BBS key (host:port). Una password
+// vuota cancella quella salvata.
+func SetPassword(key, password string) error {
+	if password == "" {
+		err := keyring.Delete(service, key)
+		if errors.Is(err, keyring.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	return keyring.Set(service, key, password)
+}
+
+// Password ritorna la password della BBS key ("" se non salvata).
+func Password(key string) (string, error) {
+	pw, err := keyring.Get(service, key)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return "", nil
+	}
+	return pw, err
+}
@@ -0,0 +1,153 @@
+// Package keymap traduce i caratteri prodotti da una tastiera nazionale
+// nei byte CP437 da inviare alle BBS: compone gli accenti dei tasti morti
+// e sostituisce i caratteri che CP437 non ha con la grafia in uso nel
+// paese (in italiano È diventa E').
+package keymap
+
+import (
+	"slices"
+	"unicode/utf8"
+
+	"github.com/rj45lab/bbs-client-go/internal/cp437"
+)
+
+// Layout descrive una tastiera nazionale.
+type Layout struct {
+	Name string `json:"name"`
+	// Dead associa i tasti morti (KeyboardEvent.code, preceduto da
+	// "Shift+" se premuti con il maiuscolo) all'accento che producono
+	Dead map[string]rune `json:"dead,omitempty"`
+	// Fallback sostituisce i caratteri senza equivalente CP437
+	Fallback map[rune]string `json:"fallback,omitempty"`
+}
+
+// Default è il layout usato se le impostazioni non ne indicano uno.
+const Default = "it"
+
+// layouts sono le tastiere conosciute, per nome.
+var layouts = map[string]*Layout{
+	"us": {Name: "us"},
+	"us-intl": {Name: "us-intl", Dead: map[string]rune{
+		"Quote": '´', "Shift+Quote": '¨',
+		"Backquote": '`', "Shift+Backquote": '~',
+		"Shift+Digit6": '^',
+	}},
+	"it": {Name: "it", Fallback: map[rune]string{
+		'À': "A'", 'È': "E'", 'Ì': "I'", 'Ò': "O'", 'Ù': "U'",
+	}},
+	"de": {Name: "de", Dead: map[string]rune{
+		"Backquote": '^', "Equal": '´', "Shift+Equal": '`',
+	}},
+	"fr": {Name: "fr", Dead: map[string]rune{
+		"BracketLeft": '^', "Shift+BracketLeft": '¨',
+	}},
+	"es": {Name: "es", Dead: map[string]rune{
+		"BracketLeft": '`', "Shift+BracketLeft": '^',
+		"Quote": '´', "Shift+Quote": '¨',
+	}},
+}
+
+// Lookup ritorna il layout name.
+func Lookup(name string) (*Layout, bool) {
+	l, ok := layouts[name]
+	return l, ok
+}
+
+// Names ritorna i nomi dei layout conosciuti, in ordine alfabetico.
+func Names() []string {
+	names := make([]string, 0, len(layouts))
+	for name := range layouts {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// DeadKey ritorna l'accento del tasto morto code, se il layout ne ha uno.
+func (l *Layout) DeadKey(code string, shift bool) (rune, bool) {
+	if shift {
+		code = "Shift+" + code
+	}
+	r, ok := l.Dead[code]
+	return r, ok
+}
+
+// ─────────────────────────────────────────────
+// Composizione degli accenti
+// ─────────────────────────────────────────────
+
+// accents elenca, per ogni accento, le coppie lettera base → lettera
+// accentata.
+var accents = map[rune]string{
+	'`': "aàeèiìoòuùAÀEÈIÌOÒUÙ",
+	'´': "aáeéiíoóuúyýAÁEÉIÍOÓUÚYÝ",
+	'^': "aâeêiîoôuûAÂEÊIÎOÔUÛ",
+	'¨': "aäeëiïoöuüyÿAÄEËIÏOÖUÜ",
+	'~': "aãnñoõAÃNÑOÕ",
+}
+
+// compose e base sono le tabelle ricavate da accents.
+var compose, base = func() (map[[2]rune]rune, map[rune]rune) {
+	c, b := map[[2]rune]rune{}, map[rune]rune{}
+	for accent, pairs := range accents {
+		p := []rune(pairs)
+		for i := 0; i+1 < len(p); i += 2 {
+			c[[2]rune{accent, p[i]}] = p[i+1]
+			b[p[i+1]] = p[i]
+		}
+	}
+	return c, b
+}()
+
+// accentText sostituisce gli accenti isolati che CP437 non ha.
+var accentText = map[rune]string{'´': "'", '¨': "\""}
+
+// Compose applica l'accento del tasto morto al testo digitato dopo. Il
+// testo già composto dal sistema operativo resta com'è; lo spazio dà
+// l'accento da solo, una lettera che non lo accetta lo riceve davanti.
+func Compose(accent rune, text string) string {
+	r, size := utf8.DecodeRuneInString(text)
+	switch {
+	case size == 0:
+		return text
+	case r == ' ' || r == accent:
+		return string(accent) + text[size:]
+	case base[r] != 0:
+		return text // già composto
+	}
+	if c, ok := compose[[2]rune{accent, r}]; ok {
+		return string(c) + text[size:]
+	}
+	return string(accent) + text
+}
+
+// ─────────────────────────────────────────────
+// Codifica
+// ─────────────────────────────────────────────
+
+// Encode converte text in byte CP437. I caratteri che CP437 non ha
+// diventano la grafia del layout, la lettera senza accento, la
+// traslitterazione di cp437.Transliterate o '?'.
+func (l *Layout) Encode(text string) []byte {
+	out := make([]byte, 0, len(text))
+	for _, r := range text {
+		if b, ok := cp437.Text(r); ok {
+			out = append(out, b)
+			continue
+		}
+		if s, ok := l.Fallback[r]; ok {
+			out = append(out, cp437.Encode(s)...)
+			continue
+		}
+		if s, ok := accentText[r]; ok {
+			out = append(out, s...)
+			continue
+		}
+		if b, ok := cp437.Text(base[r]); ok && base[r] != 0 {
+			out = append(out, b)
+			continue
+		}
+		out = append(out, cp437.Encode(string(r))...)
+	}
+	return out
+}
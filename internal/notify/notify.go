@@ -0,0 +1,55 @@
+// Package notify mostra notifiche di sistema usando gli strumenti nativi
+// di ogni piattaforma (osascript su macOS, notify-send su Linux/BSD,
+// PowerShell su Windows), senza dipendenze cgo.
+package notify
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"runtime"
+)
+
+// ErrUnsupported indica che sulla piattaforma non è disponibile un
+// meccanismo di notifica.
+var ErrUnsupported = errors.New("notifiche di sistema non disponibili")
+
+// Send mostra una notifica senza attendere che venga chiusa. Titolo e
+// testo sono passati come argomenti o variabili d'ambiente, mai inseriti
+// nello script, così il contenuto ricevuto da una BBS non può iniettare
+// comandi.
+func Send(title, body string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("osascript",
+			"-e", "on run argv",
+			"-e", "display notification (item 2 of argv) with title (item 1 of argv)",
+			"-e", "end run",
+			title, body)
+	case "windows":
+		cmd = exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", windowsScript)
+		cmd.Env = append(os.Environ(), "BBS_NOTIFY_TITLE="+title, "BBS_NOTIFY_BODY="+body)
+	default:
+		path, err := exec.LookPath("notify-send")
+		if err != nil {
+			return ErrUnsupported
+		}
+		cmd = exec.Command(path, "--app-name=BBS Client", "--", title, body)
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go cmd.Wait()
+	return nil
+}
+
+// windowsScript mostra un balloon dall'area di notifica (niente moduli
+// esterni: funziona su ogni Windows con .NET).
+const windowsScript = `Add-Type -AssemblyName System.Windows.Forms
+$n = New-Object System.Windows.Forms.NotifyIcon
+$n.Icon = [System.Drawing.SystemIcons]::Information
+$n.Visible = $true
+$n.ShowBalloonTip(5000, $env:BBS_NOTIFY_TITLE, $env:BBS_NOTIFY_BODY, 'Info')
+Start-Sleep -Seconds 6
+$n.Dispose()`
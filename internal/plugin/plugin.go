@@ -0,0 +1,397 @@
+// Package plugin esegue estensioni esterne come processi figli che
+// dialogano con il client su stdin/stdout, un messaggio JSON per riga.
+// Un plugin può essere scritto in qualsiasi linguaggio: riceve il testo
+// ricevuto dalla BBS e gli eventi della sessione, e può inviare tasti,
+// registrare trigger e mostrare messaggi, senza ricompilare il client.
+//
+// Messaggi dal client al plugin (stdin):
+//
+//	{"type":"hello","version":1,"session":"s1","bbs":"Metro Olografix","host":"bbs.olografix.org","port":23}
+//	{"type":"output","text":"\u001b[1;33mBenvenuto\r\n"}   testo ricevuto (UTF-8, sequenze ANSI intatte)
+//	{"type":"event","event":"bell"}                        evento della sessione
+//	{"type":"trigger","id":"mail","line":"You have new mail"}
+//	{"type":"error","text":"..."}                          messaggio del plugin non valido
+//
+// Messaggi dal plugin al client (stdout):
+//
+//	{"type":"send","text":"G\r"}                           tasti da inviare alla BBS
+//	{"type":"trigger","id":"mail","pattern":"(?i)new mail"} registra (o sostituisce) un trigger
+//	{"type":"untrigger","id":"mail"}
+//	{"type":"status","text":"..."}                          messaggio nella barra di stato
+//	{"type":"log","text":"..."}                             riga nel log del client
+//
+// I trigger sono espressioni regolari confrontate con le righe di testo
+// semplice (senza sequenze ANSI), compresa la riga non ancora terminata,
+// così da riconoscere i prompt; ognuno scatta al più una volta per riga.
+// Alla disconnessione il plugin riceve l'evento "disconnected" e la
+// chiusura di stdin: ha qualche secondo per terminare da solo.
+// Quello che scrive su stderr finisce nel log del client.
+package plugin
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rj45lab/bbs-client-go/pkg/ansi"
+)
+
+// Version è la versione del protocollo, inviata nel messaggio hello.
+const Version = 1
+
+const (
+	// MaxTriggers limita i trigger registrati da un plugin
+	MaxTriggers = 64
+	// maxPattern limita la lunghezza di un'espressione regolare
+	maxPattern = 512
+	// maxLine limita un messaggio del plugin
+	maxLine = 1 << 20
+	// queueSize è il numero di messaggi in attesa di essere scritti: oltre,
+	// un plugin che non legge perde i messaggi invece di bloccare la sessione
+	queueSize = 256
+	// stopTimeout è il tempo concesso al plugin per uscire da solo
+	stopTimeout = 3 * time.Second
+)
+
+// Eventi inviati ai plugin
+const (
+	EventDisconnected = "disconnected"
+	EventBell         = "bell"
+)
+
+// Spec descrive un plugin da avviare.
+type Spec struct {
+	Name    string
+	Command string
+	Args    []string
+}
+
+// Hello descrive la sessione a cui il plugin è collegato.
+type Hello struct {
+	Session string `json:"session"`
+	BBS     string `json:"bbs"`
+	Host    string `json:"host"`
+	Port    int    `json:"port"`
+}
+
+// Host è la sessione vista dal plugin.
+type Host interface {
+	// Send invia testo alla BBS come se fosse digitato.
+	Send(text string) error
+	// Status mostra un messaggio nella barra di stato.
+	Status(text string)
+	// Triggered è chiamato quando scatta il trigger id sulla riga line,
+	// mentre il messaggio parte verso il plugin. Non deve bloccare.
+	Triggered(id, line string)
+}
+
+// message è un messaggio del protocollo, in entrambe le direzioni.
+type message struct {
+	Type    string `json:"type"`
+	Version int    `json:"version,omitempty"`
+	*Hello
+	Text    string `json:"text,omitempty"`
+	Event   string `json:"event,omitempty"`
+	ID      string `json:"id,omitempty"`
+	Pattern string `json:"pattern,omitempty"`
+	Line    string `json:"line,omitempty"`
+}
+
+// trigger è un'espressione regolare registrata dal plugin.
+type trigger struct {
+	id  string
+	re  *regexp.Regexp
+	hit bool // già scattato nella riga corrente
+}
+
+// Plugin è un plugin in esecuzione. Output ed Event possono essere chiamati
+// da una sola goroutine alla volta (il loop eventi della sessione); Close
+// da qualsiasi goroutine.
+type Plugin struct {
+	name string
+	host Host
+	cmd  *exec.Cmd
+
+	queue  chan []byte
+	exited chan struct{} // chiuso all'uscita del processo
+
+	mu       sync.Mutex
+	closed   bool
+	triggers []*trigger
+	dropped  int // messaggi persi perché il plugin non legge
+
+	strip ansi.Stripper
+}
+
+// Start avvia il plugin e gli invia il messaggio hello.
+func Start(spec Spec, hello Hello, host Host) (*Plugin, error) {
+	if spec.Command == "" {
+		return nil, errors.New("comando del plugin mancante")
+	}
+	cmd := exec.Command(spec.Command, spec.Args...)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	p := &Plugin{
+		name:   spec.Name,
+		host:   host,
+		cmd:    cmd,
+		queue:  make(chan []byte, queueSize),
+		exited: make(chan struct{}),
+	}
+	var readers sync.WaitGroup
+	readers.Add(2)
+	go func() {
+		defer readers.Done()
+		p.readLoop(stdout)
+	}()
+	go func() {
+		defer readers.Done()
+		p.logStderr(stderr)
+	}()
+	go p.writeLoop(stdin)
+	go func() {
+		// Wait va chiamato dopo aver letto tutto stdout e stderr
+		readers.Wait()
+		err := cmd.Wait()
+		p.mu.Lock()
+		p.closeQueueLocked()
+		p.mu.Unlock()
+		close(p.exited)
+		log.Printf("[PLUGIN] %s terminato: %v", p.name, exitStatus(err))
+	}()
+
+	log.Printf("[PLUGIN] %s avviato (pid %d)", p.name, cmd.Process.Pid)
+	p.post(message{Type: "hello", Version: Version, Hello: &hello})
+	return p, nil
+}
+
+// Name ritorna il nome del plugin.
+func (p *Plugin) Name() string {
+	return p.name
+}
+
+// Output inoltra al plugin il testo ricevuto dalla BBS e controlla i
+// trigger registrati.
+func (p *Plugin) Output(text string) {
+	p.post(message{Type: "output", Text: text})
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.triggers) == 0 {
+		p.strip.Write(text) // mantiene allineato lo stato delle righe
+		return
+	}
+	lines := strings.Split(p.strip.Write(text), "\n")
+	for _, line := range lines[:len(lines)-1] {
+		p.scanLocked(line)
+		for _, t := range p.triggers {
+			t.hit = false // riga chiusa
+		}
+	}
+	p.scanLocked(p.strip.Pending())
+}
+
+// Event segnala al plugin un evento della sessione.
+func (p *Plugin) Event(name string) {
+	p.post(message{Type: "event", Event: name})
+}
+
+// Close segnala la disconnessione, chiude stdin e attende l'uscita del
+// plugin, terminandolo se non esce entro stopTimeout.
+func (p *Plugin) Close() {
+	p.post(message{Type: "event", Event: EventDisconnected})
+	p.mu.Lock()
+	p.closeQueueLocked()
+	p.mu.Unlock()
+
+	select {
+	case <-p.exited:
+	case <-time.After(stopTimeout):
+		log.Printf("[PLUGIN] %s non è uscito in tempo: terminato", p.name)
+		p.cmd.Process.Kill()
+		<-p.exited
+	}
+}
+
+// closeQueueLocked chiude la coda dei messaggi: writeLoop chiude stdin
+// dopo gli ultimi. Chiamare con p.mu acquisito.
+func (p *Plugin) closeQueueLocked() {
+	if !p.closed {
+		p.closed = true
+		close(p.queue)
+	}
+}
+
+// scanLocked invia al plugin i trigger che riconoscono line. Chiamare con
+// p.mu acquisito.
+func (p *Plugin) scanLocked(line string) {
+	if line == "" {
+		return
+	}
+	for _, t := range p.triggers {
+		if !t.hit && t.re.MatchString(line) {
+			t.hit = true
+			p.postLocked(message{Type: "trigger", ID: t.id, Line: strings.TrimSpace(line)})
+			p.host.Triggered(t.id, strings.TrimSpace(line))
+		}
+	}
+}
+
+// post accoda un messaggio per il plugin senza mai bloccare.
+func (p *Plugin) post(msg message) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.postLocked(msg)
+}
+
+func (p *Plugin) postLocked(msg message) {
+	if p.closed {
+		return
+	}
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return
+	}
+	select {
+	case p.queue <- append(data, '\n'):
+	default:
+		if p.dropped++; p.dropped == 1 {
+			log.Printf("[PLUGIN] %s non legge i messaggi: alcuni vengono scartati", p.name)
+		}
+	}
+}
+
+// writeLoop scrive i messaggi accodati su stdin del plugin.
+func (p *Plugin) writeLoop(stdin io.WriteCloser) {
+	defer stdin.Close()
+	for data := range p.queue {
+		if _, err := stdin.Write(data); err != nil {
+			// Plugin uscito o stdin chiuso: svuota la coda fino a Close
+			for range p.queue {
+			}
+			return
+		}
+	}
+}
+
+// readLoop esegue i messaggi scritti dal plugin su stdout.
+func (p *Plugin) readLoop(stdout io.Reader) {
+	sc := bufio.NewScanner(stdout)
+	sc.Buffer(make([]byte, 0, 4096), maxLine)
+	for sc.Scan() {
+		line := sc.Bytes()
+		if len(strings.TrimSpace(string(line))) == 0 {
+			continue
+		}
+		var msg message
+		if err := json.Unmarshal(line, &msg); err != nil {
+			p.reject("JSON non valido: " + err.Error())
+			continue
+		}
+		if err := p.handle(msg); err != nil {
+			p.reject(err.Error())
+		}
+	}
+	if err := sc.Err(); err != nil {
+		log.Printf("[PLUGIN] %s: lettura interrotta: %v", p.name, err)
+	}
+}
+
+// handle esegue un messaggio del plugin.
+func (p *Plugin) handle(msg message) error {
+	switch msg.Type {
+	case "send":
+		return p.host.Send(msg.Text)
+	case "trigger":
+		return p.addTrigger(msg.ID, msg.Pattern)
+	case "untrigger":
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		for i, t := range p.triggers {
+			if t.id == msg.ID {
+				p.triggers = append(p.triggers[:i], p.triggers[i+1:]...)
+				break
+			}
+		}
+		return nil
+	case "status":
+		p.host.Status(msg.Text)
+		return nil
+	case "log":
+		log.Printf("[PLUGIN] %s: %s", p.name, msg.Text)
+		return nil
+	}
+	return fmt.Errorf("tipo di messaggio sconosciuto: %q", msg.Type)
+}
+
+// addTrigger registra un trigger, sostituendo quello con lo stesso id.
+func (p *Plugin) addTrigger(id, pattern string) error {
+	if id == "" || pattern == "" {
+		return errors.New("trigger senza id o pattern")
+	}
+	if len(pattern) > maxPattern {
+		return fmt.Errorf("pattern del trigger %q troppo lungo", id)
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("trigger %q: %v", id, err)
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, t := range p.triggers {
+		if t.id == id {
+			t.re, t.hit = re, false
+			return nil
+		}
+	}
+	if len(p.triggers) >= MaxTriggers {
+		return fmt.Errorf("troppi trigger (massimo %d)", MaxTriggers)
+	}
+	p.triggers = append(p.triggers, &trigger{id: id, re: re})
+	return nil
+}
+
+// reject segnala al plugin (e nel log) un messaggio non valido.
+func (p *Plugin) reject(reason string) {
+	log.Printf("[PLUGIN] %s: %s", p.name, reason)
+	p.post(message{Type: "error", Text: reason})
+}
+
+// logStderr copia nel log quello che il plugin scrive su stderr.
+func (p *Plugin) logStderr(stderr io.Reader) {
+	sc := bufio.NewScanner(stderr)
+	sc.Buffer(make([]byte, 0, 4096), maxLine)
+	for sc.Scan() {
+		log.Printf("[PLUGIN] %s (stderr): %s", p.name, sc.Text())
+	}
+}
+
+// exitStatus descrive l'esito del processo per il log.
+func exitStatus(err error) string {
+	if err == nil {
+		return "uscita regolare"
+	}
+	return err.Error()
+}
@@ -0,0 +1,39 @@
+// Package printer invia file di testo alla stampante predefinita con gli
+// strumenti del sistema operativo (lp/lpr su macOS, Linux e BSD, Notepad
+// su Windows), senza dipendenze cgo.
+package printer
+
+import (
+	"errors"
+	"os/exec"
+	"runtime"
+)
+
+// ErrUnsupported indica che sulla piattaforma non è disponibile un
+// comando di stampa.
+var ErrUnsupported = errors.New("stampa di sistema non disponibile")
+
+// PrintFile stampa il file di testo path sulla stampante predefinita e
+// attende che il lavoro sia stato accodato. Il percorso è passato come
+// argomento, mai interpretato da una shell.
+func PrintFile(path string) error {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("notepad.exe", "/p", path)
+	} else {
+		tool, err := exec.LookPath("lp")
+		if err != nil {
+			if tool, err = exec.LookPath("lpr"); err != nil {
+				return ErrUnsupported
+			}
+		}
+		cmd = exec.Command(tool, "--", path)
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if len(out) > 0 {
+			return errors.New(string(out))
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+// Package proxy apre le connessioni verso le BBS attraverso un proxy:
+// SOCKS5 (anche il servizio Tor) o HTTP con CONNECT. Con entrambi il nome
+// della BBS viene risolto dal proxy, non dal sistema locale.
+package proxy
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	xproxy "golang.org/x/net/proxy"
+)
+
+// Tipi di proxy.
+const (
+	SOCKS5 = "socks5"
+	HTTP   = "http"
+)
+
+// Kinds elenca i tipi di proxy disponibili.
+var Kinds = []string{SOCKS5, HTTP}
+
+// TorAddress è l'indirizzo SOCKS del servizio Tor (il Tor Browser usa
+// 127.0.0.1:9150).
+const TorAddress = "127.0.0.1:9050"
+
+// Dialer apre connessioni TCP; net.Dialer ne è uno senza proxy.
+type Dialer interface {
+	DialContext(ctx context.Context, network, addr string) (net.Conn, error)
+}
+
+// New ritorna un Dialer che passa dal proxy kind all'indirizzo addr
+// (host:porta). user e password possono essere vuoti.
+func New(kind, addr, user, password string) (Dialer, error) {
+	switch kind {
+	case SOCKS5:
+		var auth *xproxy.Auth
+		if user != "" {
+			auth = &xproxy.Auth{User: user, Password: password}
+		}
+		d, err := xproxy.SOCKS5("tcp", addr, auth, &net.Dialer{})
+		if err != nil {
+			return nil, err
+		}
+		cd, ok := d.(Dialer)
+		if !ok {
+			return nil, fmt.Errorf("proxy SOCKS5 senza DialContext")
+		}
+		return cd, nil
+	case HTTP:
+		return &httpDialer{addr: addr, user: user, password: password}, nil
+	}
+	return nil, fmt.Errorf("tipo di proxy sconosciuto: %s", kind)
+}
+
+// Tor ritorna un Dialer che passa dal servizio Tor in addr ("" =
+// TorAddress).
+func Tor(addr string) Dialer {
+	d, _ := New(SOCKS5, cmp.Or(addr, TorAddress), "", "")
+	return d
+}
+
+// Parse interpreta un proxy scritto come URL (socks5://utente:password@
+// host:porta, http://host:porta) o "tor".
+func Parse(spec string) (Dialer, error) {
+	if spec == "tor" {
+		return Tor(""), nil
+	}
+	u, err := url.Parse(spec)
+	if err != nil || u.Host == "" || u.Port() == "" {
+		return nil, fmt.Errorf("proxy non valido: %s", spec)
+	}
+	password, _ := u.User.Password()
+	return New(u.Scheme, u.Host, u.User.Username(), password)
+}
+
+// ─────────────────────────────────────────────
+// HTTP CONNECT
+// ─────────────────────────────────────────────
+
+type httpDialer struct {
+	addr, user, password string
+}
+
+func (d *httpDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	var nd net.Dialer
+	conn, err := nd.DialContext(ctx, network, d.addr)
+	if err != nil {
+		return nil, err
+	}
+	// La risposta del proxy ha la stessa scadenza della connessione
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: addr},
+		Host:   addr,
+		Header: make(http.Header),
+	}
+	if d.user != "" {
+		auth := base64.StdEncoding.EncodeToString([]byte(d.user + ":" + d.password))
+		req.Header.Set("Proxy-Authorization", "Basic "+auth)
+	}
+	if err := req.Write(conn); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, req)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("proxy HTTP: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		conn.Close()
+		return nil, fmt.Errorf("proxy HTTP: %s", resp.Status)
+	}
+	conn.SetDeadline(time.Time{})
+	if br.Buffered() > 0 {
+		// Il banner della BBS può arrivare insieme alla risposta
+		return &bufferedConn{Conn: conn, r: br}, nil
+	}
+	return conn, nil
+}
+
+// bufferedConn legge prima i byte già letti dal proxy insieme alla
+// risposta.
+type bufferedConn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+func (c *bufferedConn) Read(p []byte) (int, error) {
+	if c.r.Buffered() > 0 {
+		return c.r.Read(p)
+	}
+	return c.Conn.Read(p)
+}
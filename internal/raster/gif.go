@@ -0,0 +1,174 @@
+package raster
+
+import (
+	"image"
+	"image/color"
+	"image/gif"
+	"io"
+	"time"
+
+	"github.com/rj45lab/bbs-client-go/internal/bitmapfont"
+	"github.com/rj45lab/bbs-client-go/pkg/ansi"
+)
+
+// ─────────────────────────────────────────────
+// Export GIF animata
+// ─────────────────────────────────────────────
+
+const (
+	// DefaultFrameDelay è l'intervallo tra due fotogrammi della GIF
+	DefaultFrameDelay = 100 * time.Millisecond
+	// DefaultMaxFrames limita la dimensione del file: oltre questo numero
+	// i fotogrammi vengono diradati alimentando più testo per ciascuno
+	DefaultMaxFrames = 1000
+	// finalFrameHold è quanto resta a video l'ultimo fotogramma
+	finalFrameHold = 3 * time.Second
+)
+
+// GIFOptions configura l'esportazione di una sessione in GIF animata.
+type GIFOptions struct {
+	Baud       int              // velocità modem simulata (0 = solo fotogramma finale)
+	FrameDelay time.Duration    // intervallo tra fotogrammi (0 = DefaultFrameDelay)
+	MaxFrames  int              // numero massimo di fotogrammi (0 = DefaultMaxFrames)
+	Font       *bitmapfont.Font // font dei caratteri (nil = font interno)
+}
+
+// WriteGIF riproduce text sullo schermo s alla velocità indicata e scrive
+// una GIF animata con un fotogramma ogni FrameDelay. Lo schermo deve essere
+// già configurato (dimensioni, palette, iCE) e viene modificato dal replay.
+func WriteGIF(w io.Writer, s *ansi.Screen, text string, opt GIFOptions) error {
+	if opt.FrameDelay <= 0 {
+		opt.FrameDelay = DefaultFrameDelay
+	}
+	if opt.MaxFrames <= 0 {
+		opt.MaxFrames = DefaultMaxFrames
+	}
+
+	data := []rune(text)
+	step := len(data)
+	if opt.Baud > 0 {
+		step = int(float64(opt.Baud) / 10 * opt.FrameDelay.Seconds())
+	}
+	step = max(step, (len(data)+opt.MaxFrames-1)/opt.MaxFrames, 1)
+
+	enc := newFrameEncoder(s, opt.Font)
+	for pos := 0; pos < len(data); {
+		end := min(pos+step, len(data))
+		s.Feed(string(data[pos:end]))
+		pos = end
+		enc.add(s, opt.FrameDelay)
+	}
+	if len(data) == 0 {
+		enc.add(s, 0)
+	}
+	enc.hold(finalFrameHold)
+	return gif.EncodeAll(w, enc.out)
+}
+
+// frameEncoder accumula i fotogrammi salvando solo il rettangolo cambiato
+// rispetto al precedente, per contenere memoria e dimensione del file.
+type frameEncoder struct {
+	font  *bitmapfont.Font
+	pal   color.Palette
+	index map[color.RGBA]uint8 // cache colore → indice palette
+	prev  *image.Paletted
+	out   *gif.GIF
+}
+
+// newFrameEncoder prepara una palette globale con i 256 colori indicizzati
+// dello schermo: i colori ANSI restano esatti, l'RGB usa il più vicino.
+func newFrameEncoder(s *ansi.Screen, font *bitmapfont.Font) *frameEncoder {
+	src := s.ActivePalette()
+	pal := make(color.Palette, 256)
+	index := make(map[color.RGBA]uint8, 256)
+	for i := range pal {
+		r, g, b := ansi.IndexColor(i).ToRGBPalette(src, false, false)
+		c := color.RGBA{r, g, b, 0xff}
+		pal[i] = c
+		if _, ok := index[c]; !ok {
+			index[c] = uint8(i)
+		}
+	}
+	return &frameEncoder{font: font, pal: pal, index: index, out: &gif.GIF{}}
+}
+
+// add rasterizza lo stato attuale dello schermo come nuovo fotogramma. Se
+// nulla è cambiato allunga la durata del fotogramma precedente.
+func (e *frameEncoder) add(s *ansi.Screen, delay time.Duration) {
+	cw, ch := cellSize(s, e.font)
+	cur := image.NewPaletted(image.Rect(0, 0, s.Cols*cw, s.Rows*ch), e.pal)
+	paint(s, e.font, func(r image.Rectangle, c color.RGBA) {
+		idx := e.colorIndex(c)
+		r = r.Intersect(cur.Rect)
+		for y := r.Min.Y; y < r.Max.Y; y++ {
+			row := cur.Pix[y*cur.Stride:]
+			for x := r.Min.X; x < r.Max.X; x++ {
+				row[x] = idx
+			}
+		}
+	})
+
+	changed := cur.Rect
+	if e.prev != nil {
+		changed = diffRect(e.prev, cur)
+		if changed.Empty() {
+			e.hold(delay)
+			return
+		}
+	}
+	e.out.Image = append(e.out.Image, crop(cur, changed))
+	e.out.Delay = append(e.out.Delay, centiseconds(delay))
+	e.out.Disposal = append(e.out.Disposal, gif.DisposalNone)
+	e.prev = cur
+}
+
+// hold allunga la durata dell'ultimo fotogramma.
+func (e *frameEncoder) hold(d time.Duration) {
+	if n := len(e.out.Delay); n > 0 {
+		e.out.Delay[n-1] += centiseconds(d)
+	}
+}
+
+// colorIndex ritorna l'indice di palette per c, cercando il più vicino
+// per i colori RGB che non fanno parte della palette ANSI.
+func (e *frameEncoder) colorIndex(c color.RGBA) uint8 {
+	if idx, ok := e.index[c]; ok {
+		return idx
+	}
+	idx := uint8(e.pal.Index(c))
+	e.index[c] = idx
+	return idx
+}
+
+// diffRect ritorna il rettangolo minimo che contiene i pixel diversi.
+func diffRect(a, b *image.Paletted) image.Rectangle {
+	var r image.Rectangle
+	w := b.Rect.Dx()
+	for y := 0; y < b.Rect.Dy(); y++ {
+		ra := a.Pix[y*a.Stride : y*a.Stride+w]
+		rb := b.Pix[y*b.Stride : y*b.Stride+w]
+		for x := 0; x < w; x++ {
+			if ra[x] != rb[x] {
+				r = r.Union(image.Rect(x, y, x+1, y+1))
+			}
+		}
+	}
+	return r
+}
+
+// crop copia la porzione r di img in un'immagine indipendente.
+func crop(img *image.Paletted, r image.Rectangle) *image.Paletted {
+	if r == img.Rect {
+		return img
+	}
+	out := image.NewPaletted(r, img.Palette)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		copy(out.Pix[out.PixOffset(r.Min.X, y):], img.Pix[img.PixOffset(r.Min.X, y):img.PixOffset(r.Max.X, y)])
+	}
+	return out
+}
+
+// centiseconds converte una durata nell'unità dei delay GIF.
+func centiseconds(d time.Duration) int {
+	return int(d / (10 * time.Millisecond))
+}
@@ -0,0 +1,270 @@
+// Package raster converte lo stato di un ansi.Screen in immagini bitmap,
+// per esportare schermate (PNG) e sessioni intere (GIF animate).
+//
+// I caratteri di blocco e i box drawing CP437 sono disegnati a mano per
+// restare pixel-perfect; il resto del testo usa un font bitmap ASCII.
+// Con un font bitmap CP437 (vedi internal/bitmapfont) tutti i caratteri
+// usano i glifi del font e la cella ne prende le dimensioni.
+package raster
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+
+	"github.com/rj45lab/bbs-client-go/internal/bitmapfont"
+	"github.com/rj45lab/bbs-client-go/pkg/ansi"
+)
+
+// ─────────────────────────────────────────────
+// Rendering
+// ─────────────────────────────────────────────
+
+// fillFunc colora il rettangolo r con c sull'immagine di destinazione.
+type fillFunc func(r image.Rectangle, c color.RGBA)
+
+// Render disegna lo schermo in un'immagine RGBA, con celle grandi
+// CellWidth×CellHeight pixel o quanto i glifi di font (nil = font
+// interno). Il chiamante deve garantire che lo schermo non venga
+// modificato durante il rendering.
+func Render(s *ansi.Screen, font *bitmapfont.Font) *image.RGBA {
+	cw, ch := cellSize(s, font)
+	img := image.NewRGBA(image.Rect(0, 0, s.Cols*cw, s.Rows*ch))
+	paint(s, font, func(r image.Rectangle, c color.RGBA) {
+		r = r.Intersect(img.Rect)
+		for y := r.Min.Y; y < r.Max.Y; y++ {
+			for x := r.Min.X; x < r.Max.X; x++ {
+				img.SetRGBA(x, y, c)
+			}
+		}
+	})
+	return img
+}
+
+// WritePNG codifica lo schermo come PNG, con il font indicato (nil =
+// font interno).
+func WritePNG(w io.Writer, s *ansi.Screen, font *bitmapfont.Font) error {
+	return png.Encode(w, Render(s, font))
+}
+
+// cellSize ritorna le dimensioni della cella: quelle del font se c'è,
+// altrimenti quelle dello schermo, con i default VGA se assenti.
+func cellSize(s *ansi.Screen, font *bitmapfont.Font) (int, int) {
+	if font != nil {
+		return font.Width, font.Height
+	}
+	cw, ch := s.CellWidth, s.CellHeight
+	if cw <= 0 || ch <= 0 {
+		return ansi.DefaultCellWidth, ansi.DefaultCellHeight
+	}
+	return cw, ch
+}
+
+// paint disegna tutte le celle dello schermo tramite fill.
+func paint(s *ansi.Screen, font *bitmapfont.Font, fill fillFunc) {
+	cw, ch := cellSize(s, font)
+	for y := 0; y < s.Rows; y++ {
+		for x := 0; x < s.Cols; x++ {
+			cellRect := image.Rect(x*cw, y*ch, (x+1)*cw, (y+1)*ch)
+			paintCell(s, font, s.Buffer[y][x], cellRect, fill)
+		}
+	}
+}
+
+// paintCell disegna sfondo, glifo e decorazioni di una cella, risolvendo
+// i colori come l'export verso il frontend (palette, bold, iCE, reverse).
+func paintCell(s *ansi.Screen, font *bitmapfont.Font, cell ansi.Cell, r image.Rectangle, fill fillFunc) {
+	attr := cell.Attr
+	fgRGB, bgRGB, ulRGB := s.CellColors(cell)
+	fg, bg, ul := rgba(fgRGB), rgba(bgRGB), rgba(ulRGB)
+	if attr.Faint {
+		fg = blend(fg, bg)
+		if !attr.HasUnderlineColor {
+			ul = fg
+		}
+	}
+
+	fill(r, bg)
+	if attr.Conceal {
+		return
+	}
+	if cell.Char > ' ' {
+		drawGlyph(font, cell.Char, r, fg, fill)
+		if attr.Bold && s.BoldPolicy.UsesFont() {
+			drawGlyph(font, cell.Char, r.Add(image.Pt(1, 0)).Intersect(r), fg, fill)
+		}
+	}
+
+	h := r.Dy()
+	if attr.Underline {
+		y := r.Min.Y + h - 2
+		fill(image.Rect(r.Min.X, y, r.Max.X, y+1), ul)
+		if attr.UnderlineStyle == ansi.UnderlineDouble {
+			fill(image.Rect(r.Min.X, y-2, r.Max.X, y-1), ul)
+		}
+	}
+	if attr.Strike {
+		y := r.Min.Y + h/2
+		fill(image.Rect(r.Min.X, y, r.Max.X, y+1), fg)
+	}
+}
+
+func rgba(c [3]uint8) color.RGBA {
+	return color.RGBA{c[0], c[1], c[2], 0xff}
+}
+
+// blend ritorna il colore a metà strada tra a e b (resa del faint).
+func blend(a, b color.RGBA) color.RGBA {
+	return rgba([3]uint8{uint8((int(a.R) + int(b.R)) / 2), uint8((int(a.G) + int(b.G)) / 2), uint8((int(a.B) + int(b.B)) / 2)})
+}
+
+// ─────────────────────────────────────────────
+// Glifi
+// ─────────────────────────────────────────────
+
+// drawGlyph disegna il carattere ch nella cella r con colore c.
+func drawGlyph(font *bitmapfont.Font, ch rune, r image.Rectangle, c color.RGBA, fill fillFunc) {
+	if font != nil && drawFontGlyph(font, ch, r, c, fill) {
+		return
+	}
+	if drawBlock(ch, r, c, fill) || drawBox(ch, r, c, fill) {
+		return
+	}
+
+	face := basicfont.Face7x13
+	dot := fixed.P(r.Min.X+(r.Dx()-face.Advance)/2, r.Min.Y+(r.Dy()-face.Height)/2+face.Ascent)
+	dr, mask, mp, _, ok := face.Glyph(dot, ch)
+	if !ok {
+		dr, mask, mp, _, _ = face.Glyph(dot, '�')
+	}
+	for y := dr.Min.Y; y < dr.Max.Y; y++ {
+		for x := dr.Min.X; x < dr.Max.X; x++ {
+			_, _, _, a := mask.At(mp.X+x-dr.Min.X, mp.Y+y-dr.Min.Y).RGBA()
+			if a >= 0x8000 {
+				fill(image.Rect(x, y, x+1, y+1).Intersect(r), c)
+			}
+		}
+	}
+}
+
+// drawFontGlyph disegna ch con il glifo del font bitmap, se il font lo ha.
+func drawFontGlyph(font *bitmapfont.Font, ch rune, r image.Rectangle, c color.RGBA, fill fillFunc) bool {
+	g, ok := font.Index(ch)
+	if !ok {
+		return false
+	}
+	for y := 0; y < font.Height; y++ {
+		for x := 0; x < font.Width; x++ {
+			if font.Pixel(g, x, y) {
+				fill(image.Rect(r.Min.X+x, r.Min.Y+y, r.Min.X+x+1, r.Min.Y+y+1).Intersect(r), c)
+			}
+		}
+	}
+	return true
+}
+
+// drawBlock gestisce i block element (U+2580–U+259F) usati nell'ANSI art.
+func drawBlock(ch rune, r image.Rectangle, c color.RGBA, fill fillFunc) bool {
+	midX := r.Min.X + r.Dx()/2
+	midY := r.Min.Y + r.Dy()/2
+	switch ch {
+	case '█':
+		fill(r, c)
+	case '▀':
+		fill(image.Rect(r.Min.X, r.Min.Y, r.Max.X, midY), c)
+	case '▄':
+		fill(image.Rect(r.Min.X, midY, r.Max.X, r.Max.Y), c)
+	case '▌':
+		fill(image.Rect(r.Min.X, r.Min.Y, midX, r.Max.Y), c)
+	case '▐':
+		fill(image.Rect(midX, r.Min.Y, r.Max.X, r.Max.Y), c)
+	case '■':
+		qx, qy := r.Dx()/4, r.Dy()/4
+		fill(image.Rect(r.Min.X+qx, r.Min.Y+qy+1, r.Max.X-qx, r.Max.Y-qy-1), c)
+	case '░', '▒', '▓':
+		for y := r.Min.Y; y < r.Max.Y; y++ {
+			for x := r.Min.X; x < r.Max.X; x++ {
+				if shade(ch, x-r.Min.X, y-r.Min.Y) {
+					fill(image.Rect(x, y, x+1, y+1), c)
+				}
+			}
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+// shade ritorna se il pixel (x, y) è acceso nel retino di ░ ▒ ▓.
+func shade(ch rune, x, y int) bool {
+	switch ch {
+	case '░': // 25%
+		return y%2 == 0 && (x+y/2)%2 == 0
+	case '▒': // 50%
+		return (x+y)%2 == 0
+	default: // ▓ 75%
+		return !(y%2 == 0 && (x+y/2)%2 == 0)
+	}
+}
+
+// boxArms descrive i box drawing CP437 come bracci {su, giù, sinistra,
+// destra}: 0 = assente, 1 = linea singola, 2 = linea doppia.
+var boxArms = map[rune][4]uint8{
+	'─': {0, 0, 1, 1}, '│': {1, 1, 0, 0},
+	'┌': {0, 1, 0, 1}, '┐': {0, 1, 1, 0}, '└': {1, 0, 0, 1}, '┘': {1, 0, 1, 0},
+	'├': {1, 1, 0, 1}, '┤': {1, 1, 1, 0}, '┬': {0, 1, 1, 1}, '┴': {1, 0, 1, 1},
+	'┼': {1, 1, 1, 1},
+	'═': {0, 0, 2, 2}, '║': {2, 2, 0, 0},
+	'╒': {0, 1, 0, 2}, '╓': {0, 2, 0, 1}, '╔': {0, 2, 0, 2},
+	'╕': {0, 1, 2, 0}, '╖': {0, 2, 1, 0}, '╗': {0, 2, 2, 0},
+	'╘': {1, 0, 0, 2}, '╙': {2, 0, 0, 1}, '╚': {2, 0, 0, 2},
+	'╛': {1, 0, 2, 0}, '╜': {2, 0, 1, 0}, '╝': {2, 0, 2, 0},
+	'╞': {1, 1, 0, 2}, '╟': {2, 2, 0, 1}, '╠': {2, 2, 0, 2},
+	'╡': {1, 1, 2, 0}, '╢': {2, 2, 1, 0}, '╣': {2, 2, 2, 0},
+	'╤': {0, 1, 2, 2}, '╥': {0, 2, 1, 1}, '╦': {0, 2, 2, 2},
+	'╧': {1, 0, 2, 2}, '╨': {2, 0, 1, 1}, '╩': {2, 0, 2, 2},
+	'╪': {1, 1, 2, 2}, '╫': {2, 2, 1, 1}, '╬': {2, 2, 2, 2},
+}
+
+// drawBox disegna un carattere box drawing tracciando i suoi bracci dal
+// centro della cella verso i bordi.
+func drawBox(ch rune, r image.Rectangle, c color.RGBA, fill fillFunc) bool {
+	arms, ok := boxArms[ch]
+	if !ok {
+		return false
+	}
+	cx := r.Min.X + r.Dx()/2
+	cy := r.Min.Y + r.Dy()/2
+
+	// Linee verticali (su/giù) e orizzontali (sinistra/destra)
+	vert := func(y0, y1 int, kind uint8) {
+		for _, off := range lineOffsets(kind) {
+			fill(image.Rect(cx+off, y0, cx+off+1, y1), c)
+		}
+	}
+	horiz := func(x0, x1 int, kind uint8) {
+		for _, off := range lineOffsets(kind) {
+			fill(image.Rect(x0, cy+off, x1, cy+off+1), c)
+		}
+	}
+	vert(r.Min.Y, cy+1, arms[0])
+	vert(cy, r.Max.Y, arms[1])
+	horiz(r.Min.X, cx+1, arms[2])
+	horiz(cx, r.Max.X, arms[3])
+	return true
+}
+
+// lineOffsets ritorna gli scostamenti dal centro delle linee di un braccio.
+func lineOffsets(kind uint8) []int {
+	switch kind {
+	case 1:
+		return []int{0}
+	case 2:
+		return []int{-1, 1}
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+// Package replay è un harness per validare il motore ANSI: riproduce
+// flussi registrati su uno schermo nuovo e ne confronta l'hash con una
+// schermata di riferimento ("golden").
+//
+// Un corpus è una directory di file <nome>.in (testo UTF-8 con sequenze
+// ANSI, come i log di sessione), ciascuno con un <nome>.golden accanto:
+//
+//	80x25 <hash sha-256 esadecimale>
+//
+// Se il golden manca il caso usa la geometria di default e non ha un
+// valore atteso: UpdateGoldens lo crea dallo stato attuale del motore.
+package replay
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/rj45lab/bbs-client-go/pkg/ansi"
+)
+
+const (
+	inputExt  = ".in"
+	goldenExt = ".golden"
+
+	// Geometria di default (schermo BBS classico)
+	DefaultCols = 80
+	DefaultRows = 25
+)
+
+// Case è un singolo flusso del corpus con il suo risultato atteso.
+type Case struct {
+	Name       string
+	Path       string // file di input
+	Cols, Rows int
+	Want       string // hash atteso ("" = nessun golden)
+}
+
+// Result è l'esito del replay di un Case.
+type Result struct {
+	Case
+	Got string
+	Err error
+}
+
+// OK ritorna true se il replay è riuscito e corrisponde al golden.
+func (r Result) OK() bool {
+	return r.Err == nil && r.Want != "" && r.Got == r.Want
+}
+
+// Replay riproduce r su uno schermo nuovo cols×rows e lo ritorna. È la
+// primitiva da usare anche per il fuzzing del parser.
+func Replay(r io.Reader, cols, rows int) (*ansi.Screen, error) {
+	s := ansi.NewScreen(cols, rows)
+	err := s.FeedReader(r)
+	return s, err
+}
+
+// Run esegue un singolo caso.
+func Run(c Case) Result {
+	res := Result{Case: c}
+	data, err := os.ReadFile(c.Path)
+	if err != nil {
+		res.Err = err
+		return res
+	}
+	s, err := Replay(bytes.NewReader(data), c.Cols, c.Rows)
+	res.Got, res.Err = s.Hash(), err
+	return res
+}
+
+// LoadCorpus elenca i casi di una directory in ordine alfabetico.
+func LoadCorpus(dir string) ([]Case, error) {
+	inputs, err := filepath.Glob(filepath.Join(dir, "*"+inputExt))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(inputs)
+
+	cases := make([]Case, 0, len(inputs))
+	for _, path := range inputs {
+		c := Case{
+			Name: strings.TrimSuffix(filepath.Base(path), inputExt),
+			Path: path,
+			Cols: DefaultCols,
+			Rows: DefaultRows,
+		}
+		golden, err := os.ReadFile(goldenPath(path))
+		if err == nil {
+			if _, err := fmt.Sscanf(string(golden), "%dx%d %s", &c.Cols, &c.Rows, &c.Want); err != nil {
+				return nil, fmt.Errorf("%s: golden non valido: %w", c.Name, err)
+			}
+		} else if !os.IsNotExist(err) {
+			return nil, err
+		}
+		cases = append(cases, c)
+	}
+	return cases, nil
+}
+
+// RunCorpus esegue tutti i casi di una directory.
+func RunCorpus(dir string) ([]Result, error) {
+	cases, err := LoadCorpus(dir)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]Result, len(cases))
+	for i, c := range cases {
+		results[i] = Run(c)
+	}
+	return results, nil
+}
+
+// UpdateGoldens riscrive i golden di tutti i casi con l'hash prodotto dal
+// motore attuale. Da usare dopo aver verificato a mano un cambiamento
+// voluto dell'emulazione.
+func UpdateGoldens(dir string) error {
+	results, err := RunCorpus(dir)
+	if err != nil {
+		return err
+	}
+	for _, r := range results {
+		if r.Err != nil {
+			return fmt.Errorf("%s: %w", r.Name, r.Err)
+		}
+		line := fmt.Sprintf("%dx%d %s\n", r.Cols, r.Rows, r.Got)
+		if err := os.WriteFile(goldenPath(r.Path), []byte(line), 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func goldenPath(input string) string {
+	return strings.TrimSuffix(input, inputExt) + goldenExt
+}
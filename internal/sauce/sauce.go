@@ -0,0 +1,144 @@
+// Package sauce implementa il parsing dei record SAUCE (Standard
+// Architecture for Universal Comment Extensions) in coda ai file di
+// ANSI art e ai log di sessione.
+//
+// Riferimento: https://www.acid.org/info/sauce/sauce.htm
+package sauce
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+)
+
+// ─────────────────────────────────────────────
+// Costanti formato SAUCE
+// ─────────────────────────────────────────────
+
+const (
+	RecordSize  = 128 // dimensione fissa del record
+	CommentSize = 64  // dimensione di una riga di commento
+	EOFChar     = 0x1A
+
+	// DataType
+	DataTypeNone      = 0
+	DataTypeCharacter = 1
+	DataTypeBinText   = 5
+	DataTypeXBin      = 6
+
+	// FileType per DataTypeCharacter
+	FileTypeASCII      = 0
+	FileTypeANSi       = 1
+	FileTypeANSiMation = 2
+
+	// TFlags
+	FlagNonBlink = 0x01 // iCE colors: blink → sfondo bright
+)
+
+var (
+	recordID  = []byte("SAUCE00")
+	commentID = []byte("COMNT")
+)
+
+// Record contiene i metadati SAUCE di un file.
+type Record struct {
+	Title    string   `json:"title"`
+	Author   string   `json:"author"`
+	Group    string   `json:"group"`
+	Date     string   `json:"date"` // CCYYMMDD
+	FileSize uint32   `json:"fileSize"`
+	DataType byte     `json:"dataType"`
+	FileType byte     `json:"fileType"`
+	TInfo1   uint16   `json:"tinfo1"`
+	TInfo2   uint16   `json:"tinfo2"`
+	TInfo3   uint16   `json:"tinfo3"`
+	TInfo4   uint16   `json:"tinfo4"`
+	Flags    byte     `json:"flags"`
+	Font     string   `json:"font"` // TInfoS, es. "IBM VGA"
+	Comments []string `json:"comments"`
+
+	// Campi derivati per il rendering
+	Width     int  `json:"width"`     // colonne previste (0 = sconosciuto)
+	Height    int  `json:"height"`    // righe previste (0 = sconosciuto)
+	IceColors bool `json:"iceColors"` // flag non-blink
+}
+
+// ─────────────────────────────────────────────
+// Parsing
+// ─────────────────────────────────────────────
+
+// Parse cerca un record SAUCE in coda a data. Ritorna il record (nil se
+// assente) e il contenuto senza SAUCE, commenti ed eventuale EOF (0x1A).
+func Parse(data []byte) (*Record, []byte) {
+	if len(data) < RecordSize {
+		return nil, data
+	}
+	start := len(data) - RecordSize
+	raw := data[start:]
+	if !bytes.Equal(raw[:7], recordID) {
+		return nil, data
+	}
+
+	r := &Record{
+		Title:    field(raw[7:42]),
+		Author:   field(raw[42:62]),
+		Group:    field(raw[62:82]),
+		Date:     field(raw[82:90]),
+		FileSize: binary.LittleEndian.Uint32(raw[90:94]),
+		DataType: raw[94],
+		FileType: raw[95],
+		TInfo1:   binary.LittleEndian.Uint16(raw[96:98]),
+		TInfo2:   binary.LittleEndian.Uint16(raw[98:100]),
+		TInfo3:   binary.LittleEndian.Uint16(raw[100:102]),
+		TInfo4:   binary.LittleEndian.Uint16(raw[102:104]),
+		Flags:    raw[105],
+		Font:     field(raw[106:128]),
+	}
+
+	// Blocco commenti opzionale: "COMNT" + N righe da 64 byte
+	if n := int(raw[104]); n > 0 {
+		cstart := start - len(commentID) - n*CommentSize
+		if cstart >= 0 && bytes.Equal(data[cstart:cstart+len(commentID)], commentID) {
+			lines := data[cstart+len(commentID) : start]
+			for i := 0; i < n; i++ {
+				r.Comments = append(r.Comments, field(lines[i*CommentSize:(i+1)*CommentSize]))
+			}
+			start = cstart
+		}
+	}
+
+	r.fillHints()
+
+	content := data[:start]
+	if n := len(content); n > 0 && content[n-1] == EOFChar {
+		content = content[:n-1]
+	}
+	return r, content
+}
+
+// fillHints ricava larghezza, altezza e iCE colors dai campi TInfo/TFlags.
+func (r *Record) fillHints() {
+	switch r.DataType {
+	case DataTypeCharacter:
+		if r.FileType <= FileTypeANSiMation {
+			r.Width = int(r.TInfo1)
+			r.Height = int(r.TInfo2)
+			r.IceColors = r.Flags&FlagNonBlink != 0
+		}
+	case DataTypeBinText:
+		// Per BinaryText la larghezza è codificata nel FileType (metà colonne)
+		r.Width = int(r.FileType) * 2
+		r.IceColors = r.Flags&FlagNonBlink != 0
+	case DataTypeXBin:
+		r.Width = int(r.TInfo1)
+		r.Height = int(r.TInfo2)
+	}
+}
+
+// field converte un campo a lunghezza fissa (padding spazi/NUL) in stringa.
+func field(b []byte) string {
+	if i := bytes.IndexByte(b, 0); i >= 0 {
+		b = b[:i]
+	}
+	return strings.TrimRight(string(b), " ")
+}
@@ -0,0 +1,115 @@
+// Package script esegue gli script Lua di automazione (login, lettura
+// della posta, download) su una sessione BBS. La stessa API è usata dal
+// comando bbsclient e dalle chiamate programmate della GUI.
+//
+// Funzioni disponibili negli script:
+//
+//	send(testo)              invia testo (convertito in CP437)
+//	sendln(testo)            invia testo seguito da CR
+//	wait(testo [, secondi])  attende testo nel flusso ricevuto (senza ANSI);
+//	                         true se arriva, false a timeout o disconnessione
+//	sleep(secondi)           pausa
+//	screen()                 testo dello schermo, righe separate da "\n"
+//	connected()              true se la connessione è attiva
+//	upload(percorso)         invia un file via ZMODEM
+//	disconnect()             chiude la connessione
+//	log(messaggio)           scrive nel log
+//
+// La variabile download_dir contiene la directory dei download.
+package script
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// DefaultWait è il timeout di wait() senza secondo argomento.
+const DefaultWait = 30 * time.Second
+
+// Host è la sessione su cui lavora uno script.
+type Host interface {
+	// Send invia testo UTF-8 alla BBS, convertito in CP437
+	Send(text string) error
+	// Wait attende pattern nel testo ricevuto (vedi Stream.Wait)
+	Wait(ctx context.Context, pattern string, timeout time.Duration) bool
+	// ScreenText ritorna lo schermo come testo semplice
+	ScreenText() string
+	Connected() bool
+	Upload(path string)
+	Disconnect()
+}
+
+// Run esegue lo script Lua in path sulla sessione h. L'esecuzione si
+// interrompe quando ctx viene annullato (in quel caso ritorna nil).
+func Run(ctx context.Context, path string, h Host, downloadDir string) error {
+	L := lua.NewState()
+	defer L.Close()
+	L.SetContext(ctx)
+
+	fns := map[string]lua.LGFunction{
+		"send": func(L *lua.LState) int {
+			if err := h.Send(L.CheckString(1)); err != nil {
+				L.RaiseError("send: %v", err)
+			}
+			return 0
+		},
+		"sendln": func(L *lua.LState) int {
+			if err := h.Send(L.CheckString(1) + "\r"); err != nil {
+				L.RaiseError("sendln: %v", err)
+			}
+			return 0
+		},
+		"wait": func(L *lua.LState) int {
+			timeout := DefaultWait
+			if L.GetTop() >= 2 {
+				timeout = time.Duration(float64(L.CheckNumber(2)) * float64(time.Second))
+			}
+			L.Push(lua.LBool(h.Wait(ctx, L.CheckString(1), timeout)))
+			return 1
+		},
+		"sleep": func(L *lua.LState) int {
+			d := time.Duration(float64(L.CheckNumber(1)) * float64(time.Second))
+			select {
+			case <-time.After(d):
+			case <-ctx.Done():
+			}
+			return 0
+		},
+		"screen": func(L *lua.LState) int {
+			L.Push(lua.LString(h.ScreenText()))
+			return 1
+		},
+		"connected": func(L *lua.LState) int {
+			L.Push(lua.LBool(h.Connected()))
+			return 1
+		},
+		"upload": func(L *lua.LState) int {
+			h.Upload(L.CheckString(1))
+			return 0
+		},
+		"disconnect": func(L *lua.LState) int {
+			h.Disconnect()
+			return 0
+		},
+		"log": func(L *lua.LState) int {
+			log.Print(L.CheckString(1))
+			return 0
+		},
+	}
+	for name, fn := range fns {
+		L.SetGlobal(name, L.NewFunction(fn))
+	}
+	L.SetGlobal("download_dir", lua.LString(downloadDir))
+
+	if err := L.DoFile(path); err != nil {
+		if ctx.Err() != nil {
+			return nil // interrotto
+		}
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package script
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rj45lab/bbs-client-go/pkg/ansi"
+)
+
+// ─────────────────────────────────────────────
+// Stream — testo ricevuto in attesa di wait()
+// ─────────────────────────────────────────────
+
+// maxReceived limita il testo conservato per Wait.
+const maxReceived = 64 * 1024
+
+// Stati della rimozione delle sequenze di escape
+const (
+	escNone = iota
+	escStart
+	escCSI
+)
+
+// Stream conserva il testo ricevuto (senza sequenze ANSI) non ancora
+// consumato da Wait. È sicuro per l'uso concorrente: Write è chiamato dal
+// loop di ricezione, Wait dallo script.
+type Stream struct {
+	mu       sync.Mutex
+	escState int             // sequenza di escape in corso nel flusso
+	received strings.Builder // testo non ancora consumato
+	changed  chan struct{}   // chiuso e ricreato a ogni dato ricevuto
+	closed   bool
+}
+
+// NewStream crea uno Stream vuoto.
+func NewStream() *Stream {
+	return &Stream{changed: make(chan struct{})}
+}
+
+// Write aggiunge il testo ricevuto e sveglia chi è in attesa.
+func (s *Stream) Write(text string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	s.appendPlain(text)
+	if s.received.Len() > maxReceived {
+		keep := s.received.String()[s.received.Len()-maxReceived/2:]
+		s.received.Reset()
+		s.received.WriteString(keep)
+	}
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// appendPlain aggiunge a received il testo senza sequenze di escape né
+// controlli (tranne CR e LF). A differenza di ansi.Stripper i caratteri
+// sono disponibili subito, senza attendere la fine della riga: i prompt
+// non finiscono con un a capo.
+func (s *Stream) appendPlain(text string) {
+	for _, ch := range text {
+		switch s.escState {
+		case escStart:
+			s.escState = escNone
+			if ch == '[' {
+				s.escState = escCSI
+			}
+		case escCSI:
+			if ch >= 0x40 && ch <= 0x7E {
+				s.escState = escNone
+			}
+		default:
+			switch {
+			case ch == 0x1B:
+				s.escState = escStart
+			case ch == '\r' || ch == '\n' || ch >= 0x20 && ch != 0x7F:
+				s.received.WriteRune(ch)
+			}
+		}
+	}
+}
+
+// Close segna la connessione come chiusa e sveglia chi è in attesa.
+func (s *Stream) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.closed {
+		s.closed = true
+		close(s.changed)
+	}
+}
+
+// Changed ritorna un canale chiuso al prossimo dato ricevuto e se lo
+// stream è già chiuso.
+func (s *Stream) Changed() (<-chan struct{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.changed, s.closed
+}
+
+// Discard scarta il testo ricevuto non ancora consumato: il prossimo Wait
+// considera solo quello che arriva dopo.
+func (s *Stream) Discard() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.received.Reset()
+}
+
+// Wait attende che pattern compaia nel testo ricevuto e consuma il testo
+// fino alla fine della corrispondenza, così due Wait uguali di fila
+// aspettano due occorrenze distinte. Ritorna false a timeout, alla
+// chiusura o all'annullamento di ctx.
+func (s *Stream) Wait(ctx context.Context, pattern string, timeout time.Duration) bool {
+	_, ok := s.wait(ctx, timeout, func(text string) []int {
+		if i := strings.Index(text, pattern); i >= 0 {
+			return []int{i, i + len(pattern)}
+		}
+		return nil
+	})
+	return ok
+}
+
+// WaitRegexp è come Wait con un'espressione regolare e ritorna anche il
+// testo corrispondente.
+func (s *Stream) WaitRegexp(ctx context.Context, re *regexp.Regexp, timeout time.Duration) (string, bool) {
+	return s.wait(ctx, timeout, re.FindStringIndex)
+}
+
+// wait attende che find trovi una corrispondenza nel testo ricevuto,
+// consuma il testo fino alla sua fine e la ritorna.
+func (s *Stream) wait(ctx context.Context, timeout time.Duration, find func(string) []int) (string, bool) {
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	for {
+		s.mu.Lock()
+		text := s.received.String()
+		if loc := find(text); loc != nil {
+			s.received.Reset()
+			s.received.WriteString(text[loc[1]:])
+			s.mu.Unlock()
+			return text[loc[0]:loc[1]], true
+		}
+		changed, closed := s.changed, s.closed
+		s.mu.Unlock()
+		if closed {
+			return "", false
+		}
+
+		select {
+		case <-changed:
+		case <-deadline.C:
+			return "", false
+		case <-ctx.Done():
+			return "", false
+		}
+	}
+}
+
+// ScreenText ritorna il contenuto di scr come testo semplice, righe
+// separate da "\n" e senza spazi finali. Chiamare con il lock di scr.
+func ScreenText(scr *ansi.Screen) string {
+	lines := make([]string, len(scr.Buffer))
+	for y, row := range scr.Buffer {
+		var b strings.Builder
+		for _, cell := range row {
+			b.WriteRune(cell.Char)
+		}
+		lines[y] = strings.TrimRight(b.String(), " ")
+	}
+	return strings.Join(lines, "\n")
+}
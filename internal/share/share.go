@@ -0,0 +1,306 @@
+// Package share trasmette in sola lettura lo schermo di una sessione su un
+// endpoint WebSocket locale, così un amico sulla LAN o un secondo
+// dispositivo possono seguire una partita a un door game in tempo reale.
+//
+// Il server offre una pagina di visualizzazione (GET /) e il flusso dei
+// fotogrammi (GET /ws). Entrambi richiedono il token generato all'avvio,
+// passato come parametro "t" dell'URL: senza token la risposta è 403.
+// I messaggi inviati dagli spettatori sono ignorati.
+package share
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// MaxViewers limita gli spettatori collegati insieme
+	MaxViewers = 8
+
+	// writeTimeout chiude gli spettatori che non ricevono più
+	writeTimeout = 10 * time.Second
+	// pingInterval mantiene aperta la connessione attraverso NAT e proxy
+	pingInterval = 30 * time.Second
+)
+
+// ErrTooManyViewers è ritornato (come 503) oltre MaxViewers spettatori.
+var ErrTooManyViewers = errors.New("troppi spettatori collegati")
+
+// Page descrive la pagina di visualizzazione.
+type Page struct {
+	Title string
+	Style string // CSS dei fotogrammi (vedi htmlexport.Stylesheet)
+}
+
+// Server trasmette i fotogrammi pubblicati con Publish a tutti gli
+// spettatori collegati. È sicuro per l'uso concorrente.
+type Server struct {
+	token string
+	page  []byte
+	ln    net.Listener
+	srv   *http.Server
+
+	mu      sync.Mutex
+	viewers map[*viewer]struct{}
+	last    []byte // ultimo fotogramma, inviato subito ai nuovi spettatori
+	closed  bool
+}
+
+// viewer è uno spettatore collegato. frames ha capacità 1: se lo
+// spettatore è lento i fotogrammi intermedi vengono scartati e riceve
+// sempre il più recente.
+type viewer struct {
+	conn   *websocket.Conn
+	frames chan []byte
+	done   chan struct{}
+}
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  512,
+	WriteBufferSize: 16 * 1024,
+	// L'accesso è protetto dal token, non dall'origine della pagina
+	CheckOrigin: func(*http.Request) bool { return true },
+}
+
+// Start apre il server su addr (":8023", "127.0.0.1:0"...) con un nuovo
+// token casuale.
+func Start(addr string, page Page) (*Server, error) {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return nil, err
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{
+		token:   hex.EncodeToString(token),
+		page:    []byte(viewerPage(page)),
+		ln:      ln,
+		viewers: make(map[*viewer]struct{}),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", s.handlePage)
+	mux.HandleFunc("/ws", s.handleWS)
+	s.srv = &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		if err := s.srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("[SHARE] Server terminato: %v", err)
+		}
+	}()
+	log.Printf("[SHARE] In ascolto su %s", ln.Addr())
+	return s, nil
+}
+
+// Token ritorna il token da passare negli URL.
+func (s *Server) Token() string {
+	return s.token
+}
+
+// Port ritorna la porta su cui il server è in ascolto.
+func (s *Server) Port() int {
+	if addr, ok := s.ln.Addr().(*net.TCPAddr); ok {
+		return addr.Port
+	}
+	return 0
+}
+
+// URLs ritorna gli indirizzi della pagina per ogni interfaccia di rete
+// (prima quelli della LAN, per ultimo localhost).
+func (s *Server) URLs() []string {
+	port := strconv.Itoa(s.Port())
+	var lan []string
+	local := false
+	if addr, ok := s.ln.Addr().(*net.TCPAddr); ok {
+		local = addr.IP.IsLoopback()
+	}
+	if addrs, err := net.InterfaceAddrs(); err == nil && !local {
+		for _, a := range addrs {
+			ipnet, ok := a.(*net.IPNet)
+			if !ok || ipnet.IP.IsLoopback() || ipnet.IP.To4() == nil {
+				continue
+			}
+			lan = append(lan, fmt.Sprintf("http://%s/?t=%s", net.JoinHostPort(ipnet.IP.String(), port), s.token))
+		}
+	}
+	return append(lan, fmt.Sprintf("http://%s/?t=%s", net.JoinHostPort("127.0.0.1", port), s.token))
+}
+
+// Viewers ritorna il numero di spettatori collegati.
+func (s *Server) Viewers() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.viewers)
+}
+
+// Publish invia un fotogramma (frammento HTML) a tutti gli spettatori.
+func (s *Server) Publish(frame []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.last = frame
+	for v := range s.viewers {
+		v.push(frame)
+	}
+}
+
+// Close chiude il server e tutte le connessioni degli spettatori.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	s.closed = true
+	for v := range s.viewers {
+		v.conn.Close()
+	}
+	s.mu.Unlock()
+	return s.srv.Close()
+}
+
+// authorized confronta il token della richiesta in tempo costante.
+func (s *Server) authorized(r *http.Request) bool {
+	t := r.URL.Query().Get("t")
+	return subtle.ConstantTimeCompare([]byte(t), []byte(s.token)) == 1
+}
+
+func (s *Server) handlePage(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	if !s.authorized(r) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Write(s.page)
+}
+
+func (s *Server) handleWS(w http.ResponseWriter, r *http.Request) {
+	if !s.authorized(r) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+	s.mu.Lock()
+	full := s.closed || len(s.viewers) >= MaxViewers
+	s.mu.Unlock()
+	if full {
+		http.Error(w, ErrTooManyViewers.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return // Upgrade ha già risposto al client
+	}
+
+	v := &viewer{conn: conn, frames: make(chan []byte, 1), done: make(chan struct{})}
+	s.mu.Lock()
+	s.viewers[v] = struct{}{}
+	if s.last != nil {
+		v.push(s.last)
+	}
+	s.mu.Unlock()
+	log.Printf("[SHARE] Spettatore collegato da %s", r.RemoteAddr)
+
+	go v.writeLoop()
+	v.readLoop()
+
+	s.mu.Lock()
+	delete(s.viewers, v)
+	s.mu.Unlock()
+	close(v.done)
+	conn.Close()
+	log.Printf("[SHARE] Spettatore scollegato: %s", r.RemoteAddr)
+}
+
+// push accoda un fotogramma, sostituendo quello non ancora inviato.
+func (v *viewer) push(frame []byte) {
+	select {
+	case <-v.frames:
+	default:
+	}
+	v.frames <- frame
+}
+
+// readLoop scarta i messaggi dello spettatore (sola lettura) e ritorna
+// alla chiusura della connessione.
+func (v *viewer) readLoop() {
+	v.conn.SetReadLimit(512)
+	for {
+		if _, _, err := v.conn.ReadMessage(); err != nil {
+			return
+		}
+	}
+}
+
+// writeLoop invia i fotogrammi e i ping fino alla chiusura.
+func (v *viewer) writeLoop() {
+	ping := time.NewTicker(pingInterval)
+	defer ping.Stop()
+	for {
+		var err error
+		select {
+		case <-v.done:
+			return
+		case frame := <-v.frames:
+			v.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+			err = v.conn.WriteMessage(websocket.TextMessage, frame)
+		case <-ping.C:
+			err = v.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeTimeout))
+		}
+		if err != nil {
+			v.conn.Close() // termina anche readLoop
+			return
+		}
+	}
+}
+
+// viewerPage genera la pagina che riceve i fotogrammi e li mostra.
+func viewerPage(p Page) string {
+	title := html.EscapeString(p.Title)
+	return `<!DOCTYPE html>
+<html lang="it">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>` + title + `</title>
+<style>
+` + p.Style + `
+#status { font-size: 12px; margin: 4px 0; }
+</style>
+</head>
+<body>
+<div id="screen"></div>
+<div id="status">Connessione…</div>
+<script>
+(function () {
+  const token = new URLSearchParams(location.search).get('t') || '';
+  const screen = document.getElementById('screen');
+  const status = document.getElementById('status');
+  function connect() {
+    const proto = location.protocol === 'https:' ? 'wss:' : 'ws:';
+    const ws = new WebSocket(proto + '//' + location.host + '/ws?t=' + encodeURIComponent(token));
+    ws.onopen = () => { status.textContent = 'In diretta · sola lettura'; };
+    ws.onmessage = (ev) => { screen.innerHTML = ev.data; };
+    ws.onclose = () => {
+      status.textContent = 'Trasmissione interrotta, nuovo tentativo…';
+      setTimeout(connect, 3000);
+    };
+  }
+  connect();
+})();
+</script>
+</body>
+</html>
+`
+}
@@ -0,0 +1,133 @@
+package sshconn
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// ─────────────────────────────────────────────
+// known_hosts
+// ─────────────────────────────────────────────
+//
+// Come OpenSSH: la prima chiave di un host si accetta dopo averla
+// mostrata all'utente e viene registrata; una chiave diversa da quella
+// registrata fa rifiutare la connessione finché la vecchia non viene
+// tolta (Forget).
+
+// Esiti di Check diversi da una chiave conosciuta.
+var (
+	ErrUnknownHost    = errors.New("chiave host sconosciuta")
+	ErrHostKeyChanged = errors.New("la chiave host è cambiata")
+	ErrHostKeyRevoked = errors.New("la chiave host è revocata")
+)
+
+// KnownHosts è un file known_hosts nel formato di OpenSSH.
+type KnownHosts struct {
+	path string
+	mu   sync.Mutex // serializza letture e scritture del file
+}
+
+// OpenKnownHosts ritorna il file known_hosts in path; se non esiste viene
+// creato alla prima chiave accettata.
+func OpenKnownHosts(path string) *KnownHosts {
+	return &KnownHosts{path: path}
+}
+
+// Path ritorna il percorso del file.
+func (k *KnownHosts) Path() string {
+	return k.path
+}
+
+// Check confronta key con le chiavi registrate per addr (host:porta).
+// Ritorna nil se è una di quelle, ErrUnknownHost se l'host non ne ha,
+// ErrHostKeyChanged (con la chiave registrata in known) se è diversa.
+func (k *KnownHosts) Check(addr string, remote net.Addr, key ssh.PublicKey) (known ssh.PublicKey, err error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if _, err := os.Stat(k.path); errors.Is(err, os.ErrNotExist) {
+		return nil, ErrUnknownHost
+	}
+	callback, err := knownhosts.New(k.path)
+	if err != nil {
+		return nil, err
+	}
+	err = callback(addr, remote, key)
+	var keyErr *knownhosts.KeyError
+	var revoked *knownhosts.RevokedError
+	switch {
+	case err == nil:
+		return nil, nil
+	case errors.As(err, &revoked):
+		return nil, ErrHostKeyRevoked
+	case errors.As(err, &keyErr) && len(keyErr.Want) == 0:
+		return nil, ErrUnknownHost
+	case errors.As(err, &keyErr):
+		return keyErr.Want[0].Key, ErrHostKeyChanged
+	}
+	return nil, err
+}
+
+// Add registra key come chiave di addr.
+func (k *KnownHosts) Add(addr string, key ssh.PublicKey) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(k.path), 0700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(k.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(f, knownhosts.Line([]string{knownhosts.Normalize(addr)}, key))
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// Forget toglie le chiavi registrate per addr, come ssh-keygen -R. Le
+// righe con più host perdono solo addr; quelle con i nomi cifrati (|1|)
+// restano.
+func (k *KnownHosts) Forget(addr string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	data, err := os.ReadFile(k.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	host := knownhosts.Normalize(addr)
+	var out bytes.Buffer
+	for _, line := range strings.SplitAfter(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 || strings.HasPrefix(fields[0], "#") || strings.HasPrefix(fields[0], "@") {
+			out.WriteString(line)
+			continue
+		}
+		hosts := strings.Split(fields[0], ",")
+		rest := slices.DeleteFunc(slices.Clone(hosts), func(h string) bool { return h == host })
+		switch {
+		case len(rest) == len(hosts):
+			out.WriteString(line)
+		case len(rest) > 0:
+			out.WriteString(strings.Join(rest, ",") + strings.TrimPrefix(line, fields[0]))
+		}
+	}
+	return os.WriteFile(k.path, out.Bytes(), 0600)
+}
+
+// Fingerprint ritorna l'impronta SHA256 di key, come la mostra OpenSSH.
+func Fingerprint(key ssh.PublicKey) string {
+	return ssh.FingerprintSHA256(key)
+}
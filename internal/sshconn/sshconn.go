@@ -0,0 +1,180 @@
+// Package sshconn apre le connessioni SSH verso le BBS: autenticazione,
+// terminale e shell, presentati come una net.Conn su cui il client legge
+// e scrive come su un socket telnet. Le chiavi host si verificano con un
+// file known_hosts nel formato di OpenSSH (vedi KnownHosts).
+package sshconn
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// Timeout è l'attesa di default di ogni fase dell'handshake SSH.
+const Timeout = 15 * time.Second
+
+// Dimensioni iniziali del terminale remoto, prima del primo Resize.
+const (
+	defaultCols = 80
+	defaultRows = 25
+)
+
+// Dialer apre connessioni TCP; net.Dialer ne è uno senza proxy.
+type Dialer interface {
+	DialContext(ctx context.Context, network, addr string) (net.Conn, error)
+}
+
+// HostKeyFunc decide se accettare la chiave key presentata da addr
+// (host:porta); un errore interrompe la connessione. Può attendere
+// l'utente senza scadenza.
+type HostKeyFunc func(addr string, remote net.Addr, key ssh.PublicKey) error
+
+// SSH apre una sessione SSH con terminale e shell. Implementa lo stesso
+// DialContext di telnet.Dialer.
+type SSH struct {
+	Base     Dialer // connessione TCP, ad esempio un proxy (nil = diretta)
+	User     string
+	Password string // per password e keyboard-interactive
+	Term     string // tipo di terminale del pty (es. "ansi")
+	HostKey  HostKeyFunc
+	Timeout  time.Duration // attesa di ogni fase (0 = Timeout)
+}
+
+// errNoHostKey è l'errore di un SSH senza HostKey: nessuna chiave viene
+// accettata alla cieca.
+var errNoHostKey = errors.New("verifica della chiave host non configurata")
+
+// DialContext si connette ad addr e apre la shell. La scadenza di ctx
+// vale per la connessione TCP; le fasi successive hanno la loro
+// (s.Timeout), sospesa mentre HostKey attende l'utente. Annullare ctx
+// interrompe la connessione in qualsiasi fase.
+func (s *SSH) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	var base Dialer = &net.Dialer{}
+	if s.Base != nil {
+		base = s.Base
+	}
+	raw, err := base.DialContext(ctx, network, addr)
+	if err != nil {
+		return nil, err
+	}
+	timeout := cmp.Or(s.Timeout, Timeout)
+	raw.SetDeadline(time.Now().Add(timeout))
+	stop := context.AfterFunc(ctx, func() {
+		if errors.Is(ctx.Err(), context.Canceled) {
+			raw.Close()
+		}
+	})
+	defer stop()
+
+	answer := func(_, _ string, questions []string, _ []bool) ([]string, error) {
+		answers := make([]string, len(questions))
+		for i := range answers {
+			answers[i] = s.Password
+		}
+		return answers, nil
+	}
+	config := &ssh.ClientConfig{
+		User: s.User,
+		Auth: []ssh.AuthMethod{ssh.Password(s.Password), ssh.KeyboardInteractive(answer)},
+		HostKeyCallback: func(_ string, remote net.Addr, key ssh.PublicKey) error {
+			if s.HostKey == nil {
+				return errNoHostKey
+			}
+			raw.SetDeadline(time.Time{}) // l'utente può prendersi il suo tempo
+			err := s.HostKey(addr, remote, key)
+			raw.SetDeadline(time.Now().Add(timeout))
+			return err
+		},
+	}
+	conn, err := s.open(raw, addr, config)
+	if err != nil {
+		raw.Close()
+		if errors.Is(ctx.Err(), context.Canceled) {
+			return nil, context.Canceled
+		}
+		return nil, err
+	}
+	raw.SetDeadline(time.Time{})
+	return conn, nil
+}
+
+// open esegue handshake e autenticazione su raw e apre la shell.
+func (s *SSH) open(raw net.Conn, addr string, config *ssh.ClientConfig) (net.Conn, error) {
+	cc, chans, reqs, err := ssh.NewClientConn(raw, addr, config)
+	if err != nil {
+		return nil, err
+	}
+	client := ssh.NewClient(cc, chans, reqs)
+	session, err := client.NewSession()
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+	stdin, err := session.StdinPipe()
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+	stdout, err := session.StdoutPipe()
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+	modes := ssh.TerminalModes{ssh.ECHO: 1}
+	if err := session.RequestPty(cmp.Or(s.Term, "ansi"), defaultRows, defaultCols, modes); err != nil {
+		client.Close()
+		return nil, err
+	}
+	if err := session.Shell(); err != nil {
+		client.Close()
+		return nil, err
+	}
+	return newConn(client, session, stdin, stdout), nil
+}
+
+// ─────────────────────────────────────────────
+// Canale SSH come net.Conn
+// ─────────────────────────────────────────────
+
+// conn è la shell remota vista come net.Conn. I dati passano da una
+// net.Pipe, che dà alle letture le scadenze usate da ZMODEM.
+type conn struct {
+	net.Conn // lato locale della pipe
+	client   *ssh.Client
+	session  *ssh.Session
+}
+
+func newConn(client *ssh.Client, session *ssh.Session, stdin io.WriteCloser, stdout io.Reader) *conn {
+	local, remote := net.Pipe()
+	go func() {
+		io.Copy(remote, stdout)
+		remote.Close() // la BBS ha chiuso: Read ritorna EOF
+	}()
+	go func() {
+		io.Copy(stdin, remote)
+		stdin.Close()
+	}()
+	return &conn{Conn: local, client: client, session: session}
+}
+
+// Close chiude la pipe e la connessione SSH.
+func (c *conn) Close() error {
+	c.Conn.Close()
+	c.session.Close()
+	return c.client.Close()
+}
+
+// RemoteAddr ritorna l'indirizzo del server SSH.
+func (c *conn) RemoteAddr() net.Addr {
+	return c.client.RemoteAddr()
+}
+
+// Resize comunica al server le nuove dimensioni del terminale.
+func (c *conn) Resize(cols, rows int) error {
+	return c.session.WindowChange(rows, cols)
+}
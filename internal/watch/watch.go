@@ -0,0 +1,76 @@
+// Package watch riconosce frasi di interesse ("You have new mail",
+// "Sysop paging"...) nel flusso ricevuto dalla BBS, per avvisare l'utente
+// anche durante sessioni lasciate inattive.
+package watch
+
+import (
+	"strings"
+
+	"github.com/rj45lab/bbs-client-go/pkg/ansi"
+)
+
+// Match è una frase trovata, con la riga di testo che la contiene.
+type Match struct {
+	Phrase string `json:"phrase"`
+	Line   string `json:"line"`
+}
+
+// Matcher cerca le frasi nel testo ricevuto, senza distinguere maiuscole e
+// minuscole e ignorando colori e sequenze ANSI. Le frasi vengono cercate
+// anche nella riga non ancora terminata (i prompt non finiscono con un
+// a capo), ma ognuna è segnalata al più una volta per riga.
+// Non è sicuro per l'uso concorrente.
+type Matcher struct {
+	phrases []string // originali, per i Match
+	lower   []string // in minuscolo, per il confronto
+	strip   ansi.Stripper
+	hits    map[int]bool // frasi già segnalate nella riga corrente
+}
+
+// New crea un Matcher per le frasi indicate (quelle vuote sono ignorate).
+func New(phrases []string) *Matcher {
+	m := &Matcher{}
+	m.SetPhrases(phrases)
+	return m
+}
+
+// SetPhrases sostituisce le frasi cercate.
+func (m *Matcher) SetPhrases(phrases []string) {
+	m.phrases, m.lower = nil, nil
+	for _, p := range phrases {
+		if p = strings.TrimSpace(p); p != "" {
+			m.phrases = append(m.phrases, p)
+			m.lower = append(m.lower, strings.ToLower(p))
+		}
+	}
+	m.hits = map[int]bool{}
+}
+
+// Feed elabora un blocco di testo decodificato e ritorna le frasi trovate.
+func (m *Matcher) Feed(text string) []Match {
+	if len(m.phrases) == 0 {
+		return nil
+	}
+	var out []Match
+	lines := strings.Split(m.strip.Write(text), "\n")
+	for _, line := range lines[:len(lines)-1] {
+		out = m.scan(line, out)
+		m.hits = map[int]bool{} // riga chiusa
+	}
+	return m.scan(m.strip.Pending(), out)
+}
+
+// scan aggiunge a out le frasi presenti in line non ancora segnalate.
+func (m *Matcher) scan(line string, out []Match) []Match {
+	if line == "" {
+		return out
+	}
+	lower := strings.ToLower(line)
+	for i, p := range m.lower {
+		if !m.hits[i] && strings.Contains(lower, p) {
+			m.hits[i] = true
+			out = append(out, Match{Phrase: m.phrases[i], Line: strings.TrimSpace(line)})
+		}
+	}
+	return out
+}
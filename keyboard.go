@@ -0,0 +1,73 @@
+package main
+
+import (
+	"github.com/rj45lab/bbs-client-go/internal/charset"
+	"github.com/rj45lab/bbs-client-go/internal/config"
+	"github.com/rj45lab/bbs-client-go/internal/i18n"
+	"github.com/rj45lab/bbs-client-go/internal/keymap"
+)
+
+// ─────────────────────────────────────────────
+// Tastiera nazionale
+// ─────────────────────────────────────────────
+//
+// I caratteri digitati passano dal layout scelto (Settings.Keyboard) prima
+// di essere inviati: i tasti morti che il frontend segnala con
+// SendDeadKey si compongono con la lettera successiva, e le lettere
+// accentate diventano i byte CP437 corrispondenti o, se CP437 non le ha,
+// la grafia del paese (È → E' in italiano).
+
+// keyboardLayout ritorna il layout della tastiera scelto nelle
+// impostazioni. Chiamare con a.mu acquisito.
+func (a *App) keyboardLayout() *keymap.Layout {
+	if l, ok := keymap.Lookup(a.settings.Keyboard); ok {
+		return l
+	}
+	l, _ := keymap.Lookup(keymap.Default)
+	return l
+}
+
+// SendDeadKey segnala un tasto morto (KeyboardEvent.code): il suo accento
+// si applica al prossimo carattere inviato con SendText.
+func (a *App) SendDeadKey(code string, shift bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if accent, ok := a.keyboardLayout().DeadKey(code, shift); ok {
+		a.deadKey = accent
+	}
+}
+
+// translateKeys compone text con l'eventuale tasto morto in attesa e lo
+// converte in byte CP437. Chiamare con a.mu acquisito.
+func (a *App) translateKeys(text string) []byte {
+	if a.deadKey != 0 {
+		text = keymap.Compose(a.deadKey, text)
+		a.deadKey = 0
+	}
+	return a.encodeText(a.session, text)
+}
+
+// encodeText converte text nella codifica della sessione s (vedi
+// applyEncoding); in CP437 secondo il layout della tastiera, con i
+// caratteri mancanti traslitterati. Chiamare con a.mu acquisito.
+func (a *App) encodeText(s *session, text string) []byte {
+	if s.encoding.Name() != charset.CP437 {
+		return s.encoding.Encode(text)
+	}
+	return a.keyboardLayout().Encode(text)
+}
+
+// GetKeyboardLayouts ritorna i layout di tastiera disponibili.
+func (a *App) GetKeyboardLayouts() []string {
+	return keymap.Names()
+}
+
+// SetKeyboardLayout sceglie il layout della tastiera.
+func (a *App) SetKeyboardLayout(name string) *i18n.Message {
+	if _, ok := keymap.Lookup(name); !ok {
+		return i18n.New(i18n.ErrUnknownKeyboard, name)
+	}
+	return a.updateSettings(func(s *config.Settings) {
+		s.Keyboard = name
+	})
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"log"
+	"time"
+)
+
+// ─────────────────────────────────────────────
+// Sequenza di uscita
+// ─────────────────────────────────────────────
+//
+// Molte BBS registrano come linea caduta una sessione chiusa senza
+// passare dal loro comando di uscita. Con Entry.Logoff Disconnect invia
+// prima quel testo e lascia alla BBS fino a logoffWait per chiudere da
+// sola; poi chiude comunque.
+
+// logoffWait è quanto si attende che la BBS chiuda dopo il logoff.
+const logoffWait = 3 * time.Second
+
+// logoff invia a s la sequenza di uscita della sua BBS e attende che
+// chiuda la connessione. Non fa nulla se s non è connessa o la voce in
+// rubrica non ha una sequenza. Chiamare senza a.mu.
+func (a *App) logoff(s *session) {
+	a.mu.Lock()
+	connected, host, port, stream := s.connected, s.host, s.port, s.stream
+	entry, ok := a.book.Lookup(host, port)
+	var data []byte
+	if ok && entry.Logoff != "" {
+		data = a.encodeText(s, entry.Logoff)
+	}
+	a.mu.Unlock()
+	if !connected || data == nil {
+		return
+	}
+	if err := s.conn.Send(data); err != nil {
+		log.Printf("[LOGOFF] %s:%d: %v", host, port, err)
+		return
+	}
+	deadline := time.After(logoffWait)
+	for {
+		// Lo stream degli script si chiude a fine sessione (endSession)
+		changed, closed := stream.Changed()
+		if closed {
+			log.Printf("[LOGOFF] %s:%d ha chiuso la sessione", host, port)
+			return
+		}
+		select {
+		case <-changed:
+		case <-deadline:
+			log.Printf("[LOGOFF] %s:%d non ha chiuso entro %v", host, port, logoffWait)
+			return
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/rj45lab/bbs-client-go/internal/config"
+	"github.com/rj45lab/bbs-client-go/internal/i18n"
+	"github.com/rj45lab/bbs-client-go/internal/sauce"
+)
+
+// ─────────────────────────────────────────────
+// Log divisi in parti
+// ─────────────────────────────────────────────
+//
+// Nelle sessioni lunghe i log possono cambiare file a mezzanotte
+// (Logging.SplitDaily) e/o ogni Logging.SplitHours ore: la prima parte è
+// Nome_data.log, le successive Nome_data_part2.log, _part3 e così via,
+// ognuna con i suoi file accanto (.timing, .txt, .raw, .jsonl, .cast) e
+// con il suo limite di dimensione. LoadLog riconosce le parti di una
+// sessione e le riapre come un unico log, con i tempi ricuciti.
+
+// maxSplitHours è l'intervallo massimo tra due parti.
+const maxSplitHours = 7 * 24
+
+// logPartRe riconosce il suffisso delle parti successive alla prima.
+var logPartRe = regexp.MustCompile(`_part([0-9]+)$`)
+
+// logPartPath ritorna il file della parte part del log con base base.
+func logPartPath(base string, part int) string {
+	if part <= 1 {
+		return base + ".log"
+	}
+	return fmt.Sprintf("%s_part%d.log", base, part)
+}
+
+// nextLogSplit ritorna quando il log aperto in now deve passare alla parte
+// successiva (zero = mai).
+func nextLogSplit(opts config.Logging, now time.Time) time.Time {
+	var at time.Time
+	if opts.SplitDaily {
+		y, m, d := now.Date()
+		at = time.Date(y, m, d+1, 0, 0, 0, 0, now.Location())
+	}
+	if opts.SplitHours > 0 {
+		if t := now.Add(time.Duration(opts.SplitHours) * time.Hour); at.IsZero() || t.Before(at) {
+			at = t
+		}
+	}
+	return at
+}
+
+// logSplitDue dice se il log di s deve passare alla parte successiva.
+func (s *session) logSplitDue(now time.Time) bool {
+	return !s.logSplit.IsZero() && !now.Before(s.logSplit)
+}
+
+// rotateSessionLog chiude la parte corrente del log di s e apre la
+// successiva. Chiamare dal loop eventi della scheda, senza a.mu.
+func (a *App) rotateSessionLog(s *session) {
+	part := s.logPart + 1
+	s.stopSessionLog()
+	a.openSessionLog(s, part)
+}
+
+// logParts ritorna le parti della sessione a cui appartiene il log path,
+// in ordine, o solo path se non fa parte di una sequenza.
+func logParts(path string) []string {
+	base, ok := strings.CutSuffix(path, ".log")
+	if !ok {
+		return []string{path}
+	}
+	if loc := logPartRe.FindStringIndex(base); loc != nil {
+		base = base[:loc[0]]
+	}
+	first := logPartPath(base, 1)
+	if _, err := os.Stat(first); err != nil {
+		return []string{path}
+	}
+	parts := []string{first}
+	for n := 2; ; n++ {
+		p := logPartPath(base, n)
+		if _, err := os.Stat(p); err != nil {
+			return parts
+		}
+		parts = append(parts, p)
+	}
+}
+
+// readLogParts legge le parti di un log e le unisce: testo senza
+// intestazioni e chiusure, tempi di tutte le parti (nil se ne manca uno) e
+// record SAUCE della prima.
+func readLogParts(parts []string) (string, []timedChunk, *sauce.Record, *i18n.Message) {
+	var text strings.Builder
+	var timing []timedChunk
+	var rec *sauce.Record
+	timed := true
+	var at time.Duration
+	runes := 0
+	for i, p := range parts {
+		content, err := os.ReadFile(p)
+		if err != nil {
+			return "", nil, nil, i18n.New(i18n.ErrRead, err)
+		}
+		if i == 0 {
+			// Metadati SAUCE in coda (se presenti) → configurano il rendering
+			rec, content = sauce.Parse(content)
+		}
+		part := stripLogFrame(string(content))
+		text.WriteString(part)
+		if data, err := os.ReadFile(timingPath(p)); err == nil && timed {
+			chunks := parseTiming(data, part)
+			for _, c := range chunks {
+				timing = append(timing, timedChunk{at: at + c.at, end: runes + c.end})
+			}
+			if n := len(chunks); n > 0 {
+				at += chunks[n-1].at
+			}
+		} else {
+			timed = false
+		}
+		runes += utf8.RuneCountInString(part)
+	}
+	if !timed {
+		timing = nil
+	}
+	return text.String(), timing, rec, nil
+}
+
+// SetLogSplit sceglie quando dividere i log delle sessioni: a mezzanotte
+// (daily) e/o ogni hours ore (0 = mai). Vale dalla prossima connessione.
+func (a *App) SetLogSplit(daily bool, hours int) *i18n.Message {
+	if hours < 0 || hours > maxSplitHours {
+		return i18n.New(i18n.ErrInvalidLimit, "splitHours")
+	}
+	return a.updateSettings(func(s *config.Settings) {
+		s.Logging.SplitDaily = daily
+		s.Logging.SplitHours = hours
+	})
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"log"
+	"sync"
+	"time"
+)
+
+// ─────────────────────────────────────────────
+// Scrittura asincrona dei log di sessione
+// ─────────────────────────────────────────────
+//
+// Log, trascrizione, tempi e registrazione .cast vengono scritti da una
+// goroutine per file: l'event loop accoda i blocchi e prosegue, così un
+// disco lento (home di rete, schede SD) non ferma lo schermo. Se la coda
+// è piena i blocchi vengono scartati e contati. Close attende che la coda
+// sia scritta: dopo stopSessionLog i file sono completi su disco.
+
+const (
+	logQueue         = 1024        // blocchi in attesa di scrittura
+	logBufferSize    = 64 << 10    // buffer verso il file
+	logFlushInterval = time.Second // il viewer legge dati al più vecchi di così
+)
+
+// logWriter accoda le scritture verso un file e le esegue in background.
+type logWriter struct {
+	f     io.WriteCloser
+	ch    chan []byte
+	flush chan chan struct{}
+	done  chan struct{}
+	err   error // primo errore di scrittura, letto dopo done
+
+	mu      sync.Mutex
+	closed  bool
+	dropped int64 // byte scartati per coda piena, non ancora segnalati
+}
+
+// newLogWriter avvia la scrittura in background su f.
+func newLogWriter(f io.WriteCloser) *logWriter {
+	w := &logWriter{
+		f:     f,
+		ch:    make(chan []byte, logQueue),
+		flush: make(chan chan struct{}),
+		done:  make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+// Write accoda una copia di p. Non blocca: con la coda piena p viene
+// scartato. Ritorna sempre len(p), così i contatori dei limiti restano
+// quelli dei dati ricevuti.
+func (w *logWriter) Write(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return 0, io.ErrClosedPipe
+	}
+	select {
+	case w.ch <- append([]byte(nil), p...):
+	default:
+		w.dropped += int64(len(p))
+	}
+	return len(p), nil
+}
+
+// WriteString è Write per le stringhe.
+func (w *logWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Flush attende che i blocchi accodati finora siano sul file.
+func (w *logWriter) Flush() {
+	ack := make(chan struct{})
+	select {
+	case w.flush <- ack:
+		<-ack
+	case <-w.done:
+	}
+}
+
+// Close scrive quanto resta in coda, chiude il file e ritorna il primo
+// errore incontrato.
+func (w *logWriter) Close() error {
+	w.mu.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.ch)
+	}
+	w.mu.Unlock()
+	<-w.done
+	return w.err
+}
+
+func (w *logWriter) run() {
+	defer close(w.done)
+	bw := bufio.NewWriterSize(w.f, logBufferSize)
+	ticker := time.NewTicker(logFlushInterval)
+	defer ticker.Stop()
+
+	write := func(p []byte) {
+		if _, err := bw.Write(p); err != nil && w.err == nil {
+			w.err = err
+			log.Printf("[LOG] Scrittura fallita: %v", err)
+		}
+	}
+	for {
+		select {
+		case p, ok := <-w.ch:
+			if !ok {
+				w.sync(bw)
+				if err := w.f.Close(); err != nil && w.err == nil {
+					w.err = err
+				}
+				return
+			}
+			write(p)
+			w.reportDropped()
+		case ack := <-w.flush:
+			// Prima i blocchi già in coda: select non dà precedenza
+			for drained := false; !drained; {
+				select {
+				case p, ok := <-w.ch:
+					if ok {
+						write(p)
+					} else {
+						drained = true
+					}
+				default:
+					drained = true
+				}
+			}
+			w.sync(bw)
+			close(ack)
+		case <-ticker.C:
+			w.sync(bw)
+		}
+	}
+}
+
+// sync svuota il buffer sul file.
+func (w *logWriter) sync(bw *bufio.Writer) {
+	if err := bw.Flush(); err != nil && w.err == nil {
+		w.err = err
+		log.Printf("[LOG] Scrittura fallita: %v", err)
+	}
+}
+
+// reportDropped annota nel log dell'applicazione i byte scartati.
+func (w *logWriter) reportDropped() {
+	w.mu.Lock()
+	n := w.dropped
+	w.dropped = 0
+	w.mu.Unlock()
+	if n > 0 {
+		log.Printf("[LOG] Disco lento: %d byte non scritti", n)
+	}
+}
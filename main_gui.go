@@ -2,6 +2,7 @@ package main
 
 import (
 	"embed"
+	"os"
 
 	"github.com/wailsapp/wails/v2"
 	"github.com/wailsapp/wails/v2/pkg/options"
@@ -14,6 +15,8 @@ var assets embed.FS
 
 func main() {
 	app := NewApp()
+	app.launchURI = uriArg(os.Args[1:])
+	app.debugAddr = debugArg(os.Args[1:])
 
 	err := wails.Run(&options.App{
 		Title:     "BBS Client for Gen-Z",
@@ -22,10 +25,18 @@ func main() {
 		MinWidth:  800,
 		MinHeight: 600,
 		AssetServer: &assetserver.Options{
-			Assets: assets,
+			Assets:  assets,
+			Handler: app.assetHandler(),
 		},
 		BackgroundColour: &options.RGBA{R: 0, G: 0, B: 0, A: 255},
 		OnStartup:        app.Startup,
+		OnShutdown:       app.Shutdown,
+		OnBeforeClose:    app.beforeClose,
+		OnDomReady:       app.domReady,
+		SingleInstanceLock: &options.SingleInstanceLock{
+			UniqueId:               singleInstanceID,
+			OnSecondInstanceLaunch: app.onSecondInstance,
+		},
 		Bind: []interface{}{
 			app,
 		},
@@ -33,6 +44,7 @@ func main() {
 			TitleBar:             mac.TitleBarDefault(),
 			WebviewIsTransparent: false,
 			WindowIsTranslucent:  false,
+			OnUrlOpen:            app.openURL,
 		},
 	})
 	if err != nil {
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"os"
+	"strings"
+
+	wailsrt "github.com/wailsapp/wails/v2/pkg/runtime"
+
+	"github.com/rj45lab/bbs-client-go/internal/bluewave"
+	"github.com/rj45lab/bbs-client-go/internal/cp437"
+	"github.com/rj45lab/bbs-client-go/internal/i18n"
+)
+
+// ─────────────────────────────────────────────
+// Posta offline (Blue Wave)
+// ─────────────────────────────────────────────
+
+// MailPacketResult è il risultato di OpenMailPacket.
+type MailPacketResult struct {
+	Packet *bluewave.Packet `json:"packet"`
+	Error  *i18n.Message    `json:"error"`
+}
+
+// OpenMailPacket sceglie un pacchetto di posta Blue Wave (archivio ZIP
+// scaricato dalla BBS) e ne ritorna aree e messaggi, con i testi
+// convertiti da CP437. Il pacchetto resta aperto per SaveMailReplies.
+func (a *App) OpenMailPacket() MailPacketResult {
+	path, err := wailsrt.OpenFileDialog(a.ctx, wailsrt.OpenDialogOptions{
+		Title:            i18n.T(i18n.DlgOpenMail),
+		DefaultDirectory: a.downloadDir(),
+		Filters: []wailsrt.FileFilter{
+			{DisplayName: i18n.T(i18n.DlgMailFiles),
+				Pattern: "*.su?;*.mo?;*.tu?;*.we?;*.th?;*.fr?;*.sa?"},
+			{DisplayName: i18n.T(i18n.DlgAllFiles), Pattern: "*"},
+		},
+	})
+	if err != nil || path == "" {
+		return MailPacketResult{Error: i18n.Err(err)}
+	}
+	p, err := bluewave.OpenFile(path)
+	if err != nil {
+		return MailPacketResult{Error: i18n.Err(err)}
+	}
+	decodePacket(p)
+
+	a.mu.Lock()
+	a.mailPacket = p
+	a.mu.Unlock()
+	return MailPacketResult{Packet: p}
+}
+
+// SaveMailReplies salva le risposte scritte offline nel pacchetto .NEW da
+// caricare sulla BBS (ad esempio con l'upload ZMODEM).
+func (a *App) SaveMailReplies(replies []bluewave.Reply) *i18n.Message {
+	a.mu.Lock()
+	p := a.mailPacket
+	a.mu.Unlock()
+	if p == nil {
+		return i18n.New(i18n.ErrNoMailPacket)
+	}
+	if len(replies) == 0 {
+		return i18n.New(i18n.ErrNoReplies)
+	}
+
+	// Il pacchetto di risposta usa la codifica della BBS
+	enc := *p
+	enc.LoginName = string(cp437.Encode(p.LoginName))
+	enc.AliasName = string(cp437.Encode(p.AliasName))
+	out := make([]bluewave.Reply, len(replies))
+	for i, r := range replies {
+		r.To = string(cp437.Encode(r.To))
+		r.Subject = string(cp437.Encode(r.Subject))
+		r.Text = string(cp437.Encode(r.Text))
+		out[i] = r
+	}
+
+	path, err := wailsrt.SaveFileDialog(a.ctx, wailsrt.SaveDialogOptions{
+		Title:            i18n.T(i18n.DlgSaveReplies),
+		DefaultDirectory: a.downloadDir(),
+		DefaultFilename:  strings.ToLower(p.ID) + ".new",
+		Filters:          []wailsrt.FileFilter{{DisplayName: i18n.T(i18n.DlgReplyFiles), Pattern: "*.new"}},
+	})
+	if err != nil || path == "" {
+		return i18n.Err(err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return i18n.Err(err)
+	}
+	if err := bluewave.WriteReplies(f, &enc, out); err != nil {
+		f.Close()
+		os.Remove(path)
+		return i18n.Err(err)
+	}
+	return i18n.Err(f.Close())
+}
+
+// decodePacket converte da CP437 tutti i testi del pacchetto.
+func decodePacket(p *bluewave.Packet) {
+	dec := func(s *string) { *s = cp437.Decode([]byte(*s)) }
+	dec(&p.BBS)
+	dec(&p.Sysop)
+	dec(&p.LoginName)
+	dec(&p.AliasName)
+	for i := range p.Areas {
+		ar := &p.Areas[i]
+		dec(&ar.Title)
+		for j := range ar.Messages {
+			m := &ar.Messages[j]
+			dec(&m.From)
+			dec(&m.To)
+			dec(&m.Subject)
+			dec(&m.Text)
+		}
+	}
+}
@@ -0,0 +1,304 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"time"
+
+	wailsrt "github.com/wailsapp/wails/v2/pkg/runtime"
+
+	"github.com/rj45lab/bbs-client-go/internal/config"
+	"github.com/rj45lab/bbs-client-go/internal/i18n"
+)
+
+// ─────────────────────────────────────────────
+// Incolla con conferma e invio cadenzato
+// ─────────────────────────────────────────────
+
+// PasteText invia al server il testo incollato. Per testi su più righe o
+// molto lunghi chiede prima conferma; i caratteri di controllo pericolosi
+// (ESC, DEL, C1...) vengono rimossi, il testo è convertito nella codifica
+// della sessione e l'invio procede al ritmo impostato in Settings.Paste,
+// con avanzamento sull'evento "paste-progress".
+func (a *App) PasteText(text string) *i18n.Message {
+	a.mu.Lock()
+	ok, pace := a.connected, pasteFor(a.settings, bbsKey(a.host, a.port))
+	data := a.encodeText(a.session, sanitizePaste(text))
+	a.mu.Unlock()
+	if !ok {
+		return i18n.New(i18n.ErrNotConnected)
+	}
+
+	if len(data) == 0 {
+		return nil
+	}
+	lines := bytes.Count(data, []byte{'\r'}) + 1
+	if (pace.ConfirmLines > 0 && lines > pace.ConfirmLines) ||
+		(pace.ConfirmChars > 0 && len(data) > pace.ConfirmChars) {
+		if !a.confirm(i18n.T(i18n.DlgPasteTitle), i18n.T(i18n.DlgPasteConfirm, lines, len(data))) {
+			return nil
+		}
+	}
+	return a.startPaced(data, pace)
+}
+
+// pasteFor ritorna le regole di invio per la BBS key: quelle di
+// Settings.Paste, con le pause sostituite da Settings.Pacing se la BBS ne
+// ha di proprie.
+func pasteFor(s config.Settings, key string) config.Paste {
+	pace := s.Paste
+	if p, ok := s.Pacing[key]; ok {
+		pace.CharDelayMs, pace.LineDelayMs = p.CharDelayMs, p.LineDelayMs
+	}
+	return pace
+}
+
+// paced indica se l'invio di testo va cadenzato.
+func paced(pace config.Paste) bool {
+	return pace.CharDelayMs > 0 || pace.LineDelayMs > 0
+}
+
+// SetPacing imposta le pause di invio (millisecondi) per la BBS corrente.
+func (a *App) SetPacing(charDelayMs, lineDelayMs int) *i18n.Message {
+	if charDelayMs < 0 || lineDelayMs < 0 {
+		return i18n.New(i18n.ErrInvalidPacing, charDelayMs, lineDelayMs)
+	}
+	a.mu.Lock()
+	key := bbsKey(a.host, a.port)
+	a.mu.Unlock()
+	return a.updateSettings(func(s *config.Settings) {
+		s.Pacing[key] = config.Pacing{CharDelayMs: charDelayMs, LineDelayMs: lineDelayMs}
+	})
+}
+
+// ClearPacing riporta la BBS corrente alle pause di default.
+func (a *App) ClearPacing() *i18n.Message {
+	a.mu.Lock()
+	key := bbsKey(a.host, a.port)
+	a.mu.Unlock()
+	return a.updateSettings(func(s *config.Settings) {
+		delete(s.Pacing, key)
+	})
+}
+
+// GetPacing ritorna le pause in uso per la BBS corrente.
+func (a *App) GetPacing() config.Pacing {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	pace := pasteFor(a.settings, bbsKey(a.host, a.port))
+	return config.Pacing{CharDelayMs: pace.CharDelayMs, LineDelayMs: pace.LineDelayMs}
+}
+
+// CancelPaste interrompe l'invio cadenzato in corso.
+func (a *App) CancelPaste() {
+	a.mu.Lock()
+	a.stopPacedLocked()
+	a.mu.Unlock()
+}
+
+// sanitizePaste normalizza i fine riga in CR (il tasto Invio) e rimuove i
+// controlli che potrebbero pilotare il server al posto dell'utente:
+// restano solo testo, TAB e CR.
+func sanitizePaste(text string) string {
+	text = strings.ReplaceAll(text, "\r\n", "\r")
+	text = strings.ReplaceAll(text, "\n", "\r")
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '\r' || r == '\t':
+		case r < 0x20 || r == 0x7F || (r >= 0x80 && r < 0xA0):
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// confirm mostra una finestra di conferma e ritorna la scelta dell'utente.
+func (a *App) confirm(title, message string) bool {
+	return a.ask(title, message, i18n.T(i18n.DlgSend))
+}
+
+// ask mostra una domanda con i pulsanti ok e "Annulla" e ritorna true se
+// l'utente sceglie ok.
+func (a *App) ask(title, message, ok string) bool {
+	cancel := i18n.T(i18n.DlgCancel)
+	res, err := wailsrt.MessageDialog(a.ctx, wailsrt.MessageDialogOptions{
+		Type:          wailsrt.QuestionDialog,
+		Title:         title,
+		Message:       message,
+		Buttons:       []string{ok, cancel},
+		DefaultButton: ok,
+		CancelButton:  cancel,
+	})
+	if err != nil {
+		return false
+	}
+	// Su Windows e Linux i pulsanti personalizzati possono essere ignorati
+	switch res {
+	case ok, "Yes", "Ok", "OK":
+		return true
+	}
+	return false
+}
+
+// startPaced avvia l'invio cadenzato di data. Un solo invio alla volta.
+func (a *App) startPaced(data []byte, pace config.Paste) *i18n.Message {
+	a.mu.Lock()
+	if a.pasteStop != nil {
+		a.mu.Unlock()
+		return i18n.New(i18n.ErrPasteBusy)
+	}
+	stop := make(chan struct{})
+	a.pasteStop = stop
+	s := a.session
+	a.mu.Unlock()
+
+	a.touchInput()
+	go a.pacedLoop(s, data, pace, stop)
+	return nil
+}
+
+// stopPacedLocked interrompe l'invio in corso. Chiamare con a.mu acquisito.
+func (s *session) stopPacedLocked() {
+	if s.pasteStop != nil {
+		close(s.pasteStop)
+		s.pasteStop = nil
+	}
+}
+
+// pacedLoop invia data riga per riga, con una pausa tra i caratteri e una
+// più lunga dopo ogni CR, finché non termina o viene interrotto.
+func (a *App) pacedLoop(s *session, data []byte, pace config.Paste, stop chan struct{}) {
+	charDelay := time.Duration(max(pace.CharDelayMs, 0)) * time.Millisecond
+	lineDelay := time.Duration(max(pace.LineDelayMs, 0)) * time.Millisecond
+	wait := func(d time.Duration) bool {
+		if d <= 0 {
+			return true
+		}
+		select {
+		case <-stop:
+			return false
+		case <-time.After(d):
+			return true
+		}
+	}
+
+	sent, canceled := 0, false
+	var sendErr error
+send:
+	for rest := data; len(rest) > 0; {
+		line := rest
+		if i := bytes.IndexByte(rest, '\r'); i >= 0 {
+			line = rest[:i+1]
+		}
+		rest = rest[len(line):]
+
+		if charDelay > 0 {
+			for i := range line {
+				if sendErr = s.conn.Send(line[i : i+1]); sendErr != nil {
+					break send
+				}
+				sent++
+				if !wait(charDelay) {
+					canceled = true
+					break send
+				}
+			}
+		} else {
+			if sendErr = s.conn.Send(line); sendErr != nil {
+				break send
+			}
+			sent += len(line)
+		}
+		a.emitFor(s, "paste-progress", PasteProgress{Session: s.id, Sent: sent, Total: len(data)})
+		if line[len(line)-1] == '\r' && !wait(lineDelay) {
+			canceled = true
+			break
+		}
+	}
+
+	a.mu.Lock()
+	if s.pasteStop == stop {
+		s.pasteStop = nil
+	}
+	a.mu.Unlock()
+	a.emitFor(s, "paste-finished", PasteResult{
+		Session:  s.id,
+		Sent:     sent,
+		Total:    len(data),
+		Canceled: canceled,
+		Error:    i18n.Err(sendErr),
+	})
+}
+
+// maxSendFileSize limita i file inviati come tasti: oltre conviene ZMODEM.
+const maxSendFileSize = 1 << 20
+
+// SendTextFile sceglie un file di testo e lo invia al server come input da
+// tastiera, allo stesso ritmo del testo incollato. Con toCp437 il file viene
+// letto come UTF-8 e ricodificato come il testo incollato (CP437, salvo
+// BBS che usano UTF-8); altrimenti i byte sono inviati
+// così come sono (file già in CP437 o ASCII).
+func (a *App) SendTextFile(toCp437 bool) *i18n.Message {
+	a.mu.Lock()
+	ok, pace := a.connected, pasteFor(a.settings, bbsKey(a.host, a.port))
+	a.mu.Unlock()
+	if !ok {
+		return i18n.New(i18n.ErrNotConnected)
+	}
+
+	path, err := wailsrt.OpenFileDialog(a.ctx, wailsrt.OpenDialogOptions{
+		Title: i18n.T(i18n.DlgSendText),
+		Filters: []wailsrt.FileFilter{
+			{DisplayName: i18n.T(i18n.DlgTextFiles), Pattern: "*.txt;*.msg"},
+			{DisplayName: i18n.T(i18n.DlgAllFiles), Pattern: "*"},
+		},
+	})
+	if err != nil || path == "" {
+		return i18n.Err(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return i18n.Err(err)
+	}
+	if info.Size() > maxSendFileSize {
+		return i18n.New(i18n.ErrFileTooLarge, info.Size()>>10, maxSendFileSize>>10)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return i18n.New(i18n.ErrRead, err)
+	}
+
+	var data []byte
+	if toCp437 {
+		a.mu.Lock()
+		data = a.encodeText(a.session, sanitizePaste(string(content)))
+		a.mu.Unlock()
+	} else {
+		data = sanitizeRaw(content)
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	return a.startPaced(data, pace)
+}
+
+// sanitizeRaw è l'equivalente di sanitizePaste per byte già nella codifica
+// del server: i byte alti (caratteri CP437) restano intatti.
+func sanitizeRaw(data []byte) []byte {
+	data = bytes.ReplaceAll(data, []byte("\r\n"), []byte("\r"))
+	out := make([]byte, 0, len(data))
+	for _, b := range data {
+		switch {
+		case b == '\n':
+			b = '\r'
+		case b == '\r' || b == '\t':
+		case b < 0x20 || b == 0x7F:
+			continue
+		}
+		out = append(out, b)
+	}
+	return out
+}
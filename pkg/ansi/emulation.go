@@ -0,0 +1,266 @@
+package ansi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ─────────────────────────────────────────────
+// Emulazioni (ANSI-BBS, VT100, solo ASCII)
+// ─────────────────────────────────────────────
+//
+// L'emulazione decide come il terminale si presenta alla BBS: a quali
+// richieste di identificazione (DSR, DA, XTWINOPS) risponde, quali sequenze
+// invia per i tasti speciali e quale tipo di terminale dichiara in TTYPE.
+// Le BBS che vedono risposte inattese spesso ripiegano sul solo ASCII:
+// scegliere l'emulazione per BBS permette di evitarlo (o di ottenerlo).
+// La resa di ciò che arriva dalla BBS non cambia.
+
+// Emulation seleziona il profilo del terminale.
+type Emulation string
+
+const (
+	// EmulationANSIBBS: terminale BBS classico (default)
+	EmulationANSIBBS Emulation = "ansi-bbs"
+	// EmulationVT100: terminale DEC VT100, tasti e risposte VT
+	EmulationVT100 Emulation = "vt100"
+	// EmulationASCII: nessuna risposta alle richieste e nessuna sequenza
+	// di escape per i tasti, così la BBS sceglie la modalità testo
+	EmulationASCII Emulation = "ascii"
+)
+
+// Emulations elenca le emulazioni disponibili.
+var Emulations = []Emulation{EmulationANSIBBS, EmulationVT100, EmulationASCII}
+
+// ParseEmulation ritorna l'emulazione di nome name ("" = ANSI-BBS).
+func ParseEmulation(name string) (Emulation, error) {
+	if name == "" {
+		return EmulationANSIBBS, nil
+	}
+	for _, e := range Emulations {
+		if string(e) == name {
+			return e, nil
+		}
+	}
+	return "", fmt.Errorf("emulazione sconosciuta: %s", name)
+}
+
+// TermType ritorna il tipo di terminale da dichiarare in TTYPE.
+func (e Emulation) TermType() string {
+	switch e {
+	case EmulationVT100:
+		return "VT100"
+	case EmulationASCII:
+		return "DUMB"
+	}
+	return "ANSI"
+}
+
+// answersProbes indica se il terminale risponde a DSR e DA.
+func (e Emulation) answersProbes() bool {
+	return e != EmulationASCII
+}
+
+// deviceAttributes ritorna la risposta a DA primario (CSI c).
+func (e Emulation) deviceAttributes() string {
+	if e == EmulationVT100 {
+		return "\x1b[?1;2c" // VT100 con Advanced Video Option
+	}
+	return "\x1b[?6c" // VT102
+}
+
+// ─────────────────────────────────────────────
+// Tasti speciali
+// ─────────────────────────────────────────────
+
+// ansiBBSKeys sono le sequenze dei tasti nell'emulazione ANSI-BBS.
+var ansiBBSKeys = map[string]string{
+	"Enter":      "\r",
+	"Backspace":  "\b",
+	"Tab":        "\t",
+	"Escape":     "\x1b",
+	"ArrowUp":    "\x1b[A",
+	"ArrowDown":  "\x1b[B",
+	"ArrowRight": "\x1b[C",
+	"ArrowLeft":  "\x1b[D",
+	"Home":       "\x1b[H",
+	"End":        "\x1b[F",
+	"PageUp":     "\x1b[5~",
+	"PageDown":   "\x1b[6~",
+	"Insert":     "\x1b[2~",
+	"Delete":     "\x1b[3~",
+	"F1":         "\x1bOP",
+	"F2":         "\x1bOQ",
+	"F3":         "\x1bOR",
+	"F4":         "\x1bOS",
+	"F5":         "\x1b[15~",
+	"F6":         "\x1b[17~",
+	"F7":         "\x1b[18~",
+	"F8":         "\x1b[19~",
+	"F9":         "\x1b[20~",
+	"F10":        "\x1b[21~",
+	"F11":        "\x1b[23~",
+	"F12":        "\x1b[24~",
+}
+
+// vt100Keys differiscono da ANSI-BBS: Backspace invia DEL e Home/End
+// usano le sequenze del keypad editing VT220.
+var vt100Keys = map[string]string{
+	"Backspace": "\x7f",
+	"Home":      "\x1b[1~",
+	"End":       "\x1b[4~",
+}
+
+// asciiKeys: solo caratteri di controllo; le frecce usano il "diamante"
+// WordStar (Ctrl+E/X/S/D), compreso dagli editor delle BBS.
+var asciiKeys = map[string]string{
+	"Enter":      "\r",
+	"Backspace":  "\b",
+	"Tab":        "\t",
+	"Escape":     "\x1b",
+	"ArrowUp":    "\x05",
+	"ArrowDown":  "\x18",
+	"ArrowLeft":  "\x13",
+	"ArrowRight": "\x04",
+	"Delete":     "\x7f",
+}
+
+// ─────────────────────────────────────────────
+// Varianti dei tasti
+// ─────────────────────────────────────────────
+//
+// Home, End, Delete e i tasti funzione non hanno sequenze universali: le
+// BBS scritte per un terminale preciso si aspettano le sue. Un KeyProfile
+// sostituisce quelle dell'emulazione per la BBS che lo richiede.
+
+// KeyProfile seleziona le sequenze dei tasti di editing e funzione.
+type KeyProfile string
+
+const (
+	// KeysDefault: le sequenze dell'emulazione
+	KeysDefault KeyProfile = ""
+	// KeysVT: tastiera VT220 (CSI n ~ per Home/End, DEL per Backspace)
+	KeysVT KeyProfile = "vt"
+	// KeysXterm: xterm (CSI H/F, SS3 in modalità applicazione)
+	KeysXterm KeyProfile = "xterm"
+	// KeysSCO: console SCO (CSI M...X per F1-F12, CSI I/G per pagina)
+	KeysSCO KeyProfile = "sco"
+)
+
+// KeyProfiles elenca le varianti disponibili.
+var KeyProfiles = []KeyProfile{KeysDefault, KeysVT, KeysXterm, KeysSCO}
+
+// ParseKeyProfile ritorna la variante di nome name ("" = dell'emulazione).
+func ParseKeyProfile(name string) (KeyProfile, error) {
+	for _, p := range KeyProfiles {
+		if string(p) == name {
+			return p, nil
+		}
+	}
+	return "", fmt.Errorf("variante dei tasti sconosciuta: %s", name)
+}
+
+// profileKeys sono le sequenze che ogni variante sostituisce.
+var profileKeys = map[KeyProfile]map[string]string{
+	KeysVT: {
+		"Backspace": "\x7f",
+		"Home":      "\x1b[1~",
+		"End":       "\x1b[4~",
+	},
+	KeysXterm: {
+		"Backspace": "\x7f",
+	},
+	KeysSCO: {
+		"Backspace": "\b",
+		"Home":      "\x1b[H",
+		"End":       "\x1b[F",
+		"PageUp":    "\x1b[I",
+		"PageDown":  "\x1b[G",
+		"Insert":    "\x1b[L",
+		"Delete":    "\x7f",
+		"F1":        "\x1b[M",
+		"F2":        "\x1b[N",
+		"F3":        "\x1b[O",
+		"F4":        "\x1b[P",
+		"F5":        "\x1b[Q",
+		"F6":        "\x1b[R",
+		"F7":        "\x1b[S",
+		"F8":        "\x1b[T",
+		"F9":        "\x1b[U",
+		"F10":       "\x1b[V",
+		"F11":       "\x1b[W",
+		"F12":       "\x1b[X",
+	},
+}
+
+// keypadKeys sono i tasti del tastierino numerico (KeyboardEvent.code):
+// il carattere in modalità numerica e il finale SS3 in modalità
+// applicazione (DECKPAM).
+var keypadKeys = map[string][2]string{
+	"Numpad0":        {"0", "p"},
+	"Numpad1":        {"1", "q"},
+	"Numpad2":        {"2", "r"},
+	"Numpad3":        {"3", "s"},
+	"Numpad4":        {"4", "t"},
+	"Numpad5":        {"5", "u"},
+	"Numpad6":        {"6", "v"},
+	"Numpad7":        {"7", "w"},
+	"Numpad8":        {"8", "x"},
+	"Numpad9":        {"9", "y"},
+	"NumpadMultiply": {"*", "j"},
+	"NumpadAdd":      {"+", "k"},
+	"NumpadSubtract": {"-", "m"},
+	"NumpadDecimal":  {".", "n"},
+	"NumpadDivide":   {"/", "o"},
+	"NumpadEnter":    {"\r", "M"},
+}
+
+// KeyModes sono gli stati del terminale che cambiano le sequenze dei
+// tasti: quelli chiesti dalla BBS (vedi Screen.AppCursorKeys e
+// Screen.AppKeypad) e la variante scelta per lei.
+type KeyModes struct {
+	AppCursor bool // DECCKM: frecce (e Home/End xterm) in SS3
+	AppKeypad bool // DECKPAM: tastierino in SS3
+	Profile   KeyProfile
+}
+
+// Key ritorna la sequenza da inviare per il tasto speciale name (nomi di
+// KeyboardEvent.key: "ArrowUp", "F1"...; per il tastierino numerico
+// KeyboardEvent.code: "Numpad1"...). Ritorna nil per i tasti senza
+// sequenza.
+func (e Emulation) Key(name string, modes KeyModes) []byte {
+	if e == EmulationASCII {
+		if seq, ok := asciiKeys[name]; ok {
+			return []byte(seq)
+		}
+		if k, ok := keypadKeys[name]; ok {
+			return []byte(k[0])
+		}
+		return nil
+	}
+
+	if k, ok := keypadKeys[name]; ok {
+		if modes.AppKeypad {
+			return []byte("\x1bO" + k[1])
+		}
+		return []byte(k[0])
+	}
+	if modes.AppCursor && (strings.HasPrefix(name, "Arrow") ||
+		(modes.Profile == KeysXterm && (name == "Home" || name == "End"))) {
+		// DECCKM: cursore in modalità applicazione (ESC O x)
+		seq := ansiBBSKeys[name]
+		return []byte{0x1B, 'O', seq[len(seq)-1]}
+	}
+	if seq, ok := profileKeys[modes.Profile][name]; ok {
+		return []byte(seq)
+	}
+	if e == EmulationVT100 {
+		if seq, ok := vt100Keys[name]; ok {
+			return []byte(seq)
+		}
+	}
+	if seq, ok := ansiBBSKeys[name]; ok {
+		return []byte(seq)
+	}
+	return nil
+}
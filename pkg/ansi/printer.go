@@ -0,0 +1,136 @@
+package ansi
+
+import "strings"
+
+// ─────────────────────────────────────────────
+// Stampante (media copy, CSI i)
+// ─────────────────────────────────────────────
+//
+// Le BBS con la funzione "stampa messaggio" usano le sequenze media copy
+// del VT100 per mandare il testo alla stampante del terminale:
+//
+//	CSI 5 i   printer controller: tutto ciò che segue va alla stampante,
+//	          non allo schermo, fino a CSI 4 i
+//	CSI ? 5 i auto print: le righe vanno anche in stampa quando il cursore
+//	          le lascia con un a capo, fino a CSI ? 4 i
+//	CSI 0 i   stampa lo schermo      CSI ? 1 i  stampa la riga del cursore
+//
+// Ogni stampa completa arriva a OnPrint come un unico lavoro. Senza
+// OnPrint i dati per la stampante vengono comunque tolti dallo schermo.
+
+// MaxPrintJob limita un lavoro di stampa; i dati oltre sono scartati.
+const MaxPrintJob = 256 * 1024
+
+// Terminatori del printer controller (il secondo con i controlli C1)
+const (
+	printerStop   = "\x1b[4i"
+	printerStopC1 = "\u009b4i"
+)
+
+// printerState è lo stato della stampante del terminale.
+type printerState struct {
+	controller bool            // CSI 5 i attivo
+	auto       bool            // CSI ? 5 i attivo
+	job        strings.Builder // lavoro in corso
+	pending    []rune          // possibile inizio del terminatore
+}
+
+// write aggiunge testo al lavoro in corso, entro MaxPrintJob.
+func (p *printerState) write(text string) {
+	if room := MaxPrintJob - p.job.Len(); room > 0 {
+		if len(text) > room {
+			text = text[:room]
+		}
+		p.job.WriteString(text)
+	}
+}
+
+// take ritorna il lavoro in corso e lo azzera.
+func (p *printerState) take() string {
+	job := p.job.String()
+	p.job.Reset()
+	return job
+}
+
+// Printing indica se il printer controller è attivo: il testo ricevuto
+// va alla stampante e lo schermo non cambia.
+func (s *Screen) Printing() bool {
+	return s.printer.controller
+}
+
+// mediaCopy esegue CSI Ps i (private = CSI ? Ps i).
+func (s *Screen) mediaCopy(mode int, private bool) {
+	p := &s.printer
+	switch {
+	case !private && mode == 0:
+		// Le righe vuote in fondo allo schermo non si stampano
+		s.emitPrint(strings.TrimRight(s.printRows(0, s.Rows), "\n") + "\n")
+	case !private && mode == 5:
+		p.controller = true
+		p.pending = p.pending[:0]
+	case private && mode == 1:
+		s.emitPrint(s.printRows(s.CursorY, s.CursorY+1))
+	case private && mode == 5:
+		p.auto = true
+	case private && mode == 4:
+		if p.auto {
+			p.auto = false
+			s.emitPrint(p.take())
+		}
+	}
+}
+
+// printerFeed passa un carattere alla stampante finché non arriva il
+// terminatore del printer controller.
+func (s *Screen) printerFeed(ch rune) {
+	p := &s.printer
+	p.pending = append(p.pending, ch)
+	pending := string(p.pending)
+	if strings.HasPrefix(printerStop, pending) || s.C1Controls && strings.HasPrefix(printerStopC1, pending) {
+		if pending == printerStop || pending == printerStopC1 {
+			p.controller = false
+			p.pending = p.pending[:0]
+			s.emitPrint(p.take())
+		}
+		return
+	}
+	// Non è il terminatore: solo ch può iniziarne uno nuovo
+	p.write(string(p.pending[:len(p.pending)-1]))
+	p.pending = p.pending[:0]
+	if ch == 0x1B || s.C1Controls && ch == 0x9B {
+		p.pending = append(p.pending, ch)
+	} else {
+		p.write(string(ch))
+	}
+}
+
+// autoPrintLine manda in stampa la riga del cursore (auto print).
+func (s *Screen) autoPrintLine() {
+	s.printer.write(s.printRows(s.CursorY, s.CursorY+1))
+}
+
+// printRows ritorna le righe [from, to) come testo, una per riga e senza
+// spazi finali.
+func (s *Screen) printRows(from, to int) string {
+	var b strings.Builder
+	for y := from; y < to; y++ {
+		var line strings.Builder
+		for _, cell := range s.Buffer[y] {
+			ch := cell.Char
+			if ch < 0x20 {
+				ch = ' '
+			}
+			line.WriteRune(ch)
+		}
+		b.WriteString(strings.TrimRight(line.String(), " "))
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// emitPrint consegna un lavoro di stampa a OnPrint.
+func (s *Screen) emitPrint(job string) {
+	if job != "" && s.OnPrint != nil {
+		s.OnPrint(job)
+	}
+}
@@ -0,0 +1,47 @@
+package ansi
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+)
+
+// ─────────────────────────────────────────────
+// Replay deterministico e hash dello schermo
+// ─────────────────────────────────────────────
+
+// FeedReader alimenta lo schermo con tutto il contenuto di r, letto come
+// testo UTF-8 (il formato dei log di sessione). Le sequenze spezzate tra
+// due letture vengono ricomposte, quindi il risultato non dipende da come
+// il flusso è suddiviso. Ritorna il primo errore di lettura diverso da EOF.
+func (s *Screen) FeedReader(r io.Reader) error {
+	br := bufio.NewReader(r)
+	for {
+		ch, _, err := br.ReadRune()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		s.process(ch)
+	}
+}
+
+// Hash ritorna un'impronta SHA-256 (esadecimale) di geometria, cursore e
+// contenuto di tutte le celle con i loro attributi. Due schermi con lo
+// stesso hash sono visivamente identici: serve a confrontare il risultato
+// di un replay con una schermata di riferimento ("golden").
+func (s *Screen) Hash() string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%dx%d@%d,%d,%t\n", s.Cols, s.Rows, s.CursorX, s.CursorY, s.CursorVisible)
+	for _, row := range s.Buffer {
+		for _, cell := range row {
+			fmt.Fprintf(h, "%d%+v", cell.Char, cell.Attr)
+		}
+		h.Write([]byte{'\n'})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,1225 @@
+// Package ansi implementa un emulatore terminale ANSI per BBS.
+//
+// Porting da AnsiScreen (bbs_client.py) → Go idiomatico.
+// Gestisce SGR (colori 16/256/TrueColor), posizionamento cursore,
+// cancellazione schermo, scroll e salvataggio cursore.
+//
+// Il package non dipende dal resto del client e può essere usato da altri
+// progetti (bot, gateway, archivi di ANSI art). Feed riceve testo già
+// decodificato; FeedCP437 riceve direttamente i byte CP437 delle BBS.
+//
+//	scr := ansi.NewScreen(80, 25)
+//	scr.OnResponse = func(b []byte) { conn.Write(b) } // risposte a DSR e DA
+//	scr.FeedCP437(data)
+//	for y, row := range scr.Buffer {
+//		for x, cell := range row {
+//			fg, bg, _ := scr.CellColors(cell) // RGB secondo palette e attributi
+//			draw(x, y, cell.Char, fg, bg)
+//		}
+//	}
+//
+// Screen non è sicuro per l'uso concorrente: Feed e la lettura di Buffer
+// vanno serializzate dal chiamante.
+package ansi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ─────────────────────────────────────────────
+// Costanti
+// ─────────────────────────────────────────────
+
+const (
+	DefaultFG = 7    // grigio chiaro
+	DefaultBG = 0    // nero
+	MaxCSIBuf = 1024 // lunghezza massima dei parametri di una sequenza CSI
+
+	// Dimensioni in pixel di una cella (font IBM VGA 8×16), per XTWINOPS
+	DefaultCellWidth  = 8
+	DefaultCellHeight = 16
+)
+
+// Palette è una tabella di 16 colori (R, G, B) per gli indici 0-15.
+type Palette [16][3]uint8
+
+// Palette IBM VGA 16 colori (R, G, B)
+var Palette16 = Palette{
+	{0, 0, 0},       //  0  Nero
+	{170, 0, 0},     //  1  Rosso
+	{0, 170, 0},     //  2  Verde
+	{170, 85, 0},    //  3  Marrone / Giallo scuro
+	{0, 0, 170},     //  4  Blu
+	{170, 0, 170},   //  5  Magenta
+	{0, 170, 170},   //  6  Ciano
+	{170, 170, 170}, //  7  Grigio chiaro (default fg)
+	{85, 85, 85},    //  8  Grigio scuro
+	{255, 85, 85},   //  9  Rosso chiaro
+	{85, 255, 85},   // 10  Verde chiaro
+	{255, 255, 85},  // 11  Giallo
+	{85, 85, 255},   // 12  Blu chiaro
+	{255, 85, 255},  // 13  Magenta chiaro
+	{85, 255, 255},  // 14  Ciano chiaro
+	{255, 255, 255}, // 15  Bianco
+}
+
+// PaletteXterm è la palette di default di xterm.
+var PaletteXterm = Palette{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+	{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+// PaletteAmiga approssima i colori della console Amiga Workbench.
+var PaletteAmiga = Palette{
+	{0, 0, 0}, {221, 0, 0}, {0, 221, 0}, {221, 221, 0},
+	{0, 0, 221}, {221, 0, 221}, {0, 221, 221}, {221, 221, 221},
+	{102, 102, 102}, {255, 102, 102}, {102, 255, 102}, {255, 255, 102},
+	{102, 102, 255}, {255, 102, 255}, {102, 255, 255}, {255, 255, 255},
+}
+
+// Palettes elenca le palette predefinite selezionabili per nome.
+var Palettes = map[string]*Palette{
+	"vga":   &Palette16,
+	"xterm": &PaletteXterm,
+	"amiga": &PaletteAmiga,
+}
+
+// ParsePalette costruisce una palette da 16 colori esadecimali
+// ("#rrggbb" o "rrggbb").
+func ParsePalette(colors []string) (*Palette, error) {
+	if len(colors) != 16 {
+		return nil, fmt.Errorf("servono 16 colori, ricevuti %d", len(colors))
+	}
+	var p Palette
+	for i, c := range colors {
+		c = strings.TrimPrefix(strings.TrimSpace(c), "#")
+		v, err := strconv.ParseUint(c, 16, 32)
+		if len(c) != 6 || err != nil {
+			return nil, fmt.Errorf("colore %d non valido: %q", i, colors[i])
+		}
+		p[i] = [3]uint8{uint8(v >> 16), uint8(v >> 8), uint8(v)}
+	}
+	return &p, nil
+}
+
+// Hex ritorna i 16 colori della palette in formato "#rrggbb".
+func (p *Palette) Hex() []string {
+	out := make([]string, len(p))
+	for i, c := range p {
+		out[i] = fmt.Sprintf("#%02x%02x%02x", c[0], c[1], c[2])
+	}
+	return out
+}
+
+// ─────────────────────────────────────────────
+// Color — colore flessibile (indice 0-255 o RGB)
+// ─────────────────────────────────────────────
+
+// Color rappresenta un colore che può essere un indice palette (0-255)
+// o un colore RGB diretto (TrueColor).
+type Color struct {
+	Index   int  // 0-255 per palette, -1 se è RGB
+	R, G, B uint8
+	IsRGB   bool
+}
+
+// IndexColor crea un Color da indice palette.
+func IndexColor(idx int) Color {
+	return Color{Index: idx}
+}
+
+// RGBColor crea un Color da valori RGB.
+func RGBColor(r, g, b uint8) Color {
+	return Color{Index: -1, R: r, G: g, B: b, IsRGB: true}
+}
+
+// ToRGB converte qualsiasi Color in valori RGB, applicando bold se fg.
+func (c Color) ToRGB(isFG, bold bool) (uint8, uint8, uint8) {
+	return c.ToRGBPalette(&Palette16, isFG, bold)
+}
+
+// ToRGBPalette è come ToRGB ma risolve gli indici 0-15 con la palette data.
+func (c Color) ToRGBPalette(pal *Palette, isFG, bold bool) (uint8, uint8, uint8) {
+	if c.IsRGB {
+		return c.R, c.G, c.B
+	}
+
+	idx := c.Index
+
+	// Bold su foreground standard → bright
+	if isFG && bold && idx >= 0 && idx <= 7 {
+		idx += 8
+	}
+
+	// 16 colori standard
+	if idx >= 0 && idx <= 15 {
+		return pal[idx][0], pal[idx][1], pal[idx][2]
+	}
+
+	// 216 colori (cubo 6×6×6): indici 16-231
+	if idx >= 16 && idx <= 231 {
+		idx -= 16
+		r := uint8((idx / 36) * 51)
+		g := uint8(((idx % 36) / 6) * 51)
+		b := uint8((idx % 6) * 51)
+		return r, g, b
+	}
+
+	// 24 livelli di grigio: indici 232-255
+	if idx >= 232 && idx <= 255 {
+		v := uint8(8 + (idx-232)*10)
+		return v, v, v
+	}
+
+	// Fallback
+	if isFG {
+		return pal[DefaultFG][0], pal[DefaultFG][1], pal[DefaultFG][2]
+	}
+	return 0, 0, 0
+}
+
+// ─────────────────────────────────────────────
+// BoldPolicy — resa dell'attributo bold
+// ─────────────────────────────────────────────
+
+// BoldPolicy stabilisce come viene reso l'attributo bold (SGR 1): come
+// colore bright (tradizione DOS/BBS), come font in grassetto o entrambi.
+type BoldPolicy int
+
+const (
+	BoldBoth   BoldPolicy = iota // bright + font bold (default storico)
+	BoldBright                   // solo colore bright
+	BoldFont                     // solo font bold, colore invariato
+)
+
+// String ritorna il nome della policy usato da frontend e impostazioni.
+func (p BoldPolicy) String() string {
+	switch p {
+	case BoldBright:
+		return "bright"
+	case BoldFont:
+		return "font"
+	}
+	return "both"
+}
+
+// ParseBoldPolicy converte un nome ("bright", "font", "both") in policy.
+// Valori sconosciuti ritornano BoldBoth e false.
+func ParseBoldPolicy(name string) (BoldPolicy, bool) {
+	switch name {
+	case "both":
+		return BoldBoth, true
+	case "bright":
+		return BoldBright, true
+	case "font":
+		return BoldFont, true
+	}
+	return BoldBoth, false
+}
+
+// Brightens ritorna true se il bold deve schiarire il foreground.
+func (p BoldPolicy) Brightens() bool {
+	return p != BoldFont
+}
+
+// UsesFont ritorna true se il bold deve usare il font in grassetto.
+func (p BoldPolicy) UsesFont() bool {
+	return p != BoldBright
+}
+
+// ─────────────────────────────────────────────
+// CellAttr — attributi grafici di una cella
+// ─────────────────────────────────────────────
+
+// UnderlineStyle è lo stile di sottolineatura (SGR 4:x).
+type UnderlineStyle int
+
+const (
+	UnderlineNone   UnderlineStyle = iota // 4:0
+	UnderlineSingle                       // 4:1 (o SGR 4)
+	UnderlineDouble                       // 4:2 (o SGR 21)
+	UnderlineCurly                        // 4:3
+	UnderlineDotted                       // 4:4
+	UnderlineDashed                       // 4:5
+)
+
+// String ritorna il nome dello stile per il frontend ("" se nessuno).
+func (u UnderlineStyle) String() string {
+	switch u {
+	case UnderlineSingle:
+		return "single"
+	case UnderlineDouble:
+		return "double"
+	case UnderlineCurly:
+		return "curly"
+	case UnderlineDotted:
+		return "dotted"
+	case UnderlineDashed:
+		return "dashed"
+	}
+	return ""
+}
+
+// CellAttr contiene gli attributi grafici di una cella del terminale.
+// Equivalente della classe CellAttr Python.
+type CellAttr struct {
+	FG        Color
+	BG        Color
+	Bold      bool
+	Blink     bool
+	Reverse   bool
+	Underline bool
+	Faint     bool // SGR 2, distinto dal non-bold
+	Italic    bool // SGR 3
+	Conceal   bool // SGR 8
+	Strike    bool // SGR 9
+
+	// Sottolineatura estesa (SGR 4:x, 58/59)
+	UnderlineStyle    UnderlineStyle
+	UnderlineColor    Color
+	HasUnderlineColor bool
+}
+
+// DefaultAttr ritorna un CellAttr con valori di default.
+func DefaultAttr() CellAttr {
+	return CellAttr{
+		FG: IndexColor(DefaultFG),
+		BG: IndexColor(DefaultBG),
+	}
+}
+
+// Copy ritorna una copia dell'attributo.
+func (a CellAttr) Copy() CellAttr {
+	return a // struct value → già una copia
+}
+
+// EffectiveBG ritorna lo sfondo effettivo della cella. In modalità iCE
+// colors il blink (SGR 5) non lampeggia ma seleziona lo sfondo bright,
+// come nella maggior parte della ANSI art.
+func (a CellAttr) EffectiveBG(ice bool) Color {
+	if ice && a.Blink && !a.BG.IsRGB && a.BG.Index >= 0 && a.BG.Index <= 7 {
+		return IndexColor(a.BG.Index + 8)
+	}
+	return a.BG
+}
+
+// EffectiveBlink ritorna true se la cella deve lampeggiare davvero
+// (in modalità iCE il blink è già stato convertito in sfondo bright).
+func (a CellAttr) EffectiveBlink(ice bool) bool {
+	return a.Blink && !ice
+}
+
+// ─────────────────────────────────────────────
+// Cell — una cella del terminale
+// ─────────────────────────────────────────────
+
+// Cell rappresenta una singola cella del terminale (carattere + attributi).
+type Cell struct {
+	Char rune
+	Attr CellAttr
+}
+
+// NewCell crea una cella vuota con attributi di default.
+func NewCell() Cell {
+	return Cell{Char: ' ', Attr: DefaultAttr()}
+}
+
+// ─────────────────────────────────────────────
+// Screen — emulatore terminale ANSI
+// ─────────────────────────────────────────────
+
+// Stati del parser
+const (
+	stateNormal = iota
+	stateESC    // ricevuto ESC
+	stateCSI    // ricevuto ESC[
+	stateOSC    // ricevuto ESC]
+)
+
+// Screen è l'emulatore terminale ANSI completo.
+// Equivalente della classe AnsiScreen Python.
+type Screen struct {
+	Cols, Rows int
+	CursorX    int
+	CursorY    int
+	Buffer     [][]Cell
+
+	// CursorVisible segue DECTCEM (CSI ?25 h/l)
+	CursorVisible bool
+
+	// Callback per risposte al server (DSR)
+	OnResponse func(data []byte)
+
+	// Emulation decide a quali richieste rispondere ("" = ANSI-BBS)
+	Emulation Emulation
+
+	// Callback per il campanello (BEL, 0x07)
+	OnBell func()
+
+	// Callback per i lavori di stampa (media copy, vedi printer.go)
+	OnPrint func(job string)
+
+	// Dimensioni in pixel di una cella, usate per i report CSI 14/15 t
+	CellWidth, CellHeight int
+
+	// IceColors: SGR 5 seleziona lo sfondo bright invece del blink
+	IceColors bool
+
+	// C1Controls: interpreta i controlli 8-bit U+0084/85/8D/9B (IND, NEL,
+	// RI, CSI). Il decoder deve lasciarli passare invece di mapparli su
+	// glifi CP437 (vedi IsC1Control).
+	C1Controls bool
+
+	// BoldPolicy: resa del bold (bright, font o entrambi)
+	BoldPolicy BoldPolicy
+
+	// Palette per gli indici 0-15 (nil = Palette16 VGA)
+	Palette *Palette
+
+	// Scrollback riceve le righe che escono dall'alto dello schermo
+	// (nil = nessuno scrollback). Reset non lo svuota.
+	Scrollback *Scrollback
+
+	attr   CellAttr
+	savedX int
+	savedY int
+	state  int
+	csiBuf strings.Builder
+
+	// Ultimo carattere stampato, per REP (CSI Ps b)
+	lastChar rune
+
+	// Intermedio CSI (es. '!' in CSI ! p)
+	csiInter rune
+	// Marcatore privato del CSI ('<', '=', '>' o '?'; 0 = nessuno)
+	csiMarker rune
+
+	// Modi e regioni (azzerati da RIS/DECSTR)
+	scrollTop    int    // prima riga della regione di scroll (0-based)
+	scrollBottom int    // ultima riga della regione di scroll (inclusa)
+	tabStops     []bool // tab stop per colonna
+	autoWrap     bool   // DECAWM
+	newlineMode  bool   // LNM: LF implica anche CR
+	appCursor    bool   // DECCKM: frecce in modalità applicazione
+	appKeypad    bool   // DECKPAM: tastierino in modalità applicazione
+
+	// Stampante del terminale (CSI i)
+	printer printerState
+}
+
+// ActivePalette ritorna la palette in uso (Palette16 se non impostata).
+func (s *Screen) ActivePalette() *Palette {
+	if s.Palette != nil {
+		return s.Palette
+	}
+	return &Palette16
+}
+
+// CellColors risolve i colori RGB effettivi di una cella (testo, sfondo,
+// sottolineatura) secondo palette, bold policy, iCE colors e reverse.
+// La sottolineatura senza colore proprio usa quello del testo.
+func (s *Screen) CellColors(cell Cell) (fg, bg, ul [3]uint8) {
+	attr := cell.Attr
+	pal := s.ActivePalette()
+	fg[0], fg[1], fg[2] = attr.FG.ToRGBPalette(pal, true, attr.Bold && s.BoldPolicy.Brightens())
+	bg[0], bg[1], bg[2] = attr.EffectiveBG(s.IceColors).ToRGBPalette(pal, false, false)
+	if attr.Reverse {
+		fg, bg = bg, fg
+	}
+	ul = fg
+	if attr.HasUnderlineColor {
+		ul[0], ul[1], ul[2] = attr.UnderlineColor.ToRGBPalette(pal, true, false)
+	}
+	return fg, bg, ul
+}
+
+// NewScreen crea uno Screen con le dimensioni date.
+func NewScreen(cols, rows int) *Screen {
+	s := &Screen{
+		Cols:       cols,
+		Rows:       rows,
+		CellWidth:  DefaultCellWidth,
+		CellHeight: DefaultCellHeight,
+		attr:       DefaultAttr(),
+	}
+	s.Buffer = s.newBuffer()
+	s.resetModes()
+	return s
+}
+
+func (s *Screen) newBuffer() [][]Cell {
+	buf := make([][]Cell, s.Rows)
+	for y := range buf {
+		buf[y] = s.newRow()
+	}
+	return buf
+}
+
+func (s *Screen) newRow() []Cell {
+	row := make([]Cell, s.Cols)
+	for x := range row {
+		row[x] = NewCell()
+	}
+	return row
+}
+
+// Reset riporta lo schermo allo stato iniziale (RIS, ESC c): buffer,
+// cursore, cursore salvato, attributi, stato del parser, modi, tab stop
+// e regione di scroll. La configurazione (palette, iCE, callback) resta.
+func (s *Screen) Reset() {
+	s.CursorX = 0
+	s.CursorY = 0
+	s.savedX = 0
+	s.savedY = 0
+	s.attr = DefaultAttr()
+	s.state = stateNormal
+	s.csiBuf.Reset()
+	s.csiInter = 0
+	s.csiMarker = 0
+	s.lastChar = 0
+	s.printer = printerState{}
+	s.Buffer = s.newBuffer()
+	s.resetModes()
+}
+
+// SoftReset esegue un soft reset (DECSTR, CSI ! p): azzera attributi,
+// modi, tab stop, regione di scroll e cursore salvato senza toccare il
+// contenuto dello schermo né la posizione del cursore.
+func (s *Screen) SoftReset() {
+	s.attr = DefaultAttr()
+	s.savedX = 0
+	s.savedY = 0
+	s.lastChar = 0
+	s.resetModes()
+}
+
+// resetModes riporta modi, tab stop e regione di scroll ai default.
+func (s *Screen) resetModes() {
+	s.scrollTop = 0
+	s.scrollBottom = s.Rows - 1
+	s.autoWrap = true // ANSI-BBS: a capo automatico sempre attivo
+	s.newlineMode = false
+	s.appCursor = false
+	s.appKeypad = false
+	s.CursorVisible = true
+	s.tabStops = make([]bool, s.Cols)
+	for x := 8; x < s.Cols; x += 8 {
+		s.tabStops[x] = true
+	}
+}
+
+// Resize cambia le dimensioni dello schermo mantenendo il contenuto
+// nell'angolo in alto a sinistra e riposizionando il cursore nei limiti.
+func (s *Screen) Resize(cols, rows int) {
+	if cols < 1 || rows < 1 || (cols == s.Cols && rows == s.Rows) {
+		return
+	}
+	old := s.Buffer
+	s.Cols, s.Rows = cols, rows
+	s.Buffer = s.newBuffer()
+	for y := 0; y < min(len(old), rows); y++ {
+		copy(s.Buffer[y], old[y])
+	}
+	s.CursorX = min(s.CursorX, cols-1)
+	s.CursorY = min(s.CursorY, rows-1)
+	s.savedX = min(s.savedX, cols-1)
+	s.savedY = min(s.savedY, rows-1)
+
+	// Le regioni dipendono dalla geometria: tornano ai default
+	wrap, visible := s.autoWrap, s.CursorVisible
+	s.resetModes()
+	s.autoWrap, s.CursorVisible = wrap, visible
+}
+
+// ─────────────────────────────────────────────
+// Feed — alimentazione testo
+// ─────────────────────────────────────────────
+
+// Feed processa una stringa di testo (già decodificata da CP437).
+func (s *Screen) Feed(text string) {
+	for _, ch := range text {
+		s.process(ch)
+	}
+}
+
+// IsC1Control ritorna true per i byte C1 a 8 bit gestiti dallo screen.
+func IsC1Control(b byte) bool {
+	return b == 0x84 || b == 0x85 || b == 0x8D || b == 0x9B
+}
+
+func (s *Screen) process(ch rune) {
+	if s.printer.controller {
+		s.printerFeed(ch)
+		return
+	}
+	if s.C1Controls && ch >= 0x80 && ch <= 0x9F && IsC1Control(byte(ch)) {
+		s.processC1(ch)
+		return
+	}
+
+	switch s.state {
+	case stateNormal:
+		switch {
+		case ch == 0x1B: // ESC
+			s.state = stateESC
+		case ch == 0x0D: // CR
+			s.CursorX = 0
+		case ch == 0x0A: // LF
+			if s.newlineMode {
+				s.CursorX = 0
+			}
+			s.lineFeed()
+		case ch == 0x08: // BS
+			if s.CursorX > 0 {
+				s.CursorX--
+			}
+		case ch == 0x09: // TAB
+			s.nextTabStop()
+		case ch == 0x07: // BEL
+			if s.OnBell != nil {
+				s.OnBell()
+			}
+		case ch >= 0x20: // stampabile
+			s.putChar(ch)
+		}
+
+	case stateESC:
+		switch ch {
+		case '[':
+			s.state = stateCSI
+			s.csiBuf.Reset()
+			s.csiInter = 0
+			s.csiMarker = 0
+		case ']':
+			s.state = stateOSC
+			s.csiBuf.Reset()
+		case 'D': // Index
+			s.lineFeed()
+			s.state = stateNormal
+		case 'M': // Reverse Index
+			s.reverseLF()
+			s.state = stateNormal
+		case 'E': // Next Line
+			s.CursorX = 0
+			s.lineFeed()
+			s.state = stateNormal
+		case '7': // Save cursor (DEC)
+			s.savedX = s.CursorX
+			s.savedY = s.CursorY
+			s.state = stateNormal
+		case '8': // Restore cursor (DEC)
+			s.CursorX = s.savedX
+			s.CursorY = s.savedY
+			s.state = stateNormal
+		case 'H': // Horizontal Tab Set (HTS)
+			if s.CursorX < s.Cols {
+				s.tabStops[s.CursorX] = true
+			}
+			s.state = stateNormal
+		case 'c': // Full reset (RIS)
+			s.Reset()
+		case '=': // Tastierino in modalità applicazione (DECKPAM)
+			s.appKeypad = true
+			s.state = stateNormal
+		case '>': // Tastierino numerico (DECKPNM)
+			s.appKeypad = false
+			s.state = stateNormal
+		default:
+			s.state = stateNormal
+		}
+
+	case stateCSI:
+		// Byte dei parametri (0x30-0x3F): cifre, ':', ';' e i marcatori
+		// privati < = > ? (es. CSI > c, il DA secondario)
+		if ch >= '0' && ch <= '?' {
+			if ch >= '<' && s.csiBuf.Len() == 0 {
+				s.csiMarker = ch
+			}
+			if s.csiBuf.Len() < MaxCSIBuf {
+				s.csiBuf.WriteRune(ch)
+			} else {
+				// Buffer troppo lungo → reset (FIND-006)
+				s.state = stateNormal
+				s.csiBuf.Reset()
+			}
+		} else if ch >= 0x20 && ch <= 0x2F {
+			// Byte intermedio (es. '!' di DECSTR): attendi il finale
+			s.csiInter = ch
+		} else {
+			s.execCSI(ch)
+			s.state = stateNormal
+		}
+
+	case stateOSC:
+		if ch == 0x07 || ch == 0x1B {
+			s.state = stateNormal
+		}
+	}
+}
+
+// processC1 esegue un controllo C1 a 8 bit come la forma ESC equivalente.
+// Come per ESC, un C1 interrompe qualsiasi sequenza in corso.
+func (s *Screen) processC1(ch rune) {
+	s.state = stateNormal
+	switch ch {
+	case 0x84: // IND = ESC D
+		s.lineFeed()
+	case 0x85: // NEL = ESC E
+		s.CursorX = 0
+		s.lineFeed()
+	case 0x8D: // RI = ESC M
+		s.reverseLF()
+	case 0x9B: // CSI = ESC [
+		s.state = stateCSI
+		s.csiBuf.Reset()
+		s.csiInter = 0
+		s.csiMarker = 0
+	}
+}
+
+// ─────────────────────────────────────────────
+// Carattere stampabile
+// ─────────────────────────────────────────────
+
+func (s *Screen) putChar(ch rune) {
+	if s.CursorX >= s.Cols {
+		if s.autoWrap {
+			s.CursorX = 0
+			s.lineFeed()
+		} else {
+			s.CursorX = s.Cols - 1
+		}
+	}
+	s.Buffer[s.CursorY][s.CursorX].Char = ch
+	s.Buffer[s.CursorY][s.CursorX].Attr = s.attr.Copy()
+	s.CursorX++
+	s.lastChar = ch
+}
+
+// repeatChar ripete l'ultimo carattere stampato n volte (REP).
+// Il conteggio è limitato a una schermata intera per evitare loop
+// enormi con parametri malevoli.
+func (s *Screen) repeatChar(n int) {
+	if s.lastChar == 0 {
+		return
+	}
+	n = min(max(1, n), s.Cols*s.Rows)
+	for range n {
+		s.putChar(s.lastChar)
+	}
+}
+
+// ─────────────────────────────────────────────
+// Scroll
+// ─────────────────────────────────────────────
+
+func (s *Screen) lineFeed() {
+	if s.printer.auto {
+		s.autoPrintLine()
+	}
+	if s.CursorY == s.scrollBottom {
+		s.scrollUp(1)
+	} else if s.CursorY < s.Rows-1 {
+		s.CursorY++
+	}
+}
+
+func (s *Screen) reverseLF() {
+	if s.CursorY == s.scrollTop {
+		s.scrollDown(1)
+	} else if s.CursorY > 0 {
+		s.CursorY--
+	}
+}
+
+// scrollUp sposta in alto di n righe la regione di scroll.
+func (s *Screen) scrollUp(n int) {
+	region := s.Buffer[s.scrollTop : s.scrollBottom+1]
+	n = min(n, len(region))
+	if s.Scrollback != nil && s.scrollTop == 0 {
+		for _, row := range region[:n] {
+			s.Scrollback.Push(row)
+		}
+	}
+	copy(region, region[n:])
+	for y := len(region) - n; y < len(region); y++ {
+		region[y] = s.newRow()
+	}
+}
+
+// scrollDown sposta in basso di n righe la regione di scroll.
+func (s *Screen) scrollDown(n int) {
+	region := s.Buffer[s.scrollTop : s.scrollBottom+1]
+	n = min(n, len(region))
+	copy(region[n:], region)
+	for y := 0; y < n; y++ {
+		region[y] = s.newRow()
+	}
+}
+
+// setScrollRegion imposta i margini (DECSTBM, 1-based) e porta il cursore
+// in home. Margini non validi vengono ignorati.
+func (s *Screen) setScrollRegion(top, bottom int) {
+	if top < 1 {
+		top = 1
+	}
+	if bottom < 1 || bottom > s.Rows {
+		bottom = s.Rows
+	}
+	if top >= bottom {
+		return
+	}
+	s.scrollTop = top - 1
+	s.scrollBottom = bottom - 1
+	s.CursorX = 0
+	s.CursorY = 0
+}
+
+// nextTabStop sposta il cursore al tab stop successivo (o a fine riga).
+func (s *Screen) nextTabStop() {
+	for x := s.CursorX + 1; x < s.Cols; x++ {
+		if s.tabStops[x] {
+			s.CursorX = x
+			return
+		}
+	}
+	s.CursorX = s.Cols - 1
+}
+
+// clearTabStops gestisce TBC: 0 = tab stop corrente, 3 = tutti.
+func (s *Screen) clearTabStops(mode int) {
+	switch mode {
+	case 0:
+		if s.CursorX < s.Cols {
+			s.tabStops[s.CursorX] = false
+		}
+	case 3:
+		clear(s.tabStops)
+	}
+}
+
+// respond invia una risposta alla BBS, se l'emulazione risponde alle
+// richieste di identificazione.
+func (s *Screen) respond(resp string) {
+	if s.OnResponse != nil && s.Emulation.answersProbes() {
+		s.OnResponse([]byte(resp))
+	}
+}
+
+// AppCursorKeys indica se la BBS ha chiesto le frecce in modalità
+// applicazione (DECCKM, CSI ?1 h).
+func (s *Screen) AppCursorKeys() bool {
+	return s.appCursor
+}
+
+// AppKeypad indica se la BBS ha chiesto il tastierino in modalità
+// applicazione (DECKPAM, ESC =).
+func (s *Screen) AppKeypad() bool {
+	return s.appKeypad
+}
+
+// windowReport risponde alle query XTWINOPS sulla geometria.
+func (s *Screen) windowReport(op int) {
+	// Estensione xterm: né il VT100 né il terminale ASCII la conoscono
+	if s.OnResponse == nil || s.Emulation == EmulationVT100 || s.Emulation == EmulationASCII {
+		return
+	}
+	var code, h, w int
+	switch op {
+	case 14, 15: // area testo / schermo in pixel
+		code, h, w = op-10, s.Rows*s.CellHeight, s.Cols*s.CellWidth
+	case 18, 19: // area testo / schermo in caratteri
+		code, h, w = op-10, s.Rows, s.Cols
+	default:
+		return
+	}
+	s.OnResponse([]byte("\x1b[" + strconv.Itoa(code) + ";" + strconv.Itoa(h) + ";" + strconv.Itoa(w) + "t"))
+}
+
+// setANSIMode gestisce i modi ANSI standard (CSI Pm h / l).
+func (s *Screen) setANSIMode(params []int, on bool) {
+	for _, p := range params {
+		switch p {
+		case 20: // LNM — Linefeed/Newline mode
+			s.newlineMode = on
+		}
+	}
+}
+
+// setMode gestisce i modi privati DEC (CSI ? Pm h / l).
+func (s *Screen) setMode(params []int, on bool) {
+	for _, p := range params {
+		switch p {
+		case 1: // DECCKM — frecce in modalità applicazione
+			s.appCursor = on
+		case 7: // DECAWM — a capo automatico
+			s.autoWrap = on
+		case 25: // DECTCEM — cursore visibile
+			s.CursorVisible = on
+		}
+	}
+}
+
+// ─────────────────────────────────────────────
+// Parsing parametri CSI
+// ─────────────────────────────────────────────
+
+func (s *Screen) parseParams(defaultVal int) []int {
+	raw := s.csiBuf.String()
+	raw = strings.TrimLeft(raw, "<=>?")
+
+	if raw == "" {
+		return []int{defaultVal}
+	}
+
+	parts := strings.Split(raw, ";")
+	result := make([]int, 0, len(parts))
+	for _, p := range parts {
+		// Sotto-parametri (4:3, 38:2::r:g:b): qui conta solo il primo
+		if i := strings.IndexByte(p, ':'); i >= 0 {
+			p = p[:i]
+		}
+		if p == "" {
+			result = append(result, defaultVal)
+		} else {
+			v, err := strconv.Atoi(p)
+			if err != nil {
+				result = append(result, defaultVal)
+			} else {
+				result = append(result, v)
+			}
+		}
+	}
+	return result
+}
+
+// parseSubParams ritorna i parametri CSI raggruppati con i rispettivi
+// sotto-parametri separati da ':' (es. "4:3;58:2::255:0:0"). I valori
+// mancanti valgono -1.
+func (s *Screen) parseSubParams() [][]int {
+	raw := strings.TrimLeft(s.csiBuf.String(), "<=>?")
+	if raw == "" {
+		return [][]int{{0}}
+	}
+	parts := strings.Split(raw, ";")
+	groups := make([][]int, len(parts))
+	for i, p := range parts {
+		subs := strings.Split(p, ":")
+		group := make([]int, len(subs))
+		for j, sub := range subs {
+			v, err := strconv.Atoi(sub)
+			if err != nil {
+				v = -1
+			}
+			group[j] = v
+		}
+		groups[i] = group
+	}
+	return groups
+}
+
+// ─────────────────────────────────────────────
+// Esecuzione comandi CSI
+// ─────────────────────────────────────────────
+
+func (s *Screen) execCSI(cmd rune) {
+	params := s.parseParams(0)
+
+	// Sequenze con intermedio
+	if s.csiInter != 0 {
+		if s.csiInter == '!' && cmd == 'p' { // Soft reset (DECSTR)
+			s.SoftReset()
+		}
+		return
+	}
+	// Le forme con < = > (DA secondario e terziario, modi di altri
+	// terminali) non hanno effetto
+	if s.csiMarker != 0 && s.csiMarker != '?' {
+		return
+	}
+	private := s.csiMarker == '?'
+
+	switch cmd {
+	case 'm': // SGR — colori e attributi
+		s.sgr(s.parseSubParams())
+
+	case 'H', 'f': // Cursor Position
+		r := max(1, safeParam(params, 0, 1))
+		c := max(1, safeParam(params, 1, 1))
+		s.CursorY = min(r-1, s.Rows-1)
+		s.CursorX = min(c-1, s.Cols-1)
+
+	case 'A': // Cursor Up
+		s.CursorY = max(0, s.CursorY-max(1, params[0]))
+
+	case 'B': // Cursor Down
+		s.CursorY = min(s.Rows-1, s.CursorY+max(1, params[0]))
+
+	case 'C': // Cursor Forward
+		s.CursorX = min(s.Cols-1, s.CursorX+max(1, params[0]))
+
+	case 'D': // Cursor Back
+		s.CursorX = max(0, s.CursorX-max(1, params[0]))
+
+	case 'E': // Cursor Next Line
+		s.CursorX = 0
+		s.CursorY = min(s.Rows-1, s.CursorY+max(1, params[0]))
+
+	case 'F': // Cursor Previous Line
+		s.CursorX = 0
+		s.CursorY = max(0, s.CursorY-max(1, params[0]))
+
+	case 'G': // Cursor Horizontal Absolute
+		s.CursorX = min(max(1, params[0])-1, s.Cols-1)
+
+	case 'J': // Erase in Display
+		s.eraseDisplay(params[0])
+
+	case 'K': // Erase in Line
+		s.eraseLine(params[0])
+
+	case 'S': // Scroll Up
+		s.scrollUp(max(1, params[0]))
+
+	case 'T': // Scroll Down
+		s.scrollDown(max(1, params[0]))
+
+	case 'r': // Set scrolling region (DECSTBM)
+		s.setScrollRegion(safeParam(params, 0, 1), safeParam(params, 1, s.Rows))
+
+	case 'g': // Tab clear (TBC)
+		s.clearTabStops(params[0])
+
+	case 'h', 'l': // Set/Reset mode
+		if private {
+			s.setMode(params, cmd == 'h')
+		} else {
+			s.setANSIMode(params, cmd == 'h')
+		}
+
+	case 'b': // Repeat preceding character (REP)
+		s.repeatChar(params[0])
+
+	case 's': // Save Cursor
+		s.savedX = s.CursorX
+		s.savedY = s.CursorY
+
+	case 'u': // Restore Cursor
+		s.CursorX = s.savedX
+		s.CursorY = s.savedY
+
+	case 'i': // Media copy (stampante)
+		s.mediaCopy(params[0], private)
+
+	case 't': // Window manipulation (XTWINOPS) — solo i report
+		s.windowReport(params[0])
+
+	case 'c': // Device Attributes (DA primario; >c e =c si fermano sopra)
+		if s.csiBuf.Len() == 0 || s.csiBuf.String() == "0" {
+			s.respond(s.Emulation.deviceAttributes())
+		}
+
+	case 'n': // Device Status Report (DSR)
+		if !s.Emulation.answersProbes() {
+			break
+		}
+		if params[0] == 6 && s.OnResponse != nil {
+			// Report Cursor Position (la BBS usa questo per verificare ANSI)
+			resp := []byte("\x1b[" + strconv.Itoa(s.CursorY+1) + ";" + strconv.Itoa(s.CursorX+1) + "R")
+			s.OnResponse(resp)
+		} else if params[0] == 5 && s.OnResponse != nil {
+			s.OnResponse([]byte("\x1b[0n")) // Terminal OK
+		}
+	}
+}
+
+// ─────────────────────────────────────────────
+// SGR (Select Graphic Rendition)
+// ─────────────────────────────────────────────
+
+func (s *Screen) sgr(groups [][]int) {
+	i := 0
+	n := len(groups)
+
+	for i < n {
+		p := max(groups[i][0], 0)
+
+		switch {
+		case p == 0: // Reset
+			s.attr = DefaultAttr()
+		case p == 1: // Bold
+			s.attr.Bold = true
+		case p == 2: // Faint
+			s.attr.Faint = true
+		case p == 3: // Italic
+			s.attr.Italic = true
+		case p == 4: // Underline (4:x = stile esteso)
+			style := UnderlineSingle
+			if len(groups[i]) > 1 && groups[i][1] >= 0 {
+				style = UnderlineStyle(min(groups[i][1], int(UnderlineDashed)))
+			}
+			s.attr.UnderlineStyle = style
+			s.attr.Underline = style != UnderlineNone
+		case p == 5 || p == 6: // Blink
+			s.attr.Blink = true
+		case p == 7: // Reverse
+			s.attr.Reverse = true
+		case p == 8: // Conceal
+			s.attr.Conceal = true
+		case p == 9: // Strikethrough
+			s.attr.Strike = true
+		case p == 21: // Double underline
+			s.attr.Underline = true
+			s.attr.UnderlineStyle = UnderlineDouble
+		case p == 22: // Normal intensity (né bold né faint)
+			s.attr.Bold = false
+			s.attr.Faint = false
+		case p == 23: // No italic
+			s.attr.Italic = false
+		case p == 24: // No underline
+			s.attr.Underline = false
+			s.attr.UnderlineStyle = UnderlineNone
+		case p == 25: // No blink
+			s.attr.Blink = false
+		case p == 27: // No reverse
+			s.attr.Reverse = false
+		case p == 28: // Reveal
+			s.attr.Conceal = false
+		case p == 29: // No strikethrough
+			s.attr.Strike = false
+
+		// Foreground standard (30-37)
+		case p >= 30 && p <= 37:
+			s.attr.FG = IndexColor(p - 30)
+
+		// Foreground esteso (38;5;n / 38;2;r;g;b / 38:2::r:g:b)
+		case p == 38:
+			if c, ok := extendedColor(groups, &i); ok {
+				s.attr.FG = c
+			}
+
+		case p == 39: // Default foreground
+			s.attr.FG = IndexColor(DefaultFG)
+
+		// Background standard (40-47)
+		case p >= 40 && p <= 47:
+			s.attr.BG = IndexColor(p - 40)
+
+		// Background esteso (48;5;n / 48;2;r;g;b / 48:2::r:g:b)
+		case p == 48:
+			if c, ok := extendedColor(groups, &i); ok {
+				s.attr.BG = c
+			}
+
+		case p == 49: // Default background
+			s.attr.BG = IndexColor(DefaultBG)
+
+		// Colore underline (58;5;n / 58;2;r;g;b / 58:2::r:g:b)
+		case p == 58:
+			if c, ok := extendedColor(groups, &i); ok {
+				s.attr.UnderlineColor = c
+				s.attr.HasUnderlineColor = true
+			}
+
+		case p == 59: // Default underline color
+			s.attr.HasUnderlineColor = false
+
+		// Bright foreground (90-97)
+		case p >= 90 && p <= 97:
+			s.attr.FG = IndexColor(p - 90 + 8)
+
+		// Bright background (100-107)
+		case p >= 100 && p <= 107:
+			s.attr.BG = IndexColor(p - 100 + 8)
+		}
+
+		i++
+	}
+}
+
+// extendedColor interpreta un colore esteso 38/48/58 a partire dal gruppo
+// groups[*i]. Supporta la forma con sotto-parametri (38:5:n, 38:2::r:g:b,
+// 38:2:r:g:b) e quella legacy con ';', nel qual caso avanza *i oltre i
+// parametri consumati.
+func extendedColor(groups [][]int, i *int) (Color, bool) {
+	if sub := groups[*i][1:]; len(sub) > 0 {
+		switch {
+		case sub[0] == 5 && len(sub) >= 2 && sub[1] >= 0:
+			return IndexColor(sub[1]), true
+		case sub[0] == 2 && len(sub) >= 5: // con colorspace ID
+			return RGBColor(uint8(sub[2]), uint8(sub[3]), uint8(sub[4])), true
+		case sub[0] == 2 && len(sub) == 4:
+			return RGBColor(uint8(sub[1]), uint8(sub[2]), uint8(sub[3])), true
+		}
+		return Color{}, false
+	}
+
+	// Forma legacy: parametri successivi separati da ';'
+	n := len(groups)
+	val := func(k int) int { return max(groups[k][0], 0) }
+	if *i+1 < n && val(*i+1) == 5 && *i+2 < n {
+		c := IndexColor(val(*i + 2))
+		*i += 2
+		return c, true
+	} else if *i+1 < n && val(*i+1) == 2 && *i+4 < n {
+		c := RGBColor(uint8(val(*i+2)), uint8(val(*i+3)), uint8(val(*i+4)))
+		*i += 4
+		return c, true
+	}
+	return Color{}, false
+}
+
+// ─────────────────────────────────────────────
+// Erase helpers
+// ─────────────────────────────────────────────
+
+func (s *Screen) eraseDisplay(mode int) {
+	switch mode {
+	case 0: // dal cursore alla fine
+		for x := s.CursorX; x < s.Cols; x++ {
+			s.Buffer[s.CursorY][x] = NewCell()
+		}
+		for y := s.CursorY + 1; y < s.Rows; y++ {
+			s.Buffer[y] = s.newRow()
+		}
+	case 1: // dall'inizio al cursore
+		for x := 0; x <= min(s.CursorX, s.Cols-1); x++ {
+			s.Buffer[s.CursorY][x] = NewCell()
+		}
+		for y := 0; y < s.CursorY; y++ {
+			s.Buffer[y] = s.newRow()
+		}
+	case 2: // tutto lo schermo
+		s.Buffer = s.newBuffer()
+	}
+}
+
+func (s *Screen) eraseLine(mode int) {
+	switch mode {
+	case 0: // dal cursore alla fine riga
+		for x := s.CursorX; x < s.Cols; x++ {
+			s.Buffer[s.CursorY][x] = NewCell()
+		}
+	case 1: // dall'inizio riga al cursore
+		for x := 0; x <= min(s.CursorX, s.Cols-1); x++ {
+			s.Buffer[s.CursorY][x] = NewCell()
+		}
+	case 2: // tutta la riga
+		s.Buffer[s.CursorY] = s.newRow()
+	}
+}
+
+// ─────────────────────────────────────────────
+// Helpers
+// ─────────────────────────────────────────────
+
+func safeParam(params []int, index, defaultVal int) int {
+	if index < len(params) {
+		return params[index]
+	}
+	return defaultVal
+}
@@ -0,0 +1,173 @@
+package ansi
+
+import "unicode/utf8"
+
+// ─────────────────────────────────────────────
+// Scrollback
+// ─────────────────────────────────────────────
+
+// Scrollback conserva le righe uscite dall'alto dello schermo, fino a una
+// capacità fissa: oltre, ogni riga nuova prende il posto della più
+// vecchia. Le righe sono compatte (testo UTF-8 più run di attributi) e gli
+// attributi sono condivisi fra tutte le righe, così anche decine di
+// migliaia di righe occupano una memoria prevedibile. Gli spazi finali
+// con gli attributi di default non vengono conservati.
+type Scrollback struct {
+	lines []scrollLine // anello: la riga più vecchia è in lines[head]
+	head  int
+	n     int
+
+	attrs []CellAttr // attributi usati dalle righe
+	ids   map[CellAttr]uint32
+	limit int // oltre questo numero di attributi si compatta la tabella
+}
+
+type scrollLine struct {
+	text []byte // caratteri in UTF-8, riusato quando la riga è sostituita
+	runs []attrRun
+}
+
+// attrRun sono n celle consecutive con gli attributi attrs[attr].
+type attrRun struct {
+	n    uint16
+	attr uint32
+}
+
+// minScrollAttrs è la dimensione minima della tabella degli attributi
+// prima di una compattazione.
+const minScrollAttrs = 4096
+
+// NewScrollback crea uno scrollback di capacity righe (0 = disattivato).
+func NewScrollback(capacity int) *Scrollback {
+	return &Scrollback{
+		lines: make([]scrollLine, max(capacity, 0)),
+		ids:   make(map[CellAttr]uint32),
+		limit: minScrollAttrs,
+	}
+}
+
+// Cap ritorna la capacità in righe.
+func (b *Scrollback) Cap() int { return len(b.lines) }
+
+// Len ritorna le righe conservate.
+func (b *Scrollback) Len() int { return b.n }
+
+// Clear svuota lo scrollback, mantenendo la capacità.
+func (b *Scrollback) Clear() {
+	for i := range b.lines {
+		b.lines[i] = scrollLine{}
+	}
+	b.head, b.n = 0, 0
+	b.attrs = nil
+	clear(b.ids)
+	b.limit = minScrollAttrs
+}
+
+// SetCapacity cambia la capacità, conservando le righe più recenti.
+func (b *Scrollback) SetCapacity(capacity int) {
+	capacity = max(capacity, 0)
+	if capacity == len(b.lines) {
+		return
+	}
+	keep := min(b.n, capacity)
+	lines := make([]scrollLine, capacity)
+	for i := range keep {
+		lines[i] = b.lines[b.slot(b.n-keep+i)]
+	}
+	b.lines, b.head, b.n = lines, 0, keep
+}
+
+// slot ritorna la posizione nell'anello della riga i (0 = più vecchia).
+func (b *Scrollback) slot(i int) int {
+	return (b.head + i) % len(b.lines)
+}
+
+// Push aggiunge in fondo la riga row.
+func (b *Scrollback) Push(row []Cell) {
+	if len(b.lines) == 0 {
+		return
+	}
+	var line *scrollLine
+	if b.n < len(b.lines) {
+		line = &b.lines[b.slot(b.n)]
+		b.n++
+	} else {
+		line = &b.lines[b.head]
+		b.head = (b.head + 1) % len(b.lines)
+	}
+
+	blank := NewCell()
+	end := len(row)
+	for end > 0 && row[end-1] == blank {
+		end--
+	}
+	line.text, line.runs = line.text[:0], line.runs[:0]
+	for _, cell := range row[:end] {
+		line.text = appendChar(line.text, cell.Char)
+		id := b.attrID(cell.Attr)
+		if k := len(line.runs) - 1; k >= 0 && line.runs[k].attr == id && line.runs[k].n < 0xFFFF {
+			line.runs[k].n++
+			continue
+		}
+		line.runs = append(line.runs, attrRun{n: 1, attr: id})
+	}
+	if len(b.attrs) > b.limit {
+		b.compact()
+	}
+}
+
+// appendChar aggiunge r in UTF-8; i controlli diventano spazi, perché
+// la riga si ricostruisce contando un carattere per cella.
+func appendChar(text []byte, r rune) []byte {
+	if r < 0x20 {
+		r = ' '
+	}
+	return append(text, string(r)...)
+}
+
+// attrID ritorna l'indice di attr nella tabella condivisa.
+func (b *Scrollback) attrID(attr CellAttr) uint32 {
+	id, ok := b.ids[attr]
+	if !ok {
+		id = uint32(len(b.attrs))
+		b.attrs = append(b.attrs, attr)
+		b.ids[attr] = id
+	}
+	return id
+}
+
+// compact ricostruisce la tabella degli attributi con quelli ancora usati
+// dalle righe conservate: senza, l'arte truecolor la farebbe crescere per
+// tutta la sessione.
+func (b *Scrollback) compact() {
+	old := b.attrs
+	b.attrs = nil
+	clear(b.ids)
+	for i := range b.n {
+		line := &b.lines[b.slot(i)]
+		for k := range line.runs {
+			line.runs[k].attr = b.attrID(old[line.runs[k].attr])
+		}
+	}
+	b.limit = max(2*len(b.attrs), minScrollAttrs)
+}
+
+// Line ritorna le celle della riga i (0 = più vecchia), senza gli spazi
+// finali.
+func (b *Scrollback) Line(i int) []Cell {
+	if i < 0 || i >= b.n {
+		return nil
+	}
+	line := &b.lines[b.slot(i)]
+	cells := make([]Cell, 0, len(line.text))
+	text := line.text
+	for _, run := range line.runs {
+		attr := b.attrs[run.attr]
+		for range run.n {
+			r, size := utf8.DecodeRune(text)
+			text = text[size:]
+			cells = append(cells, Cell{Char: r, Attr: attr})
+		}
+	}
+	return cells
+}
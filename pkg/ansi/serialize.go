@@ -0,0 +1,169 @@
+package ansi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ─────────────────────────────────────────────
+// Serializzazione dello schermo in ANSI
+// ─────────────────────────────────────────────
+
+// Serialize ritorna un flusso ANSI che ricostruisce lo schermo: dato a uno
+// Screen appena resettato delle stesse dimensioni ne riproduce caratteri,
+// attributi, posizione del cursore e attributi correnti. Modi, regione di
+// scroll e tab stop non sono inclusi.
+func (s *Screen) Serialize() string {
+	var b strings.Builder
+	b.Grow(s.Cols * s.Rows * 2)
+	last := ""
+	for y, row := range s.Buffer {
+		// Posizionamento esplicito: nessun affidamento sull'a capo automatico
+		fmt.Fprintf(&b, "\x1b[%d;1H", y+1)
+		for _, cell := range row {
+			if sgr := SGR(cell.Attr); sgr != last {
+				b.WriteString(sgr)
+				last = sgr
+			}
+			ch := cell.Char
+			if ch < 0x20 || ch >= 0x7F && ch < 0xA0 { // controlli C0/C1
+				ch = ' '
+			}
+			b.WriteRune(ch)
+		}
+	}
+	if s.CursorX >= s.Cols {
+		// A capo in sospeso: si riscrive l'ultima cella della riga
+		cell := s.Buffer[s.CursorY][s.Cols-1]
+		fmt.Fprintf(&b, "\x1b[%d;%dH%s%c", s.CursorY+1, s.Cols, SGR(cell.Attr), cell.Char)
+	} else {
+		fmt.Fprintf(&b, "\x1b[%d;%dH", s.CursorY+1, s.CursorX+1)
+	}
+	b.WriteString(SGR(s.attr)) // attributi correnti per il testo successivo
+	if !s.CursorVisible {
+		b.WriteString("\x1b[?25l")
+	}
+	return b.String()
+}
+
+// SGR ritorna la sequenza SGR completa (a partire da un reset) che
+// seleziona gli attributi a.
+func SGR(a CellAttr) string {
+	var b strings.Builder
+	b.WriteString("\x1b[0")
+	flags := []struct {
+		on   bool
+		code string
+	}{
+		{a.Bold, "1"}, {a.Faint, "2"}, {a.Italic, "3"}, {a.Blink, "5"},
+		{a.Reverse, "7"}, {a.Conceal, "8"}, {a.Strike, "9"},
+	}
+	for _, f := range flags {
+		if f.on {
+			b.WriteString(";" + f.code)
+		}
+	}
+	if a.Underline {
+		switch a.UnderlineStyle {
+		case UnderlineNone, UnderlineSingle:
+			b.WriteString(";4")
+		default:
+			b.WriteString(";4:" + strconv.Itoa(int(a.UnderlineStyle)))
+		}
+	}
+	b.WriteString(sgrColor(a.FG, 30, 90, 38))
+	b.WriteString(sgrColor(a.BG, 40, 100, 48))
+	if a.HasUnderlineColor {
+		b.WriteString(sgrColor(a.UnderlineColor, -1, -1, 58))
+	}
+	b.WriteByte('m')
+	return b.String()
+}
+
+// sgrColor codifica un colore con la forma più corta disponibile: base
+// (30-37/40-47), bright (90-97/100-107), 256 colori o RGB. base e bright
+// negativi forzano la forma estesa (colore della sottolineatura).
+func sgrColor(c Color, base, bright, ext int) string {
+	switch {
+	case c.IsRGB:
+		return fmt.Sprintf(";%d;2;%d;%d;%d", ext, c.R, c.G, c.B)
+	case base >= 0 && c.Index >= 0 && c.Index <= 7:
+		return ";" + strconv.Itoa(base+c.Index)
+	case bright >= 0 && c.Index >= 8 && c.Index <= 15:
+		return ";" + strconv.Itoa(bright+c.Index-8)
+	default:
+		return fmt.Sprintf(";%d;5;%d", ext, c.Index)
+	}
+}
+
+// ─────────────────────────────────────────────
+// Selezione
+// ─────────────────────────────────────────────
+
+// Region ritorna le celle dalla (x0, y0) alla (x1, y1) comprese, riga per
+// riga come una selezione del terminale: la prima riga parte da x0,
+// l'ultima finisce a x1, quelle in mezzo sono intere. Gli estremi possono
+// essere in qualsiasi ordine; gli spazi vuoti in fondo alle righe sono
+// tolti.
+func (s *Screen) Region(x0, y0, x1, y1 int) [][]Cell {
+	if y1 < y0 || y1 == y0 && x1 < x0 {
+		x0, y0, x1, y1 = x1, y1, x0, y0
+	}
+	if y0 < 0 {
+		x0, y0 = 0, 0
+	}
+	if y1 >= len(s.Buffer) {
+		x1, y1 = s.Cols-1, len(s.Buffer)-1
+	}
+	var out [][]Cell
+	for y := y0; y <= y1; y++ {
+		row := s.Buffer[y]
+		from, to := 0, len(row)
+		if y == y0 {
+			from = min(max(x0, 0), len(row))
+		}
+		if y == y1 {
+			to = min(max(x1+1, from), len(row))
+		}
+		cells := row[from:to]
+		for len(cells) > 0 && blankCell(cells[len(cells)-1]) {
+			cells = cells[:len(cells)-1]
+		}
+		out = append(out, append([]Cell(nil), cells...))
+	}
+	return out
+}
+
+// blankCell dice se la cella è uno spazio senza sfondo né attributi
+// visibili.
+func blankCell(c Cell) bool {
+	return (c.Char == ' ' || c.Char == 0) && c.Attr.BG == IndexColor(DefaultBG) &&
+		!c.Attr.Reverse && !c.Attr.Underline && !c.Attr.Strike
+}
+
+// SerializeCells ritorna le righe di celle (vedi Region) come testo ANSI
+// da incollare: attributi come sequenze SGR, righe separate da CR LF e un
+// reset finale.
+func SerializeCells(rows [][]Cell) string {
+	var b strings.Builder
+	last := SGR(DefaultAttr())
+	for y, row := range rows {
+		if y > 0 {
+			b.WriteString("\r\n")
+		}
+		for _, cell := range row {
+			if sgr := SGR(cell.Attr); sgr != last {
+				b.WriteString(sgr)
+				last = sgr
+			}
+			ch := cell.Char
+			if ch < 0x20 || ch >= 0x7F && ch < 0xA0 {
+				ch = ' '
+			}
+			b.WriteRune(ch)
+		}
+	}
+	b.WriteString("\x1b[0m")
+	return b.String()
+}
@@ -0,0 +1,165 @@
+package ansi
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ─────────────────────────────────────────────
+// Stripper — testo semplice da flusso ANSI
+// ─────────────────────────────────────────────
+
+// Stripper converte un flusso ANSI in testo semplice riga per riga, per
+// le trascrizioni delle sessioni: elimina le sequenze di escape, applica
+// backspace, trasforma i salti cursore in fine riga e gli spostamenti in
+// avanti (CSI C) in spazi, toglie gli spazi finali e comprime le righe
+// vuote consecutive. Lo stato è mantenuto tra una chiamata e l'altra, così
+// le sequenze spezzate tra due blocchi vengono riconosciute.
+type Stripper struct {
+	state   int
+	params  strings.Builder
+	line    []rune
+	pendCR  bool // CR non ancora seguito da LF
+	blanks  int  // righe vuote consecutive già emesse
+	started bool // emessa almeno una riga non vuota
+}
+
+// Stati del parser (separati da quelli dello Screen)
+const (
+	stripNormal = iota
+	stripESC
+	stripCSI
+	stripString // OSC, DCS, SOS, PM, APC: fino a BEL o ST
+)
+
+// maxStripLine spezza le righe patologicamente lunghe (niente fine riga).
+const maxStripLine = 4096
+
+// Write elabora un blocco di testo e ritorna le righe completate.
+func (st *Stripper) Write(text string) string {
+	var out strings.Builder
+	for _, ch := range text {
+		switch st.state {
+		case stripNormal:
+			st.normal(ch, &out)
+		case stripESC:
+			switch ch {
+			case '[':
+				st.state = stripCSI
+				st.params.Reset()
+			case ']', 'P', 'X', '^', '_':
+				st.state = stripString
+			default:
+				st.state = stripNormal // sequenza a due caratteri
+			}
+		case stripCSI:
+			if ch >= 0x40 && ch <= 0x7E {
+				st.csi(ch, &out)
+				st.state = stripNormal
+			} else if st.params.Len() < MaxCSIBuf {
+				st.params.WriteRune(ch)
+			}
+		case stripString:
+			switch ch {
+			case 0x07:
+				st.state = stripNormal
+			case 0x1B:
+				st.state = stripESC // ESC \ (ST)
+			}
+		}
+	}
+	return out.String()
+}
+
+// Flush ritorna l'eventuale riga incompleta, a fine sessione.
+func (st *Stripper) Flush() string {
+	var out strings.Builder
+	if len(st.line) > 0 {
+		st.endLine(&out)
+	}
+	return out.String()
+}
+
+// Pending ritorna la riga corrente non ancora completata (ad esempio un
+// prompt in attesa di input).
+func (st *Stripper) Pending() string {
+	return string(st.line)
+}
+
+func (st *Stripper) normal(ch rune, out *strings.Builder) {
+	if st.pendCR && ch != '\n' {
+		st.pendCR = false
+		if len(st.line) > 0 {
+			st.endLine(out) // CR isolato: la riga viene riscritta, la teniamo
+		}
+	}
+	switch {
+	case ch == 0x1B:
+		st.state = stripESC
+	case ch == '\r':
+		st.pendCR = true
+	case ch == '\n':
+		st.pendCR = false
+		st.endLine(out)
+	case ch == '\b':
+		if n := len(st.line); n > 0 {
+			st.line = st.line[:n-1]
+		}
+	case ch == '\t':
+		st.line = append(st.line, '\t')
+	case ch < 0x20 || ch == 0x7F:
+		// altri controlli: nessuna resa nel testo
+	default:
+		st.line = append(st.line, ch)
+		if len(st.line) >= maxStripLine {
+			st.endLine(out)
+		}
+	}
+}
+
+// csi gestisce le sequenze che influiscono sull'impaginazione del testo.
+func (st *Stripper) csi(final rune, out *strings.Builder) {
+	switch final {
+	case 'H', 'f', 'J', 'B', 'E', 'F', 'A':
+		// Salti del cursore e cancellazioni: chiudono la riga corrente
+		if len(st.line) > 0 {
+			st.endLine(out)
+		}
+	case 'C':
+		n, err := strconv.Atoi(st.params.String())
+		if err != nil || n < 1 {
+			n = 1
+		}
+		n = min(n, maxStripLine-len(st.line)-1)
+		for i := 0; i < n; i++ {
+			st.line = append(st.line, ' ')
+		}
+	}
+}
+
+// endLine emette la riga corrente senza spazi finali, comprimendo le
+// righe vuote ripetute (e quelle iniziali) in al massimo una.
+func (st *Stripper) endLine(out *strings.Builder) {
+	line := strings.TrimRight(string(st.line), " \t")
+	st.line = st.line[:0]
+	if line == "" {
+		if !st.started || st.blanks > 0 {
+			return
+		}
+		st.blanks++
+		out.WriteByte('\n')
+		return
+	}
+	st.started = true
+	st.blanks = 0
+	out.WriteString(line)
+	out.WriteByte('\n')
+}
+
+// Text ritorna il contenuto finale dello schermo come testo semplice: una
+// riga per riga dello schermo, senza spazi finali né righe vuote in fondo.
+// A differenza dello Stripper tiene conto dei movimenti del cursore, perché
+// legge il risultato della resa invece del flusso.
+func (s *Screen) Text() string {
+	return strings.TrimRight(s.printRows(0, s.Rows), "\n")
+}
@@ -0,0 +1,40 @@
+package ansi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ─────────────────────────────────────────────
+// Resa su terminali truecolor
+// ─────────────────────────────────────────────
+
+// TrueColorSGR ritorna la sequenza SGR completa per gli attributi della
+// cella, con i colori già risolti (palette, bold, iCE, reverse) in RGB a
+// 24 bit, per mostrare lo schermo su un terminale moderno.
+func (s *Screen) TrueColorSGR(cell Cell) string {
+	fg, bg, _ := s.CellColors(cell)
+	attr := cell.Attr
+	var b strings.Builder
+	b.WriteString("\x1b[0")
+	if attr.Bold && s.BoldPolicy.UsesFont() {
+		b.WriteString(";1")
+	}
+	if attr.Faint {
+		b.WriteString(";2")
+	}
+	if attr.Italic {
+		b.WriteString(";3")
+	}
+	if attr.Underline {
+		b.WriteString(";4")
+	}
+	if attr.EffectiveBlink(s.IceColors) {
+		b.WriteString(";5")
+	}
+	if attr.Strike {
+		b.WriteString(";9")
+	}
+	fmt.Fprintf(&b, ";38;2;%d;%d;%d;48;2;%d;%d;%dm", fg[0], fg[1], fg[2], bg[0], bg[1], bg[2])
+	return b.String()
+}
@@ -0,0 +1,97 @@
+package telnet
+
+import (
+	"bytes"
+	"errors"
+	"log"
+)
+
+// ─────────────────────────────────────────────
+// Controllo di flusso XON/XOFF
+// ─────────────────────────────────────────────
+//
+// Alcuni gateway (modem virtuali, porte seriali esposte in telnet)
+// mandano ancora XOFF e XON. Con FlowControl i due caratteri vengono
+// tolti dai dati ricevuti e XOFF sospende l'invio: Send trattiene i dati
+// fino a XON e l'upload ZMODEM si ferma tra un blocco e l'altro. Il
+// protocollo ZMODEM codifica XON e XOFF con ZDLE, quindi quelli in chiaro
+// sono sempre controllo di flusso.
+
+// Caratteri del controllo di flusso.
+const (
+	XON  byte = 0x11 // DC1, riprendi
+	XOFF byte = 0x13 // DC3, sospendi
+)
+
+// MaxHeld sono i byte che Send trattiene durante XOFF.
+const MaxHeld = 64 << 10
+
+// errHeldFull è l'errore di Send quando i dati trattenuti superano MaxHeld.
+var errHeldFull = errors.New("invio sospeso dalla BBS (XOFF): buffer pieno")
+
+// Paused indica se la BBS ha sospeso l'invio con XOFF.
+func (c *Connection) Paused() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.xoff
+}
+
+// flowControl toglie XON e XOFF da data (in place) e ne applica
+// l'effetto; conta l'ultimo ricevuto.
+func (c *Connection) flowControl(data []byte) []byte {
+	if !c.FlowControl || bytes.IndexByte(data, XON) < 0 && bytes.IndexByte(data, XOFF) < 0 {
+		return data
+	}
+	out := data[:0]
+	for _, b := range data {
+		switch b {
+		case XOFF:
+			c.setXOFF(true)
+		case XON:
+			c.setXOFF(false)
+		default:
+			out = append(out, b)
+		}
+	}
+	return out
+}
+
+// setXOFF sospende o riprende l'invio; alla ripresa parte quanto Send ha
+// trattenuto.
+func (c *Connection) setXOFF(on bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.xoff == on {
+		return
+	}
+	c.xoff = on
+	if c.Debug {
+		log.Printf("[TELNET] XOFF=%v, %d byte trattenuti", on, len(c.held))
+	}
+	if on {
+		return
+	}
+	c.flow.Broadcast()
+	if held := c.held; len(held) > 0 && c.connected {
+		c.held = nil
+		c.write(held)
+	}
+}
+
+// resetFlow riprende l'invio e scarta i dati trattenuti (connessione
+// aperta o chiusa). Chiamare con c.mu acquisito.
+func (c *Connection) resetFlow() {
+	c.xoff = false
+	c.held = nil
+	c.flow.Broadcast()
+}
+
+// waitXON attende che la BBS riprenda l'invio o che la connessione si
+// chiuda. Usata dall'upload ZMODEM tra un blocco e l'altro.
+func (c *Connection) waitXON() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for c.xoff && c.connected {
+		c.flow.Wait()
+	}
+}
@@ -0,0 +1,220 @@
+package telnet
+
+import (
+	"log"
+	"slices"
+	"sync"
+	"time"
+)
+
+// ─────────────────────────────────────────────
+// Abbonamenti agli eventi
+// ─────────────────────────────────────────────
+
+// publishWait è quanto si attende un abbonato con la coda piena prima di
+// scartare i dati (BUG-003: evita drop silenzioso durante burst). Un
+// abbonato che resta indietro non viene più atteso finché non torna ad
+// avere posto, così non rallenta gli altri.
+const publishWait = 100 * time.Millisecond
+
+// Subscription riceve su C gli eventi scelti con Subscribe, ciascuno con
+// la propria coda: un abbonato lento perde i propri dati (e riceve
+// EventDataDropped quando si rimette in pari) senza fermare gli altri.
+// Gli eventi che non sono dati non vengono mai scartati.
+type Subscription struct {
+	C <-chan Event
+
+	ch     chan Event
+	kinds  uint64 // bit per EventType (0 = tutti)
+	buffer int    // blocchi di dati in coda prima di scartarli
+	conn   *Connection
+	done   chan struct{} // chiuso da Close
+	wake   chan struct{} // nuovi eventi in coda (pump)
+	room   chan struct{} // un blocco di dati ha lasciato la coda (admit)
+
+	mu    sync.Mutex
+	queue []Event // eventi in attesa di C, in ordine
+	data  int     // EventData in queue
+
+	// Stato sotto Connection.pubMu
+	lagging bool  // coda piena: niente attesa finché non torna posto
+	dropped int64 // byte scartati da segnalare
+}
+
+// Subscribe crea un abbonamento agli eventi di tipo kinds (nessuno = tutti,
+// dati compresi) con una coda di buffer blocchi di dati (0 = quella data a
+// NewBuffered). Gli abbonamenti restano validi anche dopo una
+// riconnessione; Close li termina.
+func (c *Connection) Subscribe(buffer int, kinds ...EventType) *Subscription {
+	if buffer <= 0 {
+		buffer = c.buffer
+	}
+	ch := make(chan Event)
+	sub := &Subscription{
+		C: ch, ch: ch, buffer: buffer, conn: c,
+		done: make(chan struct{}),
+		wake: make(chan struct{}, 1),
+		room: make(chan struct{}, 1),
+	}
+	for _, k := range kinds {
+		sub.kinds |= 1 << k
+	}
+	go sub.pump()
+	c.subMu.Lock()
+	// publish scorre la lista senza subMu: mai modificarla sul posto
+	c.subs = append(slices.Clip(c.subs), sub)
+	c.subMu.Unlock()
+	return sub
+}
+
+// Close termina l'abbonamento: gli eventi ancora in coda vanno persi e C
+// viene chiuso.
+func (s *Subscription) Close() {
+	c := s.conn
+	c.subMu.Lock()
+	defer c.subMu.Unlock()
+	if i := slices.Index(c.subs, s); i >= 0 {
+		c.subs = slices.Delete(slices.Clone(c.subs), i, i+1)
+		close(s.done)
+	}
+}
+
+// Len ritorna quanti blocchi di dati sono in coda.
+func (s *Subscription) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data
+}
+
+// Cap ritorna quanti blocchi di dati la coda tiene prima di scartarli.
+func (s *Subscription) Cap() int {
+	return s.buffer
+}
+
+// wants dice se l'abbonato riceve gli eventi di tipo t.
+func (s *Subscription) wants(t EventType) bool {
+	return s.kinds == 0 || s.kinds&(1<<t) != 0
+}
+
+// publish consegna e agli abbonati interessati. Gli invii sono in fila
+// (pubMu), così ogni abbonato vede gli eventi nello stesso ordine; l'attesa
+// di un abbonato lento non blocca Subscribe né Close.
+func (c *Connection) publish(e Event) {
+	c.pubMu.Lock()
+	defer c.pubMu.Unlock()
+	c.subMu.Lock()
+	subs := c.subs
+	c.subMu.Unlock()
+	for _, s := range subs {
+		if s.wants(e.Type) {
+			c.deliver(s, e)
+		}
+	}
+}
+
+// deliver accoda e per l'abbonato s. Chiamare con pubMu acquisito.
+func (c *Connection) deliver(s *Subscription, e Event) {
+	if e.Type == EventData && !s.admit(len(e.Data)) {
+		if c.Debug {
+			log.Printf("[TELNET] Coda piena, drop dati (%d bytes)", len(e.Data))
+		}
+		return
+	}
+	s.push(e)
+}
+
+// admit attende posto in coda per un blocco di n byte: al più
+// publishWait, e per nulla se l'abbonato è già in ritardo. Senza posto
+// conta i byte scartati e ritorna false. Chiamare con pubMu acquisito.
+func (s *Subscription) admit(n int) bool {
+	var timeout <-chan time.Time
+	for s.full() {
+		if s.lagging {
+			if s.wants(EventDataDropped) {
+				s.dropped += int64(n)
+			}
+			return false
+		}
+		if timeout == nil {
+			timeout = time.After(publishWait)
+		}
+		select {
+		case <-s.room:
+		case <-timeout:
+			s.lagging = true
+		case <-s.done:
+			return false
+		}
+	}
+	s.lagging = false
+	return true
+}
+
+// full dice se la coda ha già buffer blocchi di dati.
+func (s *Subscription) full() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data >= s.buffer
+}
+
+// push mette e in coda, preceduto dalla segnalazione dei dati scartati
+// in precedenza. Chiamare con pubMu acquisito.
+func (s *Subscription) push(e Event) {
+	s.mu.Lock()
+	if s.dropped > 0 {
+		s.queue = append(s.queue, Event{Type: EventDataDropped, Bytes: s.dropped})
+		s.dropped = 0
+	}
+	s.queue = append(s.queue, e)
+	if e.Type == EventData {
+		s.data++
+	}
+	s.mu.Unlock()
+	notify(s.wake)
+}
+
+// pump consegna su C gli eventi in coda finché l'abbonamento non viene
+// chiuso. Un blocco di dati lascia la coda solo dopo la consegna, così la
+// coda non supera mai buffer blocchi.
+func (s *Subscription) pump() {
+	defer close(s.ch)
+	for {
+		s.mu.Lock()
+		if len(s.queue) == 0 {
+			s.mu.Unlock()
+			select {
+			case <-s.wake:
+				continue
+			case <-s.done:
+				return
+			}
+		}
+		e := s.queue[0]
+		s.mu.Unlock()
+
+		select {
+		case s.ch <- e:
+		case <-s.done:
+			return
+		}
+
+		s.mu.Lock()
+		s.queue[0] = Event{}
+		s.queue = s.queue[1:]
+		if e.Type == EventData {
+			s.data--
+		}
+		s.mu.Unlock()
+		if e.Type == EventData {
+			notify(s.room)
+		}
+	}
+}
+
+// notify segnala ch (capacità 1) senza bloccare.
+func notify(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
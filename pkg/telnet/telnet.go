@@ -0,0 +1,1084 @@
+// Package telnet implementa una connessione Telnet con negoziazione IAC
+// completa per BBS (NAWS, TTYPE, ECHO, SGA, BINARY).
+//
+// Porting da bbs_client.py (Python/PyQt5) → Go idiomatico.
+// Thread Python → goroutine, signal/slot Qt → channels.
+//
+// I dati ricevuti (senza comandi IAC, EventData) e gli eventi di
+// connessione e dei trasferimenti ZMODEM (avviati automaticamente)
+// arrivano agli abbonati creati con Subscribe, ognuno sul proprio canale;
+// un abbonato va letto finché la connessione è aperta o chiuso con Close:
+//
+//	c := telnet.New()
+//	c.SetDownloadDir("downloads")
+//	sub := c.Subscribe(0) // tutti gli eventi
+//	defer sub.Close()
+//	if err := c.Connect("bbs.example.org", 23); err != nil {
+//		return err
+//	}
+//	for ev := range sub.C {
+//		switch ev.Type {
+//		case telnet.EventData: // byte della BBS (di solito CP437)
+//			scr.FeedCP437(ev.Data)
+//		case telnet.EventDisconnected, telnet.EventError:
+//			return nil
+//		}
+//	}
+package telnet
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rj45lab/bbs-client-go/pkg/zmodem"
+)
+
+// ─────────────────────────────────────────────
+// Costanti protocollo Telnet (RFC 854)
+// ─────────────────────────────────────────────
+
+// Comandi e opzioni Telnet negoziati dal client
+const (
+	IAC    byte = 255
+	DONT   byte = 254
+	DO     byte = 253
+	WONT   byte = 252
+	WILL   byte = 251
+	SB     byte = 250
+	SE     byte = 240
+	NOP    byte = 241
+	NAWS   byte = 31
+	TTYPE  byte = 24
+	ECHO   byte = 1
+	SGA    byte = 3
+	BINARY byte = 0
+)
+
+// Configurazione di default
+const (
+	DefaultHost    = "bbs.olografix.org"
+	DefaultPort    = 23
+	DefaultCols    = 80
+	DefaultRows    = 25
+	ConnectTimeout = 15 * time.Second
+	CancelTimeout  = 2 * time.Second // attesa massima di CancelZmodem
+	RecvBufSize    = 8192
+
+	// DefaultDataBuffer è la coda degli abbonati creata da New, in eventi
+	DefaultDataBuffer = 256
+)
+
+// TermType inviato durante la negoziazione TTYPE (default di Connection.TermType)
+var TermType = []byte("ANSI")
+
+// ─────────────────────────────────────────────
+// Connection — connessione Telnet verso BBS
+// ─────────────────────────────────────────────
+
+// Connection gestisce la connessione TCP/Telnet verso la BBS.
+// Equivalente Go di TelnetConnection(QObject) nel codice Python.
+//
+// Il pattern è: goroutine di ricezione → canali degli abbonati (vedi
+// Subscribe) per dati ed eventi, invece di signal/slot Qt.
+type Connection struct {
+	// Configurazione terminale
+	Cols int
+	Rows int
+
+	// Tipo di terminale dichiarato in TTYPE
+	TermType []byte
+
+	// Debug
+	Debug bool
+
+	// Attese dei trasferimenti ZMODEM (zero = zmodem.DefaultTimeouts)
+	ZmodemTimeouts zmodem.Timeouts
+	// Cosa fare dei file offerti dalla BBS ("" = DownloadAlways)
+	Downloads DownloadPolicy
+	// Tipi di file bloccati o segnalati (nil = zmodem.DefaultFilePolicy)
+	FilePolicy *zmodem.FilePolicy
+	// Rispetta XON/XOFF della BBS e li toglie dai dati (vedi flow.go)
+	FlowControl bool
+	// Apre la connessione TCP, ad esempio attraverso un proxy (nil =
+	// connessione diretta)
+	Dialer Dialer
+	// Connessione senza protocollo telnet (ad esempio SSH): niente
+	// comandi IAC né regole NVT, le dimensioni passano da Resizer
+	Raw bool
+
+	conn      net.Conn
+	mu        sync.Mutex
+	connected bool
+	stopCh    chan struct{}
+
+	sent atomic.Int64 // byte inviati (BytesSent)
+
+	// Controllo di flusso, sotto mu
+	xoff bool       // la BBS ha sospeso l'invio
+	held []byte     // dati di Send trattenuti fino a XON
+	flow *sync.Cond // segnalata alla ripresa e alla chiusura
+
+	// ZMODEM state. Trasferimento in corso sotto mu: lo avviano recvLoop e
+	// StartZmodemUpload, lo chiudono i callback OnFinished
+	zmodemReceiver *zmodem.Receiver
+	zmodemSender   *zmodem.Sender
+	zmodemActive   bool
+	zmodemDetect   zmodem.Detector    // riconosce ZRQINIT anche a cavallo di due recv
+	wake           atomic.Bool        // recvLoop deve ricalcolare la scadenza (wakeRecv)
+	offerAnswer    chan bool          // risposta all'offerta in attesa (AnswerOffer)
+	zmodemCancel   chan chan struct{} // annullamento in attesa (CancelZmodem)
+	downloadDir    string
+
+	// BUG-004: buffer riporto per sequenze IAC incomplete tra recv
+	iacRemainder []byte
+
+	// Richieste di negoziazione del server, in ordine ("DO NAWS", ...)
+	negotiation []string
+	// Il server ha chiesto NAWS: i cambi di dimensione vanno comunicati
+	naws bool
+
+	// Modo BINARY (RFC 856) per i due versi. Senza, i dati seguono le
+	// regole NVT (RFC 854): CR è seguito da LF o NUL
+	binaryIn  bool // la BBS invia in binario (WILL BINARY); solo recvLoop
+	binaryOut bool // inviamo in binario (DO BINARY); sotto mu
+	crPending bool // l'ultimo byte ricevuto era CR (NVT); solo recvLoop
+
+	// Abbonati agli eventi (pubsub.go) e loro coda di default
+	pubMu  sync.Mutex // un publish alla volta
+	subMu  sync.Mutex
+	subs   []*Subscription
+	buffer int
+}
+
+// Dialer apre la connessione TCP verso la BBS; net.Dialer è la
+// connessione diretta.
+type Dialer interface {
+	DialContext(ctx context.Context, network, addr string) (net.Conn, error)
+}
+
+// Resizer è una connessione che comunica da sé le dimensioni del
+// terminale (un canale SSH): SetSize la usa al posto di NAWS.
+type Resizer interface {
+	Resize(cols, rows int) error
+}
+
+// EventType identifica il tipo di evento di connessione
+type EventType int
+
+const (
+	EventConnected      EventType = iota // connessione stabilita
+	EventDisconnected                    // chiusa dal server o da Disconnect
+	EventError                           // errore di connessione (Message)
+	EventZmodemStarted                   // filename, filesize
+	EventZmodemProgress                  // bytes, total, speed
+	EventZmodemFinished                  // filepath, success
+	EventZmodemError                     // error message
+	EventDataDropped                     // coda dell'abbonato piena: Bytes scartati
+	EventData                            // dati ricevuti (Data), senza comandi IAC
+	EventZmodemOffer                     // file offerto: filename, filesize (vedi AnswerOffer)
+	EventZmodemPolicy                    // file bloccato o segnalato: filename, message (zmodem.Verdict)
+	EventPhase                           // fase della connessione in corso (Message: Phase*)
+)
+
+// Fasi della connessione, pubblicate con EventPhase prima di EventConnected
+const (
+	PhaseResolving   = "resolving"   // risoluzione del nome (solo connessione diretta)
+	PhaseConnecting  = "connecting"  // apertura della connessione TCP (o del proxy)
+	PhaseNegotiating = "negotiating" // TCP aperto, parte la negoziazione telnet
+)
+
+// DownloadPolicy decide se i file offerti dalla BBS con ZMODEM vengono
+// scaricati.
+type DownloadPolicy string
+
+const (
+	DownloadAlways DownloadPolicy = "always" // subito, senza chiedere
+	DownloadAsk    DownloadPolicy = "ask"    // EventZmodemOffer, poi AnswerOffer
+	DownloadNever  DownloadPolicy = "never"  // rifiutati con ZSKIP
+)
+
+// Event rappresenta un evento di connessione
+type Event struct {
+	Type    EventType
+	Message string
+	// Dati ricevuti (EventData)
+	Data []byte
+	// Campi extra per eventi ZMODEM
+	Filename string
+	Filepath string
+	Filesize int64
+	Bytes    int64
+	Speed    float64
+	Success  bool
+}
+
+// New crea una nuova Connection con configurazione di default.
+func New() *Connection {
+	return NewBuffered(DefaultDataBuffer)
+}
+
+// NewBuffered crea una Connection i cui abbonati tengono in coda fino a
+// buffer eventi (blocchi di dati compresi) prima di scartarli.
+func NewBuffered(buffer int) *Connection {
+	// Directory download: ./downloads relativa all'eseguibile
+	exe, _ := os.Executable()
+	dlDir := filepath.Join(filepath.Dir(exe), "downloads")
+
+	c := &Connection{
+		Cols:         DefaultCols,
+		Rows:         DefaultRows,
+		TermType:     TermType,
+		stopCh:       make(chan struct{}),
+		offerAnswer:  make(chan bool, 1),
+		zmodemCancel: make(chan chan struct{}, 1),
+		downloadDir:  dlDir,
+		buffer:       max(buffer, 1),
+	}
+	c.flow = sync.NewCond(&c.mu)
+	return c
+}
+
+// SetDownloadDir imposta la directory di download.
+func (c *Connection) SetDownloadDir(dir string) {
+	c.downloadDir = dir
+}
+
+// BytesSent ritorna i byte inviati alla BBS da quando la Connection è
+// stata creata (comandi telnet compresi).
+func (c *Connection) BytesSent() int64 {
+	return c.sent.Load()
+}
+
+// Connected ritorna true se la connessione è attiva.
+func (c *Connection) Connected() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.connected
+}
+
+// Connect apre la connessione TCP verso host:port e avvia la goroutine
+// di ricezione. Equivalente di connect_to() nel codice Python.
+func (c *Connection) Connect(host string, port int) error {
+	return c.ConnectContext(context.Background(), host, port)
+}
+
+// ConnectContext è Connect annullabile con ctx. Le fasi attraversate
+// arrivano agli abbonati come EventPhase; se ctx viene annullato ritorna
+// context.Canceled senza pubblicare EventError.
+func (c *Connection) ConnectContext(parent context.Context, host string, port int) error {
+	addr := net.JoinHostPort(host, strconv.Itoa(port))
+
+	if c.Debug {
+		log.Printf("[TELNET] Connessione a %s...", addr)
+	}
+
+	ctx, cancel := context.WithTimeout(parent, ConnectTimeout)
+	defer cancel()
+	var d Dialer = &net.Dialer{}
+	targets := []string{addr}
+	if c.Dialer != nil {
+		d = c.Dialer // il proxy risolve il nome da sé
+	} else if net.ParseIP(host) == nil {
+		c.publish(Event{Type: EventPhase, Message: PhaseResolving})
+		ips, err := net.DefaultResolver.LookupHost(ctx, host)
+		if err != nil {
+			return c.dialFailed(parent, err)
+		}
+		targets = targets[:0]
+		for _, ip := range ips {
+			targets = append(targets, net.JoinHostPort(ip, strconv.Itoa(port)))
+		}
+	}
+	c.publish(Event{Type: EventPhase, Message: PhaseConnecting})
+	var conn net.Conn
+	var err error
+	for _, target := range targets {
+		if conn, err = d.DialContext(ctx, "tcp", target); err == nil || ctx.Err() != nil {
+			break
+		}
+	}
+	if err != nil {
+		return c.dialFailed(parent, err)
+	}
+	c.publish(Event{Type: EventPhase, Message: PhaseNegotiating})
+
+	c.mu.Lock()
+	c.conn = conn
+	c.connected = true
+	c.stopCh = make(chan struct{})
+	stop := c.stopCh
+	c.negotiation = nil
+	c.naws = false
+	c.binaryIn, c.binaryOut, c.crPending = false, false, false
+	c.resetFlow()
+	cols, rows := c.Cols, c.Rows
+	c.mu.Unlock()
+
+	if r, ok := conn.(Resizer); ok {
+		r.Resize(cols, rows)
+	}
+	c.publish(Event{Type: EventConnected, Message: addr})
+
+	// Goroutine di ricezione (equivalente di _recv_loop in Python)
+	go c.recvLoop(conn, stop)
+
+	return nil
+}
+
+// dialFailed segnala agli abbonati una connessione non riuscita, tranne
+// quando l'ha annullata il chiamante.
+func (c *Connection) dialFailed(parent context.Context, err error) error {
+	if parent.Err() == context.Canceled {
+		return context.Canceled
+	}
+	c.publish(Event{Type: EventError, Message: err.Error()})
+	return err
+}
+
+// Disconnect chiude la connessione. Equivalente di disconnect() Python.
+func (c *Connection) Disconnect() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.connected {
+		return
+	}
+
+	c.connected = false
+	close(c.stopCh)
+	c.resetFlow()
+
+	if c.conn != nil {
+		c.conn.Close()
+		c.conn = nil
+	}
+}
+
+// Send invia al server i dati dell'utente. Equivalente di send() Python.
+// I byte IAC vengono raddoppiati e, senza BINARY, i CR seguono le regole
+// NVT (vedi encodeData). Dopo un XOFF (con FlowControl) i dati vengono
+// trattenuti e partono al XON.
+func (c *Connection) Send(data []byte) error {
+	return c.send(data, true)
+}
+
+// SendData invia dati già pronti per la BBS, ad esempio l'uscita di un
+// programma collegato: come Send raddoppia gli IAC, ma lascia i CR intatti.
+func (c *Connection) SendData(data []byte) error {
+	return c.send(data, false)
+}
+
+// send invia data come Send; solo con nvt applica le regole NVT sui CR,
+// così i protocolli come ZMODEM passano intatti. Gli IAC vengono comunque
+// raddoppiati, tranne su una connessione Raw.
+func (c *Connection) send(data []byte, nvt bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.connected || c.conn == nil {
+		return fmt.Errorf("non connesso")
+	}
+	if !c.Raw {
+		data = encodeData(data, nvt && !c.binaryOut)
+	}
+	if c.xoff {
+		if len(c.held)+len(data) > MaxHeld {
+			return errHeldFull
+		}
+		c.held = append(c.held, data...)
+		return nil
+	}
+	return c.write(data)
+}
+
+// write scrive data sulla connessione. Chiamare con c.mu acquisito e la
+// connessione aperta.
+func (c *Connection) write(data []byte) error {
+	n, err := c.conn.Write(data)
+	c.sent.Add(int64(n))
+	if err != nil {
+		c.connected = false
+		c.resetFlow()
+		go c.publish(Event{Type: EventDisconnected, Message: err.Error()})
+		return err
+	}
+	return nil
+}
+
+// ─────────────────────────────────────────────
+// Loop di ricezione (goroutine)
+// ─────────────────────────────────────────────
+
+// recvLoop legge da conn finché la connessione non cade o Disconnect non
+// chiude stop (e conn, sbloccando la lettura). Le letture non hanno
+// scadenza, salvo quella del trasferimento ZMODEM in corso.
+func (c *Connection) recvLoop(conn net.Conn, stop chan struct{}) {
+	buf := make([]byte, RecvBufSize)
+
+	for {
+		// Controlla se dobbiamo fermarci
+		select {
+		case <-stop:
+			return
+		default:
+		}
+
+		// Zero = nessuna scadenza: la lettura attende i dati. Se nel
+		// frattempo è partito un trasferimento la scadenza va rifatta
+		conn.SetReadDeadline(c.zmodemDeadline())
+		if c.wake.Swap(false) {
+			conn.SetReadDeadline(c.zmodemDeadline())
+		}
+
+		n, err := conn.Read(buf)
+		c.tickZmodem(time.Now())
+		c.answerOffer()
+		c.cancelZmodem()
+		if err != nil {
+			if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+				continue
+			}
+			// Connessione persa
+			c.mu.Lock()
+			wasConnected := c.connected
+			c.connected = false
+			c.resetFlow()
+			c.mu.Unlock()
+
+			if wasConnected {
+				c.publish(Event{
+					Type:    EventDisconnected,
+					Message: err.Error(),
+				})
+			}
+			return
+		}
+
+		if n == 0 {
+			c.mu.Lock()
+			c.connected = false
+			c.resetFlow()
+			c.mu.Unlock()
+			c.publish(Event{
+				Type:    EventDisconnected,
+				Message: "Connessione chiusa dal server",
+			})
+			return
+		}
+
+		// Processa protocollo Telnet (rimuovi/gestisci IAC)
+		var clean []byte
+		if c.Raw {
+			clean = c.flowControl(bytes.Clone(buf[:n]))
+		} else {
+			clean = c.flowControl(c.processTelnet(buf[:n]))
+		}
+
+		if len(clean) == 0 {
+			continue
+		}
+
+		// ── ZMODEM: se attivo, devia dati al protocollo ──
+		if active, rx, tx := c.zmodemState(); active {
+			if rx != nil && rx.State != zmodem.RxIdle && rx.State != zmodem.RxDone {
+				rx.Feed(clean)
+			} else if tx != nil && tx.Active() {
+				tx.Feed(clean)
+			} else {
+				// ZMODEM finito, torna al terminale
+				c.setZmodem(nil, nil)
+				c.emitData(clean)
+			}
+			continue
+		}
+
+		// ── ZMODEM: auto-detect (anche a cavallo di due recv) ──
+		if start, ok := c.zmodemDetect.Feed(clean); ok {
+			if c.Debug {
+				log.Printf("[ZMODEM] *** DETECT! Avvio download")
+			}
+			// Il testo prima dell'header (es. il prompt "rz") va al terminale
+			if n := len(clean) - len(start); n > 0 {
+				c.emitData(clean[:n])
+			}
+			c.startZmodemDownload(start)
+			continue
+		}
+
+		// Invia dati puliti agli abbonati
+		c.emitData(clean)
+	}
+}
+
+func (c *Connection) emitData(data []byte) {
+	c.publish(Event{Type: EventData, Data: data})
+}
+
+func (c *Connection) emitEvent(e Event) {
+	c.publish(e)
+}
+
+// ─────────────────────────────────────────────
+// ZMODEM integration
+// ─────────────────────────────────────────────
+
+func (c *Connection) zmodemSendData(data []byte) {
+	c.send(data, false)
+}
+
+func (c *Connection) zmodemLog(msg string) {
+	if c.Debug {
+		log.Printf("[ZMODEM] %s", msg)
+	}
+}
+
+func (c *Connection) startZmodemDownload(initialData []byte) {
+	os.MkdirAll(c.downloadDir, 0700)
+
+	rx := zmodem.NewReceiver(c.downloadDir, c.zmodemSendData, c.zmodemLog)
+	rx.Timeouts = c.zmodemTimeouts()
+	if c.FilePolicy != nil {
+		rx.Policy = *c.FilePolicy
+	}
+	rx.OnPolicy = func(filename string, verdict zmodem.Verdict) {
+		c.emitEvent(Event{Type: EventZmodemPolicy, Filename: filename, Message: verdict.String()})
+	}
+
+	rx.OnStart = func(filename string, filesize int64) {
+		c.emitEvent(Event{Type: EventZmodemStarted, Filename: filename, Filesize: filesize})
+	}
+	rx.OnProgress = func(received, total int64, speed float64) {
+		c.emitEvent(Event{Type: EventZmodemProgress, Bytes: received, Filesize: total, Speed: speed})
+	}
+	rx.OnComplete = func(fp string) {
+		c.emitEvent(Event{Type: EventZmodemFinished, Filepath: fp, Success: true})
+	}
+	rx.OnError = func(msg string) {
+		c.emitEvent(Event{Type: EventZmodemError, Message: msg})
+	}
+	rx.OnFinished = func() {
+		c.setZmodem(nil, nil)
+	}
+	switch c.Downloads {
+	case DownloadAsk:
+		// Risposte rimaste da un'offerta precedente non valgono più
+		select {
+		case <-c.offerAnswer:
+		default:
+		}
+		rx.OnOffer = func(filename string, filesize int64) {
+			c.emitEvent(Event{Type: EventZmodemOffer, Filename: filename, Filesize: filesize})
+		}
+	case DownloadNever:
+		rx.OnOffer = func(filename string, filesize int64) {
+			c.emitEvent(Event{Type: EventZmodemError, Message: "Download rifiutato: " + filename})
+			rx.Skip()
+		}
+	}
+
+	c.setZmodem(rx, nil)
+	rx.Start(initialData)
+}
+
+// StartZmodemUpload avvia upload ZMODEM di un file.
+func (c *Connection) StartZmodemUpload(filepath string) {
+	tx := zmodem.NewSender(c.zmodemSendData, c.zmodemLog)
+	tx.Timeouts = c.zmodemTimeouts()
+
+	tx.OnStart = func(filename string, filesize int64) {
+		c.emitEvent(Event{Type: EventZmodemStarted, Filename: filename, Filesize: filesize})
+	}
+	tx.OnProgress = func(sent, total int64, speed float64) {
+		c.emitEvent(Event{Type: EventZmodemProgress, Bytes: sent, Filesize: total, Speed: speed})
+	}
+	tx.OnComplete = func(fp string) {
+		c.emitEvent(Event{Type: EventZmodemFinished, Filepath: fp, Success: true})
+	}
+	tx.OnError = func(msg string) {
+		c.emitEvent(Event{Type: EventZmodemError, Message: msg})
+	}
+	tx.OnFinished = func() {
+		c.setZmodem(nil, nil)
+	}
+	tx.OnWait = c.wakeRecv
+	tx.Throttle = c.waitXON
+
+	c.setZmodem(nil, tx)
+	tx.StartUpload(filepath)
+	c.wakeRecv()
+}
+
+// AnswerOffer risponde all'ultimo EventZmodemOffer: accept avvia la
+// ricezione del file, altrimenti la BBS passa al successivo.
+func (c *Connection) AnswerOffer(accept bool) {
+	select {
+	case <-c.offerAnswer:
+	default:
+	}
+	select {
+	case c.offerAnswer <- accept:
+	default: // un'altra risposta è arrivata nel frattempo
+	}
+	c.wakeRecv()
+}
+
+// answerOffer consegna al receiver la risposta di AnswerOffer, dalla
+// goroutine di ricezione.
+func (c *Connection) answerOffer() {
+	select {
+	case accept := <-c.offerAnswer:
+		_, rx, _ := c.zmodemState()
+		switch {
+		case rx == nil:
+		case accept:
+			rx.Accept()
+		default:
+			rx.Skip()
+		}
+	default:
+	}
+}
+
+// wakeRecv interrompe la lettura in corso, così recvLoop ricalcola la
+// scadenza dopo l'avvio di un trasferimento.
+func (c *Connection) wakeRecv() {
+	c.wake.Store(true)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn != nil {
+		c.conn.SetReadDeadline(time.Now())
+	}
+}
+
+// zmodemTimeouts ritorna le attese configurate, con i default per i
+// campi lasciati a zero.
+func (c *Connection) zmodemTimeouts() zmodem.Timeouts {
+	t, d := c.ZmodemTimeouts, zmodem.DefaultTimeouts
+	return zmodem.Timeouts{
+		Header:  cmp.Or(t.Header, d.Header),
+		Data:    cmp.Or(t.Data, d.Data),
+		Fin:     cmp.Or(t.Fin, d.Fin),
+		Retries: cmp.Or(t.Retries, d.Retries),
+	}
+}
+
+// zmodemDeadline ritorna la scadenza del trasferimento in corso (zero se
+// non ce n'è uno).
+func (c *Connection) zmodemDeadline() time.Time {
+	active, rx, tx := c.zmodemState()
+	switch {
+	case !active:
+		return time.Time{}
+	case rx != nil:
+		return rx.Deadline()
+	case tx != nil:
+		return tx.Deadline()
+	}
+	return time.Time{}
+}
+
+// tickZmodem fa gestire al trasferimento in corso l'attesa scaduta.
+func (c *Connection) tickZmodem(now time.Time) {
+	active, rx, tx := c.zmodemState()
+	if !active {
+		return
+	}
+	if rx != nil {
+		rx.Tick(now)
+	} else if tx != nil {
+		tx.Tick(now)
+	}
+}
+
+// zmodemState ritorna il trasferimento in corso. I metodi di receiver e
+// sender vanno chiamati senza mu: i loro invii lo acquisiscono.
+func (c *Connection) zmodemState() (active bool, rx *zmodem.Receiver, tx *zmodem.Sender) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.zmodemActive, c.zmodemReceiver, c.zmodemSender
+}
+
+// setZmodem registra il trasferimento in corso (nil, nil = nessuno).
+func (c *Connection) setZmodem(rx *zmodem.Receiver, tx *zmodem.Sender) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.zmodemReceiver, c.zmodemSender = rx, tx
+	c.zmodemActive = rx != nil || tx != nil
+	if c.zmodemActive {
+		// Un annullamento rimasto da un trasferimento precedente non vale
+		select {
+		case done := <-c.zmodemCancel:
+			close(done)
+		default:
+		}
+	}
+}
+
+// CancelZmodem annulla il trasferimento ZMODEM in corso. Come la risposta
+// di AnswerOffer, l'annullamento passa dalla goroutine di ricezione, l'unica
+// che usa il receiver; CancelZmodem lo attende al più CancelTimeout, così
+// ZABORT e CAN partono prima di un Disconnect.
+func (c *Connection) CancelZmodem() {
+	if active, _, _ := c.zmodemState(); !active {
+		return
+	}
+	done := make(chan struct{})
+	select {
+	case c.zmodemCancel <- done:
+	default:
+		return // un annullamento è già in attesa
+	}
+	c.wakeRecv()
+	select {
+	case <-done:
+	case <-time.After(CancelTimeout):
+	}
+}
+
+// cancelZmodem esegue l'annullamento chiesto da CancelZmodem, dalla
+// goroutine di ricezione.
+func (c *Connection) cancelZmodem() {
+	select {
+	case done := <-c.zmodemCancel:
+		_, rx, tx := c.zmodemState()
+		if rx != nil {
+			rx.Cancel()
+		}
+		if tx != nil {
+			tx.Cancel()
+		}
+		c.setZmodem(nil, nil)
+		close(done)
+	default:
+	}
+}
+
+// ─────────────────────────────────────────────
+// Protocollo Telnet — parsing IAC
+// ─────────────────────────────────────────────
+
+// processTelnet processa i dati raw dal socket, gestisce le sequenze IAC
+// e ritorna i dati puliti. Equivalente di _process_telnet() Python.
+func (c *Connection) processTelnet(data []byte) []byte {
+	// BUG-004: prependi eventuali byte rimasti dal ciclo precedente
+	if len(c.iacRemainder) > 0 {
+		data = append(c.iacRemainder, data...)
+		c.iacRemainder = nil
+	}
+
+	clean := make([]byte, 0, len(data))
+	i := 0
+	n := len(data)
+
+	for i < n {
+		b := data[i]
+
+		if b == IAC {
+			if i+1 >= n {
+				// BUG-004: IAC all'ultimo byte — salva per il prossimo ciclo
+				c.iacRemainder = append([]byte{}, data[i:]...)
+				break
+			}
+			cmd := data[i+1]
+
+			switch cmd {
+			case IAC:
+				// IAC IAC → byte 255 letterale
+				clean = append(clean, IAC)
+				i += 2
+
+			case DO, DONT, WILL, WONT:
+				if i+2 >= n {
+					// BUG-004: sequenza incompleta — salva per il prossimo ciclo
+					c.iacRemainder = append([]byte{}, data[i:]...)
+					i = n // esci dal loop
+					continue
+				}
+				c.negotiate(cmd, data[i+2])
+				i += 3
+
+			case SB:
+				// Cerca IAC SE per la fine della subnegotiation
+				end := findIACSE(data, i)
+				if end == -1 {
+					// BUG-004: subnegotiation incompleta — salva per il prossimo ciclo
+					c.iacRemainder = append([]byte{}, data[i:]...)
+					i = n // esci dal loop
+					continue
+				}
+				c.subnegotiate(data[i+2 : end])
+				i = end + 2
+
+			default:
+				i += 2
+			}
+		} else {
+			// NVT: CR NUL è un CR senza a capo, il NUL non è un dato
+			if !(b == 0 && c.crPending) {
+				clean = append(clean, b)
+			}
+			c.crPending = b == '\r' && !c.binaryIn
+			i++
+		}
+	}
+
+	return clean
+}
+
+// encodeData prepara data per la connessione telnet: ogni IAC diventa
+// IAC IAC, così la BBS non lo scambia per un comando. Con cr vale anche
+// la regola NVT per CR: un CR non seguito da LF diventa CR NUL, così la
+// BBS non lo unisce al carattere successivo.
+func encodeData(data []byte, cr bool) []byte {
+	bareCR := func(i int) bool {
+		return cr && data[i] == '\r' && (i+1 == len(data) || data[i+1] != '\n')
+	}
+	n := 0
+	for i, b := range data {
+		if b == IAC || bareCR(i) {
+			n++
+		}
+	}
+	if n == 0 {
+		return data
+	}
+	out := make([]byte, 0, len(data)+n)
+	for i, b := range data {
+		out = append(out, b)
+		switch {
+		case b == IAC:
+			out = append(out, IAC)
+		case bareCR(i):
+			out = append(out, 0)
+		}
+	}
+	return out
+}
+
+// findIACSE cerca la posizione di IAC SE (255, 240) in data a partire da start.
+func findIACSE(data []byte, start int) int {
+	for i := start; i < len(data)-1; i++ {
+		if data[i] == IAC && data[i+1] == SE {
+			return i
+		}
+	}
+	return -1
+}
+
+// ─────────────────────────────────────────────
+// Negoziazione Telnet
+// ─────────────────────────────────────────────
+
+// negotiate gestisce DO/DONT/WILL/WONT. Equivalente di _negotiate() Python.
+func (c *Connection) negotiate(cmd, opt byte) {
+	if c.Debug {
+		log.Printf("[TELNET] Negoziazione: cmd=%d opt=%d", cmd, opt)
+	}
+	c.mu.Lock()
+	if len(c.negotiation) < maxNegotiation {
+		c.negotiation = append(c.negotiation, commandName(cmd)+" "+optionName(opt))
+	}
+	c.mu.Unlock()
+
+	switch cmd {
+	case DO:
+		switch opt {
+		case TTYPE:
+			c.sendIAC(WILL, TTYPE)
+		case NAWS:
+			c.mu.Lock()
+			c.naws = true
+			c.mu.Unlock()
+			c.sendIAC(WILL, NAWS)
+			c.sendNAWS()
+		case BINARY:
+			c.mu.Lock()
+			c.binaryOut = true
+			c.mu.Unlock()
+			c.sendIAC(WILL, opt)
+		case SGA:
+			c.sendIAC(WILL, opt)
+		default:
+			c.sendIAC(WONT, opt)
+		}
+
+	case WILL:
+		switch opt {
+		case BINARY:
+			c.binaryIn = true
+			c.sendIAC(DO, opt)
+		case ECHO, SGA:
+			c.sendIAC(DO, opt)
+		default:
+			c.sendIAC(DONT, opt)
+		}
+
+	case DONT:
+		if opt == BINARY {
+			c.mu.Lock()
+			c.binaryOut = false
+			c.mu.Unlock()
+		}
+		c.sendIAC(WONT, opt)
+
+	case WONT:
+		if opt == BINARY {
+			c.binaryIn = false
+		}
+		c.sendIAC(DONT, opt)
+	}
+}
+
+// Negotiation ritorna le richieste di negoziazione ricevute dal server
+// dall'inizio della connessione, nell'ordine di arrivo (es. "WILL ECHO").
+// Il comportamento in negoziazione aiuta a riconoscere il software della BBS.
+func (c *Connection) Negotiation() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.negotiation...)
+}
+
+// maxNegotiation limita le richieste registrate (server che rinegoziano
+// in continuazione).
+const maxNegotiation = 64
+
+// commandName ritorna il nome di un comando di negoziazione.
+func commandName(cmd byte) string {
+	switch cmd {
+	case DO:
+		return "DO"
+	case DONT:
+		return "DONT"
+	case WILL:
+		return "WILL"
+	case WONT:
+		return "WONT"
+	}
+	return strconv.Itoa(int(cmd))
+}
+
+// optionName ritorna il nome di un'opzione telnet (il numero se non è tra
+// quelle comuni).
+func optionName(opt byte) string {
+	if name, ok := optionNames[opt]; ok {
+		return name
+	}
+	return strconv.Itoa(int(opt))
+}
+
+var optionNames = map[byte]string{
+	BINARY: "BINARY",
+	ECHO:   "ECHO",
+	SGA:    "SGA",
+	5:      "STATUS",
+	6:      "TIMING-MARK",
+	TTYPE:  "TTYPE",
+	25:     "EOR",
+	NAWS:   "NAWS",
+	32:     "TSPEED",
+	33:     "LFLOW",
+	34:     "LINEMODE",
+	35:     "XDISPLOC",
+	36:     "ENVIRON",
+	39:     "NEW-ENVIRON",
+	42:     "CHARSET",
+	46:     "START-TLS",
+}
+
+// subnegotiate gestisce le sotto-negoziazioni (SB...SE).
+// Equivalente di _subnegotiate() Python.
+func (c *Connection) subnegotiate(data []byte) {
+	if len(data) >= 2 && data[0] == TTYPE && data[1] == 1 {
+		// Server chiede il tipo di terminale → rispondiamo "ANSI"
+		resp := make([]byte, 0, 4+len(c.TermType)+2)
+		resp = append(resp, IAC, SB, TTYPE, 0)
+		resp = append(resp, c.TermType...)
+		resp = append(resp, IAC, SE)
+		c.sendCommand(resp)
+
+		if c.Debug {
+			log.Printf("[TELNET] TTYPE → %s", c.TermType)
+		}
+	}
+}
+
+// SendNOP invia un IAC NOP: traffico che tiene aperta la connessione (NAT,
+// firewall) senza arrivare all'applicazione BBS. Senza telnet (Raw) non
+// invia nulla.
+func (c *Connection) SendNOP() error {
+	if c.Raw {
+		return nil
+	}
+	return c.sendCommand([]byte{IAC, NOP})
+}
+
+// sendIAC invia un comando IAC cmd opt.
+func (c *Connection) sendIAC(cmd, opt byte) {
+	c.sendCommand([]byte{IAC, cmd, opt})
+}
+
+// sendCommand invia un comando o una sotto-negoziazione così com'è. La
+// negoziazione non è soggetta a XOFF, che riguarda i dati.
+func (c *Connection) sendCommand(data []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.connected || c.conn == nil {
+		return fmt.Errorf("non connesso")
+	}
+	return c.write(data)
+}
+
+// SetSize cambia le dimensioni del terminale. Se il server ha chiesto
+// NAWS, o la connessione è un Resizer, le nuove dimensioni gli vengono
+// comunicate subito.
+func (c *Connection) SetSize(cols, rows int) {
+	c.mu.Lock()
+	changed := cols != c.Cols || rows != c.Rows
+	c.Cols, c.Rows = cols, rows
+	r, resizer := c.conn.(Resizer)
+	notify := changed && c.connected && c.naws
+	resize := changed && c.connected && resizer
+	c.mu.Unlock()
+	switch {
+	case resize:
+		r.Resize(cols, rows)
+	case notify:
+		c.sendNAWS()
+	}
+}
+
+// sendNAWS invia la dimensione della finestra (NAWS).
+// Equivalente di _send_naws() Python.
+func (c *Connection) sendNAWS() {
+	c.mu.Lock()
+	cols, rows := c.Cols, c.Rows
+	c.mu.Unlock()
+
+	var size [4]byte
+	binary.BigEndian.PutUint16(size[0:2], uint16(cols))
+	binary.BigEndian.PutUint16(size[2:4], uint16(rows))
+	buf := []byte{IAC, SB, NAWS}
+	for _, b := range size {
+		buf = append(buf, b)
+		if b == IAC { // RFC 1073: 255 va raddoppiato
+			buf = append(buf, IAC)
+		}
+	}
+	buf = append(buf, IAC, SE)
+	c.sendCommand(buf)
+
+	if c.Debug {
+		log.Printf("[TELNET] NAWS → %dx%d", cols, rows)
+	}
+}
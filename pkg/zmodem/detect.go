@@ -0,0 +1,91 @@
+package zmodem
+
+import "bytes"
+
+// ─────────────────────────────────────────────
+// Rilevamento ZRQINIT
+// ─────────────────────────────────────────────
+
+// detectPatterns sono gli header ZRQINIT che avviano un download: hex
+// (ZRQINITHex) e binari con CRC16 e CRC32. Cominciano tutti con ZPAD.
+var detectPatterns = [][]byte{
+	ZRQINITHex,
+	{ZPAD, ZDLE, 'A', 0x00},
+	{ZPAD, ZDLE, 'C', 0x00},
+}
+
+// maxPattern è la lunghezza del pattern più lungo.
+const maxPattern = 6
+
+// Detect controlla se i dati contengono un inizio ZMODEM (ZRQINIT).
+func Detect(data []byte) bool {
+	return match(data) >= 0
+}
+
+// match ritorna la posizione del primo header ZRQINIT in data, o -1.
+// Salta da un ZPAD al successivo: senza '*' nei dati costa una sola
+// scansione con bytes.IndexByte.
+func match(data []byte) int {
+	for off := 0; ; {
+		i := bytes.IndexByte(data[off:], ZPAD)
+		if i < 0 {
+			return -1
+		}
+		off += i
+		for _, p := range detectPatterns {
+			if bytes.HasPrefix(data[off:], p) {
+				return off
+			}
+		}
+		off++
+	}
+}
+
+// Detector riconosce un ZRQINIT in un flusso di letture successive,
+// anche se l'header è spezzato fra due letture. Conserva solo gli ultimi
+// maxPattern-1 byte, senza allocare finché non trova un header. Lo zero
+// value è pronto all'uso.
+type Detector struct {
+	tail [maxPattern - 1]byte
+	n    int
+}
+
+// Feed esamina i dati ricevuti. Se contengono un ZRQINIT ritorna ok e i
+// byte dall'inizio dell'header alla fine di data, comprese le parti
+// arrivate con la lettura precedente, da passare a Receiver.Start; il
+// Detector ricomincia da capo.
+func (d *Detector) Feed(data []byte) (start []byte, ok bool) {
+	// Header a cavallo fra la lettura precedente e questa
+	if d.n > 0 {
+		var join [2*maxPattern - 2]byte
+		k := copy(join[:], d.tail[:d.n])
+		k += copy(join[k:], data)
+		if i := match(join[:k]); i >= 0 && i < d.n {
+			start = append(append([]byte(nil), d.tail[i:d.n]...), data...)
+			d.Reset()
+			return start, true
+		}
+	}
+	if i := match(data); i >= 0 {
+		d.Reset()
+		return data[i:], true
+	}
+	d.keep(data)
+	return nil, false
+}
+
+// keep aggiorna gli ultimi byte visti.
+func (d *Detector) keep(data []byte) {
+	if len(data) >= len(d.tail) {
+		d.n = copy(d.tail[:], data[len(data)-len(d.tail):])
+		return
+	}
+	// Scorre i byte vecchi per fare posto ai nuovi
+	if drop := d.n + len(data) - len(d.tail); drop > 0 {
+		d.n = copy(d.tail[:], d.tail[drop:d.n])
+	}
+	d.n += copy(d.tail[d.n:], data)
+}
+
+// Reset dimentica i byte visti finora.
+func (d *Detector) Reset() { d.n = 0 }
@@ -0,0 +1,79 @@
+package zmodem
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// ─────────────────────────────────────────────
+// Tipi di file ammessi nei download
+// ─────────────────────────────────────────────
+//
+// La BBS decide cosa inviare: il Receiver controlla l'estensione di ogni
+// file offerto prima di crearlo. I tipi bloccati vengono rifiutati con
+// ZSKIP, quelli da segnalare vengono ricevuti e notificati con OnPolicy.
+
+// FilePolicy elenca le estensioni bloccate e quelle da segnalare. Le
+// estensioni si confrontano senza distinguere maiuscole, con o senza il
+// punto iniziale ("exe" e ".EXE" sono equivalenti).
+type FilePolicy struct {
+	Blocked []string // rifiutate con ZSKIP
+	Warned  []string // ricevute, ma segnalate
+}
+
+// Verdict è l'esito del controllo di un file.
+type Verdict int
+
+const (
+	FileAllowed Verdict = iota // ricevuto senza segnalazioni
+	FileWarned                 // ricevuto e segnalato
+	FileBlocked                // rifiutato
+)
+
+func (v Verdict) String() string {
+	switch v {
+	case FileWarned:
+		return "warned"
+	case FileBlocked:
+		return "blocked"
+	}
+	return "allowed"
+}
+
+// DefaultFilePolicy è la politica usata da NewReceiver: blocca gli
+// eseguibili Windows che si avviano con un doppio clic e segnala gli
+// archivi, che possono contenerne.
+var DefaultFilePolicy = FilePolicy{
+	Blocked: []string{".exe", ".scr", ".com", ".bat", ".cmd", ".pif", ".msi", ".vbs", ".js", ".lnk"},
+	Warned:  []string{".zip", ".arj", ".lzh", ".lha", ".rar", ".7z", ".arc", ".zoo"},
+}
+
+// Check ritorna il verdetto per filename.
+func (p FilePolicy) Check(filename string) Verdict {
+	// Windows ignora punti e spazi finali: "a.exe." si apre come "a.exe"
+	ext := normalizeExt(filepath.Ext(strings.TrimRight(filename, ". ")))
+	if ext == "" {
+		return FileAllowed
+	}
+	match := func(e string) bool { return normalizeExt(e) == ext }
+	switch {
+	case slices.ContainsFunc(p.Blocked, match):
+		return FileBlocked
+	case slices.ContainsFunc(p.Warned, match):
+		return FileWarned
+	}
+	return FileAllowed
+}
+
+// normalizeExt porta un'estensione nella forma ".ext" minuscola.
+func normalizeExt(ext string) string {
+	ext = strings.ToLower(strings.TrimSpace(ext))
+	if ext == "" || ext == "." {
+		return ""
+	}
+	if !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+	return ext
+}
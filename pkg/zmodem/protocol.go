@@ -12,11 +12,24 @@
 // - Progress callback per UI
 //
 // Riferimento: Chuck Forsberg, ZMODEM Protocol Specification
+//
+// Receiver e Sender non gestiscono la connessione: i byte ricevuti vanno
+// passati a Feed, quelli da inviare arrivano alla funzione data al
+// costruttore. Per un download avviato dalla BBS:
+//
+//	if zmodem.Detect(data) {
+//		send := func(b []byte) { conn.Write(b) }
+//		rx := zmodem.NewReceiver("downloads", send, nil) // nil = nessun log
+//		rx.OnComplete = func(path string) { fmt.Println("ricevuto", path) }
+//		rx.Start(data)
+//		// ... rx.Feed(altri dati) fino a OnFinished
+//	}
 package zmodem
 
 import (
 	"encoding/binary"
 	"fmt"
+	"hash/crc32"
 )
 
 // ─────────────────────────────────────────────
@@ -49,7 +62,7 @@ const (
 	ZEOF       byte = 11 // End of file
 	ZFERR      byte = 12 // Fatal read/write error
 	ZCRC       byte = 13 // Request file CRC
-	ZCHALLENGE byte = 14
+	ZCHALLENGE byte = 14 // Challenge (echo the number)
 	ZCOMPL     byte = 15 // Request complete
 	ZCAN       byte = 16 // CAN chars received, abort
 
@@ -68,8 +81,13 @@ const (
 	// Limiti
 	MaxFileSize  = 4 * 1024 * 1024 * 1024 // 4 GB
 	MaxBufSize   = 64 * 1024              // 64 KB — limite buffer receiver/sender (PT-002: anti-OOM)
-	BlockSize    = 1024
-	MaxRetries   = 5
+	BlockSize    = 1024                   // dati per subpacket in upload
+	MinBlockSize = 128                    // subpacket più piccoli dopo gli errori
+	MaxRetries   = 5                      // ZRPOS ripetuti per la stessa posizione prima di annullare
+
+	// Finestra dell'upload dopo un errore: byte inviati senza ZACK
+	RecoveryWindow = 8 * BlockSize  // finestra iniziale dopo un ZRPOS
+	MaxWindow      = 64 * BlockSize // oltre, si torna all'invio continuo
 )
 
 // Bytes che devono essere escaped con ZDLE.
@@ -100,11 +118,12 @@ var FrameNames = map[byte]string{
 // Tabelle CRC precalcolate
 // ─────────────────────────────────────────────
 
-var crc16Table [256]uint16
-var crc32Table [256]uint32
+// crc16Tables sono le tabelle del CRC16 CCITT (polinomio 0x1021) per lo
+// slicing-by-4: crc16Tables[k][i] è l'effetto del byte i seguito da k
+// byte a zero, così CRC16 elabora quattro byte per iterazione.
+var crc16Tables [4][256]uint16
 
 func init() {
-	// CRC16 CCITT (polinomio 0x1021)
 	for i := 0; i < 256; i++ {
 		crc := uint16(i) << 8
 		for j := 0; j < 8; j++ {
@@ -114,39 +133,36 @@ func init() {
 				crc <<= 1
 			}
 		}
-		crc16Table[i] = crc
+		crc16Tables[0][i] = crc
 	}
-
-	// CRC32 (polinomio 0xEDB88320)
-	for i := 0; i < 256; i++ {
-		crc := uint32(i)
-		for j := 0; j < 8; j++ {
-			if crc&1 != 0 {
-				crc = (crc >> 1) ^ 0xEDB88320
-			} else {
-				crc >>= 1
-			}
+	for k := 1; k < len(crc16Tables); k++ {
+		for i := 0; i < 256; i++ {
+			prev := crc16Tables[k-1][i]
+			crc16Tables[k][i] = prev<<8 ^ crc16Tables[0][prev>>8]
 		}
-		crc32Table[i] = crc
 	}
 }
 
 // CRC16 calcola CRC16 CCITT.
 func CRC16(data []byte, initial uint16) uint16 {
 	crc := initial
+	t := &crc16Tables
+	for len(data) >= 4 {
+		crc = t[3][byte(crc>>8)^data[0]] ^ t[2][byte(crc)^data[1]] ^
+			t[1][data[2]] ^ t[0][data[3]]
+		data = data[4:]
+	}
 	for _, b := range data {
-		crc = (crc << 8) ^ crc16Table[((crc>>8)^uint16(b))&0xFF]
+		crc = (crc << 8) ^ t[0][byte(crc>>8)^b]
 	}
 	return crc
 }
 
-// CRC32 calcola CRC32.
+// CRC32 calcola CRC32 (polinomio 0xEDB88320) partendo dal registro
+// initial, con hash/crc32 che usa le istruzioni della CPU dove ci sono.
 func CRC32(data []byte, initial uint32) uint32 {
-	crc := initial
-	for _, b := range data {
-		crc = crc32Table[(crc^uint32(b))&0xFF] ^ (crc >> 8)
-	}
-	return crc ^ 0xFFFFFFFF
+	// crc32.Update complementa il valore all'ingresso e all'uscita
+	return crc32.Update(^initial, crc32.IEEETable, data)
 }
 
 // ─────────────────────────────────────────────
@@ -534,29 +550,3 @@ func ParseDataSubpacket(data []byte, useCRC32 bool) *DataSubpacket {
 func PositionFromParams(p0, p1, p2, p3 byte) uint32 {
 	return uint32(p0) | uint32(p1)<<8 | uint32(p2)<<16 | uint32(p3)<<24
 }
-
-// Detect controlla se i dati contengono un inizio ZMODEM (ZRQINIT).
-func Detect(data []byte) bool {
-	return containsBytes(data, ZRQINITHex) ||
-		containsBytes(data, []byte{0x2A, 0x18, 0x41, 0x00}) ||
-		containsBytes(data, []byte{0x2A, 0x18, 0x43, 0x00})
-}
-
-func containsBytes(data, pattern []byte) bool {
-	if len(pattern) > len(data) {
-		return false
-	}
-	for i := 0; i <= len(data)-len(pattern); i++ {
-		match := true
-		for j := 0; j < len(pattern); j++ {
-			if data[i+j] != pattern[j] {
-				match = false
-				break
-			}
-		}
-		if match {
-			return true
-		}
-	}
-	return false
-}
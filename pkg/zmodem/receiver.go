@@ -11,18 +11,19 @@ import (
 
 // ─────────────────────────────────────────────
 // Receiver — Download handler (stato macchina)
-// IDLE → INIT → WAIT_ZFILE → RECEIVING → DONE
+// IDLE → INIT → WAIT_ZFILE → [CONFIRM →] RECEIVING → DONE
 // ─────────────────────────────────────────────
 
 // ReceiverState rappresenta lo stato della macchina a stati del receiver
 type ReceiverState int
 
 const (
-	RxIdle      ReceiverState = iota
-	RxInit                    // ZRQINIT ricevuto, ZRINIT inviato
-	RxWaitZFile               // In attesa di ZFILE
-	RxReceiving               // Ricezione dati
-	RxDone                    // Trasferimento completato
+	RxIdle      ReceiverState = iota // Nessun trasferimento
+	RxInit                           // ZRQINIT ricevuto, ZRINIT inviato
+	RxWaitZFile                      // In attesa di ZFILE
+	RxReceiving                      // Ricezione dati
+	RxDone                           // Trasferimento completato
+	RxConfirm                        // File offerto, in attesa di Accept o Skip
 )
 
 // Receiver gestisce il download ZMODEM (ricezione file dal server).
@@ -31,6 +32,8 @@ type Receiver struct {
 	DownloadDir string
 	SendFunc    func([]byte) // callback per inviare dati al server
 	LogFunc     func(string) // callback log diagnostico
+	Timeouts    Timeouts     // attese per stato (vedi Tick)
+	Policy      FilePolicy   // tipi di file bloccati o segnalati
 
 	// Stato
 	State         ReceiverState
@@ -47,9 +50,16 @@ type Receiver struct {
 	OnComplete func(filepath string)
 	OnError    func(message string)
 	OnFinished func() // sessione ZMODEM terminata
+	// OnOffer, se impostato, riceve ogni file offerto prima che venga
+	// creato: la ricezione attende Accept o Skip (anche da OnOffer)
+	OnOffer func(filename string, filesize int64)
+	// OnPolicy riceve i file bloccati o segnalati da Policy
+	OnPolicy func(filename string, verdict Verdict)
 
 	fileHandle *os.File
+	fileInfo   bool // il prossimo subpacket descrive il file (dopo ZFILE)
 	buf        []byte
+	timer      timer
 }
 
 // NewReceiver crea un nuovo Receiver.
@@ -61,6 +71,8 @@ func NewReceiver(downloadDir string, sendFunc func([]byte), logFunc func(string)
 		DownloadDir: downloadDir,
 		SendFunc:    sendFunc,
 		LogFunc:     logFunc,
+		Timeouts:    DefaultTimeouts,
+		Policy:      DefaultFilePolicy,
 		State:       RxIdle,
 	}
 }
@@ -79,6 +91,7 @@ func (r *Receiver) Start(initialData []byte) {
 	r.LogFunc(fmt.Sprintf("[RX] Invio ZRINIT: %q", zrinit))
 	r.SendFunc(zrinit)
 	r.State = RxWaitZFile
+	r.timer.progress(r.wait())
 
 	r.processBuffer()
 }
@@ -106,6 +119,9 @@ func (r *Receiver) Feed(data []byte) {
 
 // Cancel annulla il trasferimento corrente.
 func (r *Receiver) Cancel() {
+	// ZABORT chiede al mittente di chiudere il batch; i CAN lo
+	// interrompono anche se non è in grado di rispondere
+	r.SendFunc(BuildHexHeader(ZABORT, 0, 0, 0, 0))
 	r.SendFunc(AbortSeq)
 	r.cleanup()
 	r.State = RxDone
@@ -128,7 +144,7 @@ func (r *Receiver) processBuffer() {
 			if !r.tryParseHeader() {
 				return
 			}
-		case RxReceiving:
+		case RxReceiving, RxConfirm:
 			if !r.tryParseData() {
 				return
 			}
@@ -217,6 +233,7 @@ func (r *Receiver) handleHeader(ftype, p0, p1, p2, p3 byte) {
 		name = fmt.Sprintf("0x%02x", ftype)
 	}
 	r.LogFunc(fmt.Sprintf("[RX] HEADER: %s p=[%d,%d,%d,%d]", name, p0, p1, p2, p3))
+	defer func() { r.timer.progress(r.wait()) }()
 
 	switch ftype {
 	case ZRQINIT:
@@ -225,7 +242,11 @@ func (r *Receiver) handleHeader(ftype, p0, p1, p2, p3 byte) {
 		r.State = RxWaitZFile
 
 	case ZFILE:
-		r.State = RxReceiving
+		// Durante la conferma la BBS può ripetere l'offerta: si ignora
+		r.fileInfo = true
+		if r.State != RxConfirm {
+			r.State = RxReceiving
+		}
 
 	case ZDATA:
 		offset := PositionFromParams(p0, p1, p2, p3)
@@ -271,13 +292,20 @@ func (r *Receiver) handleData(payload []byte, endType byte) {
 	if len(payload) == 0 {
 		return
 	}
+	r.timer.progress(r.wait())
 
-	// Se non abbiamo ancora il file aperto, questo è il subpacket ZFILE info
-	if r.fileHandle == nil {
+	// Subpacket dopo ZFILE: descrive il file (una ripetizione si ignora)
+	if r.fileInfo {
+		r.fileInfo = false
 		r.LogFunc(fmt.Sprintf("[RX] FILE INFO subpacket: %q", payload[:min(80, len(payload))]))
-		r.parseFileInfo(payload)
+		if r.fileHandle == nil && r.State != RxConfirm {
+			r.parseFileInfo(payload)
+		}
 		return
 	}
+	if r.fileHandle == nil {
+		return // dati prima dell'apertura del file (o file rifiutato)
+	}
 
 	// Scrivi dati su file
 	_, err := r.fileHandle.Write(payload)
@@ -352,6 +380,62 @@ func (r *Receiver) parseFileInfo(data []byte) {
 		return
 	}
 
+	switch verdict := r.Policy.Check(r.Filename); verdict {
+	case FileBlocked:
+		r.LogFunc(fmt.Sprintf("[RX] SECURITY: tipo di file bloccato: %s", r.Filename))
+		if r.OnPolicy != nil {
+			r.OnPolicy(r.Filename, verdict)
+		}
+		r.skip()
+		return
+	case FileWarned:
+		r.LogFunc(fmt.Sprintf("[RX] Tipo di file da controllare: %s", r.Filename))
+		if r.OnPolicy != nil {
+			r.OnPolicy(r.Filename, verdict)
+		}
+	}
+
+	if r.OnOffer != nil {
+		r.LogFunc(fmt.Sprintf("[RX] Offerta: %s size=%d, attendo conferma", r.Filename, r.Filesize))
+		r.State = RxConfirm
+		r.timer.progress(r.wait())
+		r.OnOffer(r.Filename, r.Filesize)
+		return
+	}
+	r.openFile()
+}
+
+// Accept accetta il file offerto (vedi OnOffer) e avvia la ricezione.
+func (r *Receiver) Accept() {
+	if r.State != RxConfirm {
+		return
+	}
+	r.LogFunc(fmt.Sprintf("[RX] Offerta accettata: %s", r.Filename))
+	r.openFile()
+	r.timer.progress(r.wait())
+}
+
+// Skip rifiuta il file offerto (vedi OnOffer) con ZSKIP: la BBS passa al
+// file successivo del batch o chiude.
+func (r *Receiver) Skip() {
+	if r.State != RxConfirm {
+		return
+	}
+	r.LogFunc(fmt.Sprintf("[RX] Offerta rifiutata: %s", r.Filename))
+	r.skip()
+}
+
+// skip rifiuta il file corrente e torna in attesa del prossimo ZFILE.
+func (r *Receiver) skip() {
+	r.SendFunc(BuildHexHeader(ZSKIP, 0, 0, 0, 0))
+	r.Filepath = ""
+	r.State = RxWaitZFile
+	r.timer.progress(r.wait())
+}
+
+// openFile crea il file di r.Filename, senza sovrascriverne uno esistente,
+// e chiede i dati dall'inizio.
+func (r *Receiver) openFile() {
 	// Gestisci file duplicati
 	base := r.Filepath
 	ext := filepath.Ext(base)
@@ -2,8 +2,10 @@ package zmodem
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"sync"
 	"time"
 )
 
@@ -15,20 +17,25 @@ import (
 type SenderState int
 
 const (
-	TxIdle      SenderState = iota
-	TxWaitRInit             // In attesa ZRINIT dal server
-	TxWaitZRPos             // ZFILE inviato, attendo ZRPOS
-	TxSending               // Invio dati
-	TxWaitAck               // In attesa conferma dopo ZEOF
-	TxWaitZFin              // ZFIN inviato, attendo ZFIN dalla BBS
-	TxDone
+	TxIdle      SenderState = iota // Nessun trasferimento
+	TxWaitRInit                    // In attesa ZRINIT dal server
+	TxWaitZRPos                    // ZFILE inviato, attendo ZRPOS
+	TxSending                      // Invio dati
+	TxWaitAck                      // In attesa conferma dopo ZEOF
+	TxWaitZFin                     // ZFIN inviato, attendo ZFIN dalla BBS
+	TxDone                         // Trasferimento completato
 )
 
-// Sender gestisce l'upload ZMODEM (invio file al server).
+// Sender gestisce l'upload ZMODEM (invio file al server). I dati partono
+// da una goroutine, un blocco alla volta: Feed, Tick e Cancel restano
+// utilizzabili durante l'invio (ZRPOS fa ripartire dalla posizione
+// chiesta, Cancel ferma al blocco successivo). I callback possono essere
+// chiamati da quella goroutine.
 type Sender struct {
 	// Configurazione
 	SendFunc func([]byte)
 	LogFunc  func(string)
+	Timeouts Timeouts // attese per stato (vedi Tick)
 
 	// Stato
 	State    SenderState
@@ -47,10 +54,25 @@ type Sender struct {
 	OnComplete func(filepath string)
 	OnError    func(message string)
 	OnFinished func()
-
-	fileHandle *os.File
-	buf        []byte
-	retryCount int
+	// OnWait è chiamato quando la goroutine di invio arma una nuova
+	// attesa: chi chiama Tick deve rileggere Deadline
+	OnWait func()
+	// Throttle, se impostato, è chiamato prima di ogni blocco senza lock
+	// e può attendere (controllo di flusso)
+	Throttle func()
+
+	mu    sync.Mutex // stato condiviso con la goroutine di invio
+	acked *sync.Cond // segnalata a ogni ZACK e all'arresto dell'invio
+	run   int        // invio in corso; incrementato per fermarlo
+	buf   []byte
+	timer timer
+
+	// Ripresa dagli errori (vedi rollback)
+	rposAt   int64 // posizione dell'ultimo ZRPOS (-1 = nessuno)
+	retries  int   // ZRPOS ripetuti per rposAt
+	ackPos   int64 // posizione confermata dal receiver
+	window   int64 // byte inviabili oltre ackPos (0 = invio continuo)
+	blockLen int   // dimensione dei subpacket
 }
 
 // NewSender crea un nuovo Sender.
@@ -58,15 +80,20 @@ func NewSender(sendFunc func([]byte), logFunc func(string)) *Sender {
 	if logFunc == nil {
 		logFunc = func(string) {}
 	}
-	return &Sender{
+	s := &Sender{
 		SendFunc: sendFunc,
 		LogFunc:  logFunc,
+		Timeouts: DefaultTimeouts,
 		State:    TxIdle,
 	}
+	s.acked = sync.NewCond(&s.mu)
+	return s
 }
 
 // StartUpload avvia l'upload di un file.
 func (s *Sender) StartUpload(path string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.LogFunc(fmt.Sprintf("[TX] start_upload: %s", path))
 
 	info, err := os.Stat(path)
@@ -92,7 +119,8 @@ func (s *Sender) StartUpload(path string) {
 	s.Filename = filepath.Base(path)
 	s.Filesize = info.Size()
 	s.BytesSent = 0
-	s.retryCount = 0
+	s.rposAt, s.retries = -1, 0
+	s.ackPos, s.window, s.blockLen = 0, 0, BlockSize
 	s.StartTime = time.Now()
 
 	// Invia ZRQINIT per iniziare sessione
@@ -100,10 +128,20 @@ func (s *Sender) StartUpload(path string) {
 	s.LogFunc(fmt.Sprintf("[TX] Invio ZRQINIT: %q", zrqinit))
 	s.SendFunc(zrqinit)
 	s.State = TxWaitRInit
+	s.timer.progress(s.wait())
+}
+
+// Active indica se l'upload è in corso.
+func (s *Sender) Active() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.State != TxIdle && s.State != TxDone
 }
 
 // Feed alimenta dati ricevuti dal server.
 func (s *Sender) Feed(data []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	if s.State == TxIdle || s.State == TxDone {
 		return
 	}
@@ -116,15 +154,21 @@ func (s *Sender) Feed(data []byte) {
 		if s.OnError != nil {
 			s.OnError("Buffer overflow: dati non validi dal server")
 		}
-		s.Cancel()
+		s.cancel()
 		return
 	}
 
 	s.processBuffer()
 }
 
-// Cancel annulla l'upload.
+// Cancel annulla l'upload, anche a metà file.
 func (s *Sender) Cancel() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cancel()
+}
+
+func (s *Sender) cancel() {
 	s.SendFunc(AbortSeq)
 	s.cleanup()
 	s.State = TxDone
@@ -133,11 +177,10 @@ func (s *Sender) Cancel() {
 	}
 }
 
+// cleanup ferma l'eventuale invio in corso, che chiude il suo file.
 func (s *Sender) cleanup() {
-	if s.fileHandle != nil {
-		s.fileHandle.Close()
-		s.fileHandle = nil
-	}
+	s.run++
+	s.acked.Broadcast()
 }
 
 func (s *Sender) processBuffer() {
@@ -167,6 +210,9 @@ func (s *Sender) handleHeader(ftype, p0, p1, p2, p3 byte) {
 		name = fmt.Sprintf("0x%02x", ftype)
 	}
 	s.LogFunc(fmt.Sprintf("[TX] HEADER: %s p=[%d,%d,%d,%d] state=%d", name, p0, p1, p2, p3, s.State))
+	if ftype != ZNAK {
+		defer func() { s.timer.progress(s.wait()) }()
+	}
 
 	switch ftype {
 	case ZRINIT:
@@ -194,20 +240,47 @@ func (s *Sender) handleHeader(ftype, p0, p1, p2, p3 byte) {
 
 	case ZRPOS:
 		offset := PositionFromParams(p0, p1, p2, p3)
-		s.retryCount++
-		s.LogFunc(fmt.Sprintf("[TX] ZRPOS offset=%d retry=%d/%d", offset, s.retryCount, MaxRetries))
-		if s.retryCount > MaxRetries {
+		// Solo le richieste ripetute della stessa posizione sono errori:
+		// su un upload lungo il receiver può riposizionare molte volte
+		if int64(offset) == s.rposAt {
+			s.retries++
+		} else {
+			s.rposAt, s.retries = int64(offset), 0
+		}
+		s.LogFunc(fmt.Sprintf("[TX] ZRPOS offset=%d retry=%d/%d", offset, s.retries, MaxRetries))
+		if s.retries > MaxRetries {
 			if s.OnError != nil {
 				s.OnError("Upload fallito: troppi retry dal server")
 			}
-			s.Cancel()
+			s.cancel()
+			return
+		}
+		if int64(offset) > s.Filesize {
+			if s.OnError != nil {
+				s.OnError(fmt.Sprintf("Upload fallito: posizione %d oltre la fine del file", offset))
+			}
+			s.cancel()
 			return
 		}
+		if s.State == TxSending || s.State == TxWaitAck {
+			s.rollback()
+		}
+		s.ackPos = int64(offset)
 		s.startSending(offset)
 
 	case ZACK:
 		offset := PositionFromParams(p0, p1, p2, p3)
 		s.LogFunc(fmt.Sprintf("[TX] ZACK offset=%d", offset))
+		if s.State == TxSending && int64(offset) > s.ackPos && int64(offset) <= s.BytesSent {
+			s.ackPos = int64(offset)
+			s.widen()
+			s.acked.Broadcast()
+		}
+
+	case ZNAK:
+		// L'ultimo header è arrivato rovinato: va ripetuto
+		s.LogFunc("[TX] ZNAK — ripeto l'ultimo header")
+		s.resend()
 
 	case ZSKIP:
 		s.LogFunc("[TX] ZSKIP — file saltato dal server")
@@ -241,6 +314,17 @@ func (s *Sender) handleHeader(ftype, p0, p1, p2, p3 byte) {
 }
 
 func (s *Sender) sendZFile() {
+	s.LogFunc(fmt.Sprintf("[TX] Invio ZFILE: %s (%d bytes)", s.Filename, s.Filesize))
+	s.SendFunc(s.zfileFrame())
+
+	if s.OnStart != nil {
+		s.OnStart(s.Filename, s.Filesize)
+	}
+}
+
+// zfileFrame costruisce header ZFILE e subpacket con le informazioni del
+// file, in un unico blocco.
+func (s *Sender) zfileFrame() []byte {
 	// Header ZFILE binario
 	zfileHdr := BuildBinHeader(ZFILE, 0, 0, 0, 0, s.UseCRC32)
 
@@ -255,33 +339,27 @@ func (s *Sender) sendZFile() {
 	combined := make([]byte, 0, len(zfileHdr)+len(subpkt))
 	combined = append(combined, zfileHdr...)
 	combined = append(combined, subpkt...)
-
-	s.LogFunc(fmt.Sprintf("[TX] Invio ZFILE: %s (%d bytes)", s.Filename, s.Filesize))
-	s.SendFunc(combined)
-
-	if s.OnStart != nil {
-		s.OnStart(s.Filename, s.Filesize)
-	}
+	return combined
 }
 
+// startSending riparte da offset con un nuovo ZDATA; l'invio precedente,
+// se ancora in corso, si ferma. Chiamare con s.mu acquisito.
 func (s *Sender) startSending(offset uint32) {
 	s.LogFunc(fmt.Sprintf("[TX] startSending offset=%d", offset))
 
-	// Chiudi eventuale file handle precedente (BUG-005: evita leak su retry/ZRPOS)
+	// BUG-005: un solo invio (e un solo file aperto) alla volta
 	s.cleanup()
 
-	var err error
-	s.fileHandle, err = os.Open(s.Filepath)
+	f, err := os.Open(s.Filepath)
 	if err != nil {
 		if s.OnError != nil {
 			s.OnError(fmt.Sprintf("Errore lettura file: %v", err))
 		}
-		s.Cancel()
+		s.cancel()
 		return
 	}
-
 	if offset > 0 {
-		s.fileHandle.Seek(int64(offset), 0)
+		f.Seek(int64(offset), io.SeekStart)
 	}
 	s.BytesSent = int64(offset)
 	s.State = TxSending
@@ -291,23 +369,55 @@ func (s *Sender) startSending(offset uint32) {
 	s.LogFunc(fmt.Sprintf("[TX] Invio ZDATA offset=%d", offset))
 	s.SendFunc(zdataHdr)
 
-	// Invia blocchi di dati
+	go s.sendBlocks(f, s.run)
+}
+
+// sendBlocks invia il file a blocchi finché non finisce o l'invio run
+// viene sostituito (ZRPOS) o fermato (Cancel, ZCAN...). Ogni blocco è
+// inviato con s.mu acquisito, così non si mescola con un invio più
+// recente; tra un blocco e l'altro Feed può elaborare le risposte.
+func (s *Sender) sendBlocks(f *os.File, run int) {
+	defer f.Close()
 	block := make([]byte, BlockSize)
 	blocksSent := 0
 
+	s.mu.Lock()
+	size := s.blockLen
+	s.mu.Unlock()
 	for {
-		n, err := s.fileHandle.Read(block)
+		if s.Throttle != nil {
+			s.Throttle()
+		}
+		n, err := f.Read(block[:size])
+
+		s.mu.Lock()
+		// Finestra piena: si attende un ZACK (o un timeout, vedi Tick)
+		for s.run == run && s.windowFull() {
+			s.timer.arm(s.wait())
+			if s.OnWait != nil {
+				s.OnWait()
+			}
+			s.acked.Wait()
+		}
+		if s.run != run {
+			s.mu.Unlock()
+			s.LogFunc(fmt.Sprintf("[TX] Invio interrotto dopo %d blocchi", blocksSent))
+			return
+		}
 		if n == 0 || err != nil {
-			break
+			break // s.mu resta acquisito per la chiusura
 		}
 
 		s.BytesSent += int64(n)
 		blocksSent++
 
-		// Ultimo blocco? usa ZCRCE, altrimenti ZCRCG
+		// Ultimo blocco? usa ZCRCE; a fine finestra ZCRCQ chiede un ZACK
 		endType := ZCRCG
-		if s.BytesSent >= s.Filesize {
+		switch {
+		case s.BytesSent >= s.Filesize:
 			endType = ZCRCE
+		case s.windowFull():
+			endType = ZCRCQ
 		}
 
 		s.SendFunc(BuildDataSubpacket(block[:n], endType, s.UseCRC32))
@@ -321,11 +431,57 @@ func (s *Sender) startSending(offset uint32) {
 			speed := float64(s.BytesSent) / 1024.0 / elapsed
 			s.OnProgress(s.BytesSent, s.Filesize, speed)
 		}
+		size = s.blockLen
+		s.mu.Unlock()
 	}
+	defer s.mu.Unlock()
 
 	// Fine file
 	s.LogFunc(fmt.Sprintf("[TX] File inviato: %d blocchi, %d bytes", blocksSent, s.BytesSent))
 	s.cleanup()
 	s.SendFunc(BuildPosHeader(ZEOF, uint32(s.BytesSent)))
 	s.State = TxWaitAck
+	s.timer.progress(s.wait())
+	if s.OnWait != nil {
+		s.OnWait()
+	}
+}
+
+// ─────────────────────────────────────────────
+// Ripresa dagli errori
+// ─────────────────────────────────────────────
+//
+// Dopo un errore (ZRPOS durante l'invio o timeout in attesa di un ZACK)
+// il sender torna indietro alla posizione confermata, dimezza i subpacket
+// e invia al più window byte prima di chiedere un ZACK (ZCRCQ): su una
+// linea disturbata un errore costa al più una finestra. Ogni ZACK
+// raddoppia finestra e subpacket; oltre MaxWindow l'invio torna continuo.
+
+// rollback restringe l'invio dopo un errore. Chiamare con s.mu acquisito.
+func (s *Sender) rollback() {
+	s.blockLen = max(s.blockLen/2, MinBlockSize)
+	if s.window == 0 {
+		s.window = RecoveryWindow
+	} else {
+		s.window = max(s.window/2, int64(s.blockLen))
+	}
+	s.LogFunc(fmt.Sprintf("[TX] Ripresa: finestra %d, blocchi da %d", s.window, s.blockLen))
+}
+
+// widen allarga l'invio dopo un ZACK. Chiamare con s.mu acquisito.
+func (s *Sender) widen() {
+	if s.window == 0 {
+		return
+	}
+	s.blockLen = min(s.blockLen*2, BlockSize)
+	s.window *= 2
+	if s.window > MaxWindow {
+		s.window = 0
+		s.LogFunc("[TX] Linea stabile, torno all'invio continuo")
+	}
+}
+
+// windowFull dice se l'invio deve attendere un ZACK.
+func (s *Sender) windowFull() bool {
+	return s.window > 0 && s.BytesSent-s.ackPos >= s.window
 }
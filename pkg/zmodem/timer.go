@@ -0,0 +1,184 @@
+package zmodem
+
+import (
+	"fmt"
+	"time"
+)
+
+// ─────────────────────────────────────────────
+// Timeout per stato
+// ─────────────────────────────────────────────
+//
+// Receiver e Sender non avviano goroutine: Deadline dice quando scade
+// l'attesa dello stato corrente e chi gestisce la connessione chiama Tick
+// a quell'ora (o dopo). Allo scadere si ripete l'ultimo header, come
+// prevede la specifica: ZRINIT o ZRPOS dal receiver, ZRQINIT, ZFILE, ZEOF
+// o ZFIN dal sender. Dopo Timeouts.Retries tentativi senza risposta il
+// trasferimento viene annullato.
+
+// Timeouts sono le attese dei due lati del trasferimento.
+type Timeouts struct {
+	Header  time.Duration // attesa di un header (ZFILE, ZRINIT, ZRPOS...)
+	Data    time.Duration // silenzio ammesso durante la ricezione dei dati
+	Fin     time.Duration // attesa del ZFIN finale
+	Retries int           // ripetizioni prima di annullare
+}
+
+// DefaultTimeouts sono i valori usati da NewReceiver e NewSender.
+var DefaultTimeouts = Timeouts{
+	Header:  10 * time.Second,
+	Data:    10 * time.Second,
+	Fin:     5 * time.Second,
+	Retries: 10,
+}
+
+// timer è l'attesa in corso di un lato del trasferimento.
+type timer struct {
+	deadline time.Time // zero = nessuna attesa
+	tries    int       // ripetizioni dall'ultimo frame valido
+}
+
+// arm fa ripartire l'attesa di d (0 = nessuna attesa).
+func (t *timer) arm(d time.Duration) {
+	if d <= 0 {
+		t.deadline = time.Time{}
+		return
+	}
+	t.deadline = time.Now().Add(d)
+}
+
+// progress registra un frame valido: le ripetizioni ricominciano da zero.
+func (t *timer) progress(d time.Duration) {
+	t.tries = 0
+	t.arm(d)
+}
+
+// expired dice se l'attesa è scaduta all'ora now.
+func (t *timer) expired(now time.Time) bool {
+	return !t.deadline.IsZero() && !now.Before(t.deadline)
+}
+
+// ── Receiver ──
+
+// wait ritorna l'attesa dello stato corrente del receiver.
+func (r *Receiver) wait() time.Duration {
+	switch r.State {
+	case RxInit, RxWaitZFile:
+		return r.Timeouts.Header
+	case RxReceiving:
+		return r.Timeouts.Data
+	}
+	return 0
+}
+
+// Deadline ritorna quando scade l'attesa corrente (zero = nessuna).
+func (r *Receiver) Deadline() time.Time {
+	return r.timer.deadline
+}
+
+// Tick gestisce l'attesa scaduta all'ora now: ripete ZRINIT in attesa
+// del file, ZNAK in attesa delle sue informazioni e ZRPOS dalla posizione
+// raggiunta durante i dati. Senza effetto prima di Deadline.
+func (r *Receiver) Tick(now time.Time) {
+	if r.State == RxIdle || r.State == RxDone || !r.timer.expired(now) {
+		return
+	}
+	r.timer.tries++
+	if r.timer.tries > r.Timeouts.Retries {
+		msg := "Timeout ZMODEM — nessun file offerto dal server"
+		if r.State == RxReceiving {
+			msg = "Timeout ZMODEM — nessun dato ricevuto"
+		}
+		r.LogFunc(fmt.Sprintf("[RX] %s (stato %d)", msg, r.State))
+		if r.OnError != nil {
+			r.OnError(msg)
+		}
+		r.Cancel()
+		return
+	}
+
+	r.LogFunc(fmt.Sprintf("[RX] Timeout stato %d, tentativo %d/%d", r.State, r.timer.tries, r.Timeouts.Retries))
+	switch {
+	case r.State != RxReceiving:
+		r.SendFunc(BuildHexHeader(ZRINIT, 0, 0, 0, CANFDX|CANOVIO|CANFC32))
+	case r.fileHandle == nil:
+		r.SendFunc(BuildHexHeader(ZNAK, 0, 0, 0, 0))
+	default:
+		r.SendFunc(BuildPosHeader(ZRPOS, uint32(r.BytesReceived)))
+	}
+	r.timer.arm(r.wait())
+}
+
+// ── Sender ──
+
+// wait ritorna l'attesa dello stato corrente del sender.
+func (s *Sender) wait() time.Duration {
+	switch s.State {
+	case TxWaitRInit, TxWaitZRPos, TxWaitAck:
+		return s.Timeouts.Header
+	case TxWaitZFin:
+		return s.Timeouts.Fin
+	case TxSending:
+		if s.windowFull() {
+			return s.Timeouts.Header // ZACK di fine finestra
+		}
+	}
+	return 0
+}
+
+// Deadline ritorna quando scade l'attesa corrente (zero = nessuna).
+// Durante l'invio dei dati il receiver tace: non c'è attesa.
+func (s *Sender) Deadline() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.timer.deadline
+}
+
+// Tick gestisce l'attesa scaduta all'ora now ripetendo l'ultimo header.
+// Senza effetto prima di Deadline.
+func (s *Sender) Tick(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.State == TxIdle || s.State == TxDone || !s.timer.expired(now) {
+		return
+	}
+	s.timer.tries++
+	if s.timer.tries > s.Timeouts.Retries {
+		if s.State == TxWaitZFin {
+			// Il file è arrivato: la BBS non ha solo confermato la chiusura
+			s.LogFunc("[TX] Nessun ZFIN dalla BBS, chiudo")
+			s.State = TxDone
+			if s.OnFinished != nil {
+				s.OnFinished()
+			}
+			return
+		}
+		s.LogFunc(fmt.Sprintf("[TX] Timeout stato %d, annullo", s.State))
+		if s.OnError != nil {
+			s.OnError("Timeout ZMODEM — nessuna risposta dal server")
+		}
+		s.cancel()
+		return
+	}
+	s.LogFunc(fmt.Sprintf("[TX] Timeout stato %d, tentativo %d/%d", s.State, s.timer.tries, s.Timeouts.Retries))
+	s.resend()
+	s.timer.arm(s.wait())
+}
+
+// resend ripete l'ultimo header inviato, dopo un timeout o un ZNAK.
+func (s *Sender) resend() {
+	switch s.State {
+	case TxWaitRInit:
+		s.SendFunc(BuildHexHeader(ZRQINIT, 0, 0, 0, 0))
+	case TxWaitZRPos:
+		s.SendFunc(s.zfileFrame())
+	case TxWaitAck:
+		s.SendFunc(BuildPosHeader(ZEOF, uint32(s.BytesSent)))
+	case TxWaitZFin:
+		s.SendFunc(BuildHexHeader(ZFIN, 0, 0, 0, 0))
+	case TxSending:
+		// ZACK perso: si riparte dalla posizione confermata
+		s.rollback()
+		s.startSending(uint32(s.ackPos))
+	}
+}
@@ -0,0 +1,375 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	wailsrt "github.com/wailsapp/wails/v2/pkg/runtime"
+
+	"github.com/rj45lab/bbs-client-go/internal/i18n"
+)
+
+// ─────────────────────────────────────────────
+// Replay ANSImation — velocità modem simulata
+// ─────────────────────────────────────────────
+
+const (
+	// DefaultPlaybackBaud è la velocità di replay di default (modem 14.4k)
+	DefaultPlaybackBaud = 14400
+	// playbackTick è l'intervallo tra due aggiornamenti dello schermo
+	playbackTick = 33 * time.Millisecond
+)
+
+// player alimenta lo schermo con il contenuto del viewer a una velocità
+// simulata (baud/10 caratteri al secondo, 8N1; baud 0 = istantaneo)
+// oppure, se il log ha i tempi registrati, con le pause originali.
+// Tutti i campi sono protetti da a.mu.
+type player struct {
+	data   []rune
+	pos    int
+	baud   int
+	paused bool
+	stopCh chan struct{}
+	carry  float64 // frazione di carattere accumulata tra i tick
+
+	// Replay temporizzato
+	timing []timedChunk // nil = velocità in baud
+	scale  float64      // fattore di velocità (1 = tempo reale)
+	clock  time.Duration
+}
+
+// timedChunk è un blocco di dati ricevuto all'istante at dall'inizio della
+// sessione; end è la posizione (in rune) a cui il blocco termina.
+type timedChunk struct {
+	at  time.Duration
+	end int
+}
+
+// charsPerTick ritorna quanti caratteri alimentare in un tick.
+func (p *player) charsPerTick() int {
+	if p.timing != nil {
+		p.clock += time.Duration(float64(playbackTick) * p.scale)
+		if p.clock >= p.duration() {
+			return len(p.data) - p.pos // coda del log senza tempi
+		}
+		return p.posAt(p.clock) - p.pos
+	}
+	if p.baud <= 0 {
+		return len(p.data)
+	}
+	p.carry += float64(p.baud) / 10 * playbackTick.Seconds()
+	n := int(p.carry)
+	p.carry -= float64(n)
+	return n
+}
+
+// posAt ritorna la posizione nei dati raggiunta all'istante t.
+func (p *player) posAt(t time.Duration) int {
+	if p.timing == nil {
+		if p.baud <= 0 {
+			return len(p.data)
+		}
+		return min(int(t.Seconds()*float64(p.baud)/10), len(p.data))
+	}
+	pos := 0
+	for _, c := range p.timing {
+		if c.at > t {
+			break
+		}
+		pos = c.end
+	}
+	return min(pos, len(p.data))
+}
+
+// duration ritorna la durata totale del replay (0 se istantaneo).
+func (p *player) duration() time.Duration {
+	if p.timing != nil {
+		if n := len(p.timing); n > 0 {
+			return p.timing[n-1].at
+		}
+		return 0
+	}
+	if p.baud <= 0 {
+		return 0
+	}
+	return time.Duration(float64(len(p.data)) * 10 / float64(p.baud) * float64(time.Second))
+}
+
+// StartPlayback avvia il replay del log o dell'artwork aperto nel viewer
+// alla velocità indicata in baud (0 = istantaneo).
+func (a *App) StartPlayback(baud int) *i18n.Message {
+	a.haltPlayback()
+
+	a.mu.Lock()
+	if !a.viewingLog || a.viewerText == "" {
+		a.mu.Unlock()
+		return i18n.New(i18n.ErrNothingOpen)
+	}
+	p := &player{
+		data:   []rune(a.viewerText),
+		baud:   max(baud, 0),
+		stopCh: make(chan struct{}),
+	}
+	a.player = p
+	a.screenMu.Lock()
+	a.screen.Reset()
+	a.screenMu.Unlock()
+	a.mu.Unlock()
+
+	a.spawn(func() { a.playbackLoop(p) })
+	a.emitPlaybackState()
+	return nil
+}
+
+// StartTimedPlayback riproduce il log aperto con le pause originali della
+// sessione, accelerate o rallentate di scale volte (1 = tempo reale).
+// Richiede il file .timing registrato accanto al log.
+func (a *App) StartTimedPlayback(scale float64) *i18n.Message {
+	a.haltPlayback()
+
+	a.mu.Lock()
+	if !a.viewingLog || a.viewerText == "" {
+		a.mu.Unlock()
+		return i18n.New(i18n.ErrNothingOpen)
+	}
+	if a.viewerTiming == nil {
+		a.mu.Unlock()
+		return i18n.New(i18n.ErrNoTiming)
+	}
+	p := &player{
+		data:   []rune(a.viewerText),
+		timing: a.viewerTiming,
+		scale:  playbackScale(scale),
+		stopCh: make(chan struct{}),
+	}
+	a.player = p
+	a.screenMu.Lock()
+	a.screen.Reset()
+	a.screenMu.Unlock()
+	a.mu.Unlock()
+
+	a.spawn(func() { a.playbackLoop(p) })
+	a.emitPlaybackState()
+	return nil
+}
+
+// SetPlaybackScale cambia il fattore di velocità del replay temporizzato.
+func (a *App) SetPlaybackScale(scale float64) {
+	a.mu.Lock()
+	if a.player != nil {
+		a.player.scale = playbackScale(scale)
+	}
+	a.mu.Unlock()
+	a.emitPlaybackState()
+}
+
+// SeekPlayback porta il replay all'istante ms (millisecondi dall'inizio),
+// ridisegnando lo schermo da capo fino a quel punto.
+func (a *App) SeekPlayback(ms int) {
+	a.mu.Lock()
+	p := a.player
+	if p == nil {
+		a.mu.Unlock()
+		return
+	}
+	t := time.Duration(max(ms, 0)) * time.Millisecond
+	p.clock, p.carry = t, 0
+	p.pos = 0
+	a.screenMu.Lock()
+	a.screen.Reset()
+	a.screenMu.Unlock()
+	a.feedPlayback(p, p.posAt(t))
+	a.mu.Unlock()
+	a.pushScreen()
+	a.emitPlaybackState()
+}
+
+// playbackScale limita il fattore di velocità a valori sensati.
+func playbackScale(scale float64) float64 {
+	if scale <= 0 {
+		return 1
+	}
+	return min(max(scale, 0.1), 100)
+}
+
+// PausePlayback mette in pausa il replay.
+func (a *App) PausePlayback() {
+	a.setPlaybackPaused(true)
+}
+
+// ResumePlayback riprende il replay in pausa.
+func (a *App) ResumePlayback() {
+	a.setPlaybackPaused(false)
+}
+
+func (a *App) setPlaybackPaused(paused bool) {
+	a.mu.Lock()
+	if a.player != nil {
+		a.player.paused = paused
+	}
+	a.mu.Unlock()
+	a.emitPlaybackState()
+}
+
+// StepPlayback avanza di un singolo tick (a replay in pausa), per
+// esaminare un'animazione fotogramma per fotogramma.
+func (a *App) StepPlayback() {
+	a.mu.Lock()
+	p := a.player
+	if p == nil || !p.paused {
+		a.mu.Unlock()
+		return
+	}
+	a.feedPlayback(p, max(p.charsPerTick(), 1))
+	a.mu.Unlock()
+	a.pushScreen()
+	a.emitPlaybackState()
+}
+
+// SetPlaybackSpeed cambia la velocità del replay in corso.
+func (a *App) SetPlaybackSpeed(baud int) {
+	a.mu.Lock()
+	if a.player != nil {
+		a.player.baud = max(baud, 0)
+		a.player.carry = 0
+	}
+	a.mu.Unlock()
+	a.emitPlaybackState()
+}
+
+// StopPlayback interrompe il replay e torna alla visualizzazione a pagine.
+func (a *App) StopPlayback() {
+	if a.haltPlayback() {
+		a.showLogPage()
+		a.emitPlaybackState()
+	}
+}
+
+// haltPlayback ferma la goroutine di replay, se attiva.
+func (a *App) haltPlayback() bool {
+	a.mu.Lock()
+	p := a.player
+	a.player = nil
+	a.mu.Unlock()
+	if p == nil {
+		return false
+	}
+	close(p.stopCh)
+	return true
+}
+
+// feedPlayback alimenta lo schermo con n caratteri. Chiamare con a.mu acquisito.
+func (a *App) feedPlayback(p *player, n int) {
+	end := min(p.pos+n, len(p.data))
+	a.screenMu.Lock()
+	a.screen.Feed(string(p.data[p.pos:end]))
+	a.screenMu.Unlock()
+	p.pos = end
+}
+
+func (a *App) playbackLoop(p *player) {
+	ticker := time.NewTicker(playbackTick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stopCh:
+			return
+		case <-a.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		a.mu.Lock()
+		if a.player != p {
+			a.mu.Unlock()
+			return // fermato mentre attendevamo il lock
+		}
+		if p.paused {
+			a.mu.Unlock()
+			continue
+		}
+		a.feedPlayback(p, p.charsPerTick())
+		done := p.pos >= len(p.data)
+		if done {
+			p.paused = true
+		}
+		a.mu.Unlock()
+
+		a.pushScreen()
+		if done {
+			a.emitPlaybackState()
+		}
+	}
+}
+
+// emitPlaybackState notifica il frontend dello stato del replay.
+func (a *App) emitPlaybackState() {
+	a.mu.Lock()
+	var state PlaybackState
+	if p := a.player; p != nil {
+		elapsed := p.clock
+		if p.timing == nil {
+			elapsed = p.duration() * time.Duration(p.pos) / time.Duration(max(len(p.data), 1))
+		}
+		state = PlaybackState{
+			Active: true, Paused: p.paused, Baud: p.baud,
+			Pos: p.pos, Total: len(p.data),
+			Timed: p.timing != nil, Scale: p.scale,
+			ElapsedMs: elapsed.Milliseconds(), DurationMs: p.duration().Milliseconds(),
+		}
+	}
+	a.mu.Unlock()
+	wailsrt.EventsEmit(a.ctx, "playback-state", state)
+}
+
+// ─────────────────────────────────────────────
+// Temporizzazione dei log (formato scriptreplay)
+// ─────────────────────────────────────────────
+
+// timingPath ritorna il file dei tempi associato a un log di sessione.
+func timingPath(logPath string) string {
+	return strings.TrimSuffix(logPath, ".log") + ".timing"
+}
+
+// writeTiming aggiunge al file dei tempi un record "<secondi dal record
+// precedente> <byte>", lo stesso formato di script(1)/scriptreplay.
+func (s *session) writeTiming(n int) {
+	if s.timingFile == nil || n == 0 {
+		return
+	}
+	now := time.Now()
+	fmt.Fprintf(s.timingFile, "%.6f %d\n", now.Sub(s.lastLogWrite).Seconds(), n)
+	s.lastLogWrite = now
+}
+
+// parseTiming converte i record di un file .timing in blocchi con istante
+// cumulativo e posizione (in rune) nel testo del log.
+func parseTiming(data []byte, text string) []timedChunk {
+	var chunks []timedChunk
+	var at time.Duration
+	off, runes := 0, 0
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		delay, err1 := strconv.ParseFloat(fields[0], 64)
+		n, err2 := strconv.Atoi(fields[1])
+		if err1 != nil || err2 != nil || n < 0 {
+			continue
+		}
+		end := min(off+n, len(text))
+		runes += utf8.RuneCountInString(text[off:end])
+		off = end
+		at += time.Duration(delay * float64(time.Second))
+		chunks = append(chunks, timedChunk{at: at, end: runes})
+	}
+	return chunks
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"log"
+	"slices"
+	"time"
+
+	"github.com/rj45lab/bbs-client-go/internal/config"
+	"github.com/rj45lab/bbs-client-go/internal/cp437"
+	"github.com/rj45lab/bbs-client-go/internal/i18n"
+	"github.com/rj45lab/bbs-client-go/internal/plugin"
+)
+
+// ─────────────────────────────────────────────
+// Plugin esterni
+// ─────────────────────────────────────────────
+//
+// I plugin configurati in Settings.Plugins vengono avviati quando una
+// sessione si connette e fermati alla disconnessione. Ognuno riceve il
+// testo della BBS e gli eventi della sessione con il protocollo descritto
+// in internal/plugin.
+
+// startPlugins avvia i plugin abilitati per la BBS di s. Un plugin che non
+// parte viene segnalato senza interrompere la connessione.
+func (a *App) startPlugins(s *session) {
+	a.mu.Lock()
+	key := bbsKey(s.host, s.port)
+	hello := plugin.Hello{Session: s.id, BBS: s.bbsName, Host: s.host, Port: s.port}
+	var specs []config.Plugin
+	for _, p := range a.settings.Plugins {
+		if p.Enabled && (len(p.BBS) == 0 || slices.Contains(p.BBS, key)) {
+			specs = append(specs, p)
+		}
+	}
+	a.mu.Unlock()
+	if len(specs) == 0 {
+		return
+	}
+
+	var started []*plugin.Plugin
+	for _, spec := range specs {
+		p, err := plugin.Start(plugin.Spec{Name: spec.Name, Command: spec.Command, Args: spec.Args}, hello, pluginHost{a, s, spec.Name})
+		if err != nil {
+			log.Printf("[PLUGIN] %s: %v", spec.Name, err)
+			a.emitFor(s, "status-message", i18n.New(i18n.ErrPluginStart, spec.Name, err.Error()))
+			continue
+		}
+		started = append(started, p)
+	}
+
+	a.mu.Lock()
+	old := s.plugins
+	s.plugins = started
+	if !s.connected {
+		// Disconnessa mentre i plugin partivano: endPlugins è già passato
+		old, s.plugins = append(old, started...), nil
+	}
+	a.mu.Unlock()
+	stopPlugins(old)
+}
+
+// endPlugins ferma i plugin della sessione. Chiamare senza a.mu.
+func (a *App) endPlugins(s *session) {
+	a.mu.Lock()
+	plugins := s.plugins
+	s.plugins = nil
+	a.mu.Unlock()
+	stopPlugins(plugins)
+}
+
+// stopPlugins chiude i plugin in background: ognuno ha qualche secondo
+// per uscire da solo.
+func stopPlugins(plugins []*plugin.Plugin) {
+	for _, p := range plugins {
+		go p.Close()
+	}
+}
+
+// GetPlugins ritorna i plugin configurati.
+func (a *App) GetPlugins() []config.Plugin {
+	return a.config.Get().Plugins
+}
+
+// SetPlugins sostituisce i plugin configurati. Valgono dalla prossima
+// connessione.
+func (a *App) SetPlugins(plugins []config.Plugin) *i18n.Message {
+	for i := range plugins {
+		if err := validatePlugin(&plugins[i]); err != nil {
+			return err
+		}
+	}
+	return a.updateSettings(func(s *config.Settings) {
+		s.Plugins = plugins
+	})
+}
+
+// validatePlugin controlla un plugin e completa il nome mancante.
+func validatePlugin(p *config.Plugin) *i18n.Message {
+	if p.Command == "" {
+		return i18n.New(i18n.ErrInvalidPlugin, p.Name)
+	}
+	if p.Name == "" {
+		p.Name = p.Command
+	}
+	return nil
+}
+
+// pluginHost collega un plugin a una scheda (vedi plugin.Host).
+type pluginHost struct {
+	a    *App
+	s    *session
+	name string
+}
+
+func (h pluginHost) Send(text string) error {
+	h.a.mu.Lock()
+	connected := h.s.connected
+	if connected {
+		// Come per gli script, i tasti del plugin contano come input
+		h.s.idle = idleState{lastInput: time.Now()}
+	}
+	h.a.mu.Unlock()
+	if !connected {
+		return i18n.New(i18n.ErrNotConnected)
+	}
+	return h.s.conn.Send(cp437.Encode(text))
+}
+
+func (h pluginHost) Status(text string) {
+	h.a.emitFor(h.s, "status-message", i18n.New(i18n.MsgPluginStatus, h.name, text))
+}
+
+// Triggered è chiamato dal loop eventi della scheda (vedi Plugin.Output).
+func (h pluginHost) Triggered(id, line string) {
+	h.s.eventLog.write(evTrigger, map[string]any{"plugin": h.name, "id": id, "line": line})
+}
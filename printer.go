@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/rj45lab/bbs-client-go/internal/config"
+	"github.com/rj45lab/bbs-client-go/internal/i18n"
+	"github.com/rj45lab/bbs-client-go/internal/printer"
+)
+
+// ─────────────────────────────────────────────
+// Stampa richiesta dalla BBS (media copy)
+// ─────────────────────────────────────────────
+//
+// Lo screen toglie dallo schermo il testo destinato alla stampante (vedi
+// ansi/printer.go) e consegna ogni lavoro completo a onPrint. Secondo
+// Settings.Printer il lavoro viene salvato in un file nella directory dei
+// log, mandato alla stampante del sistema dopo una conferma, o scartato.
+
+// Destinazioni delle stampe (Settings.Printer)
+const (
+	printToFile   = "file"
+	printToSystem = "system"
+	printOff      = "off"
+)
+
+// printModes elenca le destinazioni valide.
+var printModes = []string{printToFile, printToSystem, printOff}
+
+// onPrint riceve un lavoro di stampa dallo screen di s. Chiamato da
+// Screen.Feed, con a.mu acquisito: il lavoro prosegue in una goroutine.
+func (a *App) onPrint(s *session, job string) {
+	// Log e replay non stampano di nuovo ciò che la BBS aveva stampato
+	if !s.connected || s.viewingLog {
+		return
+	}
+	go a.printJob(s, a.settings.Printer, s.bbsName, job)
+}
+
+// printJob salva o stampa un lavoro secondo mode.
+func (a *App) printJob(s *session, mode, bbsName, job string) {
+	if mode == printOff {
+		log.Printf("[PRINT] Lavoro di %d byte scartato", len(job))
+		return
+	}
+	path, err := a.savePrintJob(bbsName, job)
+	if err != nil {
+		log.Printf("[PRINT] Salvataggio fallito: %v", err)
+		a.emitFor(s, "status-message", i18n.New(i18n.ErrPrint, err.Error()))
+		return
+	}
+	if mode != printToSystem {
+		log.Printf("[PRINT] Lavoro salvato in %s", path)
+		a.emitFor(s, "status-message", i18n.New(i18n.MsgPrintSaved, path))
+		return
+	}
+
+	// La BBS non può stampare senza il consenso dell'utente
+	msg := i18n.T(i18n.DlgPrintMessage, bbsName, len(job))
+	if !a.ask(i18n.T(i18n.DlgPrintTitle), msg, i18n.T(i18n.DlgPrint)) {
+		a.emitFor(s, "status-message", i18n.New(i18n.MsgPrintSaved, path))
+		return
+	}
+	if err := printer.PrintFile(path); err != nil {
+		log.Printf("[PRINT] Stampa fallita: %v", err)
+		a.emitFor(s, "status-message", i18n.New(i18n.ErrPrint, err.Error()))
+		return
+	}
+	log.Printf("[PRINT] %s inviato alla stampante", path)
+	a.emitFor(s, "status-message", i18n.New(i18n.MsgPrinted))
+}
+
+// savePrintJob scrive il lavoro in un nuovo file nella directory dei log.
+func (a *App) savePrintJob(bbsName, job string) (string, error) {
+	name := fmt.Sprintf("print_%s_%s.txt", safeFileName(bbsName), time.Now().Format("2006-01-02_150405"))
+	path := filepath.Join(a.logDir, name)
+	// SEC-005: i messaggi stampati possono contenere dati personali
+	if err := os.MkdirAll(a.logDir, 0700); err != nil {
+		return "", err
+	}
+	return path, os.WriteFile(path, []byte(job), 0600)
+}
+
+// SetPrinter sceglie la destinazione delle stampe: file, system o off.
+func (a *App) SetPrinter(mode string) *i18n.Message {
+	if !slices.Contains(printModes, mode) {
+		return i18n.New(i18n.ErrUnknownPrinter, mode)
+	}
+	return a.updateSettings(func(s *config.Settings) {
+		s.Printer = mode
+	})
+}
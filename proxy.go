@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"slices"
+	"strconv"
+
+	"github.com/rj45lab/bbs-client-go/internal/addressbook"
+	"github.com/rj45lab/bbs-client-go/internal/config"
+	"github.com/rj45lab/bbs-client-go/internal/i18n"
+	"github.com/rj45lab/bbs-client-go/internal/keychain"
+	"github.com/rj45lab/bbs-client-go/internal/proxy"
+	"github.com/rj45lab/bbs-client-go/pkg/telnet"
+)
+
+// ─────────────────────────────────────────────
+// Proxy per BBS
+// ─────────────────────────────────────────────
+//
+// Ogni voce della rubrica sceglie come connettersi (Entry.Proxy):
+// direttamente, attraverso Tor o con uno dei profili delle impostazioni.
+// Le password dei profili sono nel portachiavi, come quelle delle BBS.
+
+// proxyKey è la voce del portachiavi con la password del profilo name.
+func proxyKey(name string) string {
+	return "proxy:" + name
+}
+
+// dialerFor ritorna il Dialer per il proxy name di una voce (nil =
+// connessione diretta).
+func (a *App) dialerFor(name string) (telnet.Dialer, *i18n.Message) {
+	a.mu.Lock()
+	tor := a.settings.Tor
+	i := slices.IndexFunc(a.settings.Proxies, func(p config.Proxy) bool { return p.Name == name })
+	var p config.Proxy
+	if i >= 0 {
+		p = a.settings.Proxies[i]
+	}
+	a.mu.Unlock()
+
+	switch {
+	case name == "" || name == addressbook.ProxyDirect:
+		return nil, nil
+	case name == addressbook.ProxyTor:
+		return proxy.Tor(tor), nil
+	case i < 0:
+		return nil, i18n.New(i18n.ErrUnknownProxy, name)
+	}
+	password, err := keychain.Password(proxyKey(name))
+	if err != nil {
+		log.Printf("[PROXY] Password di %s non disponibile: %v", name, err)
+	}
+	d, err := proxy.New(p.Type, net.JoinHostPort(p.Host, strconv.Itoa(p.Port)), p.Username, password)
+	if err != nil {
+		return nil, i18n.New(i18n.ErrInvalidProxy, err.Error())
+	}
+	log.Printf("[PROXY] Connessione attraverso %s (%s)", name, p.Type)
+	return d, nil
+}
+
+// validProxy controlla un profilo di proxy.
+func validProxy(p config.Proxy) error {
+	switch {
+	case p.Name == "" || p.Name == addressbook.ProxyDirect || p.Name == addressbook.ProxyTor:
+		return fmt.Errorf("nome %q", p.Name)
+	case !slices.Contains(proxy.Kinds, p.Type):
+		return fmt.Errorf("tipo %q", p.Type)
+	case p.Host == "":
+		return fmt.Errorf("host mancante")
+	case p.Port < 1 || p.Port > 65535:
+		return fmt.Errorf("porta %d", p.Port)
+	}
+	return nil
+}
+
+// GetProxies ritorna i profili di proxy.
+func (a *App) GetProxies() []config.Proxy {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return slices.Clone(a.settings.Proxies)
+}
+
+// GetProxyTypes ritorna i tipi di proxy disponibili.
+func (a *App) GetProxyTypes() []string {
+	return proxy.Kinds
+}
+
+// SetProxy aggiunge o sostituisce (per nome) un profilo di proxy e ne
+// salva la password nel portachiavi; una password vuota cancella quella
+// salvata.
+func (a *App) SetProxy(p config.Proxy, password string) *i18n.Message {
+	if err := validProxy(p); err != nil {
+		return i18n.New(i18n.ErrInvalidProxy, err.Error())
+	}
+	if err := keychain.SetPassword(proxyKey(p.Name), password); err != nil {
+		return i18n.New(i18n.ErrKeychain, err)
+	}
+	return a.updateSettings(func(s *config.Settings) {
+		if i := slices.IndexFunc(s.Proxies, func(q config.Proxy) bool { return q.Name == p.Name }); i >= 0 {
+			s.Proxies[i] = p
+		} else {
+			s.Proxies = append(s.Proxies, p)
+		}
+	})
+}
+
+// DeleteProxy rimuove un profilo di proxy e la sua password. Le BBS che lo
+// usano non si connettono finché non ne scelgono un altro.
+func (a *App) DeleteProxy(name string) *i18n.Message {
+	if err := keychain.SetPassword(proxyKey(name), ""); err != nil {
+		return i18n.New(i18n.ErrKeychain, err)
+	}
+	return a.updateSettings(func(s *config.Settings) {
+		s.Proxies = slices.DeleteFunc(s.Proxies, func(p config.Proxy) bool { return p.Name == name })
+	})
+}
+
+// SetTorAddress imposta l'indirizzo SOCKS del servizio Tor ("" =
+// 127.0.0.1:9050).
+func (a *App) SetTorAddress(addr string) *i18n.Message {
+	if addr != "" {
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			return i18n.New(i18n.ErrInvalidProxy, err.Error())
+		}
+	}
+	return a.updateSettings(func(s *config.Settings) {
+		s.Tor = addr
+	})
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"log"
+	"time"
+
+	wailsrt "github.com/wailsapp/wails/v2/pkg/runtime"
+
+	"github.com/rj45lab/bbs-client-go/internal/addressbook"
+	"github.com/rj45lab/bbs-client-go/internal/i18n"
+)
+
+// ─────────────────────────────────────────────
+// Riconnessione quando la linea cade
+// ─────────────────────────────────────────────
+//
+// Se la connessione si chiude senza che l'utente l'abbia chiesto, decide
+// la voce della rubrica (Entry.Reconnect): con "ask" il frontend riceve
+// reconnect-prompt e richiama Reconnect, con "auto" la scheda riprova da
+// sola fino a Entry.Retries() volte, con un'attesa crescente e il conto
+// alla rovescia inviato come reconnect-countdown.
+
+const (
+	// reconnectDelay è l'attesa prima del primo tentativo
+	reconnectDelay = 5 * time.Second
+	// maxReconnectDelay limita l'attesa, che raddoppia a ogni tentativo
+	maxReconnectDelay = time.Minute
+)
+
+// ReconnectEvent descrive una riconnessione per il frontend.
+type ReconnectEvent struct {
+	Session string `json:"session"`
+	Name    string `json:"name"`
+	Host    string `json:"host"`
+	Port    int    `json:"port"`
+	Attempt int    `json:"attempt,omitempty"` // tentativo in attesa o in corso
+	Retries int    `json:"retries,omitempty"`
+	Seconds int    `json:"seconds"` // al tentativo (0 = in corso)
+}
+
+// onLineDropped applica la politica di riconnessione della BBS quando la
+// connessione di s si chiude. Le chiusure volute dall'utente e le
+// connessioni mai riuscite non contano.
+func (a *App) onLineDropped(s *session) {
+	a.mu.Lock()
+	dropped := !s.hangup && !s.stats.ConnectedAt.IsZero() && s.reconnect == nil
+	ev := ReconnectEvent{Session: s.id, Name: s.bbsName, Host: s.host, Port: s.port}
+	a.mu.Unlock()
+	if !dropped {
+		return
+	}
+	entry, ok := a.book.Lookup(ev.Host, ev.Port)
+	if !ok {
+		return
+	}
+	switch entry.Reconnect {
+	case addressbook.ReconnectAsk:
+		wailsrt.EventsEmit(a.ctx, "reconnect-prompt", ev)
+	case addressbook.ReconnectAuto:
+		stop := make(chan struct{})
+		a.mu.Lock()
+		s.reconnect = stop
+		a.mu.Unlock()
+		go a.autoReconnect(s, ev, entry.Retries(), stop)
+	}
+}
+
+// autoReconnect riprova a connettere s finché non riesce, finché non
+// finiscono i tentativi o finché stop non viene chiuso.
+func (a *App) autoReconnect(s *session, ev ReconnectEvent, retries int, stop chan struct{}) {
+	defer func() {
+		a.mu.Lock()
+		if s.reconnect == stop {
+			s.reconnect = nil
+		}
+		a.mu.Unlock()
+	}()
+
+	delay := reconnectDelay
+	ev.Retries = retries
+	for ev.Attempt = 1; ev.Attempt <= retries; ev.Attempt++ {
+		for ev.Seconds = int(delay / time.Second); ev.Seconds > 0; ev.Seconds-- {
+			wailsrt.EventsEmit(a.ctx, "reconnect-countdown", ev)
+			select {
+			case <-stop:
+				wailsrt.EventsEmit(a.ctx, "reconnect-cancelled", ev)
+				return
+			case <-s.done:
+				return
+			case <-time.After(time.Second):
+			}
+		}
+		wailsrt.EventsEmit(a.ctx, "reconnect-countdown", ev)
+		log.Printf("[RECONNECT] %s:%d, tentativo %d di %d", ev.Host, ev.Port, ev.Attempt, retries)
+		err := a.connectSession(s, ev.Host, ev.Port, ev.Name)
+		if err == nil || err.Code == i18n.ErrAlreadyConnected {
+			return
+		}
+		delay = min(delay*2, maxReconnectDelay)
+	}
+	wailsrt.EventsEmit(a.ctx, "reconnect-failed", ev)
+	a.emitFor(s, "status-message", i18n.New(i18n.MsgReconnectFailed, ev.Name, retries))
+}
+
+// cancelReconnectLocked ferma la riconnessione automatica in corso su s.
+// Chiamare con a.mu acquisito.
+func (s *session) cancelReconnectLocked() {
+	if s.reconnect != nil {
+		close(s.reconnect)
+		s.reconnect = nil
+	}
+}
+
+// Reconnect riconnette la scheda id ("" = attiva) all'ultima BBS chiamata,
+// in risposta a reconnect-prompt.
+func (a *App) Reconnect(id string) *i18n.Message {
+	a.mu.Lock()
+	s := a.session
+	if id != "" {
+		s = a.findSession(id)
+	}
+	if s == nil {
+		a.mu.Unlock()
+		return i18n.New(i18n.ErrUnknownSession, id)
+	}
+	s.cancelReconnectLocked()
+	host, port, name := s.host, s.port, s.bbsName
+	a.mu.Unlock()
+	return a.connectSession(s, host, port, name)
+}
+
+// CancelReconnect ferma la riconnessione automatica della scheda id ("" =
+// attiva).
+func (a *App) CancelReconnect(id string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	s := a.session
+	if id != "" {
+		s = a.findSession(id)
+	}
+	if s != nil {
+		s.cancelReconnectLocked()
+	}
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	wailsrt "github.com/wailsapp/wails/v2/pkg/runtime"
+
+	"github.com/rj45lab/bbs-client-go/internal/i18n"
+)
+
+// ─────────────────────────────────────────────
+// Ripristino dopo un crash
+// ─────────────────────────────────────────────
+//
+// Mentre almeno una scheda è connessa, lo stato essenziale delle sessioni
+// (BBS, schermo, download in corso) viene salvato periodicamente. A una
+// chiusura regolare il file viene cancellato: se all'avvio esiste ancora,
+// l'app si era chiusa in modo inatteso e propone di riconnettersi.
+
+const (
+	restoreFileName = "session-state.json"
+	restoreInterval = 10 * time.Second
+)
+
+// savedSession è lo stato salvato di una scheda connessa.
+type savedSession struct {
+	Name     string `json:"name"`
+	Host     string `json:"host"`
+	Port     int    `json:"port"`
+	Cols     int    `json:"cols"`
+	Rows     int    `json:"rows"`
+	Screen   string `json:"screen"`             // ANSI, vedi ansi.Screen.Serialize
+	Transfer string `json:"transfer,omitempty"` // download ZMODEM in corso
+}
+
+// restoreState è il contenuto del file di ripristino.
+type restoreState struct {
+	SavedAt  time.Time      `json:"savedAt"`
+	Sessions []savedSession `json:"sessions"`
+}
+
+// restoreFilePath ritorna il percorso del file di ripristino ("" se la
+// directory di configurazione non è disponibile).
+func restoreFilePath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "bbs-client", restoreFileName)
+}
+
+// loadRestoreState legge lo stato lasciato da una chiusura inattesa.
+func loadRestoreState(path string) *restoreState {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var st restoreState
+	if err := json.Unmarshal(data, &st); err != nil || len(st.Sessions) == 0 {
+		log.Printf("[RESTORE] Stato non valido, ignorato: %v", err)
+		os.Remove(path)
+		return nil
+	}
+	return &st
+}
+
+// snapshotSessions raccoglie lo stato delle schede connesse.
+func (a *App) snapshotSessions() []savedSession {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	var out []savedSession
+	for _, s := range a.sessions {
+		if !s.connected {
+			continue
+		}
+		out = append(out, savedSession{
+			Name:     s.bbsName,
+			Host:     s.host,
+			Port:     s.port,
+			Cols:     s.screen.Cols,
+			Rows:     s.screen.Rows,
+			Screen:   s.screen.Serialize(),
+			Transfer: s.transfer,
+		})
+	}
+	return out
+}
+
+// restoreLoop salva lo stato ogni restoreInterval, solo se cambiato, fino
+// alla chiusura dell'app (stopRestore).
+func (a *App) restoreLoop(path string) {
+	defer close(a.restoreDone)
+	ticker := time.NewTicker(restoreInterval)
+	defer ticker.Stop()
+
+	var last []byte
+	for {
+		select {
+		case <-a.restoreStop:
+			return
+		case <-ticker.C:
+		}
+
+		sessions := a.snapshotSessions()
+		if len(sessions) == 0 {
+			if last != nil {
+				os.Remove(path)
+				last = nil
+			}
+			continue
+		}
+		// Il confronto ignora l'ora di salvataggio
+		cur, _ := json.Marshal(sessions)
+		if bytes.Equal(cur, last) {
+			continue
+		}
+		data, err := json.Marshal(restoreState{SavedAt: time.Now(), Sessions: sessions})
+		if err != nil {
+			continue
+		}
+		if err := writeRestoreFile(path, data); err != nil {
+			log.Printf("[RESTORE] Salvataggio fallito: %v", err)
+			continue
+		}
+		last = cur
+	}
+}
+
+// writeRestoreFile scrive il file in modo atomico.
+func writeRestoreFile(path string, data []byte) error {
+	// SEC-005: lo schermo può contenere dati personali
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// startRestore legge lo stato di una chiusura inattesa e avvia il
+// salvataggio periodico.
+func (a *App) startRestore() {
+	path := restoreFilePath()
+	if path == "" {
+		return
+	}
+	// Lo stato letto resta in memoria fino a offerRestore: il file viene
+	// riscritto solo se una scheda si connette
+	a.pendingRestore = loadRestoreState(path)
+	os.Remove(path)
+	a.restoreStop = make(chan struct{})
+	a.restoreDone = make(chan struct{})
+	go a.restoreLoop(path)
+}
+
+// stopRestore ferma il salvataggio e cancella il file: chiusura regolare.
+func (a *App) stopRestore() {
+	if a.restoreStop == nil {
+		return
+	}
+	close(a.restoreStop)
+	<-a.restoreDone
+	os.Remove(restoreFilePath())
+}
+
+// offerRestore ripristina gli schermi delle sessioni interrotte e propone
+// di riconnettersi. Chiamato a frontend pronto.
+func (a *App) offerRestore() {
+	a.mu.Lock()
+	st := a.pendingRestore
+	a.pendingRestore = nil
+	a.mu.Unlock()
+	if st == nil {
+		return
+	}
+
+	// Schermi: la prima nella scheda attiva (se libera), le altre in nuove schede
+	var restored []*session
+	var names, transfers []string
+	a.mu.Lock()
+	for i, saved := range st.Sessions {
+		s := a.session
+		if i > 0 || s.connected || s.viewingLog {
+			s = a.newSession()
+		}
+		s.host, s.port, s.bbsName = saved.Host, saved.Port, saved.Name
+		a.screenMu.Lock()
+		s.screen.Reset()
+		if saved.Cols == s.screen.Cols && saved.Rows == s.screen.Rows {
+			s.screen.Feed(saved.Screen)
+		}
+		a.screenMu.Unlock()
+		restored = append(restored, s)
+		names = append(names, fmt.Sprintf("%s (%s:%d)", saved.Name, saved.Host, saved.Port))
+		if saved.Transfer != "" {
+			transfers = append(transfers, saved.Transfer)
+		}
+	}
+	a.mu.Unlock()
+	a.SwitchSession(restored[0].id)
+
+	msg := i18n.T(i18n.DlgRestoreMessage, st.SavedAt.Format("02/01/2006 15:04"), strings.Join(names, "\n"))
+	if len(transfers) > 0 {
+		msg += "\n\n" + i18n.T(i18n.DlgRestoreFiles, strings.Join(transfers, "\n"))
+	}
+	if !a.ask(i18n.T(i18n.DlgRestoreTitle), msg, i18n.T(i18n.DlgReconnect)) {
+		return
+	}
+	for _, s := range restored {
+		a.SwitchSession(s.id)
+		if err := a.Connect(s.host, s.port, s.bbsName).Error; err != nil {
+			wailsrt.EventsEmit(a.ctx, "status-message", err)
+		}
+	}
+	a.SwitchSession(restored[0].id)
+}
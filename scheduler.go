@@ -0,0 +1,255 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	wailsrt "github.com/wailsapp/wails/v2/pkg/runtime"
+
+	"github.com/rj45lab/bbs-client-go/internal/config"
+	"github.com/rj45lab/bbs-client-go/internal/cp437"
+	"github.com/rj45lab/bbs-client-go/internal/i18n"
+	"github.com/rj45lab/bbs-client-go/internal/notify"
+	"github.com/rj45lab/bbs-client-go/internal/script"
+)
+
+// ─────────────────────────────────────────────
+// Chiamate programmate
+// ─────────────────────────────────────────────
+//
+// Ogni chiamata programmata apre una scheda in background, si connette,
+// esegue lo script (ad esempio il download della posta) e riaggancia.
+// Il controllo avviene anche con la finestra nascosta nel tray; se il
+// computer era sospeso all'ora prevista, la chiamata parte al risveglio.
+// L'esito arriva come notifica di sistema e con l'evento
+// "schedule-finished".
+
+const (
+	// scheduleTick è la frequenza di controllo delle chiamate
+	scheduleTick = 20 * time.Second
+	// scheduleTimeout limita la durata di uno script programmato
+	scheduleTimeout = 30 * time.Minute
+)
+
+// ScheduleResult è l'esito di una chiamata programmata.
+type ScheduleResult struct {
+	ID    string        `json:"id"`
+	Name  string        `json:"name"`
+	Error *i18n.Message `json:"error"` // nil = riuscita
+}
+
+// scheduleLoop avvia le chiamate programmate all'ora prevista, fino alla
+// chiusura dell'app.
+func (a *App) scheduleLoop() {
+	ticker := time.NewTicker(scheduleTick)
+	defer ticker.Stop()
+	last := time.Now()
+	for {
+		select {
+		case <-a.ctx.Done():
+			return
+		case now := <-ticker.C:
+			for _, sc := range a.config.Get().Schedules {
+				if next, ok := nextRun(sc, last); ok && !next.After(now) {
+					a.spawn(func() { a.runSchedule(sc) })
+				}
+			}
+			last = now
+		}
+	}
+}
+
+// nextRun ritorna la prima esecuzione di sc successiva a after; false se
+// la chiamata è disattivata o non valida.
+func nextRun(sc config.Schedule, after time.Time) (time.Time, bool) {
+	if !sc.Enabled {
+		return time.Time{}, false
+	}
+	at, err := time.Parse("15:04", sc.Time)
+	if err != nil {
+		return time.Time{}, false
+	}
+	day := time.Date(after.Year(), after.Month(), after.Day(), at.Hour(), at.Minute(), 0, 0, after.Location())
+	for i := 0; i < 8; i++ {
+		t := day.AddDate(0, 0, i)
+		if t.After(after) && (len(sc.Days) == 0 || slices.Contains(sc.Days, int(t.Weekday()))) {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// runSchedule esegue una chiamata programmata in una nuova scheda.
+func (a *App) runSchedule(sc config.Schedule) {
+	a.mu.Lock()
+	if a.scheduleRunning[sc.ID] {
+		a.mu.Unlock()
+		return // la chiamata precedente non è ancora finita
+	}
+	a.scheduleRunning[sc.ID] = true
+	s := a.newSession()
+	a.mu.Unlock()
+	defer func() {
+		a.mu.Lock()
+		delete(a.scheduleRunning, sc.ID)
+		a.mu.Unlock()
+	}()
+	a.emitSessions()
+
+	log.Printf("[SCHEDULE] %s: chiamata a %s:%d", sc.Name, sc.Host, sc.Port)
+	err := a.connectSession(s, sc.Host, sc.Port, sc.Name)
+	if err == nil && sc.Script != "" {
+		ctx, cancel := context.WithTimeout(a.ctx, scheduleTimeout)
+		err = i18n.Err(script.Run(ctx, sc.Script, sessionHost{a, s}, a.downloadDir()))
+		cancel()
+	}
+	if sc.Disconnect {
+		a.disconnectSession(s)
+		if err == nil {
+			// Il log resta su disco: la scheda non serve più
+			a.CloseSession(s.id)
+		}
+	}
+
+	res := ScheduleResult{ID: sc.ID, Name: sc.Name, Error: err}
+	title := i18n.T(i18n.MsgScheduleDone, sc.Name)
+	body := fmt.Sprintf("%s:%d", sc.Host, sc.Port)
+	if err != nil {
+		log.Printf("[SCHEDULE] %s: %s", sc.Name, err.Text)
+		title = i18n.T(i18n.ErrScheduleFailed, sc.Name)
+		body = err.Text
+	} else {
+		log.Printf("[SCHEDULE] %s: completata", sc.Name)
+	}
+	if nerr := notify.Send(title, body); nerr != nil {
+		log.Printf("[SCHEDULE] Notifica non inviata: %v", nerr)
+	}
+	wailsrt.EventsEmit(a.ctx, "schedule-finished", res)
+}
+
+// ─────────────────────────────────────────────
+// Binding
+// ─────────────────────────────────────────────
+
+// GetSchedules ritorna le chiamate programmate.
+func (a *App) GetSchedules() []config.Schedule {
+	return a.config.Get().Schedules
+}
+
+// SaveSchedule aggiunge una chiamata programmata (ID vuoto) o sostituisce
+// quella con lo stesso ID.
+func (a *App) SaveSchedule(sc config.Schedule) *i18n.Message {
+	if err := validateSchedule(&sc); err != nil {
+		return err
+	}
+	var notFound bool
+	msg := a.updateSettings(func(s *config.Settings) {
+		if sc.ID == "" {
+			sc.ID = newScheduleID()
+			s.Schedules = append(s.Schedules, sc)
+			return
+		}
+		i := slices.IndexFunc(s.Schedules, func(x config.Schedule) bool { return x.ID == sc.ID })
+		if i < 0 {
+			notFound = true
+			return
+		}
+		s.Schedules[i] = sc
+	})
+	if notFound {
+		return i18n.New(i18n.ErrUnknownSchedule, sc.ID)
+	}
+	return msg
+}
+
+// DeleteSchedule elimina una chiamata programmata.
+func (a *App) DeleteSchedule(id string) *i18n.Message {
+	return a.updateSettings(func(s *config.Settings) {
+		s.Schedules = slices.DeleteFunc(s.Schedules, func(x config.Schedule) bool { return x.ID == id })
+	})
+}
+
+// RunScheduleNow esegue subito una chiamata programmata (per provarla).
+func (a *App) RunScheduleNow(id string) *i18n.Message {
+	for _, sc := range a.config.Get().Schedules {
+		if sc.ID == id {
+			a.spawn(func() { a.runSchedule(sc) })
+			return nil
+		}
+	}
+	return i18n.New(i18n.ErrUnknownSchedule, id)
+}
+
+// validateSchedule controlla (e completa) una chiamata programmata.
+func validateSchedule(sc *config.Schedule) *i18n.Message {
+	if _, err := time.Parse("15:04", sc.Time); err != nil {
+		return i18n.New(i18n.ErrInvalidSchedule, sc.Time)
+	}
+	if sc.Host == "" || sc.Port < 0 || sc.Port > 65535 {
+		return i18n.New(i18n.ErrInvalidSchedule, fmt.Sprintf("%s:%d", sc.Host, sc.Port))
+	}
+	for _, d := range sc.Days {
+		if d < 0 || d > 6 {
+			return i18n.New(i18n.ErrInvalidSchedule, d)
+		}
+	}
+	if sc.Name == "" {
+		sc.Name = sc.Host
+	}
+	return nil
+}
+
+// newScheduleID genera un identificativo casuale.
+func newScheduleID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// ─────────────────────────────────────────────
+// Script sulle schede della GUI
+// ─────────────────────────────────────────────
+
+// sessionHost collega uno script a una scheda (vedi script.Host).
+type sessionHost struct {
+	a *App
+	s *session
+}
+
+func (h sessionHost) Send(text string) error {
+	// Per il timer di inattività lo script conta come input dell'utente
+	h.a.mu.Lock()
+	h.s.idle = idleState{lastInput: time.Now()}
+	h.a.mu.Unlock()
+	return h.s.conn.Send(cp437.Encode(text))
+}
+
+func (h sessionHost) Wait(ctx context.Context, pattern string, timeout time.Duration) bool {
+	h.a.mu.Lock()
+	stream := h.s.stream
+	h.a.mu.Unlock()
+	return stream.Wait(ctx, pattern, timeout)
+}
+
+func (h sessionHost) ScreenText() string {
+	h.a.mu.Lock()
+	defer h.a.mu.Unlock()
+	return script.ScreenText(h.s.screen)
+}
+
+func (h sessionHost) Connected() bool {
+	return h.s.conn.Connected()
+}
+
+func (h sessionHost) Upload(path string) {
+	h.s.conn.StartZmodemUpload(path)
+}
+
+func (h sessionHost) Disconnect() {
+	h.a.disconnectSession(h.s)
+}
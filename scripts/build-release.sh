@@ -164,8 +164,9 @@ build_linux() {
     echo "→ Creazione tar.gz..."
     TAR_NAME="BBS-Client-v${VERSION}-Linux-x64.tar.gz"
     TAR_PATH="$DIST_DIR/$TAR_NAME"
+    cp "$PROJECT_DIR/build/linux/bbsclient-gui.desktop" "$PROJECT_DIR/build/bin/"
     cd "$PROJECT_DIR/build/bin"
-    tar czf "$TAR_PATH" bbsclient-gui
+    tar czf "$TAR_PATH" bbsclient-gui bbsclient-gui.desktop
     cd "$PROJECT_DIR"
     echo "✓ TAR: $TAR_NAME ($(du -sh "$TAR_PATH" | cut -f1))"
 }
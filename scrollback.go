@@ -0,0 +1,72 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rj45lab/bbs-client-go/internal/i18n"
+	"github.com/rj45lab/bbs-client-go/pkg/ansi"
+)
+
+// ─────────────────────────────────────────────
+// Esportazione dello scrollback
+// ─────────────────────────────────────────────
+//
+// Un messaggio appena letto sta di solito a cavallo tra lo scrollback e lo
+// schermo: qui si esportano entrambi in un colpo solo, dalla riga più
+// vecchia all'ultima non vuota dello schermo.
+
+// scrollbackRows ritorna le righe dello scrollback della scheda attiva
+// seguite da quelle dello schermo, senza le righe vuote in fondo. Chiamare
+// con a.mu acquisito.
+func (a *App) scrollbackRows() [][]ansi.Cell {
+	var rows [][]ansi.Cell
+	if sb := a.screen.Scrollback; sb != nil {
+		for i := range sb.Len() {
+			rows = append(rows, sb.Line(i))
+		}
+	}
+	rows = append(rows, a.screen.Region(0, 0, a.screen.Cols-1, a.screen.Rows-1)...)
+	for len(rows) > 0 && len(rows[len(rows)-1]) == 0 {
+		rows = rows[:len(rows)-1]
+	}
+	return rows
+}
+
+// CopyScrollback ritorna scrollback e schermo della scheda attiva: Text
+// come testo semplice, Data come testo ANSI con i colori.
+func (a *App) CopyScrollback() SelectionResult {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	rows := a.scrollbackRows()
+	return SelectionResult{Text: selectionPlain(rows), Data: ansi.SerializeCells(rows)}
+}
+
+// SaveScrollback salva scrollback e schermo della scheda attiva in path:
+// testo ANSI se l'estensione è .ans, testo semplice altrimenti. Con path
+// vuoto chiede il file all'utente.
+func (a *App) SaveScrollback(path string) *i18n.Message {
+	if path == "" {
+		a.mu.Lock()
+		name := safeFileName(a.bbsName)
+		a.mu.Unlock()
+		if name == "" {
+			name = "scrollback"
+		}
+		var err error
+		path, err = a.exportDialog(i18n.T(i18n.DlgSaveScrollback), name+".txt", i18n.T(i18n.DlgScrollback), "*.txt;*.ans")
+		if err != nil || path == "" {
+			return i18n.Err(err)
+		}
+	}
+
+	a.mu.Lock()
+	rows := a.scrollbackRows()
+	a.mu.Unlock()
+	data := selectionPlain(rows) + "\n"
+	if strings.EqualFold(filepath.Ext(path), ".ans") {
+		data = ansi.SerializeCells(rows)
+	}
+	return i18n.Err(os.WriteFile(path, []byte(data), 0600))
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/rj45lab/bbs-client-go/internal/htmlexport"
+	"github.com/rj45lab/bbs-client-go/internal/i18n"
+	"github.com/rj45lab/bbs-client-go/pkg/ansi"
+)
+
+// ─────────────────────────────────────────────
+// Copia della selezione con i colori
+// ─────────────────────────────────────────────
+//
+// Il frontend seleziona le celle con il mouse e chiede la selezione in uno
+// dei formati qui sotto: l'HTML con gli stili in linea si incolla nei
+// documenti, il testo ANSI nei terminali e nei blocchi ```ansi di Discord.
+
+// Formati della selezione
+const (
+	selectionText = "text"
+	selectionANSI = "ansi"
+	selectionHTML = "html"
+)
+
+// SelectionResult è la selezione nel formato richiesto, con il testo
+// semplice da mettere negli appunti insieme.
+type SelectionResult struct {
+	Text  string        `json:"text"`
+	Data  string        `json:"data"`
+	Error *i18n.Message `json:"error"`
+}
+
+// CopySelection ritorna le celle dalla (x0, y0) alla (x1, y1) dello
+// schermo visibile nel formato richiesto (text, ansi, html).
+func (a *App) CopySelection(x0, y0, x1, y1 int, format string) SelectionResult {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	rows := a.screen.Region(x0, y0, x1, y1)
+	res := SelectionResult{Text: selectionPlain(rows)}
+	switch format {
+	case selectionText:
+		res.Data = res.Text
+	case selectionANSI:
+		res.Data = ansi.SerializeCells(rows)
+	case selectionHTML:
+		res.Data = htmlexport.Cells(a.screen, rows)
+	default:
+		return SelectionResult{Error: i18n.New(i18n.ErrUnknownFormat, format)}
+	}
+	return res
+}
+
+// selectionPlain ritorna le celle come testo semplice, righe separate da
+// "\n" e senza spazi finali.
+func selectionPlain(rows [][]ansi.Cell) string {
+	lines := make([]string, len(rows))
+	for y, row := range rows {
+		var b strings.Builder
+		for _, cell := range row {
+			ch := cell.Char
+			if ch < ' ' || cell.Attr.Conceal {
+				ch = ' '
+			}
+			b.WriteRune(ch)
+		}
+		lines[y] = strings.TrimRight(b.String(), " ")
+	}
+	return strings.Join(lines, "\n")
+}
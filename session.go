@@ -0,0 +1,340 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	wailsrt "github.com/wailsapp/wails/v2/pkg/runtime"
+
+	"github.com/rj45lab/bbs-client-go/internal/asciicast"
+	"github.com/rj45lab/bbs-client-go/internal/bridge"
+	"github.com/rj45lab/bbs-client-go/internal/charset"
+	"github.com/rj45lab/bbs-client-go/internal/filelist"
+	"github.com/rj45lab/bbs-client-go/internal/fingerprint"
+	"github.com/rj45lab/bbs-client-go/internal/i18n"
+	"github.com/rj45lab/bbs-client-go/internal/iemsi"
+	"github.com/rj45lab/bbs-client-go/internal/plugin"
+	"github.com/rj45lab/bbs-client-go/internal/sauce"
+	"github.com/rj45lab/bbs-client-go/internal/script"
+	"github.com/rj45lab/bbs-client-go/internal/watch"
+	"github.com/rj45lab/bbs-client-go/pkg/ansi"
+	"github.com/rj45lab/bbs-client-go/pkg/telnet"
+)
+
+// ─────────────────────────────────────────────
+// Sessioni multiple (schede)
+// ─────────────────────────────────────────────
+
+// session è lo stato di una connessione: terminale, log, viewer e
+// statistiche. L'App incorpora la sessione attiva, così i binding esistenti
+// (a.conn, a.screen, a.host...) operano sempre sulla scheda visibile; le
+// sessioni in background continuano a ricevere dati, scrivere log e
+// aggiornare il proprio schermo. Tutti i campi sono protetti da App.mu.
+type session struct {
+	id     string
+	conn   *telnet.Connection
+	events *telnet.Subscription // dati ed eventi di conn, letti da eventLoop
+	screen *ansi.Screen
+	done   chan struct{} // chiuso da CloseSession: termina eventLoop
+
+	// Stato
+	host      string
+	port      int
+	connected bool
+	bbsName   string // ultima BBS chiamata, per "Riconnetti" e le schede
+	hangup    bool   // chiusura voluta dall'utente: niente riconnessione
+
+	// Connessione in corso (nil = nessuna): annullarla interrompe il dial
+	dialCancel context.CancelFunc
+
+	// Righe della BBS dalla rubrica (vedi bbsRows)
+	rows       int  // righe fisse (0 = quelle della finestra)
+	statusLine bool // ultima riga riservata alla riga di stato locale
+
+	// Riconnessione automatica in corso (chiuso per annullarla)
+	reconnect chan struct{}
+
+	// Invio cadenzato di testo incollato (nil = nessun invio in corso)
+	pasteStop chan struct{}
+
+	// Accento del tasto morto in attesa della lettera (0 = nessuno)
+	deadKey rune
+
+	// Statistiche della connessione corrente
+	stats    ConnectionStats
+	lastBell time.Time
+
+	// Log viewer
+	logPages     []string
+	logPageIdx   int
+	viewingLog   bool
+	sauce        *sauce.Record
+	art          *artView     // artwork .ANS/.ASC aperto (nil per i log)
+	viewerText   string       // contenuto decodificato, per il replay
+	viewerTiming []timedChunk // tempi originali del log (nil se assenti)
+	player       *player
+
+	// Session logger (scritture in background, vedi logwriter.go)
+	logFile         *logWriter
+	castFile        *asciicast.Writer // registrazione .cast parallela (opzionale)
+	timingFile      *logWriter        // tempi dei blocchi scritti nel log (.timing)
+	lastLogWrite    time.Time
+	transcriptFile  *logWriter     // trascrizione in testo semplice (.txt)
+	transcript      *ansi.Stripper // stato della rimozione ANSI
+	logBytes        int64          // PT-004: byte scritti nel log corrente
+	transcriptBytes int64          // byte scritti nella trascrizione corrente
+	rawFile         *logWriter     // byte ricevuti prima della decodifica (.raw)
+	rawBytes        int64          // byte scritti nel log grezzo corrente
+	logLimit        int64          // dimensione massima di log e trascrizione
+	eventLog        *eventLog      // eventi in JSON Lines (.jsonl, opzionale)
+	logBase         string         // percorso del log senza ".log" (vedi logPartPath)
+	logName         string         // nome della BBS nelle intestazioni
+	logPart         int            // parte corrente del log, da 1
+	logSplit        time.Time      // prossimo cambio di parte (zero = mai)
+
+	// Capture buffer manuale (StartCapture/StopCapture)
+	capture capture
+
+	// Frasi sorvegliate nel flusso ricevuto
+	watcher *watch.Matcher
+
+	// Codifica dei dati scambiati con la BBS (vedi applyEncoding)
+	encoding charset.Encoding
+	decoder  charset.Decoder
+	decodeC1 bool // il decoder lascia passare i controlli C1
+
+	// Riconoscimento del software della BBS dal banner
+	detector *fingerprint.Detector
+	software string // riconosciuto o annotato in rubrica ("" = ignoto)
+
+	// Login automatico IEMSI (nil = disattivato per questa connessione)
+	iemsi *iemsi.Client
+
+	// File riconosciuti nelle liste della BBS
+	files *filelist.Scraper
+
+	// Testo ricevuto per gli script (wait), ricreato a ogni connessione
+	stream *script.Stream
+
+	// Plugin esterni avviati alla connessione
+	plugins []*plugin.Plugin
+
+	// Programma locale collegato alla sessione (bridge.go)
+	bridge *bridge.Bridge
+
+	// Non disturbare: avvisi soppressi e conservati in missed
+	dnd    bool
+	missed []MissedEvent
+
+	// Aggiornamenti dello schermo verso il frontend (Limits.UpdatesPerSec)
+	throttle screenThrottle
+
+	unread   int       // blocchi ricevuti mentre la scheda non era visibile
+	idle     idleState // inattività dell'utente
+	transfer string    // file in download via ZMODEM ("" = nessuno)
+}
+
+// SessionInfo descrive una scheda per il frontend.
+type SessionInfo struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Host      string `json:"host"`
+	Port      int    `json:"port"`
+	Connected bool   `json:"connected"`
+	Active    bool   `json:"active"`
+	Unread    int    `json:"unread"`
+	Software  string `json:"software,omitempty"`
+	DND       bool   `json:"dnd"`
+	Missed    int    `json:"missed"` // eventi persi in modalità non disturbare
+}
+
+// newSession crea una sessione non connessa con le impostazioni correnti
+// e ne avvia il loop eventi. Chiamare con a.mu acquisito.
+func (a *App) newSession() *session {
+	a.nextSession++
+	s := &session{
+		id:       fmt.Sprintf("s%d", a.nextSession),
+		conn:     telnet.NewBuffered(a.settings.Limits.DataBuffer),
+		screen:   ansi.NewScreen(a.termSize.Cols, a.termSize.Rows),
+		done:     make(chan struct{}),
+		host:     telnet.DefaultHost,
+		port:     telnet.DefaultPort,
+		watcher:  watch.New(nil),
+		detector: fingerprint.New(),
+		files:    filelist.New(),
+		stream:   script.NewStream(),
+	}
+	s.events = s.conn.Subscribe(0)
+	s.conn.SetDownloadDir(a.downloadDir())
+	s.conn.SetSize(a.termSize.Cols, a.termSize.Rows)
+
+	// DSR callback
+	s.screen.OnResponse = func(data []byte) {
+		s.conn.Send(data)
+	}
+	s.screen.OnBell = func() { a.onBell(s) }
+	s.screen.OnPrint = func(job string) { a.onPrint(s, job) }
+	s.screen.Scrollback = ansi.NewScrollback(a.settings.Limits.ScrollbackLines)
+	a.applyScreenSettings(s)
+
+	a.sessions = append(a.sessions, s)
+	a.spawn(func() { a.eventLoop(s) })
+	return s
+}
+
+// findSession ritorna la sessione con l'id indicato. Chiamare con a.mu.
+func (a *App) findSession(id string) *session {
+	for _, s := range a.sessions {
+		if s.id == id {
+			return s
+		}
+	}
+	return nil
+}
+
+// emitFor invia un evento della sessione s: per la scheda attiva come
+// evento normale, per quelle in background come "session-activity", così
+// il frontend può segnalare l'attività sulla scheda senza ridisegnare.
+// "screen-update" della scheda attiva porta le righe cambiate (pushScreen).
+func (a *App) emitFor(s *session, name string, data ...interface{}) {
+	if name == "screen-update" {
+		a.debugCount.updates.Add(1)
+	}
+	a.mu.Lock()
+	active := s == a.session
+	repeat := false
+	if !active && name == "screen-update" {
+		s.unread++
+		repeat = s.unread > 1 // la scheda è già segnalata
+	}
+	a.mu.Unlock()
+	if active && name == "screen-update" {
+		a.pushScreen()
+		return
+	}
+	if active {
+		wailsrt.EventsEmit(a.ctx, name, data...)
+		return
+	}
+	if repeat {
+		return
+	}
+	wailsrt.EventsEmit(a.ctx, "session-activity", SessionActivity{ID: s.id, Event: name})
+}
+
+// ListSessions ritorna le schede aperte, nell'ordine di apertura.
+func (a *App) ListSessions() []SessionInfo {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	out := make([]SessionInfo, 0, len(a.sessions))
+	for _, s := range a.sessions {
+		name := s.bbsName
+		if name == "" {
+			name = i18n.T(i18n.MsgNewSession)
+		}
+		out = append(out, SessionInfo{
+			ID: s.id, Name: name, Host: s.host, Port: s.port,
+			Connected: s.connected, Active: s == a.session, Unread: s.unread,
+			Software: s.software, DND: s.dnd, Missed: len(s.missed),
+		})
+	}
+	return out
+}
+
+// CreateSession apre una nuova scheda non connessa e la rende attiva.
+func (a *App) CreateSession() SessionInfo {
+	a.mu.Lock()
+	s := a.newSession()
+	a.mu.Unlock()
+	a.SwitchSession(s.id)
+	return a.sessionInfo(s.id)
+}
+
+// SwitchSession rende attiva la scheda id.
+func (a *App) SwitchSession(id string) *i18n.Message {
+	a.mu.Lock()
+	s := a.findSession(id)
+	if s == nil {
+		a.mu.Unlock()
+		return i18n.New(i18n.ErrUnknownSession, id)
+	}
+	a.screenMu.Lock()
+	a.session = s
+	a.screenMu.Unlock()
+	s.unread = 0
+	connected, viewing := s.connected, s.viewingLog
+	page, total := s.logPageIdx+1, a.logPageCount()
+	a.mu.Unlock()
+
+	// Riallinea la UI allo stato della scheda
+	wailsrt.EventsEmit(a.ctx, "log-mode", LogMode{})
+	status := "disconnected"
+	if connected {
+		status = "connected"
+	}
+	wailsrt.EventsEmit(a.ctx, "connection-status", status)
+	if viewing {
+		wailsrt.EventsEmit(a.ctx, "log-mode", LogMode{Active: true, Page: page, Total: total})
+	}
+	a.emitSessions()
+	a.emitTheme()
+	a.emitGeometry()
+	a.pushScreen()
+	a.updateTray()
+	return nil
+}
+
+// CloseSession chiude la scheda id (disconnettendola). Chiudendo l'ultima
+// scheda ne viene aperta una nuova vuota.
+func (a *App) CloseSession(id string) *i18n.Message {
+	a.mu.Lock()
+	s := a.findSession(id)
+	if s == nil {
+		a.mu.Unlock()
+		return i18n.New(i18n.ErrUnknownSession, id)
+	}
+	s.hangup = true
+	s.cancelReconnectLocked()
+	a.mu.Unlock()
+
+	s.conn.Disconnect()
+	a.endSession(s)
+	a.mu.Lock()
+	s.stopSessionLog()
+	s.capture.stop()
+	s.player = nil // il ticker del replay si ferma da solo
+	close(s.done)
+
+	i := slices.Index(a.sessions, s)
+	a.sessions = slices.Delete(a.sessions, i, i+1)
+	if len(a.sessions) == 0 {
+		a.newSession()
+	}
+	next := a.session
+	if next == s {
+		next = a.sessions[min(i, len(a.sessions)-1)]
+	}
+	shared := a.share.session == s
+	a.mu.Unlock()
+	if shared {
+		a.StopSharing()
+	}
+	return a.SwitchSession(next.id)
+}
+
+// sessionInfo ritorna la descrizione della scheda id.
+func (a *App) sessionInfo(id string) SessionInfo {
+	for _, info := range a.ListSessions() {
+		if info.ID == id {
+			return info
+		}
+	}
+	return SessionInfo{}
+}
+
+// emitSessions notifica al frontend l'elenco aggiornato delle schede.
+func (a *App) emitSessions() {
+	wailsrt.EventsEmit(a.ctx, "sessions-changed", a.ListSessions())
+}
@@ -0,0 +1,311 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"slices"
+
+	wailsrt "github.com/wailsapp/wails/v2/pkg/runtime"
+
+	"github.com/rj45lab/bbs-client-go/internal/charset"
+	"github.com/rj45lab/bbs-client-go/internal/config"
+	"github.com/rj45lab/bbs-client-go/internal/i18n"
+	"github.com/rj45lab/bbs-client-go/internal/keymap"
+	"github.com/rj45lab/bbs-client-go/pkg/ansi"
+)
+
+// ─────────────────────────────────────────────
+// Impostazioni persistenti
+// ─────────────────────────────────────────────
+
+// openSettings apre le impostazioni nella directory di configurazione. Se
+// il file è illeggibile (o di una versione più recente) le impostazioni
+// restano solo in memoria, per non sovrascrivere quelle dell'utente.
+func (a *App) openSettings() *config.Store {
+	path, err := config.DefaultPath()
+	if err != nil {
+		return config.New("")
+	}
+	st, err := config.Open(path)
+	if err != nil {
+		log.Printf("[CONFIG] %v — modifiche non salvate", err)
+		return config.New("")
+	}
+	return st
+}
+
+// applySettings applica le impostazioni agli schermi di tutte le schede e
+// le notifica al frontend con l'evento "settings-changed". Registrato
+// come osservatore dello store: ogni modifica, da qualunque binding,
+// passa di qui.
+func (a *App) applySettings(s config.Settings) {
+	if !i18n.SetLanguage(s.Language) {
+		i18n.SetLanguage(i18n.Default)
+	}
+
+	a.mu.Lock()
+	a.settings = s
+	for _, sess := range a.sessions {
+		a.applyScreenSettings(sess)
+	}
+	a.mu.Unlock()
+
+	a.updateTray() // etichette nella nuova lingua
+	a.syncControl(s.Control)
+	wailsrt.EventsEmit(a.ctx, "settings-changed", s)
+	a.emitTheme()
+	a.pushScreen()
+}
+
+// applyScreenSettings configura lo schermo e le frasi sorvegliate di una
+// scheda secondo le impostazioni correnti. Chiamare con a.mu acquisito.
+func (a *App) applyScreenSettings(sess *session) {
+	key := bbsKey(sess.host, sess.port)
+	a.applyTheme(sess)
+	sess.screen.C1Controls = a.settings.C1Controls
+	a.applyEncoding(sess)
+	sess.screen.Emulation = emulationFor(a.settings, key)
+	a.screenMu.Lock()
+	if !sess.viewingLog {
+		sess.screen.IceColors = a.iceColorsFor(sess)
+	}
+	sess.screen.Scrollback.SetCapacity(a.settings.Limits.ScrollbackLines)
+	a.screenMu.Unlock()
+	sess.watcher.SetPhrases(watchPhrases(a.settings, key))
+}
+
+// applyTheme configura palette e bold policy dello schermo secondo il tema
+// della BBS della scheda. Chiamare con a.mu acquisito.
+func (a *App) applyTheme(sess *session) {
+	theme := themeFor(a.settings, bbsKey(sess.host, sess.port))
+	pal, err := resolvePalette(theme.Palette, theme.CustomPalette)
+	if err != nil {
+		log.Printf("[CONFIG] %v — uso la palette vga", err)
+		pal = ansi.Palettes["vga"]
+	}
+	bold, _ := ansi.ParseBoldPolicy(theme.BoldPolicy)
+	a.screenMu.Lock()
+	sess.screen.Palette = pal
+	sess.screen.BoldPolicy = bold
+	a.screenMu.Unlock()
+}
+
+// resolvePalette ritorna la palette predefinita name, oppure con name
+// "custom" quella personalizzata da 16 colori esadecimali.
+func resolvePalette(name string, colors []string) (*ansi.Palette, error) {
+	if name == "custom" {
+		return ansi.ParsePalette(colors)
+	}
+	if p, ok := ansi.Palettes[name]; ok {
+		return p, nil
+	}
+	return nil, fmt.Errorf("palette sconosciuta: %s", name)
+}
+
+// updateSettings modifica le impostazioni correnti e le salva.
+func (a *App) updateSettings(fn func(*config.Settings)) *i18n.Message {
+	return i18n.Err(a.config.Update(fn))
+}
+
+// GetSettings ritorna tutte le impostazioni correnti.
+func (a *App) GetSettings() config.Settings {
+	return a.config.Get()
+}
+
+// SetSettings sostituisce tutte le impostazioni, dopo averle validate.
+// Le modifiche vengono applicate subito e salvate su disco.
+func (a *App) SetSettings(s config.Settings) *i18n.Message {
+	if _, err := resolvePalette(s.Palette, s.CustomPalette); err != nil {
+		return i18n.Err(err)
+	}
+	if _, ok := ansi.ParseBoldPolicy(s.BoldPolicy); !ok {
+		return i18n.New(i18n.ErrUnknownBold, s.BoldPolicy)
+	}
+	if !slices.Contains(i18n.Languages(), s.Language) {
+		return i18n.New(i18n.ErrUnknownLanguage, s.Language)
+	}
+	for _, name := range s.Emulation {
+		if _, err := ansi.ParseEmulation(name); err != nil {
+			return i18n.New(i18n.ErrUnknownEmulation, name)
+		}
+	}
+	for _, name := range s.Charset {
+		if _, err := charset.Lookup(name); err != nil {
+			return i18n.New(i18n.ErrUnknownCharset, name)
+		}
+	}
+	for _, name := range s.Keys {
+		if _, err := ansi.ParseKeyProfile(name); err != nil {
+			return i18n.New(i18n.ErrUnknownKeyProfile, name)
+		}
+	}
+	if _, ok := keymap.Lookup(s.Keyboard); s.Keyboard != "" && !ok {
+		return i18n.New(i18n.ErrUnknownKeyboard, s.Keyboard)
+	}
+	if !slices.Contains(downloadModes, s.Downloads) {
+		return i18n.New(i18n.ErrUnknownDownloads, s.Downloads)
+	}
+	for _, ext := range slices.Concat(s.FileTypes.Blocked, s.FileTypes.Warned) {
+		if !validFileType(ext) {
+			return i18n.New(i18n.ErrInvalidFileType, ext)
+		}
+	}
+	names := map[string]bool{}
+	for _, p := range s.Proxies {
+		if err := validProxy(p); err != nil || names[p.Name] {
+			return i18n.New(i18n.ErrInvalidProxy, p.Name)
+		}
+		names[p.Name] = true
+	}
+	if !slices.Contains(printModes, s.Printer) {
+		return i18n.New(i18n.ErrUnknownPrinter, s.Printer)
+	}
+	for _, p := range s.Pacing {
+		if p.CharDelayMs < 0 || p.LineDelayMs < 0 {
+			return i18n.New(i18n.ErrInvalidPacing, p.CharDelayMs, p.LineDelayMs)
+		}
+	}
+	for i := range s.Schedules {
+		if err := validateSchedule(&s.Schedules[i]); err != nil {
+			return err
+		}
+	}
+	for i := range s.Plugins {
+		if err := validatePlugin(&s.Plugins[i]); err != nil {
+			return err
+		}
+	}
+	if err := validateThemeSettings(s); err != nil {
+		return err
+	}
+	if err := validateLimits(s.Limits); err != nil {
+		return err
+	}
+	// Il font degli export si controlla solo quando cambia: un file
+	// rimosso nel frattempo fa tornare al font interno
+	if s.ExportFont != "" && s.ExportFont != a.config.Get().ExportFont {
+		if _, err := loadBitmapFont(s.ExportFont); err != nil {
+			return i18n.New(i18n.ErrBitmapFont, err.Error())
+		}
+	}
+	return i18n.Err(a.config.Set(s))
+}
+
+// SetLanguage sceglie la lingua dei messaggi del backend.
+func (a *App) SetLanguage(lang string) *i18n.Message {
+	if !slices.Contains(i18n.Languages(), lang) {
+		return i18n.New(i18n.ErrUnknownLanguage, lang)
+	}
+	return a.updateSettings(func(s *config.Settings) {
+		s.Language = lang
+	})
+}
+
+// GetLanguages ritorna le lingue disponibili per i messaggi.
+func (a *App) GetLanguages() []string {
+	return i18n.Languages()
+}
+
+// emulationFor ritorna l'emulazione scelta per la BBS key (ANSI-BBS se
+// non impostata).
+func emulationFor(s config.Settings, key string) ansi.Emulation {
+	emu, err := ansi.ParseEmulation(s.Emulation[key])
+	if err != nil {
+		return ansi.EmulationANSIBBS
+	}
+	return emu
+}
+
+// SetEmulation sceglie l'emulazione del terminale per la BBS corrente
+// (ansi-bbs, vt100, ascii). Risposte e tasti cambiano subito, il tipo di
+// terminale dichiarato in TTYPE dalla connessione successiva.
+func (a *App) SetEmulation(name string) *i18n.Message {
+	emu, err := ansi.ParseEmulation(name)
+	if err != nil {
+		return i18n.New(i18n.ErrUnknownEmulation, name)
+	}
+	a.mu.Lock()
+	key := bbsKey(a.host, a.port)
+	a.mu.Unlock()
+	return a.updateSettings(func(s *config.Settings) {
+		if emu == ansi.EmulationANSIBBS {
+			delete(s.Emulation, key)
+		} else {
+			s.Emulation[key] = string(emu)
+		}
+	})
+}
+
+// GetEmulation ritorna l'emulazione della scheda corrente.
+func (a *App) GetEmulation() string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return string(a.screen.Emulation)
+}
+
+// GetEmulations ritorna le emulazioni disponibili.
+func (a *App) GetEmulations() []string {
+	out := make([]string, len(ansi.Emulations))
+	for i, e := range ansi.Emulations {
+		out[i] = string(e)
+	}
+	return out
+}
+
+// keyProfileFor ritorna la variante dei tasti scelta per la BBS key
+// (quella dell'emulazione se non impostata).
+func keyProfileFor(s config.Settings, key string) ansi.KeyProfile {
+	p, err := ansi.ParseKeyProfile(s.Keys[key])
+	if err != nil {
+		return ansi.KeysDefault
+	}
+	return p
+}
+
+// SetKeyProfile sceglie la variante dei tasti speciali per la BBS corrente
+// ("" = quella dell'emulazione, vt, xterm, sco).
+func (a *App) SetKeyProfile(name string) *i18n.Message {
+	p, err := ansi.ParseKeyProfile(name)
+	if err != nil {
+		return i18n.New(i18n.ErrUnknownKeyProfile, name)
+	}
+	a.mu.Lock()
+	key := bbsKey(a.host, a.port)
+	a.mu.Unlock()
+	return a.updateSettings(func(s *config.Settings) {
+		if p == ansi.KeysDefault {
+			delete(s.Keys, key)
+		} else {
+			s.Keys[key] = string(p)
+		}
+	})
+}
+
+// GetKeyProfile ritorna la variante dei tasti della BBS corrente.
+func (a *App) GetKeyProfile() string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return string(keyProfileFor(a.settings, bbsKey(a.host, a.port)))
+}
+
+// GetKeyProfiles ritorna le varianti dei tasti disponibili.
+func (a *App) GetKeyProfiles() []string {
+	out := make([]string, len(ansi.KeyProfiles))
+	for i, p := range ansi.KeyProfiles {
+		out[i] = string(p)
+	}
+	return out
+}
+
+// SetFlowControl attiva il rispetto di XON/XOFF della BBS. Vale dalla
+// prossima connessione.
+func (a *App) SetFlowControl(enabled bool) *i18n.Message {
+	return a.updateSettings(func(s *config.Settings) {
+		s.FlowControl = enabled
+	})
+}
+
+// ResetSettings ripristina le impostazioni di fabbrica.
+func (a *App) ResetSettings() *i18n.Message {
+	return i18n.Err(a.config.Set(config.Defaults()))
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	wailsrt "github.com/wailsapp/wails/v2/pkg/runtime"
+
+	"github.com/rj45lab/bbs-client-go/internal/htmlexport"
+	"github.com/rj45lab/bbs-client-go/internal/i18n"
+	"github.com/rj45lab/bbs-client-go/internal/share"
+)
+
+// ─────────────────────────────────────────────
+// Condivisione dello schermo in sola lettura
+// ─────────────────────────────────────────────
+//
+// StartSharing trasmette lo schermo della scheda attiva su un endpoint
+// WebSocket locale (vedi internal/share): chi ha l'URL con il token può
+// seguire la sessione da un browser, senza poter inviare nulla alla BBS.
+
+// shareFrameInterval limita la frequenza dei fotogrammi inviati.
+const shareFrameInterval = 100 * time.Millisecond
+
+// shareState è lo stato della condivisione, protetto da App.mu.
+type shareState struct {
+	server  *share.Server
+	session *session // scheda trasmessa
+	dirty   bool     // schermo cambiato dall'ultimo fotogramma
+	stop    chan struct{}
+}
+
+// ShareInfo descrive la condivisione per il frontend.
+type ShareInfo struct {
+	Active  bool     `json:"active"`
+	Session string   `json:"session,omitempty"` // id della scheda trasmessa
+	URLs    []string `json:"urls,omitempty"`    // pagina per gli spettatori, con il token
+	Viewers int      `json:"viewers"`
+}
+
+// StartSharing avvia la condivisione della scheda attiva. Se è già attiva
+// passa a trasmettere la scheda attiva, con lo stesso token.
+func (a *App) StartSharing() *i18n.Message {
+	a.mu.Lock()
+	if a.share.server != nil {
+		a.share.session = a.session
+		a.share.dirty = true
+		a.mu.Unlock()
+		a.emitSharing()
+		return nil
+	}
+	cfg := a.settings.Share
+	title := a.bbsName
+	a.mu.Unlock()
+
+	addr := fmt.Sprintf(":%d", cfg.Port)
+	if cfg.LocalOnly {
+		addr = fmt.Sprintf("127.0.0.1:%d", cfg.Port)
+	}
+	font, _ := assets.ReadFile(cp437FontPath)
+	srv, err := share.Start(addr, share.Page{Title: "BBS " + title, Style: htmlexport.Stylesheet(font)})
+	if err != nil {
+		return i18n.New(i18n.ErrShareStart, err.Error())
+	}
+
+	stop := make(chan struct{})
+	a.mu.Lock()
+	a.share = shareState{server: srv, session: a.session, dirty: true, stop: stop}
+	a.mu.Unlock()
+	go a.shareLoop(srv, stop)
+	a.emitSharing()
+	return nil
+}
+
+// StopSharing chiude la condivisione e disconnette gli spettatori.
+func (a *App) StopSharing() {
+	a.mu.Lock()
+	st := a.share
+	a.share = shareState{}
+	a.mu.Unlock()
+	if st.server == nil {
+		return
+	}
+	close(st.stop)
+	st.server.Close()
+	log.Printf("[SHARE] Condivisione terminata")
+	a.emitSharing()
+}
+
+// GetSharing ritorna lo stato della condivisione.
+func (a *App) GetSharing() ShareInfo {
+	a.mu.Lock()
+	st := a.share
+	a.mu.Unlock()
+	if st.server == nil {
+		return ShareInfo{}
+	}
+	return ShareInfo{
+		Active:  true,
+		Session: st.session.id,
+		URLs:    st.server.URLs(),
+		Viewers: st.server.Viewers(),
+	}
+}
+
+// emitSharing notifica al frontend lo stato della condivisione.
+func (a *App) emitSharing() {
+	wailsrt.EventsEmit(a.ctx, "sharing-changed", a.GetSharing())
+}
+
+// markShared segnala che lo schermo di s è cambiato. Chiamare con a.mu.
+func (a *App) markShared(s *session) {
+	if a.share.session == s {
+		a.share.dirty = true
+	}
+}
+
+// shareLoop invia un fotogramma quando lo schermo trasmesso cambia, al
+// massimo ogni shareFrameInterval, e segnala i cambi di spettatori.
+func (a *App) shareLoop(srv *share.Server, stop chan struct{}) {
+	ticker := time.NewTicker(shareFrameInterval)
+	defer ticker.Stop()
+	viewers := 0
+	for {
+		select {
+		case <-a.ctx.Done():
+			srv.Close()
+			return
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		if n := srv.Viewers(); n != viewers {
+			viewers = n
+			a.emitSharing()
+		}
+		a.mu.Lock()
+		var frame string
+		if a.share.dirty && viewers > 0 {
+			frame = htmlexport.Screen(a.share.session.screen)
+			a.share.dirty = false
+		}
+		a.mu.Unlock()
+		if frame != "" {
+			srv.Publish([]byte(frame))
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"log"
+	"slices"
+	"time"
+)
+
+// ─────────────────────────────────────────────
+// Chiusura ordinata
+// ─────────────────────────────────────────────
+//
+// Alla chiusura dell'app i trasferimenti ZMODEM vengono annullati
+// avvisando la BBS, le connessioni chiuse, le goroutine fermate (con un
+// limite di attesa) e solo dopo log, catture e statistiche della rubrica
+// vengono scritti e chiusi, quando nessuno li sta più aggiornando.
+
+// shutdownTimeout limita l'attesa delle goroutine alla chiusura.
+const shutdownTimeout = 3 * time.Second
+
+// spawn avvia fn in una goroutine che Shutdown attende. fn deve
+// terminare quando a.ctx viene annullato.
+func (a *App) spawn(fn func()) {
+	a.workers.Add(1)
+	go func() {
+		defer a.workers.Done()
+		fn()
+	}()
+}
+
+// Shutdown è chiamato da Wails alla chiusura dell'app.
+func (a *App) Shutdown(ctx context.Context) {
+	log.Printf("[SHUTDOWN] Chiusura in corso")
+	a.stopTray()
+	a.StopSharing()
+	a.stopControl()
+
+	a.mu.Lock()
+	sessions := slices.Clone(a.sessions)
+	for _, s := range sessions {
+		s.hangup = true // niente riconnessione automatica
+		s.cancelReconnectLocked()
+	}
+	a.mu.Unlock()
+
+	// Trasferimenti e connessioni, finché le connessioni sono aperte
+	for _, s := range sessions {
+		a.mu.Lock()
+		transfer := s.transfer
+		a.mu.Unlock()
+		if transfer != "" {
+			log.Printf("[SHUTDOWN] Annullo il trasferimento di %s", transfer)
+		}
+		s.conn.CancelZmodem() // ZABORT e CAN alla BBS, se un trasferimento è in corso
+		s.conn.Disconnect()
+	}
+
+	// Goroutine: loop eventi, inattività, programmazioni, replay
+	if a.cancel != nil {
+		a.cancel()
+	}
+	done := make(chan struct{})
+	go func() {
+		a.workers.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(shutdownTimeout):
+		log.Printf("[SHUTDOWN] Goroutine ancora attive dopo %v", shutdownTimeout)
+	}
+
+	// Log, catture e statistiche delle sessioni
+	for _, s := range sessions {
+		a.endSession(s)
+		s.stopSessionLog()
+		s.capture.stop()
+	}
+	a.stopRestore()
+	log.Printf("[SHUTDOWN] Completata")
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"net/http"
+	"slices"
+)
+
+// ─────────────────────────────────────────────
+// Snapshot binario dello schermo
+// ─────────────────────────────────────────────
+//
+// Alternativa a GetScreenRuns senza JSON: un array di byte a record fissi,
+// servito dall'asset server come /screen.bin (letto con fetch in un
+// ArrayBuffer) o in base64 da GetScreenPacked. Il formato, little endian:
+//
+//	intestazione (16 byte)
+//	  0  "BBSS"
+//	  4  versione (1)
+//	  5  flag: bit 0 cursore visibile, bit 1 iCE colors
+//	  6  colonne (u16)   8  righe (u16)
+//	 10  cursore x (u16) 12  cursore y (u16)
+//	 14  riservati
+//	celle (16 byte ciascuna, riga per riga)
+//	  0  carattere (u32)
+//	  4  testo R G B     7  sfondo R G B    10  sottolineatura R G B
+//	 13  attributi: bit 0 bold, 1 underline, 2 blink, 3 reverse, 4 faint,
+//	     5 italic, 6 strike, 7 conceal
+//	 14  stile della sottolineatura (0 nessuno, 1 single, 2 double,
+//	     3 curly, 4 dotted, 5 dashed)
+//	 15  riservato
+
+const (
+	packedVersion    = 1
+	packedHeaderSize = 16
+	packedCellSize   = 16
+	// packedPath è il percorso dello snapshot sull'asset server
+	packedPath = "/screen.bin"
+)
+
+// ulStyles sono gli stili della sottolineatura nell'ordine del formato.
+var ulStyles = []string{"", "single", "double", "curly", "dotted", "dashed"}
+
+// packedScreen codifica lo schermo attivo nel formato binario (nil prima
+// dell'avvio). Non cambia la base delle differenze (vedi delta.go), che
+// resta all'ultimo invio di pushScreen o GetScreenRuns.
+func (a *App) packedScreen() []byte {
+	a.pushMu.Lock()
+	defer a.pushMu.Unlock()
+	f := a.takeFrame()
+	if f == nil {
+		return nil
+	}
+
+	buf := make([]byte, packedHeaderSize+f.cols*f.rows*packedCellSize)
+	copy(buf, "BBSS")
+	buf[4] = packedVersion
+	if f.cursorVisible {
+		buf[5] |= 1 << 0
+	}
+	if f.colors.IceColors {
+		buf[5] |= 1 << 1
+	}
+	le := binary.LittleEndian
+	le.PutUint16(buf[6:], uint16(f.cols))
+	le.PutUint16(buf[8:], uint16(f.rows))
+	le.PutUint16(buf[10:], uint16(f.cursorX))
+	le.PutUint16(buf[12:], uint16(f.cursorY))
+
+	for y := range f.rows {
+		off := packedHeaderSize + y*f.cols*packedCellSize
+		for _, run := range a.rowRuns(f, y) {
+			attr := packedAttr(run.Attr)
+			style := byte(max(slices.Index(ulStyles, run.Attr.UlStyle), 0))
+			for _, ch := range run.Text {
+				c := buf[off : off+packedCellSize]
+				le.PutUint32(c, uint32(ch))
+				c[4], c[5], c[6] = run.Attr.FgR, run.Attr.FgG, run.Attr.FgB
+				c[7], c[8], c[9] = run.Attr.BgR, run.Attr.BgG, run.Attr.BgB
+				c[10], c[11], c[12] = run.Attr.UlR, run.Attr.UlG, run.Attr.UlB
+				c[13], c[14] = attr, style
+				off += packedCellSize
+			}
+		}
+	}
+	return buf
+}
+
+// packedAttr raccoglie gli attributi di una cella in un byte.
+func packedAttr(c ScreenCell) byte {
+	var b byte
+	for i, on := range []bool{c.Bold, c.Underline, c.Blink, c.Reverse, c.Faint, c.Italic, c.Strike, c.Conceal} {
+		if on {
+			b |= 1 << i
+		}
+	}
+	return b
+}
+
+// GetScreenPacked ritorna lo snapshot binario dello schermo in base64,
+// per i frontend che non possono usare /screen.bin ("" prima dell'avvio).
+func (a *App) GetScreenPacked() string {
+	return base64.StdEncoding.EncodeToString(a.packedScreen())
+}
+
+// assetHandler serve all'asset server le risorse generate dal backend
+// (lo snapshot binario); le altre richieste ricevono 404.
+func (a *App) assetHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(packedPath, func(w http.ResponseWriter, r *http.Request) {
+		data := a.packedScreen()
+		if data == nil {
+			http.Error(w, "non pronto", http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Cache-Control", "no-store")
+		w.Write(data)
+	})
+	return mux
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"log"
+
+	"github.com/rj45lab/bbs-client-go/internal/fingerprint"
+)
+
+// ─────────────────────────────────────────────
+// Software della BBS
+// ─────────────────────────────────────────────
+
+// iceColorsFor ritorna l'impostazione iCE colors di una scheda: quella
+// scelta dall'utente per la BBS o, in mancanza, quella consigliata per il
+// suo software. Chiamare con a.mu acquisito.
+func (a *App) iceColorsFor(s *session) bool {
+	if ice, ok := a.settings.IceColors[bbsKey(s.host, s.port)]; ok {
+		return ice
+	}
+	return s.software != "" && fingerprint.PresetFor(s.software).IceColors
+}
+
+// onSoftwareDetected applica il software riconosciuto nel banner: lo
+// annota in rubrica (se la voce non ne ha già uno), applica il preset e
+// lo notifica al frontend con "bbs-software".
+func (a *App) onSoftwareDetected(s *session, res fingerprint.Result) {
+	res.Negotiation = s.conn.Negotiation()
+
+	a.mu.Lock()
+	host, port := s.host, s.port
+	s.software = res.Software
+	a.applyEncoding(s)
+	ice := a.iceColorsFor(s)
+	changed := ice != s.screen.IceColors
+	a.screenMu.Lock()
+	s.screen.IceColors = ice
+	a.screenMu.Unlock()
+	a.mu.Unlock()
+
+	log.Printf("[FINGERPRINT] %s:%d → %s %s", host, port, res.Software, res.Version)
+	if err := a.book.SetSoftware(host, port, res.Software); err != nil {
+		log.Printf("[FINGERPRINT] Rubrica non aggiornata: %v", err)
+	}
+	s.eventLog.write(evSoftware, map[string]any{
+		"software": res.Software, "version": res.Version, "negotiation": res.Negotiation,
+	})
+	a.emitFor(s, "bbs-software", res)
+	a.emitSessions()
+	if changed {
+		a.emitFor(s, "screen-update")
+	}
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"strings"
+	"time"
+
+	wailsrt "github.com/wailsapp/wails/v2/pkg/runtime"
+
+	"github.com/rj45lab/bbs-client-go/internal/config"
+	"github.com/rj45lab/bbs-client-go/internal/i18n"
+	"github.com/rj45lab/bbs-client-go/pkg/ansi"
+)
+
+// ─────────────────────────────────────────────
+// Righe cambiate per i lettori di schermo
+// ─────────────────────────────────────────────
+//
+// Con Settings.Accessibility il frontend riceve "screen-lines": le righe
+// dello schermo attivo il cui testo è cambiato dall'ultimo annuncio, come
+// testo semplice. Un lettore di schermo può così leggere solo l'output
+// nuovo invece dell'intera griglia. Gli annunci aspettano che lo schermo
+// resti fermo per speechDelay, così una schermata disegnata a pezzi
+// arriva in un solo evento; durante un flusso continuo partono comunque
+// almeno ogni speechMaxDelay.
+
+// Attese degli annunci
+const (
+	speechDelay    = 300 * time.Millisecond
+	speechMaxDelay = 2 * time.Second
+)
+
+// LineChange è una riga annunciata in "screen-lines".
+type LineChange struct {
+	Line int    `json:"line"` // numero della riga, da 1
+	Text string `json:"text"` // testo senza spazi finali ("" = riga svuotata)
+}
+
+// speechState è lo schermo come l'ha annunciato il frontend. Protetto da
+// App.pushMu.
+type speechState struct {
+	screen *ansi.Screen // scheda annunciata (nil = niente annunciato)
+	lines  []string
+	timer  *time.Timer
+	since  time.Time // primo cambiamento non ancora annunciato (zero = nessuno)
+}
+
+// scheduleSpeech rimanda l'annuncio delle righe cambiate a quando lo
+// schermo resta fermo. Chiamare con pushMu acquisito e senza a.mu.
+func (a *App) scheduleSpeech() {
+	a.mu.Lock()
+	enabled := a.settings.Accessibility
+	a.mu.Unlock()
+	sp := &a.speech
+	if !enabled {
+		if sp.timer != nil {
+			sp.timer.Stop()
+		}
+		*sp = speechState{}
+		return
+	}
+	now := time.Now()
+	if sp.since.IsZero() {
+		sp.since = now
+	}
+	wait := min(speechDelay, speechMaxDelay-now.Sub(sp.since))
+	if sp.timer == nil {
+		sp.timer = time.AfterFunc(wait, a.speakLines)
+		return
+	}
+	sp.timer.Reset(wait)
+}
+
+// speakLines invia "screen-lines" con le righe dell'ultima copia dello
+// schermo cambiate dall'annuncio precedente, tutte quelle non vuote se la
+// scheda o le dimensioni sono cambiate.
+func (a *App) speakLines() {
+	a.pushMu.Lock()
+	defer a.pushMu.Unlock()
+	sp := &a.speech
+	sp.since = time.Time{}
+	f := a.frame
+	if f == nil || sp.timer == nil {
+		return
+	}
+	if sp.screen != f.screen || len(sp.lines) != f.rows {
+		sp.screen = f.screen
+		sp.lines = make([]string, f.rows)
+	}
+	var changed []LineChange
+	for y, row := range f.cells {
+		text := rowText(row)
+		if text == sp.lines[y] {
+			continue
+		}
+		sp.lines[y] = text
+		changed = append(changed, LineChange{Line: y + 1, Text: text})
+	}
+	if len(changed) > 0 {
+		wailsrt.EventsEmit(a.ctx, "screen-lines", changed)
+	}
+}
+
+// rowText ritorna il testo di una riga come in Screen.Text.
+func rowText(row []ansi.Cell) string {
+	var b strings.Builder
+	for _, c := range row {
+		ch := c.Char
+		if ch < 0x20 {
+			ch = ' '
+		}
+		b.WriteRune(ch)
+	}
+	return strings.TrimRight(b.String(), " ")
+}
+
+// SetAccessibility attiva gli eventi "screen-lines" per i lettori di
+// schermo.
+func (a *App) SetAccessibility(enabled bool) *i18n.Message {
+	return a.updateSettings(func(s *config.Settings) {
+		s.Accessibility = enabled
+	})
+}
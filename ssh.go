@@ -0,0 +1,133 @@
+package main
+
+import (
+	"cmp"
+	"errors"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+
+	"github.com/rj45lab/bbs-client-go/internal/i18n"
+	"github.com/rj45lab/bbs-client-go/internal/keychain"
+	"github.com/rj45lab/bbs-client-go/internal/sshconn"
+	"github.com/rj45lab/bbs-client-go/pkg/telnet"
+)
+
+// ─────────────────────────────────────────────
+// Connessioni SSH
+// ─────────────────────────────────────────────
+//
+// Le voci della rubrica con protocollo ssh si connettono con sshconn: il
+// terminale è quello del telnet, senza negoziazione (telnet.Raw). Nome
+// utente e password sono il login della BBS (SetLogin). Le chiavi host
+// seguono OpenSSH: la prima va approvata dall'utente e finisce in
+// known_hosts nella directory di configurazione; una chiave diversa da
+// quella registrata fa rifiutare la connessione finché l'utente non la
+// dimentica (ForgetHostKey).
+
+const (
+	protocolSSH = "ssh"
+	// Utente SSH senza login salvato: le BBS chiedono poi il loro
+	sshGuest = "guest"
+)
+
+// Esiti della verifica in HostKeyInfo.Status
+const (
+	hostKeyNew     = "new"     // host mai visto: si chiede all'utente
+	hostKeyChanged = "changed" // chiave diversa da quella registrata
+	hostKeyRevoked = "revoked" // chiave marcata @revoked in known_hosts
+)
+
+// HostKeyInfo è il payload di "host-key": la chiave presentata da una BBS
+// SSH che non è tra quelle registrate.
+type HostKeyInfo struct {
+	Session     string `json:"session"`
+	Host        string `json:"host"`        // host:porta
+	Type        string `json:"type"`        // algoritmo (es. ssh-ed25519)
+	Fingerprint string `json:"fingerprint"` // impronta SHA256 della chiave presentata
+	Known       string `json:"known"`       // impronta di quella registrata (solo changed)
+	Status      string `json:"status"`      // new, changed, revoked
+}
+
+// knownHostsPath ritorna il percorso del file known_hosts ("" se la
+// directory di configurazione non è disponibile).
+func knownHostsPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "bbs-client", "known_hosts")
+}
+
+// sshDialer ritorna il Dialer SSH di s verso host:port, attraverso base
+// (nil = connessione diretta).
+func (a *App) sshDialer(s *session, host string, port int, base telnet.Dialer) telnet.Dialer {
+	st := a.config.Get()
+	key := bbsKey(host, port)
+	password, err := keychain.Password(key)
+	if err != nil {
+		log.Printf("[SSH] Portachiavi non disponibile, login senza password: %v", err)
+	}
+	return &sshconn.SSH{
+		Base:     base,
+		User:     cmp.Or(st.Logins[key], sshGuest),
+		Password: password,
+		Term:     strings.ToLower(emulationFor(st, key).TermType()),
+		HostKey: func(addr string, remote net.Addr, hostKey ssh.PublicKey) error {
+			return a.verifyHostKey(s, addr, remote, hostKey)
+		},
+	}
+}
+
+// verifyHostKey accetta le chiavi registrate in known_hosts e chiede
+// all'utente di approvare quella di un host nuovo; rifiuta le chiavi
+// cambiate o revocate. Chiamata durante l'handshake, senza a.mu.
+func (a *App) verifyHostKey(s *session, addr string, remote net.Addr, key ssh.PublicKey) error {
+	known, err := a.knownHosts.Check(addr, remote, key)
+	if err == nil {
+		return nil
+	}
+	info := HostKeyInfo{Session: s.id, Host: addr, Type: key.Type(), Fingerprint: sshconn.Fingerprint(key)}
+	switch {
+	case errors.Is(err, sshconn.ErrUnknownHost):
+		info.Status = hostKeyNew
+	case errors.Is(err, sshconn.ErrHostKeyChanged):
+		info.Status, info.Known = hostKeyChanged, sshconn.Fingerprint(known)
+	case errors.Is(err, sshconn.ErrHostKeyRevoked):
+		info.Status = hostKeyRevoked
+	default:
+		log.Printf("[SSH] %s illeggibile: %v", a.knownHosts.Path(), err)
+		return err
+	}
+	a.emitFor(s, "host-key", info)
+
+	switch info.Status {
+	case hostKeyChanged:
+		log.Printf("[SSH] La chiave host di %s è cambiata (%s, registrata %s): connessione rifiutata", addr, info.Fingerprint, info.Known)
+		return i18n.New(i18n.ErrHostKeyChanged, addr, info.Fingerprint)
+	case hostKeyRevoked:
+		log.Printf("[SSH] La chiave host di %s è revocata: connessione rifiutata", addr)
+		return i18n.New(i18n.ErrHostKeyRejected, addr)
+	}
+	msg := i18n.T(i18n.DlgHostKey, addr, info.Type, info.Fingerprint)
+	if !a.ask(i18n.T(i18n.DlgHostKeyTitle), msg, i18n.T(i18n.DlgTrust)) {
+		log.Printf("[SSH] Chiave host di %s non accettata", addr)
+		return i18n.New(i18n.ErrHostKeyRejected, addr)
+	}
+	if err := a.knownHosts.Add(addr, key); err != nil {
+		log.Printf("[SSH] Impossibile registrare la chiave di %s: %v", addr, err)
+	}
+	log.Printf("[SSH] Chiave host di %s accettata (%s)", addr, info.Fingerprint)
+	return nil
+}
+
+// ForgetHostKey dimentica la chiave host registrata per host:port, come
+// ssh-keygen -R: alla prossima connessione verrà chiesta di nuovo.
+func (a *App) ForgetHostKey(host string, port int) *i18n.Message {
+	return i18n.Err(a.knownHosts.Forget(net.JoinHostPort(host, strconv.Itoa(port))))
+}
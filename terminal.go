@@ -0,0 +1,108 @@
+package main
+
+import (
+	"log"
+
+	wailsrt "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// ─────────────────────────────────────────────
+// Dimensioni del terminale
+// ─────────────────────────────────────────────
+//
+// Il frontend adatta il terminale alla finestra con SetTerminalSize. Le
+// dimensioni valgono per tutte le schede: gli schermi vengono ridimensionati
+// e le BBS che hanno chiesto NAWS ricevono subito le nuove. Gli artwork
+// con una larghezza nel record SAUCE la conservano.
+
+// Limiti delle dimensioni accettate
+const (
+	minTermCols = 20
+	maxTermCols = 255
+	minTermRows = 5
+	maxTermRows = 200
+)
+
+// TerminalSize sono le dimensioni del terminale in celle.
+type TerminalSize struct {
+	Cols int `json:"cols"`
+	Rows int `json:"rows"`
+	// Sotto le Rows righe della BBS il frontend mostra la riga di stato
+	// locale (Entry.StatusLine)
+	StatusLine bool `json:"statusLine,omitempty"`
+}
+
+// SetTerminalSize ridimensiona il terminale di tutte le schede, entro i
+// limiti ammessi, e ritorna le dimensioni applicate.
+func (a *App) SetTerminalSize(cols, rows int) TerminalSize {
+	size := TerminalSize{
+		Cols: min(max(cols, minTermCols), maxTermCols),
+		Rows: min(max(rows, minTermRows), maxTermRows),
+	}
+	a.mu.Lock()
+	if size == a.termSize {
+		a.mu.Unlock()
+		return size
+	}
+	a.termSize = size
+	for _, s := range a.sessions {
+		a.resizeSession(s)
+	}
+	viewing := a.viewingLog
+	a.mu.Unlock()
+	log.Printf("[TERM] Dimensioni %dx%d", size.Cols, size.Rows)
+
+	if viewing {
+		// La barra di navigazione va ridisegnata sulla nuova larghezza
+		a.showLogPage()
+		return size
+	}
+	a.emitGeometry()
+	a.pushScreen()
+	return size
+}
+
+// GetTerminalSize ritorna le dimensioni dello schermo della scheda attiva.
+func (a *App) GetTerminalSize() TerminalSize {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return TerminalSize{Cols: a.screen.Cols, Rows: a.screen.Rows, StatusLine: a.statusLine && !a.viewingLog}
+}
+
+// emitGeometry invia al frontend le dimensioni della scheda attiva.
+func (a *App) emitGeometry() {
+	wailsrt.EventsEmit(a.ctx, "terminal-resized", a.GetTerminalSize())
+}
+
+// resizeSession porta schermo e connessione di s alle dimensioni correnti.
+// Chiamare con a.mu acquisito.
+func (a *App) resizeSession(s *session) {
+	cols := a.termSize.Cols
+	if s.viewingLog && s.sauce != nil && s.sauce.Width > 0 && s.sauce.Width <= maxSauceWidth {
+		cols = s.sauce.Width
+	}
+	rows := a.bbsRows(s)
+	a.screenMu.Lock()
+	s.screen.Resize(cols, rows)
+	a.screenMu.Unlock()
+	s.conn.SetSize(a.termSize.Cols, rows)
+}
+
+// bbsRows ritorna le righe dello schermo di s che vede la BBS: quelle
+// fisse della voce in rubrica o quelle della finestra, meno la riga di
+// stato se riservata. Lo schermo e NAWS usano queste, così gli
+// indirizzamenti del cursore della BBS non arrivano mai alla riga di
+// stato. Chiamare con a.mu acquisito.
+func (a *App) bbsRows(s *session) int {
+	rows := a.termSize.Rows
+	if s.viewingLog {
+		return rows
+	}
+	if s.rows > 0 {
+		rows = s.rows
+	}
+	if s.statusLine {
+		rows--
+	}
+	return max(rows, 1)
+}
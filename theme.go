@@ -0,0 +1,219 @@
+package main
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+
+	wailsrt "github.com/wailsapp/wails/v2/pkg/runtime"
+
+	"github.com/rj45lab/bbs-client-go/internal/config"
+	"github.com/rj45lab/bbs-client-go/internal/i18n"
+	"github.com/rj45lab/bbs-client-go/pkg/ansi"
+)
+
+// ─────────────────────────────────────────────
+// Temi
+// ─────────────────────────────────────────────
+//
+// Un tema raccoglie l'aspetto del terminale: palette e bold policy, usate
+// dallo screen, e font, dimensione, forma del cursore e sfondo, applicati
+// dal frontend. Il tema scelto (Settings.Theme) vale per tutte le BBS
+// tranne quelle con un tema proprio (Settings.BBSThemes). Senza tema
+// scelto valgono Settings.Palette e Settings.BoldPolicy con l'aspetto
+// classico.
+
+// Valori ammessi nei temi
+var (
+	themeFonts   = []string{"ibm-vga", "vt323"}
+	themeCursors = []string{"block", "underline", "bar"}
+	themeColorRe = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+)
+
+// Limiti della dimensione del font, in pixel
+const (
+	minThemeFontSize = 8
+	maxThemeFontSize = 48
+)
+
+// builtinThemes sono i temi sempre disponibili, non modificabili.
+var builtinThemes = []config.Theme{
+	{Name: "classico", Palette: "vga", BoldPolicy: "both", Font: "ibm-vga", FontSize: 16, Cursor: "block", Background: "#000000"},
+	{Name: "amiga", Palette: "amiga", BoldPolicy: "bright", Font: "ibm-vga", FontSize: 16, Cursor: "block", Background: "#0055aa"},
+	{Name: "xterm", Palette: "xterm", BoldPolicy: "both", Font: "vt323", FontSize: 20, Cursor: "underline", Background: "#101010"},
+}
+
+// ThemeInfo è un tema per il frontend.
+type ThemeInfo struct {
+	config.Theme
+	Builtin bool `json:"builtin"`
+}
+
+// findTheme cerca un tema per nome tra quelli predefiniti e dell'utente.
+func findTheme(s config.Settings, name string) (config.Theme, bool) {
+	for _, list := range [][]config.Theme{builtinThemes, s.Themes} {
+		if i := slices.IndexFunc(list, func(t config.Theme) bool { return t.Name == name }); i >= 0 {
+			return list[i], true
+		}
+	}
+	return config.Theme{}, false
+}
+
+// themeFor ritorna il tema della BBS key: il suo tema, altrimenti quello
+// scelto, altrimenti l'aspetto classico con palette e bold policy delle
+// impostazioni.
+func themeFor(s config.Settings, key string) config.Theme {
+	if t, ok := findTheme(s, s.BBSThemes[key]); ok {
+		return t
+	}
+	if t, ok := findTheme(s, s.Theme); ok {
+		return t
+	}
+	t := builtinThemes[0]
+	t.Name = ""
+	t.Palette, t.CustomPalette, t.BoldPolicy = s.Palette, s.CustomPalette, s.BoldPolicy
+	return t
+}
+
+// validateTheme controlla tutti i campi di un tema.
+func validateTheme(t config.Theme) *i18n.Message {
+	switch {
+	case t.Name == "":
+		return i18n.New(i18n.ErrInvalidTheme, "nome mancante")
+	case !slices.Contains(themeFonts, t.Font):
+		return i18n.New(i18n.ErrInvalidTheme, "font "+t.Font)
+	case t.FontSize < minThemeFontSize || t.FontSize > maxThemeFontSize:
+		return i18n.New(i18n.ErrInvalidTheme, "dimensione "+strconv.Itoa(t.FontSize))
+	case !slices.Contains(themeCursors, t.Cursor):
+		return i18n.New(i18n.ErrInvalidTheme, "cursore "+t.Cursor)
+	case !themeColorRe.MatchString(t.Background):
+		return i18n.New(i18n.ErrInvalidTheme, "sfondo "+t.Background)
+	}
+	if _, err := resolvePalette(t.Palette, t.CustomPalette); err != nil {
+		return i18n.Err(err)
+	}
+	if _, ok := ansi.ParseBoldPolicy(t.BoldPolicy); !ok {
+		return i18n.New(i18n.ErrUnknownBold, t.BoldPolicy)
+	}
+	return nil
+}
+
+// validateThemeSettings controlla temi e riferimenti delle impostazioni.
+func validateThemeSettings(s config.Settings) *i18n.Message {
+	for i, t := range s.Themes {
+		if err := validateTheme(t); err != nil {
+			return err
+		}
+		// Nomi unici, diversi da quelli dei temi predefiniti
+		if isBuiltinTheme(t.Name) || slices.ContainsFunc(s.Themes[:i], func(o config.Theme) bool { return o.Name == t.Name }) {
+			return i18n.New(i18n.ErrInvalidTheme, t.Name)
+		}
+	}
+	if _, ok := findTheme(s, s.Theme); s.Theme != "" && !ok {
+		return i18n.New(i18n.ErrUnknownTheme, s.Theme)
+	}
+	for _, name := range s.BBSThemes {
+		if _, ok := findTheme(s, name); !ok {
+			return i18n.New(i18n.ErrUnknownTheme, name)
+		}
+	}
+	return nil
+}
+
+// isBuiltinTheme dice se name è un tema predefinito.
+func isBuiltinTheme(name string) bool {
+	return slices.ContainsFunc(builtinThemes, func(t config.Theme) bool { return t.Name == name })
+}
+
+// emitTheme invia al frontend il tema della scheda attiva.
+func (a *App) emitTheme() {
+	wailsrt.EventsEmit(a.ctx, "theme-changed", a.GetTheme())
+}
+
+// ListThemes ritorna i temi predefiniti seguiti da quelli dell'utente.
+func (a *App) ListThemes() []ThemeInfo {
+	var out []ThemeInfo
+	for _, t := range builtinThemes {
+		out = append(out, ThemeInfo{Theme: t, Builtin: true})
+	}
+	for _, t := range a.config.Get().Themes {
+		out = append(out, ThemeInfo{Theme: t})
+	}
+	return out
+}
+
+// GetTheme ritorna il tema in uso nella scheda attiva.
+func (a *App) GetTheme() config.Theme {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return themeFor(a.settings, bbsKey(a.host, a.port))
+}
+
+// SetTheme sceglie il tema per tutte le BBS ("" = aspetto classico con
+// palette e bold policy delle impostazioni).
+func (a *App) SetTheme(name string) *i18n.Message {
+	if _, ok := findTheme(a.config.Get(), name); name != "" && !ok {
+		return i18n.New(i18n.ErrUnknownTheme, name)
+	}
+	return a.updateSettings(func(s *config.Settings) {
+		s.Theme = name
+	})
+}
+
+// SetBBSTheme sceglie il tema per la BBS key (host:port, "" per la BBS
+// corrente). Con name vuoto la BBS torna al tema generale.
+func (a *App) SetBBSTheme(key, name string) *i18n.Message {
+	if _, ok := findTheme(a.config.Get(), name); name != "" && !ok {
+		return i18n.New(i18n.ErrUnknownTheme, name)
+	}
+	if key == "" {
+		a.mu.Lock()
+		key = bbsKey(a.host, a.port)
+		a.mu.Unlock()
+	}
+	return a.updateSettings(func(s *config.Settings) {
+		if name == "" {
+			delete(s.BBSThemes, key)
+		} else {
+			s.BBSThemes[key] = name
+		}
+	})
+}
+
+// SaveTheme crea un tema dell'utente o sostituisce quello con lo stesso
+// nome. I temi predefiniti non si possono modificare.
+func (a *App) SaveTheme(t config.Theme) *i18n.Message {
+	if err := validateTheme(t); err != nil {
+		return err
+	}
+	if isBuiltinTheme(t.Name) {
+		return i18n.New(i18n.ErrInvalidTheme, t.Name)
+	}
+	return a.updateSettings(func(s *config.Settings) {
+		if i := slices.IndexFunc(s.Themes, func(o config.Theme) bool { return o.Name == t.Name }); i >= 0 {
+			s.Themes[i] = t
+		} else {
+			s.Themes = append(s.Themes, t)
+		}
+	})
+}
+
+// DeleteTheme elimina un tema dell'utente. Le BBS che lo usavano tornano
+// al tema generale, e questo all'aspetto classico.
+func (a *App) DeleteTheme(name string) *i18n.Message {
+	s := a.config.Get()
+	if !slices.ContainsFunc(s.Themes, func(t config.Theme) bool { return t.Name == name }) {
+		return i18n.New(i18n.ErrUnknownTheme, name)
+	}
+	return a.updateSettings(func(s *config.Settings) {
+		s.Themes = slices.DeleteFunc(s.Themes, func(t config.Theme) bool { return t.Name == name })
+		if s.Theme == name {
+			s.Theme = ""
+		}
+		for key, n := range s.BBSThemes {
+			if n == name {
+				delete(s.BBSThemes, key)
+			}
+		}
+	})
+}
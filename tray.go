@@ -0,0 +1,209 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"fmt"
+	"image"
+	"image/png"
+	"log"
+	"runtime"
+
+	"fyne.io/systray"
+	wailsrt "github.com/wailsapp/wails/v2/pkg/runtime"
+	xdraw "golang.org/x/image/draw"
+
+	"github.com/rj45lab/bbs-client-go/internal/config"
+	"github.com/rj45lab/bbs-client-go/internal/i18n"
+)
+
+// ─────────────────────────────────────────────
+// System tray e modalità in background
+// ─────────────────────────────────────────────
+
+var (
+	//go:embed build/appicon.png
+	appIconPNG []byte
+	//go:embed build/windows/icon.ico
+	appIconICO []byte
+)
+
+// trayIconSize è il lato dell'icona PNG passata al tray (l'icona
+// dell'app è troppo grande da inviare così com'è su D-Bus).
+const trayIconSize = 64
+
+// tray contiene le voci di menu dell'icona nell'area di notifica.
+type tray struct {
+	ready      bool
+	end        func()
+	show       *systray.MenuItem
+	reconnect  *systray.MenuItem
+	disconnect *systray.MenuItem
+	quit       *systray.MenuItem
+
+	hidden bool // finestra nascosta nel tray
+	unread int  // frasi sorvegliate arrivate con la finestra nascosta
+}
+
+// startTray crea l'icona nel tray usando il loop eventi di Wails.
+func (a *App) startTray() {
+	start, end := systray.RunWithExternalLoop(a.onTrayReady, nil)
+	a.mu.Lock()
+	a.tray.end = end
+	a.mu.Unlock()
+	start()
+}
+
+// stopTray rimuove l'icona dal tray.
+func (a *App) stopTray() {
+	a.mu.Lock()
+	end := a.tray.end
+	a.tray.end = nil
+	a.mu.Unlock()
+	if end != nil {
+		end()
+	}
+}
+
+func (a *App) onTrayReady() {
+	systray.SetIcon(trayIcon())
+	systray.SetTooltip("BBS Client")
+
+	t := &a.tray
+	show := systray.AddMenuItem(i18n.T(i18n.TrayShow), i18n.T(i18n.TrayShowHint))
+	systray.AddSeparator()
+	reconnect := systray.AddMenuItem(i18n.T(i18n.TrayReconnect), i18n.T(i18n.TrayReconnectHint))
+	disconnect := systray.AddMenuItem(i18n.T(i18n.TrayDisconnect), i18n.T(i18n.TrayDisconnHint))
+	systray.AddSeparator()
+	quit := systray.AddMenuItem(i18n.T(i18n.TrayQuit), i18n.T(i18n.TrayQuitHint))
+
+	a.mu.Lock()
+	t.show, t.reconnect, t.disconnect, t.quit = show, reconnect, disconnect, quit
+	t.ready = true
+	a.mu.Unlock()
+	a.updateTray()
+
+	go func() {
+		for {
+			select {
+			case <-show.ClickedCh:
+				a.showWindow()
+			case <-reconnect.ClickedCh:
+				a.mu.Lock()
+				host, port, name := a.host, a.port, a.bbsName
+				a.mu.Unlock()
+				if err := a.Connect(host, port, name).Error; err != nil {
+					log.Printf("[TRAY] riconnessione: %s", err)
+				}
+			case <-disconnect.ClickedCh:
+				a.Disconnect()
+			case <-quit.ClickedCh:
+				wailsrt.Quit(a.ctx)
+				return
+			}
+		}
+	}()
+}
+
+// updateTray aggiorna voci abilitate, titolo e tooltip secondo lo stato
+// della connessione e gli avvisi non letti.
+func (a *App) updateTray() {
+	a.mu.Lock()
+	t, connected, host := a.tray, a.connected, a.host
+	a.mu.Unlock()
+	if !t.ready {
+		return
+	}
+	if connected {
+		t.reconnect.Disable()
+		t.disconnect.Enable()
+	} else {
+		t.reconnect.Enable()
+		t.disconnect.Disable()
+	}
+
+	t.show.SetTitle(i18n.T(i18n.TrayShow))
+	t.reconnect.SetTitle(i18n.T(i18n.TrayReconnect))
+	t.disconnect.SetTitle(i18n.T(i18n.TrayDisconnect))
+	t.quit.SetTitle(i18n.T(i18n.TrayQuit))
+
+	tip := "BBS Client — " + i18n.T(i18n.TrayOffline)
+	if connected {
+		tip = "BBS Client — " + host
+	}
+	title := ""
+	if t.unread > 0 {
+		tip += i18n.T(i18n.TrayUnread, t.unread)
+		title = fmt.Sprintf("%d", t.unread)
+	}
+	systray.SetTooltip(tip)
+	if runtime.GOOS != "windows" {
+		systray.SetTitle(title) // badge accanto all'icona (macOS, Linux)
+	}
+}
+
+// beforeClose è l'hook OnBeforeClose di Wails: con Settings.CloseToTray la
+// finestra viene solo nascosta e la sessione resta attiva.
+func (a *App) beforeClose(ctx context.Context) bool {
+	a.mu.Lock()
+	toTray := a.settings.CloseToTray && a.tray.ready
+	if toTray {
+		a.tray.hidden = true
+	}
+	a.mu.Unlock()
+	if toTray {
+		wailsrt.WindowHide(ctx)
+	}
+	return toTray
+}
+
+// showWindow riporta la finestra dal tray e azzera gli avvisi non letti.
+func (a *App) showWindow() {
+	a.mu.Lock()
+	a.tray.hidden = false
+	a.tray.unread = 0
+	a.mu.Unlock()
+	wailsrt.WindowShow(a.ctx)
+	wailsrt.WindowUnminimise(a.ctx)
+	a.updateTray()
+}
+
+// trayUnread conta gli avvisi arrivati mentre la finestra è nascosta.
+func (a *App) trayUnread(n int) {
+	a.mu.Lock()
+	hidden := a.tray.hidden
+	if hidden {
+		a.tray.unread += n
+	}
+	a.mu.Unlock()
+	if hidden {
+		a.updateTray()
+	}
+}
+
+// SetCloseToTray sceglie se chiudere la finestra la nasconde nel tray
+// lasciando attiva la sessione.
+func (a *App) SetCloseToTray(enabled bool) *i18n.Message {
+	return a.updateSettings(func(s *config.Settings) {
+		s.CloseToTray = enabled
+	})
+}
+
+// trayIcon ritorna l'icona nel formato richiesto dalla piattaforma.
+func trayIcon() []byte {
+	if runtime.GOOS == "windows" {
+		return appIconICO
+	}
+	src, err := png.Decode(bytes.NewReader(appIconPNG))
+	if err != nil {
+		return appIconPNG
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, trayIconSize, trayIconSize))
+	xdraw.CatmullRom.Scale(dst, dst.Rect, src, src.Bounds(), xdraw.Over, nil)
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, dst); err != nil {
+		return appIconPNG
+	}
+	return buf.Bytes()
+}
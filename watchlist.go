@@ -0,0 +1,93 @@
+package main
+
+import (
+	"log"
+	"slices"
+
+	wailsrt "github.com/wailsapp/wails/v2/pkg/runtime"
+
+	"github.com/rj45lab/bbs-client-go/internal/config"
+	"github.com/rj45lab/bbs-client-go/internal/i18n"
+	"github.com/rj45lab/bbs-client-go/internal/notify"
+	"github.com/rj45lab/bbs-client-go/internal/watch"
+)
+
+// ─────────────────────────────────────────────
+// Frasi sorvegliate (watch strings)
+// ─────────────────────────────────────────────
+
+// watchAll è la chiave delle frasi valide per tutte le BBS.
+const watchAll = "*"
+
+// watchPhrases ritorna le frasi da cercare per la BBS key: quelle comuni
+// a tutte le BBS più quelle specifiche.
+func watchPhrases(s config.Settings, key string) []string {
+	return append(slices.Clone(s.Watch[watchAll]), s.Watch[key]...)
+}
+
+// GetWatchPhrases ritorna le frasi sorvegliate per key (host:port, "*"
+// per tutte le BBS, "" per la BBS corrente).
+func (a *App) GetWatchPhrases(key string) []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if key == "" {
+		key = bbsKey(a.host, a.port)
+	}
+	return slices.Clone(a.settings.Watch[key])
+}
+
+// SetWatchPhrases imposta le frasi sorvegliate per key (host:port, "*"
+// per tutte le BBS, "" per la BBS corrente). Valgono subito.
+func (a *App) SetWatchPhrases(key string, phrases []string) *i18n.Message {
+	if key == "" {
+		a.mu.Lock()
+		key = bbsKey(a.host, a.port)
+		a.mu.Unlock()
+	}
+	return a.updateSettings(func(s *config.Settings) {
+		if len(phrases) == 0 {
+			delete(s.Watch, key)
+		} else {
+			s.Watch[key] = phrases
+		}
+	})
+}
+
+// SetWatchNotify attiva le notifiche di sistema per le frasi trovate.
+func (a *App) SetWatchNotify(enabled bool) *i18n.Message {
+	return a.updateSettings(func(s *config.Settings) {
+		s.WatchNotify = enabled
+	})
+}
+
+// onWatchMatches segnala al frontend (evento "watch-match") e, se
+// richiesto, al sistema le frasi trovate nell'ultimo blocco ricevuto.
+func (a *App) onWatchMatches(s *session, matches []watch.Match) {
+	if len(matches) == 0 {
+		return
+	}
+	for _, m := range matches {
+		s.eventLog.write(evWatch, map[string]any{"phrase": m.Phrase, "line": m.Line})
+	}
+	if a.missWatchMatches(s, matches) {
+		for _, m := range matches {
+			log.Printf("[WATCH] %s (non disturbare): %q", s.id, m.Line)
+		}
+		a.emitSessions()
+		return
+	}
+	a.mu.Lock()
+	host, osNotify := s.host, a.settings.WatchNotify
+	a.mu.Unlock()
+
+	a.trayUnread(len(matches))
+	for _, m := range matches {
+		log.Printf("[WATCH] %s: %q", host, m.Line)
+		wailsrt.EventsEmit(a.ctx, "watch-match", WatchMatch{Session: s.id, BBS: host, Phrase: m.Phrase, Line: m.Line})
+		if osNotify {
+			if err := notify.Send("BBS "+host+": "+m.Phrase, m.Line); err != nil {
+				log.Printf("[WATCH] notifica: %v", err)
+			}
+		}
+	}
+}